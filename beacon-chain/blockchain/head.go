@@ -108,8 +108,10 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Reorg,
 			Data: &statefeed.ReorgData{
-				NewSlot: newHeadBlock.Block.Slot,
-				OldSlot: s.headSlot(),
+				NewSlot:     newHeadBlock.Block.Slot,
+				OldSlot:     s.headSlot(),
+				NewHeadRoot: headRoot,
+				OldHeadRoot: s.headRoot(),
 			},
 		})
 
@@ -187,6 +187,9 @@ func (s *Service) Start() {
 		s.finalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.prevFinalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.resumeForkChoice(justifiedCheckpoint, finalizedCheckpoint)
+		if err := s.insertAnchorToForkChoice(ctx, finalizedCheckpoint); err != nil {
+			log.Fatalf("Could not insert anchor block to fork choice: %v", err)
+		}
 
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Initialized,
@@ -382,8 +385,17 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "could not get genesis block from db")
 	}
+	anchorBlock, err := s.beaconDB.AnchorBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get anchor block from db")
+	}
 	if genesisBlock == nil {
-		return errors.New("no genesis block in db")
+		// A node started from a finalized checkpoint has no genesis block, the anchor block
+		// takes its place as the root of the chain.
+		if anchorBlock == nil {
+			return errors.New("no genesis block in db")
+		}
+		genesisBlock = anchorBlock
 	}
 	genesisBlkRoot, err := stateutil.BlockRoot(genesisBlock.Block)
 	if err != nil {
@@ -439,8 +451,10 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 
 	// To skip the regeneration of historical state, the node has to generate the parent of the last finalized state.
 	// We don't need to do this for genesis.
+	// We don't need to do this for the anchor block either, as its parent is not in the db.
 	atGenesis := s.CurrentSlot() == 0
-	if featureconfig.Get().SkipRegenHistoricalStates && !atGenesis {
+	atAnchor := anchorBlock != nil && finalizedBlock != nil && anchorBlock.Block.Slot == finalizedBlock.Block.Slot
+	if featureconfig.Get().SkipRegenHistoricalStates && !atGenesis && !atAnchor {
 		parentRoot := bytesutil.ToBytes32(finalizedBlock.Block.ParentRoot)
 		parentState, err := s.generateState(ctx, finalizedRoot, parentRoot)
 		if err != nil {
@@ -489,6 +503,31 @@ func (s *Service) resumeForkChoice(justifiedCheckpoint *ethpb.Checkpoint, finali
 	s.forkChoiceStore = store
 }
 
+// This inserts the anchor block of a node started from a finalized checkpoint to the fork choice store,
+// when it is still the finalized block. Its ancestors are not in the db, so fork choice can not
+// fill them in from the first block processed after it.
+func (s *Service) insertAnchorToForkChoice(ctx context.Context, finalizedCheckpoint *ethpb.Checkpoint) error {
+	anchorBlock, err := s.beaconDB.AnchorBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if anchorBlock == nil || anchorBlock.Block == nil {
+		return nil
+	}
+	anchorRoot, err := stateutil.BlockRoot(anchorBlock.Block)
+	if err != nil {
+		return err
+	}
+	if anchorRoot != bytesutil.ToBytes32(finalizedCheckpoint.Root) || s.forkChoiceStore.HasNode(anchorRoot) {
+		return nil
+	}
+	b := anchorBlock.Block
+	return s.forkChoiceStore.ProcessBlock(ctx,
+		b.Slot, anchorRoot, bytesutil.ToBytes32(b.ParentRoot), bytesutil.ToBytes32(b.Body.Graffiti),
+		finalizedCheckpoint.Epoch,
+		finalizedCheckpoint.Epoch)
+}
+
 // This returns true if block has been processed before. Two ways to verify the block has been processed:
 // 1.) Check fork choice store.
 // 2.) Check DB.
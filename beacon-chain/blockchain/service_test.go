@@ -365,6 +365,57 @@ func TestChainService_InitializeChainInfo(t *testing.T) {
 	}
 }
 
+func TestChainService_InitializeChainInfo_FromAnchor(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+
+	anchorSlot := params.BeaconConfig().SlotsPerEpoch * 2
+	anchorBlock := testutil.NewBeaconBlock()
+	anchorBlock.Block.Slot = anchorSlot
+	anchorBlock.Block.ParentRoot = bytesutil.PadTo([]byte("parent"), 32)
+	anchorState := testutil.NewBeaconState()
+	if err := anchorState.SetSlot(anchorSlot); err != nil {
+		t.Fatal(err)
+	}
+	anchorRoot, err := stateutil.BlockRoot(anchorBlock.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, anchorBlock); err != nil {
+		t.Fatal(err)
+	}
+	sg := stategen.New(db, cache.NewStateSummaryCache())
+	if err := sg.SaveAnchorState(ctx, anchorRoot, anchorState); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	cp := &ethpb.Checkpoint{Epoch: helpers.SlotToEpoch(anchorSlot), Root: anchorRoot[:]}
+	if err := db.SaveFinalizedCheckpoint(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Service{beaconDB: db, stateGen: sg}
+	if err := c.initializeChainInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if c.HeadSlot() != anchorSlot {
+		t.Errorf("Wanted head slot %d, received %d", anchorSlot, c.HeadSlot())
+	}
+	if c.genesisRoot != anchorRoot {
+		t.Error("Anchor block root should be used in place of genesis block root")
+	}
+
+	c.resumeForkChoice(cp, cp)
+	if err := c.insertAnchorToForkChoice(ctx, cp); err != nil {
+		t.Fatal(err)
+	}
+	if !c.forkChoiceStore.HasNode(anchorRoot) {
+		t.Error("Anchor block was not inserted to fork choice")
+	}
+}
+
 func TestChainService_SaveHeadNoDB(t *testing.T) {
 	db := testDB.SetupDB(t)
 	ctx := context.Background()
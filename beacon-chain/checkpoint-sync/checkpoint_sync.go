@@ -0,0 +1,217 @@
+// Package checkpointsync allows a beacon node to start from a trusted finalized
+// (weak subjectivity) checkpoint instead of syncing from genesis. The finalized
+// state and block are either loaded from SSZ files or fetched from another beacon
+// node and saved as the anchor of the node's database.
+package checkpointsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// Config options for starting a node from a finalized checkpoint.
+type Config struct {
+	BeaconDB           db.HeadAccessDatabase
+	StateGen           *stategen.State
+	StatePath          string
+	BlockPath          string
+	Provider           string
+	BlockRoot          []byte
+	Epoch              uint64
+	MaxCallRecvMsgSize int
+}
+
+// Enabled returns true if the configuration requests starting from a finalized checkpoint.
+func (cfg *Config) Enabled() bool {
+	return cfg.StatePath != "" || cfg.BlockPath != "" || cfg.Provider != ""
+}
+
+// Initialize saves the configured finalized state and block as the anchor of the database,
+// from which the beacon node syncs forward instead of from genesis. It does nothing if the
+// database already contains chain data.
+func Initialize(ctx context.Context, cfg *Config) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	headState, err := cfg.BeaconDB.HeadState(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get head state")
+	}
+	if headState != nil {
+		log.Info("Chain data already exists in DB, ignoring checkpoint sync configuration")
+		return nil
+	}
+
+	var blk *ethpb.SignedBeaconBlock
+	var state *pb.BeaconState
+	var epoch uint64
+	if cfg.Provider != "" {
+		log.WithField("provider", cfg.Provider).Info("Fetching finalized checkpoint state and block")
+		blk, state, epoch, err = fetchFromProvider(ctx, cfg.Provider, cfg.MaxCallRecvMsgSize)
+	} else {
+		blk, state, err = loadFromFiles(cfg.StatePath, cfg.BlockPath)
+		epoch = cfg.Epoch
+		if epoch == 0 && blk != nil && blk.Block != nil {
+			// Without a configured epoch, the block is taken to be the checkpoint of the
+			// first epoch starting at or after its slot.
+			epoch = helpers.SlotToEpoch(blk.Block.Slot + params.BeaconConfig().SlotsPerEpoch - 1)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return saveAnchor(ctx, cfg, blk, state, epoch)
+}
+
+func loadFromFiles(statePath string, blockPath string) (*ethpb.SignedBeaconBlock, *pb.BeaconState, error) {
+	if statePath == "" || blockPath == "" {
+		return nil, nil, errors.New("both a checkpoint state and block file are required")
+	}
+	enc, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint state")
+	}
+	state := &pb.BeaconState{}
+	if err := state.UnmarshalSSZ(enc); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	enc, err = ioutil.ReadFile(blockPath)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read checkpoint block")
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(enc); err != nil {
+		return nil, nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	return blk, state, nil
+}
+
+func fetchFromProvider(ctx context.Context, endpoint string, maxCallRecvMsgSize int) (*ethpb.SignedBeaconBlock, *pb.BeaconState, uint64, error) {
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if maxCallRecvMsgSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize)))
+	}
+	conn, err := grpc.DialContext(ctx, endpoint, opts...)
+	if err != nil {
+		return nil, nil, 0, errors.Wrapf(err, "could not dial endpoint %s", endpoint)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Error("Could not close connection to checkpoint sync provider")
+		}
+	}()
+	return fetchFinalized(ctx, ethpb.NewBeaconChainClient(conn), pbrpc.NewDebugClient(conn))
+}
+
+// fetchFinalized fetches the latest finalized block, its post state and the epoch of the
+// finalized checkpoint from a beacon node.
+func fetchFinalized(
+	ctx context.Context,
+	beaconClient ethpb.BeaconChainClient,
+	debugClient pbrpc.DebugClient,
+) (*ethpb.SignedBeaconBlock, *pb.BeaconState, uint64, error) {
+	head, err := beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, nil, 0, errors.Wrap(err, "could not get chain head")
+	}
+	blkResp, err := debugClient.GetBlock(ctx, &pbrpc.BlockRequest{BlockRoot: head.FinalizedBlockRoot})
+	if err != nil {
+		return nil, nil, 0, errors.Wrap(err, "could not get finalized block")
+	}
+	if len(blkResp.Encoded) == 0 {
+		return nil, nil, 0, fmt.Errorf("finalized block %#x not found", head.FinalizedBlockRoot)
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(blkResp.Encoded); err != nil {
+		return nil, nil, 0, errors.Wrap(err, "could not unmarshal finalized block")
+	}
+	stateResp, err := debugClient.GetBeaconState(ctx, &pbrpc.BeaconStateRequest{
+		QueryFilter: &pbrpc.BeaconStateRequest_BlockRoot{BlockRoot: head.FinalizedBlockRoot},
+	})
+	if err != nil {
+		return nil, nil, 0, errors.Wrap(err, "could not get finalized state")
+	}
+	state := &pb.BeaconState{}
+	if err := state.UnmarshalSSZ(stateResp.Encoded); err != nil {
+		return nil, nil, 0, errors.Wrap(err, "could not unmarshal finalized state")
+	}
+	return blk, state, head.FinalizedEpoch, nil
+}
+
+// saveAnchor verifies the block and state match, and saves them as the anchor of the database.
+// The anchor is the head, the justified and the finalized checkpoint of the node until it syncs
+// forward. The checkpoint epoch is the finalized epoch the block was requested for, which is later
+// than the epoch of the block if the slot starting the checkpoint epoch was skipped.
+func saveAnchor(ctx context.Context, cfg *Config, blk *ethpb.SignedBeaconBlock, state *pb.BeaconState, epoch uint64) error {
+	if blk == nil || blk.Block == nil {
+		return errors.New("nil checkpoint block")
+	}
+	if blk.Block.Slot > helpers.StartSlot(epoch) {
+		return fmt.Errorf("checkpoint block slot %d is after the start of checkpoint epoch %d", blk.Block.Slot, epoch)
+	}
+	st, err := stateTrie.InitializeFromProto(state)
+	if err != nil {
+		return errors.Wrap(err, "could not get state trie")
+	}
+	if st.Slot() != blk.Block.Slot {
+		return fmt.Errorf("checkpoint state slot %d does not match block slot %d", st.Slot(), blk.Block.Slot)
+	}
+	stateRoot, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not hash checkpoint state")
+	}
+	if !bytes.Equal(stateRoot[:], blk.Block.StateRoot) {
+		return fmt.Errorf("checkpoint state root %#x does not match block state root %#x", stateRoot, blk.Block.StateRoot)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return errors.Wrap(err, "could not get checkpoint block root")
+	}
+	if len(cfg.BlockRoot) > 0 && !bytes.Equal(cfg.BlockRoot, root[:]) {
+		return fmt.Errorf("checkpoint block root %#x does not match expected root %#x", root, cfg.BlockRoot)
+	}
+
+	if err := cfg.BeaconDB.SaveBlock(ctx, blk); err != nil {
+		return errors.Wrap(err, "could not save checkpoint block")
+	}
+	if err := cfg.StateGen.SaveAnchorState(ctx, root, st); err != nil {
+		return err
+	}
+	if err := cfg.BeaconDB.SaveAnchorBlockRoot(ctx, root); err != nil {
+		return errors.Wrap(err, "could not save anchor block root")
+	}
+	if err := cfg.BeaconDB.SaveHeadBlockRoot(ctx, root); err != nil {
+		return errors.Wrap(err, "could not save head block root")
+	}
+	checkpoint := &ethpb.Checkpoint{Epoch: epoch, Root: root[:]}
+	if err := cfg.BeaconDB.SaveJustifiedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save justified checkpoint")
+	}
+	if err := cfg.BeaconDB.SaveFinalizedCheckpoint(ctx, checkpoint); err != nil {
+		return errors.Wrap(err, "could not save finalized checkpoint")
+	}
+
+	log.WithFields(logrus.Fields{
+		"slot":  st.Slot(),
+		"epoch": checkpoint.Epoch,
+		"root":  fmt.Sprintf("%#x", bytesutil.Trunc(root[:])),
+	}).Info("Saved finalized checkpoint as sync anchor")
+	return nil
+}
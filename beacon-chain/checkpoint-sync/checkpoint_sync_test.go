@@ -0,0 +1,285 @@
+package checkpointsync
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"google.golang.org/grpc"
+)
+
+// anchorPair returns a finalized block and its matching post state.
+func anchorPair(t *testing.T, slot uint64) (*ethpb.SignedBeaconBlock, *pb.BeaconState) {
+	st, _ := testutil.DeterministicGenesisState(t, 32)
+	if err := st.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = slot
+	blk.Block.ParentRoot = bytesutil.PadTo([]byte("parent"), 32)
+	blk.Block.StateRoot = stateRoot[:]
+	return blk, st.CloneInnerState()
+}
+
+func writeAnchorFiles(t *testing.T, blk *ethpb.SignedBeaconBlock, state *pb.BeaconState) (string, string) {
+	dir := filepath.Join(testutil.TempDir(), "checkpointsync")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	encState, err := state.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statePath := filepath.Join(dir, "state.ssz")
+	if err := ioutil.WriteFile(statePath, encState, 0600); err != nil {
+		t.Fatal(err)
+	}
+	encBlock, err := blk.MarshalSSZ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockPath := filepath.Join(dir, "block.ssz")
+	if err := ioutil.WriteFile(blockPath, encBlock, 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	})
+	return statePath, blockPath
+}
+
+func testConfig(beaconDB db.Database) *Config {
+	return &Config{
+		BeaconDB: beaconDB,
+		StateGen: stategen.New(beaconDB, cache.NewStateSummaryCache()),
+	}
+}
+
+func TestInitialize_FromFiles(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	slot := 2 * params.BeaconConfig().SlotsPerEpoch
+	blk, state := anchorPair(t, slot)
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(beaconDB)
+	cfg.StatePath, cfg.BlockPath = writeAnchorFiles(t, blk, state)
+	cfg.BlockRoot = root[:]
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	anchor, err := beaconDB.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(anchor, blk) {
+		t.Errorf("Wanted anchor block %v, received %v", blk, anchor)
+	}
+	headState, err := beaconDB.HeadState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headState == nil || headState.Slot() != slot {
+		t.Fatal("Did not save anchor state as head state")
+	}
+	finalized, err := beaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finalized.Epoch != 2 || bytesutil.ToBytes32(finalized.Root) != root {
+		t.Errorf("Unexpected finalized checkpoint %v", finalized)
+	}
+	justified, err := beaconDB.JustifiedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(justified, finalized) {
+		t.Errorf("Wanted justified checkpoint %v, received %v", finalized, justified)
+	}
+	if beaconDB.LastArchivedIndexRoot(ctx) != root {
+		t.Error("Did not save anchor state as last archived point")
+	}
+	if !beaconDB.IsFinalizedBlock(ctx, root) {
+		t.Error("Anchor block is not indexed as finalized")
+	}
+}
+
+func TestInitialize_SkippedEpochBoundary(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	// The first slot of epoch 2 was skipped, so the last block of epoch 1 is the checkpoint
+	// block of epoch 2.
+	blk, state := anchorPair(t, 2*params.BeaconConfig().SlotsPerEpoch-1)
+	cfg := testConfig(beaconDB)
+	cfg.StatePath, cfg.BlockPath = writeAnchorFiles(t, blk, state)
+	cfg.Epoch = 2
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	finalized, err := beaconDB.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finalized.Epoch != 2 {
+		t.Errorf("Wanted finalized epoch 2, received %d", finalized.Epoch)
+	}
+}
+
+func TestInitialize_ExistingChainDataIgnored(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := testDB.SetupDB(t)
+	blk, state := anchorPair(t, params.BeaconConfig().SlotsPerEpoch)
+	cfg := testConfig(beaconDB)
+	cfg.StatePath, cfg.BlockPath = writeAnchorFiles(t, blk, state)
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	other, otherState := anchorPair(t, 2*params.BeaconConfig().SlotsPerEpoch)
+	cfg.StatePath, cfg.BlockPath = writeAnchorFiles(t, other, otherState)
+	if err := Initialize(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+	anchor, err := beaconDB.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(anchor, blk) {
+		t.Error("Existing anchor was overwritten")
+	}
+}
+
+func TestInitialize_Mismatches(t *testing.T) {
+	slot := 2 * params.BeaconConfig().SlotsPerEpoch
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config, blk *ethpb.SignedBeaconBlock, state *pb.BeaconState)
+		wantErr string
+	}{
+		{
+			name: "state root",
+			modify: func(_ *Config, blk *ethpb.SignedBeaconBlock, _ *pb.BeaconState) {
+				blk.Block.StateRoot = bytesutil.PadTo([]byte("bad"), 32)
+			},
+			wantErr: "does not match block state root",
+		},
+		{
+			name: "slot",
+			modify: func(_ *Config, _ *ethpb.SignedBeaconBlock, state *pb.BeaconState) {
+				state.Slot++
+			},
+			wantErr: "does not match block slot",
+		},
+		{
+			name: "epoch",
+			modify: func(cfg *Config, _ *ethpb.SignedBeaconBlock, _ *pb.BeaconState) {
+				cfg.Epoch = 1
+			},
+			wantErr: "after the start of checkpoint epoch",
+		},
+		{
+			name: "expected root",
+			modify: func(cfg *Config, _ *ethpb.SignedBeaconBlock, _ *pb.BeaconState) {
+				cfg.BlockRoot = bytesutil.PadTo([]byte("root"), 32)
+			},
+			wantErr: "does not match expected root",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beaconDB := testDB.SetupDB(t)
+			blk, state := anchorPair(t, slot)
+			cfg := testConfig(beaconDB)
+			cfg.Epoch = 2
+			tt.modify(cfg, blk, state)
+			cfg.StatePath, cfg.BlockPath = writeAnchorFiles(t, blk, state)
+			err := Initialize(context.Background(), cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, received %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+type beaconChainClient struct {
+	ethpb.BeaconChainClient
+	finalizedRoot []byte
+}
+
+func (c *beaconChainClient) GetChainHead(_ context.Context, _ *ptypes.Empty, _ ...grpc.CallOption) (*ethpb.ChainHead, error) {
+	return &ethpb.ChainHead{FinalizedBlockRoot: c.finalizedRoot, FinalizedEpoch: 1}, nil
+}
+
+type debugClient struct {
+	pbrpc.DebugClient
+	root  []byte
+	block *ethpb.SignedBeaconBlock
+	state *pb.BeaconState
+}
+
+func (c *debugClient) GetBlock(_ context.Context, req *pbrpc.BlockRequest, _ ...grpc.CallOption) (*pbrpc.SSZResponse, error) {
+	if string(req.BlockRoot) != string(c.root) {
+		return &pbrpc.SSZResponse{Encoded: make([]byte, 0)}, nil
+	}
+	enc, err := c.block.MarshalSSZ()
+	return &pbrpc.SSZResponse{Encoded: enc}, err
+}
+
+func (c *debugClient) GetBeaconState(_ context.Context, req *pbrpc.BeaconStateRequest, _ ...grpc.CallOption) (*pbrpc.SSZResponse, error) {
+	enc, err := c.state.MarshalSSZ()
+	return &pbrpc.SSZResponse{Encoded: enc}, err
+}
+
+func TestFetchFinalized(t *testing.T) {
+	blk, state := anchorPair(t, params.BeaconConfig().SlotsPerEpoch)
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debug := &debugClient{root: root[:], block: blk, state: state}
+
+	fetchedBlock, fetchedState, epoch, err := fetchFinalized(context.Background(), &beaconChainClient{finalizedRoot: root[:]}, debug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(fetchedBlock, blk) {
+		t.Errorf("Wanted block %v, received %v", blk, fetchedBlock)
+	}
+	if fetchedState.Slot != state.Slot {
+		t.Errorf("Wanted state slot %d, received %d", state.Slot, fetchedState.Slot)
+	}
+	if epoch != 1 {
+		t.Errorf("Wanted finalized epoch 1, received %d", epoch)
+	}
+
+	_, _, _, err = fetchFinalized(context.Background(), &beaconChainClient{finalizedRoot: []byte("unknown")}, debug)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected missing block error, received %v", err)
+	}
+}
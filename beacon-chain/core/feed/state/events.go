@@ -55,4 +55,8 @@ type ReorgData struct {
 	NewSlot uint64
 	// OldSlot is the slot of the head state before the reorg.
 	OldSlot uint64
+	// NewHeadRoot is the root of the head block after the reorg.
+	NewHeadRoot [32]byte
+	// OldHeadRoot is the root of the head block before the reorg.
+	OldHeadRoot [32]byte
 }
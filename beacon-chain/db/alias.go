@@ -1,6 +1,9 @@
 package db
 
-import "github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+import (
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+)
 
 // ReadOnlyDatabase exposes Prysm's eth2 data backend for read access only, no information about
 // head info. For head info, use github.com/prysmaticlabs/prysm/blockchain.HeadFetcher.
@@ -22,3 +25,14 @@ type HeadAccessDatabase = iface.HeadAccessDatabase
 // key-value or relational database in practice. This is the full database interface which should
 // not be used often. Prefer a more restrictive interface in this package.
 type Database = iface.Database
+
+// DryRunMigrations runs the pending schema migrations of the database in the directory without
+// saving their changes, reporting whether they would succeed.
+func DryRunMigrations(dirPath string) error {
+	return kv.DryRunMigrations(dirPath)
+}
+
+// Restore replaces the database in the directory with the backup file.
+func Restore(backupPath string, dirPath string) error {
+	return kv.Restore(backupPath, dirPath)
+}
@@ -203,6 +203,26 @@ func (e Exporter) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte)
 	return e.db.SaveGenesisBlockRoot(ctx, blockRoot)
 }
 
+// AnchorBlock -- passthrough.
+func (e Exporter) AnchorBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	return e.db.AnchorBlock(ctx)
+}
+
+// SaveAnchorBlockRoot -- passthrough.
+func (e Exporter) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	return e.db.SaveAnchorBlockRoot(ctx, blockRoot)
+}
+
+// BackfillBlock -- passthrough.
+func (e Exporter) BackfillBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	return e.db.BackfillBlock(ctx)
+}
+
+// SaveBackfillBlockRoot -- passthrough.
+func (e Exporter) SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	return e.db.SaveBackfillBlockRoot(ctx, blockRoot)
+}
+
 // SaveState -- passthrough.
 func (e Exporter) SaveState(ctx context.Context, state *state.BeaconState, blockRoot [32]byte) error {
 	return e.db.SaveState(ctx, state, blockRoot)
@@ -348,6 +368,21 @@ func (e Exporter) HighestSlotStatesBelow(ctx context.Context, slot uint64) ([]*s
 	return e.db.HighestSlotStatesBelow(ctx, slot)
 }
 
+// StateDiff -- passthrough
+func (e Exporter) StateDiff(ctx context.Context, slot uint64) ([]byte, error) {
+	return e.db.StateDiff(ctx, slot)
+}
+
+// HighestStateDiffBelow -- passthrough
+func (e Exporter) HighestStateDiffBelow(ctx context.Context, slot uint64) (uint64, []byte, error) {
+	return e.db.HighestStateDiffBelow(ctx, slot)
+}
+
+// SaveStateDiff -- passthrough
+func (e Exporter) SaveStateDiff(ctx context.Context, slot uint64, diff []byte) error {
+	return e.db.SaveStateDiff(ctx, slot, diff)
+}
+
 // SaveLastArchivedIndex -- passthrough
 func (e Exporter) SaveLastArchivedIndex(ctx context.Context, index uint64) error {
 	return e.db.SaveLastArchivedIndex(ctx, index)
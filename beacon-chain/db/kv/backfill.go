@@ -0,0 +1,105 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/traceutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+var errMissingAnchorBlock = errors.New("no anchor block root in db")
+
+// BackfillBlock retrieves the oldest block saved by backfilling the blocks before the
+// checkpoint sync anchor. It returns nil if no blocks have been backfilled yet.
+func (kv *Store) BackfillBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.BackfillBlock")
+	defer span.End()
+	var block *ethpb.SignedBeaconBlock
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		root := bkt.Get(backfillBlockRootKey)
+		if root == nil {
+			return nil
+		}
+		enc := bkt.Get(root)
+		if enc == nil {
+			return nil
+		}
+		block = &ethpb.SignedBeaconBlock{}
+		return decode(enc, block)
+	})
+	return block, err
+}
+
+// SaveBackfillBlockRoot records the block root as the oldest backfilled block. The blocks
+// between the previous oldest block and this block, which must already be saved, are
+// added to the finalized block roots index, as they are ancestors of the finalized anchor.
+func (kv *Store) SaveBackfillBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBackfillBlockRoot")
+	defer span.End()
+
+	return kv.db.Update(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		bkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+
+		childRoot := blocks.Get(backfillBlockRootKey)
+		if childRoot == nil {
+			childRoot = blocks.Get(anchorBlockRootKey)
+		}
+		if childRoot == nil {
+			traceutil.AnnotateError(span, errMissingAnchorBlock)
+			return errMissingAnchorBlock
+		}
+		child, err := kv.Block(ctx, bytesutil.ToBytes32(childRoot))
+		if err != nil {
+			traceutil.AnnotateError(span, err)
+			return err
+		}
+		if child == nil || child.Block == nil {
+			err := fmt.Errorf("missing block in database: block root=%#x", childRoot)
+			traceutil.AnnotateError(span, err)
+			return err
+		}
+
+		// Walk down the ancestry chain from the previous oldest block to the new oldest block.
+		root := child.Block.ParentRoot
+		for {
+			signedBlock, err := kv.Block(ctx, bytesutil.ToBytes32(root))
+			if err != nil {
+				traceutil.AnnotateError(span, err)
+				return err
+			}
+			if signedBlock == nil || signedBlock.Block == nil {
+				err := fmt.Errorf("missing block in database: block root=%#x", root)
+				traceutil.AnnotateError(span, err)
+				return err
+			}
+			enc, err := encode(&dbpb.FinalizedBlockRootContainer{
+				ParentRoot: signedBlock.Block.ParentRoot,
+				ChildRoot:  childRoot,
+			})
+			if err != nil {
+				traceutil.AnnotateError(span, err)
+				return err
+			}
+			if err := bkt.Put(root, enc); err != nil {
+				traceutil.AnnotateError(span, err)
+				return err
+			}
+			if bytes.Equal(root, blockRoot[:]) {
+				break
+			}
+			childRoot = root
+			root = signedBlock.Block.ParentRoot
+		}
+
+		return blocks.Put(backfillBlockRootKey, blockRoot[:])
+	})
+}
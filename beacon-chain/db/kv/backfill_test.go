@@ -0,0 +1,89 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStore_SaveBackfillBlockRoot(t *testing.T) {
+	slotsPerEpoch := int(params.BeaconConfig().SlotsPerEpoch)
+	db := setupDB(t)
+	ctx := context.Background()
+
+	blks := makeBlocks(t, 0, slotsPerEpoch*3, genesisBlockRoot)
+	anchor := blks[slotsPerEpoch*2-1]
+	anchorRoot, err := stateutil.BlockRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), anchorRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 2, Root: anchorRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	backfilled, err := db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backfilled != nil {
+		t.Errorf("Expected no backfilled block, received %v", backfilled)
+	}
+
+	// Backfill one epoch of blocks before the anchor.
+	batch := blks[slotsPerEpoch-1 : slotsPerEpoch*2-1]
+	if err := db.SaveBlocks(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	oldestRoot, err := stateutil.BlockRoot(batch[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBackfillBlockRoot(ctx, oldestRoot); err != nil {
+		t.Fatal(err)
+	}
+	backfilled, err = db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(backfilled, batch[0]) {
+		t.Errorf("Wanted backfilled block %v, received %v", batch[0], backfilled)
+	}
+	for i, blk := range batch {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !db.IsFinalizedBlock(ctx, root) {
+			t.Errorf("Backfilled block at index %d was not considered finalized in the index", i)
+		}
+	}
+
+	// The next batch has to be saved before it can be recorded as backfilled.
+	missingRoot, err := stateutil.BlockRoot(blks[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBackfillBlockRoot(ctx, missingRoot); err == nil {
+		t.Error("Expected error for missing backfilled blocks")
+	}
+	backfilled, err = db.BackfillBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(backfilled, batch[0]) {
+		t.Error("Oldest backfilled block changed after failed update")
+	}
+}
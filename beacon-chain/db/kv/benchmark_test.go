@@ -0,0 +1,124 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	fastssz "github.com/ferranbt/fastssz"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+const benchmarkValidators = 4096
+
+// sszSize returns the size of the SSZ encoding of the message.
+func sszSize(b *testing.B, msg fastssz.Marshaler) int {
+	raw, err := msg.MarshalSSZ()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return len(raw)
+}
+
+// reportSize reports the bytes the bucket uses in the database next to the total size of the
+// raw SSZ encodings of the values saved to it, so that storing the values snappy compressed can
+// be compared with storing them uncompressed. The bucket bytes include the keys and the page
+// overhead of bolt.
+func reportSize(b *testing.B, db *Store, bucket []byte, rawBytes int) {
+	buckets, err := db.Buckets()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bkt := range buckets {
+		if bkt.Name == string(bucket) {
+			b.ReportMetric(float64(rawBytes), "raw-ssz-bytes")
+			b.ReportMetric(float64(bkt.Bytes), "bucket-bytes")
+			b.ReportMetric(float64(bkt.Bytes)/float64(rawBytes), "bucket/raw")
+			return
+		}
+	}
+	b.Fatalf("No bucket %s in the database", bucket)
+}
+
+// saveBenchmarkState saves a state with benchmarkValidators validators at the slot, along with
+// a block at the slot, and returns the state and the block root.
+func saveBenchmarkState(b *testing.B, db *Store, slot uint64) (*state.BeaconState, [32]byte) {
+	ctx := context.Background()
+	st, _ := testutil.DeterministicGenesisState(b, benchmarkValidators)
+	if err := st.SetSlot(slot); err != nil {
+		b.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot}}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		b.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := db.SaveState(ctx, st, root); err != nil {
+		b.Fatal(err)
+	}
+	return st, root
+}
+
+func BenchmarkStore_State(b *testing.B) {
+	db := setupDB(b)
+	ctx := context.Background()
+	st, root := saveBenchmarkState(b, db, 100)
+	reportSize(b, db, stateBucket, sszSize(b, st.InnerStateUnsafe()))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.State(ctx, root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStore_Block(b *testing.B) {
+	db := setupDB(b)
+	ctx := context.Background()
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 100
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		b.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		b.Fatal(err)
+	}
+	reportSize(b, db, blocksBucket, sszSize(b, blk))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Read the block from the database rather than from the block cache.
+		db.blockCache.Del(string(root[:]))
+		if _, err := db.Block(ctx, root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStore_HighestSlotStatesBelow(b *testing.B) {
+	db := setupDB(b)
+	ctx := context.Background()
+	rawBytes := 0
+	for _, slot := range []uint64{10, 100, 1000} {
+		st, _ := saveBenchmarkState(b, db, slot)
+		rawBytes += sszSize(b, st.InnerStateUnsafe())
+	}
+	reportSize(b, db, stateBucket, rawBytes)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := db.HighestSlotStatesBelow(ctx, 1001); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
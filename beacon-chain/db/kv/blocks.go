@@ -309,6 +309,38 @@ func (kv *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) e
 	})
 }
 
+// AnchorBlock retrieves the block the node was started from when it was synced from a
+// trusted finalized checkpoint instead of genesis. It returns nil if there is none.
+func (kv *Store) AnchorBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.AnchorBlock")
+	defer span.End()
+	var block *ethpb.SignedBeaconBlock
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		root := bkt.Get(anchorBlockRootKey)
+		if root == nil {
+			return nil
+		}
+		enc := bkt.Get(root)
+		if enc == nil {
+			return nil
+		}
+		block = &ethpb.SignedBeaconBlock{}
+		return decode(enc, block)
+	})
+	return block, err
+}
+
+// SaveAnchorBlockRoot to the db.
+func (kv *Store) SaveAnchorBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveAnchorBlockRoot")
+	defer span.End()
+	return kv.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(blocksBucket)
+		return bucket.Put(anchorBlockRootKey, blockRoot[:])
+	})
+}
+
 // HighestSlotBlocks returns the blocks with the highest slot from the db.
 func (kv *Store) HighestSlotBlocks(ctx context.Context) ([]*ethpb.SignedBeaconBlock, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HighestSlotBlocks")
@@ -170,6 +170,39 @@ func TestStore_GenesisBlock(t *testing.T) {
 	}
 }
 
+func TestStore_AnchorBlock(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	retrievedBlock, err := db.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retrievedBlock != nil {
+		t.Errorf("Expected nil anchor block, received %v", retrievedBlock)
+	}
+
+	anchorBlock := testutil.NewBeaconBlock()
+	anchorBlock.Block.Slot = 64
+	anchorBlock.Block.ParentRoot = bytesutil.PadTo([]byte{1, 2, 3}, 32)
+	blockRoot, err := stateutil.BlockRoot(anchorBlock.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, anchorBlock); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveAnchorBlockRoot(ctx, blockRoot); err != nil {
+		t.Fatal(err)
+	}
+	retrievedBlock, err = db.AnchorBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(anchorBlock, retrievedBlock) {
+		t.Errorf("Wanted %v, received %v", anchorBlock, retrievedBlock)
+	}
+}
+
 func TestStore_BlocksCRUD_NoCache(t *testing.T) {
 	db := setupDB(t)
 	ctx := context.Background()
@@ -0,0 +1,57 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// DanglingIndex is an entry of a block index bucket referring to a block missing from the database.
+type DanglingIndex struct {
+	Bucket    string
+	Key       []byte
+	BlockRoot [32]byte
+}
+
+// DanglingBlockIndices returns the entries of the block slot and finalized block roots indices
+// referring to blocks which are not in the database.
+func (kv *Store) DanglingBlockIndices(ctx context.Context) ([]*DanglingIndex, error) {
+	_, span := trace.StartSpan(ctx, "BeaconDB.DanglingBlockIndices")
+	defer span.End()
+
+	var dangling []*DanglingIndex
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		blocks := tx.Bucket(blocksBucket)
+		if err := tx.Bucket(blockSlotIndicesBucket).ForEach(func(k []byte, v []byte) error {
+			for i := 0; i+32 <= len(v); i += 32 {
+				if blocks.Get(v[i:i+32]) == nil {
+					dangling = append(dangling, &DanglingIndex{
+						Bucket:    string(blockSlotIndicesBucket),
+						Key:       append([]byte{}, k...),
+						BlockRoot: bytesutil.ToBytes32(v[i : i+32]),
+					})
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.Bucket(finalizedBlockRootsIndexBucket).ForEach(func(k []byte, _ []byte) error {
+			// Skip the previous finalized checkpoint stored along the roots.
+			if len(k) != 32 {
+				return nil
+			}
+			if blocks.Get(k) == nil {
+				dangling = append(dangling, &DanglingIndex{
+					Bucket:    string(finalizedBlockRootsIndexBucket),
+					Key:       append([]byte{}, k...),
+					BlockRoot: bytesutil.ToBytes32(k),
+				})
+			}
+			return nil
+		})
+	})
+	return dangling, err
+}
@@ -0,0 +1,60 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_DanglingBlockIndices(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	kept := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 1}}
+	removed := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 2}}
+	for _, blk := range []*eth.SignedBeaconBlock{kept, removed} {
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dangling, err := db.DanglingBlockIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 0 {
+		t.Fatalf("Wanted no dangling indices, received %d", len(dangling))
+	}
+
+	removedRoot, err := stateutil.BlockRoot(removed.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Remove the block while keeping its indices, and index it as finalized.
+	if err := db.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(finalizedBlockRootsIndexBucket).Put(removedRoot[:], []byte{}); err != nil {
+			return err
+		}
+		return tx.Bucket(blocksBucket).Delete(removedRoot[:])
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dangling, err = db.DanglingBlockIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dangling) != 2 {
+		t.Fatalf("Wanted 2 dangling indices, received %d", len(dangling))
+	}
+	for _, index := range dangling {
+		if index.BlockRoot != removedRoot {
+			t.Errorf("Wanted dangling index of block %#x, received %#x", removedRoot, index.BlockRoot)
+		}
+	}
+	if dangling[0].Bucket != string(blockSlotIndicesBucket) || dangling[1].Bucket != string(finalizedBlockRootsIndexBucket) {
+		t.Errorf("Unexpected buckets of dangling indices: %s, %s", dangling[0].Bucket, dangling[1].Bucket)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 )
 
+// decode decompresses the snappy compressed value and unmarshals it into the destination, from SSZ
+// or protobuf depending on the type of the destination.
 func decode(data []byte, dst proto.Message) error {
 	data, err := snappy.Decode(nil, data)
 	if err != nil {
@@ -22,6 +24,9 @@ func decode(data []byte, dst proto.Message) error {
 	return proto.Unmarshal(data, dst)
 }
 
+// encode marshals the message to SSZ or protobuf depending on its type and compresses it with
+// snappy. All values of the database are stored compressed, see the benchmarks for the effect on
+// their size and read latency.
 func encode(msg proto.Message) ([]byte, error) {
 	if msg == nil || reflect.ValueOf(msg).IsNil() {
 		return nil, errors.New("cannot encode nil message")
@@ -14,6 +14,7 @@ import (
 	prombolt "github.com/prysmaticlabs/prombbolt"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	log "github.com/sirupsen/logrus"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -45,8 +46,9 @@ type Store struct {
 }
 
 // NewKVStore initializes a new boltDB key-value store at the directory
-// path specified, creates the kv-buckets based on the schema, and stores
-// an open connection db object as a property of the Store struct.
+// path specified, migrates the database to the latest schema version, and stores
+// an open connection db object as a property of the Store struct. Databases with
+// a schema newer than supported are refused with ErrSchemaTooNew.
 func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*Store, error) {
 	if err := os.MkdirAll(dirPath, 0700); err != nil {
 		return nil, err
@@ -60,6 +62,69 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 		return nil, err
 	}
 	boltDB.AllocSize = boltAllocSize
+	kv, err := newStore(boltDB, dirPath, stateSummaryCache)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateSchema(kv.db, false /* dryRun */); err != nil {
+		if closeErr := kv.db.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close database")
+		}
+		return nil, errors.Wrap(err, "could not migrate database schema")
+	}
+
+	err = prometheus.Register(createBoltCollector(kv.db))
+
+	return kv, err
+}
+
+// NewReadOnlyKVStore opens the boltDB key-value store at the directory path specified without
+// write access, so that it can be inspected without modifying it. The schema is not migrated,
+// databases with a schema newer than supported are refused with ErrSchemaTooNew.
+func NewReadOnlyKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*Store, error) {
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); err != nil {
+		return nil, err
+	}
+	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	kv, err := newStore(boltDB, dirPath, stateSummaryCache)
+	if err != nil {
+		closeReadOnly(boltDB)
+		return nil, err
+	}
+	version, err := kv.SchemaVersion()
+	if err != nil {
+		closeReadOnly(boltDB)
+		return nil, err
+	}
+	if version > LatestSchemaVersion() {
+		closeReadOnly(boltDB)
+		return nil, errors.Wrapf(ErrSchemaTooNew, "database has schema version %d, latest supported version is %d", version, LatestSchemaVersion())
+	}
+	if version < LatestSchemaVersion() {
+		log.WithFields(log.Fields{
+			"version": version,
+			"latest":  LatestSchemaVersion(),
+		}).Warn("Database has pending schema migrations, some of its content may not be read correctly")
+	}
+	return kv, nil
+}
+
+// closeReadOnly closes a database which failed to open read-only.
+func closeReadOnly(boltDB *bolt.DB) {
+	if err := boltDB.Close(); err != nil {
+		log.WithError(err).Error("Could not close database")
+	}
+}
+
+func newStore(boltDB *bolt.DB, dirPath string, stateSummaryCache *cache.StateSummaryCache) (*Store, error) {
 	blockCache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1000,           // number of keys to track frequency of (1000).
 		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
@@ -78,52 +143,37 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 		return nil, err
 	}
 
-	kv := &Store{
+	return &Store{
 		db:                  boltDB,
 		databasePath:        dirPath,
 		blockCache:          blockCache,
 		validatorIndexCache: validatorCache,
 		stateSummaryCache:   stateSummaryCache,
-	}
-
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
-		return createBuckets(
-			tx,
-			attestationsBucket,
-			blocksBucket,
-			stateBucket,
-			proposerSlashingsBucket,
-			attesterSlashingsBucket,
-			voluntaryExitsBucket,
-			chainMetadataBucket,
-			checkpointBucket,
-			archivedValidatorSetChangesBucket,
-			archivedCommitteeInfoBucket,
-			archivedBalancesBucket,
-			archivedValidatorParticipationBucket,
-			powchainBucket,
-			stateSummaryBucket,
-			archivedIndexRootBucket,
-			slotsHasObjectBucket,
-			// Indices buckets.
-			attestationHeadBlockRootBucket,
-			attestationSourceRootIndicesBucket,
-			attestationSourceEpochIndicesBucket,
-			attestationTargetRootIndicesBucket,
-			attestationTargetEpochIndicesBucket,
-			blockSlotIndicesBucket,
-			blockParentRootIndicesBucket,
-			finalizedBlockRootsIndexBucket,
-			// New State Management service bucket.
-			newStateServiceCompatibleBucket,
-		)
-	}); err != nil {
-		return nil, err
-	}
+	}, nil
+}
 
-	err = prometheus.Register(createBoltCollector(kv.db))
+// BucketStats describes the content of a bucket of the database.
+type BucketStats struct {
+	Name  string
+	Keys  int
+	Bytes int // Bytes used by the keys and values of the bucket.
+}
 
-	return kv, err
+// Buckets returns the statistics of each bucket of the database, ordered by name.
+func (kv *Store) Buckets() ([]*BucketStats, error) {
+	var stats []*BucketStats
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			s := b.Stats()
+			stats = append(stats, &BucketStats{
+				Name:  string(name),
+				Keys:  s.KeyN,
+				Bytes: s.BranchInuse + s.LeafInuse,
+			})
+			return nil
+		})
+	})
+	return stats, err
 }
 
 // ClearDB removes the previously stored database in the data directory.
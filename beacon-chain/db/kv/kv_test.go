@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
@@ -8,7 +9,9 @@ import (
 	"path"
 	"testing"
 
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 )
 
@@ -36,3 +39,57 @@ func setupDB(t testing.TB) *Store {
 	})
 	return db
 }
+
+func TestNewReadOnlyKVStore(t *testing.T) {
+	p := newMigrationTestDB(t)
+	ctx := context.Background()
+	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 10}}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly, err := NewReadOnlyKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := readOnly.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if !readOnly.HasBlock(ctx, root) {
+		t.Error("Expected the block to be read from the read-only database")
+	}
+	if err := readOnly.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 11}}); err == nil {
+		t.Error("Expected saving to a read-only database to fail")
+	}
+	buckets, err := readOnly.Buckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range buckets {
+		if b.Name == string(blocksBucket) && b.Keys != 1 {
+			t.Errorf("Wanted 1 key in the blocks bucket, received %d", b.Keys)
+		}
+	}
+	if len(buckets) == 0 {
+		t.Error("Expected the buckets of the database")
+	}
+}
+
+func TestNewReadOnlyKVStore_MissingDatabase(t *testing.T) {
+	if _, err := NewReadOnlyKVStore(path.Join(testutil.TempDir(), "missing"), cache.NewStateSummaryCache()); err == nil {
+		t.Error("Expected opening a missing database to fail")
+	}
+}
@@ -0,0 +1,211 @@
+package kv
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrSchemaTooNew is returned when opening a database migrated by a newer version of the beacon
+// node than the running one.
+var ErrSchemaTooNew = errors.New("database schema is newer than supported by this beacon node")
+
+// ErrSchemaNotMigrated is returned when writing to a bucket of a schema version the database was
+// not migrated to, such as a database opened read-only.
+var ErrSchemaNotMigrated = errors.New("database schema is not migrated to the latest version")
+
+var errDryRun = errors.New("dry run")
+
+// migration changes the schema of the database to the given version.
+type migration struct {
+	version     uint64
+	description string
+	migrate     func(tx *bolt.Tx) error
+}
+
+// migrations of the database schema, ordered by version. Versions start at 1 and are consecutive.
+// A released migration must never be changed or removed: format changes are made by appending a
+// migration with the next version.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "Create the buckets of the initial schema",
+		migrate:     createInitialBuckets,
+	},
+	{
+		version:     2,
+		description: "Create the bucket of the cold state diffs",
+		migrate: func(tx *bolt.Tx) error {
+			return createBuckets(tx, stateDiffBucket)
+		},
+	},
+}
+
+// LatestSchemaVersion is the database schema version understood by this beacon node.
+func LatestSchemaVersion() uint64 {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns the current version of the database schema.
+func (kv *Store) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+// DryRunMigrations runs the pending migrations of the database in the directory and reports
+// them, without changing the database: the migrations are run in a single transaction which is
+// rolled back.
+func DryRunMigrations(dirPath string) error {
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); os.IsNotExist(err) {
+		log.WithField("path", datafile).Info("No database found, a new one would be created with the latest schema")
+		return nil
+	}
+	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return err
+	}
+	if err := migrateSchema(boltDB, true /* dryRun */); err != nil {
+		if closeErr := boltDB.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close database")
+		}
+		return err
+	}
+	return boltDB.Close()
+}
+
+// migrateSchema runs the migrations from the schema version of the database to the latest one. Each
+// migration runs in a single transaction with the update of the schema version, so that an
+// interrupted migration leaves the database at the previous version. In a dry run, all pending
+// migrations run in one transaction which is rolled back.
+func migrateSchema(db *bolt.DB, dryRun bool) error {
+	var version uint64
+	if err := db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return err
+	}
+	latest := LatestSchemaVersion()
+	if version > latest {
+		return errors.Wrapf(ErrSchemaTooNew, "database has schema version %d, latest supported version is %d", version, latest)
+	}
+	var pending []migration
+	for _, m := range migrations {
+		if m.version > version {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		log.WithField("version", version).Debug("Database schema is up to date")
+		return nil
+	}
+
+	if dryRun {
+		err := db.Update(func(tx *bolt.Tx) error {
+			for _, m := range pending {
+				if err := applyMigration(tx, m); err != nil {
+					return errors.Wrapf(err, "migration to version %d failed", m.version)
+				}
+				log.WithFields(log.Fields{
+					"version":     m.version,
+					"description": m.description,
+				}).Info("Database migration would be applied")
+			}
+			return errDryRun
+		})
+		if err != errDryRun {
+			return err
+		}
+		log.WithFields(log.Fields{
+			"from": version,
+			"to":   latest,
+		}).Info("Dry run of database migrations succeeded, no changes were made")
+		return nil
+	}
+
+	for _, m := range pending {
+		start := time.Now()
+		if err := db.Update(func(tx *bolt.Tx) error {
+			return applyMigration(tx, m)
+		}); err != nil {
+			return errors.Wrapf(err, "migration to version %d failed", m.version)
+		}
+		log.WithFields(log.Fields{
+			"version":     m.version,
+			"description": m.description,
+			"duration":    time.Since(start),
+		}).Info("Applied database migration")
+	}
+	return nil
+}
+
+func applyMigration(tx *bolt.Tx, m migration) error {
+	if err := m.migrate(tx); err != nil {
+		return err
+	}
+	bkt, err := tx.CreateBucketIfNotExists(chainMetadataBucket)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(schemaVersionKey, bytesutil.Bytes8(m.version))
+}
+
+// schemaVersion returns the schema version stored in the database, 0 for a new database or one
+// created before versioning.
+func schemaVersion(tx *bolt.Tx) uint64 {
+	bkt := tx.Bucket(chainMetadataBucket)
+	if bkt == nil {
+		return 0
+	}
+	enc := bkt.Get(schemaVersionKey)
+	if enc == nil {
+		return 0
+	}
+	return bytesutil.FromBytes8(enc)
+}
+
+func createInitialBuckets(tx *bolt.Tx) error {
+	return createBuckets(
+		tx,
+		attestationsBucket,
+		blocksBucket,
+		stateBucket,
+		proposerSlashingsBucket,
+		attesterSlashingsBucket,
+		voluntaryExitsBucket,
+		chainMetadataBucket,
+		checkpointBucket,
+		archivedValidatorSetChangesBucket,
+		archivedCommitteeInfoBucket,
+		archivedBalancesBucket,
+		archivedValidatorParticipationBucket,
+		powchainBucket,
+		stateSummaryBucket,
+		archivedIndexRootBucket,
+		slotsHasObjectBucket,
+		// Indices buckets.
+		attestationHeadBlockRootBucket,
+		attestationSourceRootIndicesBucket,
+		attestationSourceEpochIndicesBucket,
+		attestationTargetRootIndicesBucket,
+		attestationTargetEpochIndicesBucket,
+		blockSlotIndicesBucket,
+		blockParentRootIndicesBucket,
+		finalizedBlockRootsIndexBucket,
+		// New State Management service bucket.
+		newStateServiceCompatibleBucket,
+	)
+}
@@ -0,0 +1,179 @@
+package kv
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	testMigrationBucket = []byte("test-migration")
+	testMigrationKey    = []byte("test-key")
+)
+
+// withMigrations replaces the registered migrations with the given ones for the test.
+func withMigrations(t *testing.T, ms ...migration) {
+	registered := migrations
+	migrations = append(append([]migration{}, registered...), ms...)
+	t.Cleanup(func() {
+		migrations = registered
+	})
+}
+
+func putTestValue(version uint64) func(tx *bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists(testMigrationBucket)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(testMigrationKey, bytesutil.Bytes8(version))
+	}
+}
+
+// newMigrationTestDB creates a database which is closed and returns its directory.
+func newMigrationTestDB(t *testing.T) string {
+	p, err := ioutil.TempDir("", "migration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(p); err != nil {
+			t.Fatalf("Failed to remove directory: %v", err)
+		}
+	})
+	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func testValue(t *testing.T, db *Store) []byte {
+	var value []byte
+	if err := db.db.View(func(tx *bolt.Tx) error {
+		if bkt := tx.Bucket(testMigrationBucket); bkt != nil {
+			if v := bkt.Get(testMigrationKey); v != nil {
+				value = append([]byte{}, v...)
+			}
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestStore_NewDatabaseHasLatestSchema(t *testing.T) {
+	db := setupDB(t)
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("Wanted schema version %d, received %d", LatestSchemaVersion(), version)
+	}
+}
+
+func TestStore_RunsPendingMigrations(t *testing.T) {
+	p := newMigrationTestDB(t)
+
+	latest := LatestSchemaVersion()
+	withMigrations(t,
+		migration{version: latest + 1, description: "Put a value", migrate: putTestValue(latest + 1)},
+		migration{version: latest + 2, description: "Fail", migrate: func(tx *bolt.Tx) error {
+			if err := putTestValue(latest + 2)(tx); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		}},
+	)
+	if _, err := NewKVStore(p, cache.NewStateSummaryCache()); err == nil {
+		t.Fatal("Expected the failing migration to prevent opening the database")
+	}
+
+	// The failed migration was rolled back, the previous one was kept.
+	migrations = migrations[:len(migrations)-1]
+	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest+1 {
+		t.Errorf("Wanted schema version %d, received %d", latest+1, version)
+	}
+	if value := testValue(t, db); bytesutil.FromBytes8(value) != latest+1 {
+		t.Errorf("Wanted the value of migration %d, received %v", latest+1, value)
+	}
+}
+
+func TestStore_RefusesNewerSchema(t *testing.T) {
+	p := newMigrationTestDB(t)
+	latest := LatestSchemaVersion()
+	withMigrations(t, migration{version: latest + 1, description: "Put a value", migrate: putTestValue(latest + 1)})
+	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	migrations = migrations[:len(migrations)-1]
+
+	_, err = NewKVStore(p, cache.NewStateSummaryCache())
+	if err == nil {
+		t.Fatal("Expected a database with a newer schema to be refused")
+	}
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Wanted %v, received %v", ErrSchemaTooNew, err)
+	}
+	if err := DryRunMigrations(p); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Wanted %v from the dry run, received %v", ErrSchemaTooNew, err)
+	}
+}
+
+func TestDryRunMigrations_LeavesDatabaseUnchanged(t *testing.T) {
+	p := newMigrationTestDB(t)
+
+	latest := LatestSchemaVersion()
+	withMigrations(t, migration{version: latest + 1, description: "Put a value", migrate: putTestValue(latest + 1)})
+	if err := DryRunMigrations(p); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations = migrations[:len(migrations)-1]
+	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest {
+		t.Errorf("Wanted schema version %d, received %d", latest, version)
+	}
+	if value := testValue(t, db); value != nil {
+		t.Errorf("Wanted no value saved by the dry run, received %v", value)
+	}
+}
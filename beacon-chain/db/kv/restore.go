@@ -0,0 +1,109 @@
+package kv
+
+import (
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Restore replaces the database in the directory with the backup file written by Backup. The
+// backup is checked to be a database with a supported schema, then copied next to the database
+// and renamed over it, so that an interrupted restore leaves the previous database in place.
+func Restore(backupPath string, dirPath string) error {
+	if err := checkBackup(backupPath); err != nil {
+		return errors.Wrapf(err, "could not use backup %s", backupPath)
+	}
+	if err := os.MkdirAll(dirPath, 0700); err != nil {
+		return err
+	}
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); err == nil {
+		// Holding the lock of the database ensures no beacon node is running on it.
+		current, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second})
+		if err != nil {
+			if err == bolt.ErrTimeout {
+				return errors.New("cannot obtain database lock, database may be in use by another process")
+			}
+			return err
+		}
+		if err := current.Close(); err != nil {
+			return err
+		}
+		log.WithField("path", datafile).Warn("Replacing the existing database with the backup")
+	}
+
+	tmpfile := datafile + ".restore"
+	if err := copyFile(backupPath, tmpfile); err != nil {
+		if rmErr := os.Remove(tmpfile); rmErr != nil && !os.IsNotExist(rmErr) {
+			log.WithError(rmErr).Error("Could not remove partially restored database")
+		}
+		return errors.Wrap(err, "could not copy backup")
+	}
+	if err := os.Rename(tmpfile, datafile); err != nil {
+		return errors.Wrap(err, "could not replace database")
+	}
+	log.WithFields(log.Fields{
+		"backup": backupPath,
+		"path":   datafile,
+	}).Info("Restored database from backup")
+	return nil
+}
+
+// checkBackup verifies the backup is a bolt database with a schema supported by this beacon node.
+func checkBackup(backupPath string) error {
+	if _, err := os.Stat(backupPath); err != nil {
+		return err
+	}
+	backup, err := bolt.Open(backupPath, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return errors.Wrap(err, "could not open backup")
+	}
+	defer func() {
+		if err := backup.Close(); err != nil {
+			log.WithError(err).Error("Could not close backup")
+		}
+	}()
+	return backup.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(blocksBucket) == nil {
+			return errors.New("backup has no blocks bucket")
+		}
+		if version := schemaVersion(tx); version > LatestSchemaVersion() {
+			return errors.Wrapf(ErrSchemaTooNew, "backup has schema version %d, latest supported version is %d", version, LatestSchemaVersion())
+		}
+		return nil
+	})
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := in.Close(); err != nil {
+			log.WithError(err).Error("Could not close file")
+		}
+	}()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		if closeErr := out.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close file")
+		}
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		if closeErr := out.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close file")
+		}
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,84 @@
+package kv
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+)
+
+func TestRestore_ReplacesDatabaseWithBackup(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	head := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 5000}}
+	if err := db.SaveBlock(ctx, head); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backupPath := path.Join(db.databasePath, backupsDirectoryName, "prysm_beacondb_at_slot_0005000.backup")
+
+	p := newMigrationTestDB(t)
+	if err := Restore(backupPath, p); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := NewKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restored.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	restoredHead, err := restored.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restoredHead == nil || restoredHead.Block.Slot != head.Block.Slot {
+		t.Errorf("Wanted head block at slot %d, received %v", head.Block.Slot, restoredHead)
+	}
+}
+
+func TestRestore_RefusesInvalidBackup(t *testing.T) {
+	p := newMigrationTestDB(t)
+	backupPath := path.Join(p, "invalid.backup")
+	if err := ioutil.WriteFile(backupPath, []byte("not a database"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	before, err := ioutil.ReadFile(path.Join(p, databaseFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Restore(backupPath, p); err == nil {
+		t.Fatal("Expected an invalid backup to be refused")
+	}
+	if err := Restore(path.Join(p, "missing.backup"), p); err == nil {
+		t.Fatal("Expected a missing backup to be refused")
+	}
+	after, err := ioutil.ReadFile(path.Join(p, databaseFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) {
+		t.Error("Expected the database to be left unchanged")
+	}
+	if _, err := os.Stat(path.Join(p, databaseFileName+".restore")); !os.IsNotExist(err) {
+		t.Error("Expected no partially restored database")
+	}
+}
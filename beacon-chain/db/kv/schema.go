@@ -23,6 +23,7 @@ var (
 	powchainBucket                       = []byte("powchain")
 	archivedIndexRootBucket              = []byte("archived-index-root")
 	slotsHasObjectBucket                 = []byte("slots-has-objects")
+	stateDiffBucket                      = []byte("state-diffs")
 
 	// Key indices buckets.
 	blockParentRootIndicesBucket        = []byte("block-parent-root-indices")
@@ -37,6 +38,8 @@ var (
 	// Specific item keys.
 	headBlockRootKey          = []byte("head-root")
 	genesisBlockRootKey       = []byte("genesis-root")
+	anchorBlockRootKey        = []byte("anchor-root")
+	backfillBlockRootKey      = []byte("backfill-root")
 	depositContractAddressKey = []byte("deposit-contract")
 	justifiedCheckpointKey    = []byte("justified-checkpoint")
 	finalizedCheckpointKey    = []byte("finalized-checkpoint")
@@ -44,6 +47,7 @@ var (
 	lastArchivedIndexKey      = []byte("last-archived")
 	savedBlockSlotsKey        = []byte("saved-block-slots")
 	savedStateSlotsKey        = []byte("saved-state-slots")
+	schemaVersionKey          = []byte("schema-version")
 
 	// New state management service compatibility bucket.
 	newStateServiceCompatibleBucket = []byte("new-state-compatible")
@@ -0,0 +1,89 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// SaveStateDiff saves the encoded diff of the cold state at the slot. Diffs are keyed by slot in
+// big endian order, so that the closest diff below a slot can be found with a cursor. The bucket of
+// the diffs only exists from schema version 2.
+func (kv *Store) SaveStateDiff(ctx context.Context, slot uint64, diff []byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveStateDiff")
+	defer span.End()
+
+	return kv.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(stateDiffBucket)
+		if bkt == nil {
+			return errors.Wrap(ErrSchemaNotMigrated, "no state diff bucket")
+		}
+		return bkt.Put(stateDiffKey(slot), snappy.Encode(nil, diff))
+	})
+}
+
+// StateDiff returns the encoded diff of the cold state at the slot, nil if there is none or the
+// database was not migrated to hold diffs.
+func (kv *Store) StateDiff(ctx context.Context, slot uint64) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.StateDiff")
+	defer span.End()
+
+	var diff []byte
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(stateDiffBucket)
+		if bkt == nil {
+			return nil
+		}
+		enc := bkt.Get(stateDiffKey(slot))
+		if enc == nil {
+			return nil
+		}
+		var err error
+		diff, err = snappy.Decode(nil, enc)
+		return err
+	})
+	return diff, err
+}
+
+// HighestStateDiffBelow returns the slot and the encoded diff of the cold state with the highest
+// slot below the input slot. The diff is nil if there is none or the database was not migrated to
+// hold diffs.
+func (kv *Store) HighestStateDiffBelow(ctx context.Context, slot uint64) (uint64, []byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.HighestStateDiffBelow")
+	defer span.End()
+
+	var diffSlot uint64
+	var diff []byte
+	err := kv.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(stateDiffBucket)
+		if bkt == nil {
+			return nil
+		}
+		c := bkt.Cursor()
+		k, _ := c.Seek(stateDiffKey(slot))
+		var v []byte
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return nil
+		}
+		diffSlot = binary.BigEndian.Uint64(k)
+		var err error
+		diff, err = snappy.Decode(nil, v)
+		return err
+	})
+	return diffSlot, diff, err
+}
+
+func stateDiffKey(slot uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, slot)
+	return key
+}
@@ -0,0 +1,114 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStateDiff_CanSaveRetrieve(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	diff, err := db.StateDiff(ctx, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		t.Fatal("Should not have been saved")
+	}
+
+	if err := db.SaveStateDiff(ctx, 64, []byte{'A'}); err != nil {
+		t.Fatal(err)
+	}
+	diff, err = db.StateDiff(ctx, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(diff, []byte{'A'}) {
+		t.Errorf("Wanted diff %#x, received %#x", []byte{'A'}, diff)
+	}
+}
+
+func TestHighestStateDiffBelow(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	for _, slot := range []uint64{32, 64, 256} {
+		if err := db.SaveStateDiff(ctx, slot, bytesutil.Bytes8(slot)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		slot     uint64
+		wantSlot uint64
+		wantNil  bool
+	}{
+		{slot: 32, wantNil: true},
+		{slot: 33, wantSlot: 32},
+		{slot: 64, wantSlot: 32},
+		{slot: 100, wantSlot: 64},
+		{slot: 257, wantSlot: 256},
+		{slot: 10000, wantSlot: 256},
+	}
+	for _, tt := range tests {
+		slot, diff, err := db.HighestStateDiffBelow(ctx, tt.slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.wantNil {
+			if diff != nil {
+				t.Errorf("Wanted no diff below slot %d, received diff at slot %d", tt.slot, slot)
+			}
+			continue
+		}
+		if slot != tt.wantSlot || !bytes.Equal(diff, bytesutil.Bytes8(tt.wantSlot)) {
+			t.Errorf("Wanted diff at slot %d below slot %d, received slot %d", tt.wantSlot, tt.slot, slot)
+		}
+	}
+}
+
+func TestStateDiff_UnmigratedDatabase(t *testing.T) {
+	ctx := context.Background()
+	// Create a database at schema version 1, before the state diff bucket.
+	registered := migrations
+	migrations = migrations[:1]
+	p := newMigrationTestDB(t)
+	migrations = registered
+
+	readOnly, err := NewReadOnlyKVStore(p, cache.NewStateSummaryCache())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := readOnly.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	diff, err := readOnly.StateDiff(ctx, 64)
+	if err != nil || diff != nil {
+		t.Errorf("Wanted no diff from an unmigrated database, received %#x and error %v", diff, err)
+	}
+	_, diff, err = readOnly.HighestStateDiffBelow(ctx, 64)
+	if err != nil || diff != nil {
+		t.Errorf("Wanted no diff from an unmigrated database, received %#x and error %v", diff, err)
+	}
+}
+
+func TestSaveStateDiff_UnmigratedDatabase(t *testing.T) {
+	db := setupDB(t)
+	if err := db.db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(stateDiffBucket)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateDiff(context.Background(), 64, []byte{'A'}); errors.Cause(err) != ErrSchemaNotMigrated {
+		t.Errorf("Wanted error %v, received %v", ErrSchemaNotMigrated, err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package verify checks the consistency of a beacon node database, to find out whether a database
+// or a restored backup is usable before starting a beacon node on it.
+package verify
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// Report of the verification of a database. Problems lists the inconsistencies found, the database
+// is usable when there are none.
+type Report struct {
+	HeadSlot        uint64
+	FinalizedEpoch  uint64
+	JustifiedEpoch  uint64
+	ChainLength     int // Blocks from the head back to the finalized block.
+	ArchivedPoints  int
+	DanglingIndices []*kv.DanglingIndex
+	Problems        []string
+}
+
+func (r *Report) problem(format string, args ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// Database checks the head block and checkpoints, that the blocks from the head back to the
+// finalized block all have a parent and a state summary, that the states of the archived points
+// load through state generation, and that the block indices refer to existing blocks. An error is
+// returned when the database could not be read, inconsistencies are listed in the report.
+func Database(ctx context.Context, db *kv.Store) (*Report, error) {
+	r := &Report{}
+
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get finalized checkpoint")
+	}
+	justified, err := db.JustifiedCheckpoint(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get justified checkpoint")
+	}
+	r.FinalizedEpoch = finalized.Epoch
+	r.JustifiedEpoch = justified.Epoch
+	if finalized.Epoch > justified.Epoch {
+		r.problem("finalized epoch %d is after justified epoch %d", finalized.Epoch, justified.Epoch)
+	}
+	finalizedBlock := checkCheckpoint(ctx, db, r, "finalized", finalized)
+	checkCheckpoint(ctx, db, r, "justified", justified)
+
+	head, err := db.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	if head == nil || head.Block == nil {
+		r.problem("no head block")
+	} else {
+		r.HeadSlot = head.Block.Slot
+		if finalizedBlock != nil {
+			if err := checkChain(ctx, db, r, head.Block, finalizedBlock.Block); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := checkArchivedPoints(ctx, db, r); err != nil {
+		return nil, err
+	}
+
+	r.DanglingIndices, err = db.DanglingBlockIndices(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not check block indices")
+	}
+	for _, index := range r.DanglingIndices {
+		r.problem("index %s at key %#x refers to missing block %#x", index.Bucket, index.Key, index.BlockRoot)
+	}
+	return r, nil
+}
+
+// checkCheckpoint checks the block of the checkpoint is in the database, in or before the epoch of
+// the checkpoint, and that its state can be loaded. The checkpoint block is returned if found.
+func checkCheckpoint(ctx context.Context, db *kv.Store, r *Report, name string, cp *ethpb.Checkpoint) *ethpb.SignedBeaconBlock {
+	root := bytesutil.ToBytes32(cp.Root)
+	// The checkpoints of a new chain refer to genesis with a zero root.
+	if cp.Epoch == 0 && root == params.BeaconConfig().ZeroHash {
+		genesis, err := db.GenesisBlock(ctx)
+		if err != nil || genesis == nil || genesis.Block == nil {
+			r.problem("%s checkpoint refers to genesis, but there is no genesis block", name)
+			return nil
+		}
+		return genesis
+	}
+	blk, err := db.Block(ctx, root)
+	if err != nil || blk == nil || blk.Block == nil {
+		r.problem("%s checkpoint block %#x is missing", name, root)
+		return nil
+	}
+	if helpers.SlotToEpoch(blk.Block.Slot) > cp.Epoch {
+		r.problem("%s checkpoint block %#x at slot %d is after checkpoint epoch %d", name, root, blk.Block.Slot, cp.Epoch)
+	}
+	if !db.HasState(ctx, root) && !db.HasStateSummary(ctx, root) {
+		r.problem("%s checkpoint block %#x has no state nor state summary", name, root)
+	}
+	return blk
+}
+
+// checkChain walks the parents of the head block back to the finalized block, checking each block
+// is present and has a state summary.
+func checkChain(ctx context.Context, db *kv.Store, r *Report, head *ethpb.BeaconBlock, finalized *ethpb.BeaconBlock) error {
+	finalizedRoot, err := stateutil.BlockRoot(finalized)
+	if err != nil {
+		return errors.Wrap(err, "could not compute finalized block root")
+	}
+	root, err := stateutil.BlockRoot(head)
+	if err != nil {
+		return errors.Wrap(err, "could not compute head block root")
+	}
+	blk := head
+	for root != finalizedRoot {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if blk.Slot <= finalized.Slot {
+			r.problem("head does not descend from finalized block %#x, reached block %#x at slot %d", finalizedRoot, root, blk.Slot)
+			return nil
+		}
+		if !db.HasState(ctx, root) && !db.HasStateSummary(ctx, root) {
+			r.problem("block %#x at slot %d has no state nor state summary", root, blk.Slot)
+		}
+		r.ChainLength++
+		parentRoot := bytesutil.ToBytes32(blk.ParentRoot)
+		parent, err := db.Block(ctx, parentRoot)
+		if err != nil {
+			return errors.Wrapf(err, "could not get block %#x", parentRoot)
+		}
+		if parent == nil || parent.Block == nil {
+			r.problem("parent %#x of block %#x at slot %d is missing", parentRoot, root, blk.Slot)
+			return nil
+		}
+		root, blk = parentRoot, parent.Block
+	}
+	return nil
+}
+
+// checkArchivedPoints loads the state of each archived point through state generation.
+func checkArchivedPoints(ctx context.Context, db *kv.Store, r *Report) error {
+	lastIndex, err := db.LastArchivedIndex(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get last archived index")
+	}
+	sg := stategen.New(db, cache.NewStateSummaryCache())
+	if _, err := sg.Resume(ctx); err != nil {
+		r.problem("could not resume state generation from the last archived point: %v", err)
+		return nil
+	}
+	for i := uint64(1); i <= lastIndex; i++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !db.HasArchivedPoint(ctx, i) {
+			continue
+		}
+		root := db.ArchivedPointRoot(ctx, i)
+		st, err := sg.StateByRoot(ctx, root)
+		if err != nil {
+			r.problem("could not load state of archived point %d with root %#x: %v", i, root, err)
+			continue
+		}
+		if st == nil {
+			r.problem("state of archived point %d with root %#x is missing", i, root)
+			continue
+		}
+		r.ArchivedPoints++
+	}
+	return nil
+}
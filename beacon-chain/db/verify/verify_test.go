@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// saveChain saves a genesis block and a head block at slot 1 with their states, the head block
+// being the first archived point. The parent of the head block is not saved when skipParent is set.
+func saveChain(t *testing.T, db *kv.Store, skipParent bool) {
+	ctx := context.Background()
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 0}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisState := testutil.NewBeaconState()
+	if err := db.SaveState(ctx, genesisState, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if !skipParent {
+		if err := db.SaveBlock(ctx, genesis); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	head := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: genesisRoot[:]}}
+	if err := db.SaveBlock(ctx, head); err != nil {
+		t.Fatal(err)
+	}
+	headRoot, err := stateutil.BlockRoot(head.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headState := testutil.NewBeaconState()
+	if err := headState.SetSlot(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, headState, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateSummary(ctx, &pb.StateSummary{Root: headRoot[:], Slot: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, headRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedPointRoot(ctx, headRoot, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveLastArchivedIndex(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabase_Consistent(t *testing.T) {
+	db := dbutil.SetupDB(t).(*kv.Store)
+	saveChain(t, db, false)
+
+	report, err := Database(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Problems) != 0 {
+		t.Fatalf("Wanted no problems, received %v", report.Problems)
+	}
+	if report.HeadSlot != 1 {
+		t.Errorf("Wanted head slot 1, received %d", report.HeadSlot)
+	}
+	if report.ChainLength != 1 {
+		t.Errorf("Wanted chain length 1, received %d", report.ChainLength)
+	}
+	if report.ArchivedPoints != 1 {
+		t.Errorf("Wanted 1 archived point, received %d", report.ArchivedPoints)
+	}
+}
+
+func TestDatabase_ReportsMissingBlocks(t *testing.T) {
+	db := dbutil.SetupDB(t).(*kv.Store)
+	saveChain(t, db, true /* skipParent */)
+
+	report, err := Database(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := []string{
+		"finalized checkpoint refers to genesis, but there is no genesis block",
+		"justified checkpoint refers to genesis, but there is no genesis block",
+	}
+	if len(report.Problems) != len(wanted) {
+		t.Fatalf("Wanted %d problems, received %v", len(wanted), report.Problems)
+	}
+	for i, problem := range report.Problems {
+		if !strings.Contains(problem, wanted[i]) {
+			t.Errorf("Wanted problem %q, received %q", wanted[i], problem)
+		}
+	}
+}
@@ -77,6 +77,23 @@ var (
 		Usage: "Comma separated list of domains from which to accept cross origin requests " +
 			"(browser enforced). This flag has no effect if not used with --grpc-gateway-port.",
 	}
+	// DisableStandardAPI disables the standard Eth2 REST API server.
+	DisableStandardAPI = &cli.BoolFlag{
+		Name:  "disable-standard-api",
+		Usage: "Disable the standard Eth2 REST API (/eth/v1) server",
+	}
+	// StandardAPIHost specifies the host on which the standard Eth2 REST API listens.
+	StandardAPIHost = &cli.StringFlag{
+		Name:  "standard-api-host",
+		Usage: "The host on which the standard Eth2 REST API server runs on",
+		Value: "127.0.0.1",
+	}
+	// StandardAPIPort specifies the port on which the standard Eth2 REST API listens.
+	StandardAPIPort = &cli.IntFlag{
+		Name:  "standard-api-port",
+		Usage: "The port on which the standard Eth2 REST API server runs on",
+		Value: 5052,
+	}
 	// MinSyncPeers specifies the required number of successful peer handshakes in order
 	// to start syncing with external peers.
 	MinSyncPeers = &cli.IntFlag{
@@ -136,9 +153,32 @@ var (
 		Usage: "The factor by which block batch limit may increase on burst.",
 		Value: 10,
 	}
+	// RPCRequestRateLimit specifies the number of requests per second a peer may make on a non-block req/resp topic.
+	RPCRequestRateLimit = &cli.IntFlag{
+		Name:  "rpc-request-rate-limit",
+		Usage: "The amount of status, ping, metadata and goodbye requests per second the local peer responds to from a single peer.",
+		Value: 1,
+	}
+	// RPCRequestBurstLimit specifies the number of requests a peer may burst on a non-block req/resp topic.
+	RPCRequestBurstLimit = &cli.IntFlag{
+		Name:  "rpc-request-burst-limit",
+		Usage: "The amount of status, ping, metadata and goodbye requests a single peer may send at once before being rate limited.",
+		Value: 5,
+	}
 	// EnableDebugRPCEndpoints as /v1/beacon/state.
 	EnableDebugRPCEndpoints = &cli.BoolFlag{
 		Name:  "enable-debug-rpc-endpoints",
 		Usage: "Enables the debug rpc service, containing utility endpoints such as /eth/v1alpha1/beacon/state. Requires --new-state-mgmt",
 	}
+	// DBMigrateDryRunFlag reports the pending database schema migrations and exits without applying them.
+	DBMigrateDryRunFlag = &cli.BoolFlag{
+		Name: "db-migrate-dry-run",
+		Usage: "Runs the pending database schema migrations without saving their changes to report whether they " +
+			"would succeed, then exits",
+	}
+	// DBBackupFileFlag specifies the database backup used by the db restore and verify commands.
+	DBBackupFileFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "Path to a beacon chain database backup file, as written by the database backup endpoint",
+	}
 )
@@ -0,0 +1,37 @@
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// CheckpointStateFlag defines a flag for the beacon node to start from a finalized state loaded via file.
+	CheckpointStateFlag = &cli.StringFlag{
+		Name:  "checkpoint-state",
+		Usage: "The finalized beacon state file (.SSZ) to start syncing from instead of genesis. Must be used with --checkpoint-block",
+	}
+	// CheckpointBlockFlag defines a flag for the beacon node to start from a finalized block loaded via file.
+	CheckpointBlockFlag = &cli.StringFlag{
+		Name:  "checkpoint-block",
+		Usage: "The finalized signed beacon block file (.SSZ) to start syncing from instead of genesis. Must be used with --checkpoint-state",
+	}
+	// CheckpointSyncProviderFlag defines a beacon node gRPC endpoint to fetch the latest finalized
+	// state and block from, when starting from a finalized checkpoint.
+	CheckpointSyncProviderFlag = &cli.StringFlag{
+		Name: "checkpoint-sync-provider",
+		Usage: "A trusted beacon node gRPC endpoint with debug endpoints enabled to fetch the latest finalized " +
+			"state and block from, to start syncing from instead of genesis",
+	}
+	// CheckpointBlockRootFlag defines the expected root of the finalized block the node starts syncing from.
+	CheckpointBlockRootFlag = &cli.StringFlag{
+		Name:  "checkpoint-block-root",
+		Usage: "The hex encoded root of the trusted finalized block to start syncing from, verified against the loaded or fetched block",
+	}
+	// CheckpointEpochFlag defines the epoch of the finalized checkpoint loaded via file.
+	CheckpointEpochFlag = &cli.Uint64Flag{
+		Name: "checkpoint-epoch",
+		Usage: "The epoch of the finalized checkpoint loaded with --checkpoint-block, which is later than the epoch " +
+			"of the block if the first slot of the checkpoint epoch was skipped. Defaults to the first epoch starting " +
+			"at or after the block's slot",
+	}
+)
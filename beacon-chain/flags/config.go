@@ -20,6 +20,8 @@ type GlobalFlags struct {
 	DeploymentBlock                   int
 	BlockBatchLimit                   int
 	BlockBatchLimitBurstFactor        int
+	RPCRequestRateLimit               int
+	RPCRequestBurstLimit              int
 }
 
 var globalConfig *GlobalFlags
@@ -61,6 +63,8 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 	}
 	cfg.BlockBatchLimit = ctx.Int(BlockBatchLimit.Name)
 	cfg.BlockBatchLimitBurstFactor = ctx.Int(BlockBatchLimitBurstFactor.Name)
+	cfg.RPCRequestRateLimit = ctx.Int(RPCRequestRateLimit.Name)
+	cfg.RPCRequestBurstLimit = ctx.Int(RPCRequestBurstLimit.Name)
 	cfg.MaxPageSize = ctx.Int(RPCMaxPageSize.Name)
 	cfg.DeploymentBlock = ctx.Int(ContractDeploymentBlock.Name)
 	configureMinimumPeers(ctx, cfg)
@@ -0,0 +1,18 @@
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// MonitorIndicesFlag defines the validator indices followed by the validator monitor.
+	MonitorIndicesFlag = &cli.StringSliceFlag{
+		Name:  "monitor-indices",
+		Usage: "Validator indices to monitor, logging and exporting metrics about their proposals, attestations, balances and slashings",
+	}
+	// MonitorPublicKeysFlag defines the validator public keys followed by the validator monitor.
+	MonitorPublicKeysFlag = &cli.StringSliceFlag{
+		Name:  "monitor-pubkeys",
+		Usage: "Hex encoded validator public keys to monitor, once they are in the validator registry",
+	}
+)
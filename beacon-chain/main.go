@@ -34,6 +34,9 @@ var appFlags = []cli.Flag{
 	flags.DisableGRPCGateway,
 	flags.GRPCGatewayHost,
 	flags.GRPCGatewayPort,
+	flags.DisableStandardAPI,
+	flags.StandardAPIHost,
+	flags.StandardAPIPort,
 	flags.MinSyncPeers,
 	flags.RPCMaxPageSize,
 	flags.ContractDeploymentBlock,
@@ -44,16 +47,26 @@ var appFlags = []cli.Flag{
 	flags.DisableDiscv5,
 	flags.BlockBatchLimit,
 	flags.BlockBatchLimitBurstFactor,
+	flags.RPCRequestRateLimit,
+	flags.RPCRequestBurstLimit,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropGenesisStateFlag,
 	flags.InteropNumValidatorsFlag,
 	flags.InteropGenesisTimeFlag,
+	flags.CheckpointStateFlag,
+	flags.CheckpointBlockFlag,
+	flags.CheckpointSyncProviderFlag,
+	flags.CheckpointBlockRootFlag,
+	flags.CheckpointEpochFlag,
 	flags.ArchiveEnableFlag,
 	flags.ArchiveValidatorSetChangesFlag,
 	flags.ArchiveBlocksFlag,
 	flags.ArchiveAttestationsFlag,
+	flags.MonitorIndicesFlag,
+	flags.MonitorPublicKeysFlag,
 	flags.SlotsPerArchivedPoint,
 	flags.EnableDebugRPCEndpoints,
+	flags.DBMigrateDryRunFlag,
 	cmd.BootstrapNode,
 	cmd.NoDiscovery,
 	cmd.StaticPeers,
@@ -109,6 +122,36 @@ func main() {
 	app.Version = version.GetVersion()
 
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{
+		{
+			Name:     "db",
+			Category: "db",
+			Usage:    "defines commands for maintaining the beacon node database",
+			Subcommands: []*cli.Command{
+				{
+					Name: "restore",
+					Description: `replaces the database in the data directory with a backup written by the database
+backup endpoint of a beacon node, the beacon node must not be running`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.DBBackupFileFlag,
+					},
+					Action: node.RestoreDB,
+				},
+				{
+					Name: "verify",
+					Description: `checks the consistency of the database in the data directory, or of a backup if
+specified: the head and checkpoints, the chain of blocks back to the finalized block, the state
+summaries, the archived states and the block indices`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.DBBackupFileFlag,
+					},
+					Action: node.VerifyDB,
+				},
+			},
+		},
+	}
 
 	app.Before = func(ctx *cli.Context) error {
 		// Load any flags from file, if specified.
@@ -186,6 +229,10 @@ func startNode(ctx *cli.Context) error {
 		gethlog.Root().SetHandler(glogger)
 	}
 
+	if ctx.Bool(flags.DBMigrateDryRunFlag.Name) {
+		return node.DryRunDBMigrations(ctx)
+	}
+
 	beacon, err := node.NewBeaconNode(ctx)
 	if err != nil {
 		return err
@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/attestationutil"
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+	"github.com/sirupsen/logrus"
+)
+
+// processBlock reports the proposal, the included attestations and the slashings of the monitored
+// validators in the block.
+func (s *Service) processBlock(headState *state.BeaconState, blk *ethpb.BeaconBlock) error {
+	if s.tracked[blk.ProposerIndex] {
+		delete(s.proposals, blk.Slot)
+		log.WithFields(logrus.Fields{
+			"validatorIndex": blk.ProposerIndex,
+			"slot":           blk.Slot,
+		}).Info("Monitored validator proposed a block")
+		proposalsCount.WithLabelValues(fmt.Sprintf("%d", blk.ProposerIndex), "made").Inc()
+	}
+	for _, att := range blk.Body.Attestations {
+		if err := s.processAttestation(headState, att, blk.Slot); err != nil {
+			return errors.Wrapf(err, "could not process attestation of slot %d", att.Data.Slot)
+		}
+	}
+	for _, slashing := range blk.Body.ProposerSlashings {
+		s.reportSlashing(slashing.Header_1.Header.ProposerIndex, "proposer", blk.Slot)
+	}
+	for _, slashing := range blk.Body.AttesterSlashings {
+		slashed := sliceutil.IntersectionUint64(slashing.Attestation_1.AttestingIndices, slashing.Attestation_2.AttestingIndices)
+		for _, index := range slashed {
+			s.reportSlashing(index, "attester", blk.Slot)
+		}
+	}
+	return nil
+}
+
+// processAttestation reports the inclusion of the attestation for the monitored validators which
+// took part in it, with the correctness of their votes according to the head state. Only the first
+// inclusion of the attestation of a validator for an epoch is reported.
+func (s *Service) processAttestation(headState *state.BeaconState, att *ethpb.Attestation, inclusionSlot uint64) error {
+	committee, err := helpers.BeaconCommitteeFromState(headState, att.Data.Slot, att.Data.CommitteeIndex)
+	if err != nil {
+		return errors.Wrap(err, "could not get committee")
+	}
+	var v *votes
+	distance := inclusionSlot - att.Data.Slot
+	for _, index := range attestationutil.AttestingIndices(att.AggregationBits, committee) {
+		key := attestationKey{index: index, epoch: att.Data.Target.Epoch}
+		if !s.tracked[index] || s.attested[key] {
+			continue
+		}
+		s.attested[key] = true
+		if v == nil {
+			v, err = checkVotes(headState, att.Data)
+			if err != nil {
+				return err
+			}
+		}
+		log.WithFields(logrus.Fields{
+			"validatorIndex":    index,
+			"slot":              att.Data.Slot,
+			"inclusionSlot":     inclusionSlot,
+			"inclusionDistance": distance,
+			"correctHead":       v.head,
+			"correctTarget":     v.target,
+			"correctSource":     v.source,
+		}).Info("Monitored validator attestation included")
+		label := fmt.Sprintf("%d", index)
+		attestationsIncludedCount.WithLabelValues(label).Inc()
+		inclusionDistance.WithLabelValues(label).Set(float64(distance))
+		attestationVotesCount.WithLabelValues(label, "head", fmt.Sprintf("%t", v.head)).Inc()
+		attestationVotesCount.WithLabelValues(label, "target", fmt.Sprintf("%t", v.target)).Inc()
+		attestationVotesCount.WithLabelValues(label, "source", fmt.Sprintf("%t", v.source)).Inc()
+	}
+	return nil
+}
+
+// votes records whether the head, target and source votes of an attestation are correct.
+type votes struct {
+	head   bool
+	target bool
+	source bool
+}
+
+// checkVotes compares the votes of the attestation data with the block roots and justified
+// checkpoints of the head state.
+func checkVotes(headState *state.BeaconState, data *ethpb.AttestationData) (*votes, error) {
+	headRoot, err := helpers.BlockRootAtSlot(headState, data.Slot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head root")
+	}
+	targetRoot, err := helpers.BlockRoot(headState, data.Target.Epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get target root")
+	}
+	source := headState.PreviousJustifiedCheckpoint()
+	if data.Target.Epoch == helpers.CurrentEpoch(headState) {
+		source = headState.CurrentJustifiedCheckpoint()
+	}
+	return &votes{
+		head:   bytes.Equal(headRoot, data.BeaconBlockRoot),
+		target: bytes.Equal(targetRoot, data.Target.Root),
+		source: data.Source.Epoch == source.Epoch && bytes.Equal(source.Root, data.Source.Root),
+	}, nil
+}
+
+// reportSlashing reports the slashing of the validator if it is monitored.
+func (s *Service) reportSlashing(index uint64, kind string, slot uint64) {
+	if !s.tracked[index] {
+		return
+	}
+	log.WithFields(logrus.Fields{
+		"validatorIndex": index,
+		"slot":           slot,
+		"type":           kind,
+	}).Warn("Monitored validator slashed")
+	slashingsCount.WithLabelValues(fmt.Sprintf("%d", index), kind).Inc()
+}
@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	proposalsCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_monitor_proposals_total",
+		Help: "Number of block proposals of the monitored validator, labeled by whether they were made or missed",
+	}, []string{"validator_index", "result"})
+	attestationsIncludedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_monitor_attestations_included_total",
+		Help: "Number of attestations of the monitored validator included in processed blocks",
+	}, []string{"validator_index"})
+	attestationsMissedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_monitor_attestations_missed_total",
+		Help: "Number of epochs in which no attestation of the active monitored validator was included",
+	}, []string{"validator_index"})
+	attestationVotesCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_monitor_attestation_votes_total",
+		Help: "Head, target and source votes of the included attestations of the monitored validator, labeled by their correctness",
+	}, []string{"validator_index", "vote", "correct"})
+	inclusionDistance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "validator_monitor_inclusion_distance",
+		Help: "Distance in slots between the last included attestation of the monitored validator and its inclusion",
+	}, []string{"validator_index"})
+	balanceGwei = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "validator_monitor_balance_gwei",
+		Help: "Balance of the monitored validator at the start of the epoch",
+	}, []string{"validator_index"})
+	balanceDeltaGwei = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "validator_monitor_balance_delta_gwei",
+		Help: "Change of the balance of the monitored validator since the start of the previous epoch",
+	}, []string{"validator_index"})
+	slashingsCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_monitor_slashings_total",
+		Help: "Number of proposer and attester slashings of the monitored validator included in processed blocks",
+	}, []string{"validator_index", "type"})
+)
@@ -0,0 +1,306 @@
+// Package monitor defines a service which follows a watched set of validators through every
+// processed block and epoch transition of the beacon node, logging and exporting metrics about
+// their proposals, attestations, balances and slashings. Unlike the validator performance
+// endpoints of the beacon chain API, it needs no client polling it, and works for validators run
+// on any validator client.
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "monitor")
+
+// attestationKey identifies the attestation of a validator for an epoch.
+type attestationKey struct {
+	index uint64
+	epoch uint64
+}
+
+// Service monitoring the proposals, attestations, balances and slashings of a watched set of
+// validators.
+type Service struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	beaconDB      db.ReadOnlyDatabase
+	headFetcher   blockchain.HeadFetcher
+	stateNotifier statefeed.Notifier
+	pubKeys       [][48]byte // Watched public keys not yet found in the validator registry.
+	tracked       map[uint64]bool
+	started       bool
+	lastEpoch     uint64
+	nextAttEpoch  uint64                  // Next epoch to check for missed attestations.
+	proposals     map[uint64]uint64       // Slot to the monitored validator expected to propose.
+	attested      map[attestationKey]bool // Included attestations of the monitored validators.
+	balances      map[uint64]uint64       // Balances at the start of the last epoch.
+}
+
+// Config options for the validator monitor service.
+type Config struct {
+	BeaconDB      db.ReadOnlyDatabase
+	HeadFetcher   blockchain.HeadFetcher
+	StateNotifier statefeed.Notifier
+	Indices       []uint64
+	PublicKeys    [][48]byte
+}
+
+// NewService initializes the service from configuration options.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	tracked := make(map[uint64]bool, len(cfg.Indices))
+	for _, index := range cfg.Indices {
+		tracked[index] = true
+	}
+	return &Service{
+		ctx:           ctx,
+		cancel:        cancel,
+		beaconDB:      cfg.BeaconDB,
+		headFetcher:   cfg.HeadFetcher,
+		stateNotifier: cfg.StateNotifier,
+		pubKeys:       cfg.PublicKeys,
+		tracked:       tracked,
+		proposals:     make(map[uint64]uint64),
+		attested:      make(map[attestationKey]bool),
+		balances:      make(map[uint64]uint64),
+	}
+}
+
+// Start the validator monitor event loop.
+func (s *Service) Start() {
+	log.WithFields(logrus.Fields{
+		"indices":    len(s.tracked),
+		"publicKeys": len(s.pubKeys),
+	}).Info("Monitoring validators")
+	go s.run(s.ctx)
+}
+
+// Stop the validator monitor event loop.
+func (s *Service) Stop() error {
+	defer s.cancel()
+	return nil
+}
+
+// Status reports the healthy status of the validator monitor. Returning nil means service
+// is correctly running without error.
+func (s *Service) Status() error {
+	return nil
+}
+
+func (s *Service) run(ctx context.Context) {
+	stateChannel := make(chan *feed.Event, 1)
+	stateSub := s.stateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	for {
+		select {
+		case event := <-stateChannel:
+			if event.Type == statefeed.BlockProcessed {
+				data, ok := event.Data.(*statefeed.BlockProcessedData)
+				if !ok {
+					log.Error("Event feed data is not type *statefeed.BlockProcessedData")
+					continue
+				}
+				if err := s.onBlockProcessed(ctx, data.BlockRoot); err != nil {
+					log.WithError(err).WithField("blockRoot", fmt.Sprintf("%#x", data.BlockRoot)).Error("Could not monitor block")
+				}
+			}
+		case <-s.ctx.Done():
+			log.Debug("Context closed, exiting goroutine")
+			return
+		case err := <-stateSub.Err():
+			log.WithError(err).Error("Subscription to state feed notifier failed")
+			return
+		}
+	}
+}
+
+// onBlockProcessed handles the epoch transition when the block is the first seen of an epoch, then
+// the operations of the block. Committees and votes are checked against the head state.
+func (s *Service) onBlockProcessed(ctx context.Context, blockRoot [32]byte) error {
+	headState, err := s.headFetcher.HeadState(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not get head state")
+	}
+	if headState == nil {
+		return errors.New("head state is not available")
+	}
+	blk, err := s.beaconDB.Block(ctx, blockRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not get block")
+	}
+	if blk == nil || blk.Block == nil {
+		return errors.New("block not found in the database")
+	}
+	if blk.Block.Slot > headState.Slot() {
+		return fmt.Errorf("block slot %d is after head slot %d", blk.Block.Slot, headState.Slot())
+	}
+	epoch := helpers.SlotToEpoch(headState.Slot())
+	if !s.started || epoch > s.lastEpoch {
+		if err := s.processEpoch(headState, epoch); err != nil {
+			return errors.Wrapf(err, "could not process epoch %d", epoch)
+		}
+	}
+	return s.processBlock(headState, blk.Block)
+}
+
+// processEpoch reports the missed proposals of the previous epoch, the missed attestations of the
+// epochs which can no longer be included and the balances at the start of the epoch, then looks
+// up the proposals of the monitored validators during the epoch.
+func (s *Service) processEpoch(headState *state.BeaconState, epoch uint64) error {
+	s.resolvePublicKeys(headState)
+	if !s.started {
+		// Attestations of the epoch may have been included before the monitor started.
+		s.nextAttEpoch = epoch + 1
+	}
+	s.reportMissedProposals(helpers.StartSlot(epoch))
+	for ; s.nextAttEpoch+2 <= epoch; s.nextAttEpoch++ {
+		s.reportMissedAttestations(headState, s.nextAttEpoch)
+	}
+	s.reportBalances(headState, epoch)
+	if err := s.updateProposals(headState, epoch); err != nil {
+		return err
+	}
+	s.started = true
+	s.lastEpoch = epoch
+	return nil
+}
+
+// resolvePublicKeys starts monitoring the watched public keys which entered the validator registry.
+func (s *Service) resolvePublicKeys(headState *state.BeaconState) {
+	pending := s.pubKeys[:0]
+	for _, pubKey := range s.pubKeys {
+		index, ok := headState.ValidatorIndexByPubkey(pubKey)
+		if !ok {
+			pending = append(pending, pubKey)
+			continue
+		}
+		log.WithFields(logrus.Fields{
+			"pubKey":         fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])),
+			"validatorIndex": index,
+		}).Info("Monitoring validator found in the registry")
+		s.tracked[index] = true
+	}
+	s.pubKeys = pending
+}
+
+// updateProposals records the slots of the epoch in which monitored validators are expected to
+// propose. When the monitor starts, slots before the head are skipped as their blocks were not
+// followed.
+func (s *Service) updateProposals(headState *state.BeaconState, epoch uint64) error {
+	startSlot := helpers.StartSlot(epoch)
+	if !s.started {
+		startSlot = headState.Slot()
+	}
+	st := headState.Copy()
+	for slot := startSlot; slot < helpers.StartSlot(epoch+1); slot++ {
+		// Skip proposer assignment for genesis slot.
+		if slot == 0 {
+			continue
+		}
+		if err := st.SetSlot(slot); err != nil {
+			return err
+		}
+		index, err := helpers.BeaconProposerIndex(st)
+		if err != nil {
+			return errors.Wrapf(err, "could not get proposer at slot %d", slot)
+		}
+		if s.tracked[index] {
+			s.proposals[slot] = index
+		}
+	}
+	return nil
+}
+
+// reportMissedProposals reports the expected proposals before the slot which were not seen.
+func (s *Service) reportMissedProposals(beforeSlot uint64) {
+	var slots []uint64
+	for slot := range s.proposals {
+		if slot < beforeSlot {
+			slots = append(slots, slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i] < slots[j]
+	})
+	for _, slot := range slots {
+		index := s.proposals[slot]
+		delete(s.proposals, slot)
+		log.WithFields(logrus.Fields{
+			"validatorIndex": index,
+			"slot":           slot,
+		}).Warn("Monitored validator missed a block proposal")
+		proposalsCount.WithLabelValues(fmt.Sprintf("%d", index), "missed").Inc()
+	}
+}
+
+// reportMissedAttestations reports the monitored validators active during the epoch which had no
+// attestation for it included.
+func (s *Service) reportMissedAttestations(headState *state.BeaconState, epoch uint64) {
+	for _, index := range s.trackedIndices() {
+		if s.attested[attestationKey{index: index, epoch: epoch}] {
+			continue
+		}
+		val, err := headState.ValidatorAtIndexReadOnly(index)
+		if err != nil || !helpers.IsActiveValidatorUsingTrie(val, epoch) {
+			continue
+		}
+		log.WithFields(logrus.Fields{
+			"validatorIndex": index,
+			"epoch":          epoch,
+		}).Warn("Monitored validator missed an attestation")
+		attestationsMissedCount.WithLabelValues(fmt.Sprintf("%d", index)).Inc()
+	}
+	for key := range s.attested {
+		if key.epoch <= epoch {
+			delete(s.attested, key)
+		}
+	}
+}
+
+// reportBalances reports the balances of the monitored validators and their change since the last
+// epoch transition.
+func (s *Service) reportBalances(headState *state.BeaconState, epoch uint64) {
+	for _, index := range s.trackedIndices() {
+		balance, err := headState.BalanceAtIndex(index)
+		if err != nil {
+			continue
+		}
+		label := fmt.Sprintf("%d", index)
+		fields := logrus.Fields{
+			"validatorIndex": index,
+			"epoch":          epoch,
+			"balance":        balance,
+		}
+		if previous, ok := s.balances[index]; ok {
+			delta := int64(balance) - int64(previous)
+			fields["balanceDelta"] = delta
+			balanceDeltaGwei.WithLabelValues(label).Set(float64(delta))
+		}
+		log.WithFields(fields).Info("Monitored validator balance")
+		balanceGwei.WithLabelValues(label).Set(float64(balance))
+		s.balances[index] = balance
+	}
+}
+
+// trackedIndices returns the monitored validator indices in order.
+func (s *Service) trackedIndices() []uint64 {
+	indices := make([]uint64, 0, len(s.tracked))
+	for index := range s.tracked {
+		indices = append(indices, index)
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return indices[i] < indices[j]
+	})
+	return indices
+}
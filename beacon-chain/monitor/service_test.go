@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	dbutil "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/sirupsen/logrus"
+	logTest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func init() {
+	logrus.SetLevel(logrus.DebugLevel)
+	logrus.SetOutput(ioutil.Discard)
+}
+
+func TestOnBlockProcessed_ReportsBlockOperations(t *testing.T) {
+	hook := logTest.NewGlobal()
+	ctx := context.Background()
+	beaconDB := dbutil.SetupDB(t)
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	if err := st.SetSlot(2); err != nil {
+		t.Fatal(err)
+	}
+	committee, err := helpers.BeaconCommitteeFromState(st, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := committee[0]
+	bits := bitfield.NewBitlist(uint64(len(committee)))
+	bits.SetBitAt(0, true)
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			Slot:            1,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: bits,
+	}
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 2
+	blk.Block.ProposerIndex = index
+	// The attestation is included twice, but reported once.
+	blk.Block.Body.Attestations = []*ethpb.Attestation{att, att}
+	blk.Block.Body.ProposerSlashings = []*ethpb.ProposerSlashing{{
+		Header_1: &ethpb.SignedBeaconBlockHeader{Header: &ethpb.BeaconBlockHeader{ProposerIndex: index}},
+		Header_2: &ethpb.SignedBeaconBlockHeader{Header: &ethpb.BeaconBlockHeader{ProposerIndex: index}},
+	}}
+	if err := beaconDB.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(ctx, &Config{
+		BeaconDB:    beaconDB,
+		HeadFetcher: &mock.ChainService{State: st},
+		Indices:     []uint64{index},
+	})
+	if err := svc.onBlockProcessed(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertLogsContain(t, hook, "Monitored validator proposed a block")
+	testutil.AssertLogsContain(t, hook, "Monitored validator slashed")
+	testutil.AssertLogsContain(t, hook, "inclusionDistance=1")
+	testutil.AssertLogsContain(t, hook, "correctHead=true correctSource=true correctTarget=true")
+	included := 0
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Monitored validator attestation included" {
+			included++
+		}
+	}
+	if included != 1 {
+		t.Errorf("Wanted the attestation reported once, reported %d times", included)
+	}
+	if !svc.attested[attestationKey{index: index, epoch: 0}] {
+		t.Error("Expected the attestation to be recorded")
+	}
+}
+
+func TestProcessEpoch_ReportsMissedDutiesAndBalances(t *testing.T) {
+	hook := logTest.NewGlobal()
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	if err := st.SetSlot(slotsPerEpoch + 1); err != nil {
+		t.Fatal(err)
+	}
+	index, err := helpers.BeaconProposerIndex(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetSlot(slotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(context.Background(), &Config{Indices: []uint64{index}})
+	if err := svc.processEpoch(st, 1); err != nil {
+		t.Fatal(err)
+	}
+	if svc.proposals[slotsPerEpoch+1] != index {
+		t.Fatalf("Expected a proposal at slot %d, received %v", slotsPerEpoch+1, svc.proposals)
+	}
+	testutil.AssertLogsDoNotContain(t, hook, "balanceDelta")
+
+	balance, err := st.BalanceAtIndex(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.UpdateBalancesAtIndex(index, balance-1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetSlot(4 * slotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.processEpoch(st, 4); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertLogsContain(t, hook, "Monitored validator missed a block proposal")
+	testutil.AssertLogsContain(t, hook, fmt.Sprintf("slot=%d", slotsPerEpoch+1))
+	testutil.AssertLogsContain(t, hook, "balanceDelta=-1000")
+	testutil.AssertLogsContain(t, hook, "Monitored validator missed an attestation")
+	testutil.AssertLogsContain(t, hook, "epoch=2")
+	// Attestations of the epoch the monitor started in are not checked, and those of epoch 3 can
+	// still be included.
+	if svc.nextAttEpoch != 3 {
+		t.Errorf("Wanted epoch 3 to be checked next, received %d", svc.nextAttEpoch)
+	}
+}
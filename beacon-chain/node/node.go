@@ -6,11 +6,13 @@ package node
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -21,12 +23,16 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache/depositcache"
+	checkpointsync "github.com/prysmaticlabs/prysm/beacon-chain/checkpoint-sync"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/verify"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice"
 	"github.com/prysmaticlabs/prysm/beacon-chain/forkchoice/protoarray"
 	"github.com/prysmaticlabs/prysm/beacon-chain/gateway"
 	interopcoldstart "github.com/prysmaticlabs/prysm/beacon-chain/interop-cold-start"
+	"github.com/prysmaticlabs/prysm/beacon-chain/monitor"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
@@ -35,8 +41,10 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/backfill"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/event"
@@ -128,6 +136,10 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 
 	beacon.startStateGen()
 
+	if err := beacon.startCheckpointSync(cliCtx); err != nil {
+		return nil, err
+	}
+
 	if err := beacon.registerP2P(cliCtx); err != nil {
 		return nil, err
 	}
@@ -158,6 +170,10 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerBackfillService(); err != nil {
+		return nil, err
+	}
+
 	if err := beacon.registerRPCService(); err != nil {
 		return nil, err
 	}
@@ -170,6 +186,10 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerValidatorMonitorService(); err != nil {
+		return nil, err
+	}
+
 	if !cliCtx.Bool(cmd.DisableMonitoringFlag.Name) {
 		if err := beacon.registerPrometheusService(); err != nil {
 			return nil, err
@@ -247,6 +267,74 @@ func (b *BeaconNode) startForkChoice() {
 	b.forkChoiceStore = f
 }
 
+// DryRunDBMigrations reports whether the pending schema migrations of the beacon node database in
+// the data directory would succeed, without applying them.
+func DryRunDBMigrations(cliCtx *cli.Context) error {
+	dbPath := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), beaconChainDBName)
+	return db.DryRunMigrations(dbPath)
+}
+
+// RestoreDB replaces the beacon node database in the data directory with a backup.
+func RestoreDB(cliCtx *cli.Context) error {
+	backupPath := cliCtx.String(flags.DBBackupFileFlag.Name)
+	if backupPath == "" {
+		return fmt.Errorf("no backup file specified with --%s", flags.DBBackupFileFlag.Name)
+	}
+	dbPath := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), beaconChainDBName)
+	return db.Restore(backupPath, dbPath)
+}
+
+// VerifyDB checks the consistency of the beacon node database in the data directory, or of a
+// backup when one is specified. A backup is verified on a temporary copy. The database is opened
+// read-only, so verifying it neither migrates its schema nor changes its content.
+func VerifyDB(cliCtx *cli.Context) error {
+	dbPath := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), beaconChainDBName)
+	if backupPath := cliCtx.String(flags.DBBackupFileFlag.Name); backupPath != "" {
+		tmpDir, err := ioutil.TempDir("", "beacondb-verify")
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := os.RemoveAll(tmpDir); err != nil {
+				log.WithError(err).Error("Could not remove temporary copy of the backup")
+			}
+		}()
+		if err := db.Restore(backupPath, tmpDir); err != nil {
+			return err
+		}
+		dbPath = tmpDir
+	}
+
+	d, err := kv.NewReadOnlyKVStore(dbPath, cache.NewStateSummaryCache())
+	if err != nil {
+		return errors.Wrap(err, "could not open database")
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			log.WithError(err).Error("Could not close database")
+		}
+	}()
+	report, err := verify.Database(cliCtx.Context, d)
+	if err != nil {
+		return errors.Wrap(err, "could not verify database")
+	}
+	for _, problem := range report.Problems {
+		log.Error(problem)
+	}
+	log.WithFields(logrus.Fields{
+		"headSlot":        report.HeadSlot,
+		"finalizedEpoch":  report.FinalizedEpoch,
+		"justifiedEpoch":  report.JustifiedEpoch,
+		"chainLength":     report.ChainLength,
+		"archivedPoints":  report.ArchivedPoints,
+		"danglingIndices": len(report.DanglingIndices),
+	}).Info("Verified database")
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("database has %d inconsistencies", len(report.Problems))
+	}
+	return nil
+}
+
 func (b *BeaconNode) startDB(cliCtx *cli.Context) error {
 	baseDir := cliCtx.String(cmd.DataDirFlag.Name)
 	dbPath := filepath.Join(baseDir, beaconChainDBName)
@@ -296,6 +384,32 @@ func (b *BeaconNode) startStateGen() {
 	b.stateGen = stategen.New(b.db, b.stateSummaryCache)
 }
 
+func (b *BeaconNode) startCheckpointSync(cliCtx *cli.Context) error {
+	cfg := &checkpointsync.Config{
+		BeaconDB:           b.db,
+		StateGen:           b.stateGen,
+		StatePath:          cliCtx.String(flags.CheckpointStateFlag.Name),
+		BlockPath:          cliCtx.String(flags.CheckpointBlockFlag.Name),
+		Provider:           cliCtx.String(flags.CheckpointSyncProviderFlag.Name),
+		Epoch:              cliCtx.Uint64(flags.CheckpointEpochFlag.Name),
+		MaxCallRecvMsgSize: cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+	}
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cliCtx.IsSet(flags.CheckpointBlockRootFlag.Name) {
+		root, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(flags.CheckpointBlockRootFlag.Name), "0x"))
+		if err != nil || len(root) != 32 {
+			return fmt.Errorf("invalid %s: %s", flags.CheckpointBlockRootFlag.Name, cliCtx.String(flags.CheckpointBlockRootFlag.Name))
+		}
+		cfg.BlockRoot = root
+	}
+	if err := checkpointsync.Initialize(b.ctx, cfg); err != nil {
+		return errors.Wrap(err, "could not start from finalized checkpoint")
+	}
+	return nil
+}
+
 func (b *BeaconNode) registerP2P(cliCtx *cli.Context) error {
 	// Bootnode ENR may be a filepath to an ENR file.
 	bootnodeAddrs := strings.Split(cliCtx.String(cmd.BootstrapNode.Name), ",")
@@ -485,6 +599,14 @@ func (b *BeaconNode) registerInitialSyncService() error {
 	return b.services.RegisterService(is)
 }
 
+func (b *BeaconNode) registerBackfillService() error {
+	svc := backfill.NewService(b.ctx, &backfill.Config{
+		P2P: b.fetchP2P(),
+		DB:  b.db,
+	})
+	return b.services.RegisterService(svc)
+}
+
 func (b *BeaconNode) registerRPCService() error {
 	var chainService *blockchain.Service
 	if err := b.services.FetchService(&chainService); err != nil {
@@ -525,6 +647,14 @@ func (b *BeaconNode) registerRPCService() error {
 	slasherProvider := b.cliCtx.String(flags.SlasherProviderFlag.Name)
 	mockEth1DataVotes := b.cliCtx.Bool(flags.InteropMockEth1DataVotesFlag.Name)
 	enableDebugRPCEndpoints := b.cliCtx.Bool(flags.EnableDebugRPCEndpoints.Name)
+	var standardAPIAddress string
+	if !b.cliCtx.Bool(flags.DisableStandardAPI.Name) {
+		standardAPIAddress = fmt.Sprintf(
+			"%s:%d",
+			b.cliCtx.String(flags.StandardAPIHost.Name),
+			b.cliCtx.Int(flags.StandardAPIPort.Name),
+		)
+	}
 	p2pService := b.fetchP2P()
 	rpcService := rpc.NewService(b.ctx, &rpc.Config{
 		Host:                    host,
@@ -535,6 +665,7 @@ func (b *BeaconNode) registerRPCService() error {
 		Broadcaster:             p2pService,
 		PeersFetcher:            p2pService,
 		PeerManager:             p2pService,
+		MetadataProvider:        p2pService,
 		HeadFetcher:             chainService,
 		ForkFetcher:             chainService,
 		FinalizationFetcher:     chainService,
@@ -559,6 +690,7 @@ func (b *BeaconNode) registerRPCService() error {
 		SlasherProvider:         slasherProvider,
 		StateGen:                b.stateGen,
 		EnableDebugRPCEndpoints: enableDebugRPCEndpoints,
+		StandardAPIAddress:      standardAPIAddress,
 	})
 
 	return b.services.RegisterService(rpcService)
@@ -652,3 +784,39 @@ func (b *BeaconNode) registerArchiverService() error {
 	})
 	return b.services.RegisterService(svc)
 }
+
+func (b *BeaconNode) registerValidatorMonitorService() error {
+	cliIndices := b.cliCtx.StringSlice(flags.MonitorIndicesFlag.Name)
+	cliPubKeys := b.cliCtx.StringSlice(flags.MonitorPublicKeysFlag.Name)
+	if len(cliIndices) == 0 && len(cliPubKeys) == 0 {
+		return nil
+	}
+	indices := make([]uint64, 0, len(cliIndices))
+	for _, index := range cliIndices {
+		i, err := strconv.ParseUint(index, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "could not parse validator index %s", index)
+		}
+		indices = append(indices, i)
+	}
+	pubKeys := make([][48]byte, 0, len(cliPubKeys))
+	for _, pubKey := range cliPubKeys {
+		enc, err := hex.DecodeString(strings.TrimPrefix(pubKey, "0x"))
+		if err != nil || len(enc) != 48 {
+			return fmt.Errorf("invalid validator public key %s", pubKey)
+		}
+		pubKeys = append(pubKeys, bytesutil.ToBytes48(enc))
+	}
+	var chainService *blockchain.Service
+	if err := b.services.FetchService(&chainService); err != nil {
+		return err
+	}
+	svc := monitor.NewService(b.ctx, &monitor.Config{
+		BeaconDB:      b.db,
+		HeadFetcher:   chainService,
+		StateNotifier: b,
+		Indices:       indices,
+		PublicKeys:    pubKeys,
+	})
+	return b.services.RegisterService(svc)
+}
@@ -21,7 +21,13 @@ func TestPeer_AtMaxLimit(t *testing.T) {
 		t.Fatalf("Failed to p2p listen: %v", err)
 	}
 	s := &Service{}
-	s.peers = peers.NewStatus(3)
+	s.peers = peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 3,
+			},
+		},
+	})
 	s.cfg = &Config{MaxPeers: 0}
 	s.addrFilter, err = configureFilter(&Config{})
 	if err != nil {
@@ -75,7 +81,13 @@ func TestPeer_BelowMaxLimit(t *testing.T) {
 		t.Fatalf("Failed to p2p listen: %v", err)
 	}
 	s := &Service{}
-	s.peers = peers.NewStatus(3)
+	s.peers = peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 3,
+			},
+		},
+	})
 	s.cfg = &Config{MaxPeers: 1}
 	s.addrFilter, err = configureFilter(&Config{})
 	if err != nil {
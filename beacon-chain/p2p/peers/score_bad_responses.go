@@ -0,0 +1,140 @@
+package peers
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultBadResponsesThreshold defines how many bad responses to tolerate before peer is deemed bad.
+	DefaultBadResponsesThreshold = 5
+	// DefaultBadResponsesWeight is a default weight. Since score represents penalty, it has negative weight.
+	DefaultBadResponsesWeight = -1.0
+	// DefaultBadResponsesDecayInterval defines how often to decay previous statistics.
+	// Every interval bad responses counter will be decremented by 1.
+	DefaultBadResponsesDecayInterval = time.Hour
+)
+
+// BadResponsesScorer represents bad responses scoring service.
+type BadResponsesScorer struct {
+	config *BadResponsesScorerConfig
+	store  *peerDataStore
+}
+
+// BadResponsesScorerConfig holds configuration parameters for bad response scoring service.
+type BadResponsesScorerConfig struct {
+	// Threshold specifies number of bad responses tolerated, before peer is banned.
+	Threshold int
+	// Weight defines weight of bad response/threshold ratio on overall score.
+	Weight float64
+	// DecayInterval specifies how often bad response stats should be decayed.
+	DecayInterval time.Duration
+}
+
+// newBadResponsesScorer creates new bad responses scoring service.
+func newBadResponsesScorer(store *peerDataStore, config *BadResponsesScorerConfig) *BadResponsesScorer {
+	if config == nil {
+		config = &BadResponsesScorerConfig{}
+	}
+	scorer := &BadResponsesScorer{
+		config: config,
+		store:  store,
+	}
+	if scorer.config.Threshold == 0 {
+		scorer.config.Threshold = DefaultBadResponsesThreshold
+	}
+	if scorer.config.Weight == 0.0 {
+		scorer.config.Weight = DefaultBadResponsesWeight
+	}
+	if scorer.config.DecayInterval == 0 {
+		scorer.config.DecayInterval = DefaultBadResponsesDecayInterval
+	}
+	return scorer
+}
+
+// Score calculates and returns bad responses score.
+func (s *BadResponsesScorer) Score(pid peer.ID) float64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.score(pid)
+}
+
+// score is a lock-free version of Score.
+func (s *BadResponsesScorer) score(pid peer.ID) float64 {
+	status, ok := s.store.peers[pid]
+	if !ok || status.badResponses <= 0 {
+		return 0
+	}
+	return float64(status.badResponses) / float64(s.config.Threshold) * s.config.Weight
+}
+
+// Params exposes scorer's parameters.
+func (s *BadResponsesScorer) Params() *BadResponsesScorerConfig {
+	return s.config
+}
+
+// Count obtains the number of bad responses we have received from the given remote peer.
+// This will error if the peer does not exist.
+func (s *BadResponsesScorer) Count(pid peer.ID) (int, error) {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	if status, ok := s.store.peers[pid]; ok {
+		return status.badResponses, nil
+	}
+	return -1, ErrPeerUnknown
+}
+
+// Increment increments the number of bad responses we have received from the given remote peer.
+func (s *BadResponsesScorer) Increment(pid peer.ID) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	status := s.store.fetch(pid)
+	status.badResponses++
+}
+
+// IsBadPeer states if the peer has sent more bad responses than tolerated.
+// If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
+func (s *BadResponsesScorer) IsBadPeer(pid peer.ID) bool {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.isBadPeer(pid)
+}
+
+// isBadPeer is a lock-free version of IsBadPeer.
+func (s *BadResponsesScorer) isBadPeer(pid peer.ID) bool {
+	if status, ok := s.store.peers[pid]; ok {
+		return status.badResponses >= s.config.Threshold
+	}
+	return false
+}
+
+// BadPeers returns the peers that are considered bad by this scorer.
+func (s *BadResponsesScorer) BadPeers() []peer.ID {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	badPeers := make([]peer.ID, 0)
+	for pid := range s.store.peers {
+		if s.isBadPeer(pid) {
+			badPeers = append(badPeers, pid)
+		}
+	}
+	return badPeers
+}
+
+// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
+// This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
+// the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
+func (s *BadResponsesScorer) Decay() {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	for _, status := range s.store.peers {
+		if status.badResponses > 0 {
+			status.badResponses--
+		}
+	}
+}
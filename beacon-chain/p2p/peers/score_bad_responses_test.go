@@ -0,0 +1,186 @@
+package peers_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestPeerScorer_BadResponses_Score(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 4,
+			},
+		},
+	})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+
+	if score := scorer.Score("peer1"); score != 0 {
+		t.Errorf("Unexpected score for unregistered peer, want: 0, got: %v", score)
+	}
+	scorer.Increment("peer1")
+	if score := scorer.Score("peer1"); score != -0.25 {
+		t.Errorf("Unexpected score, want: -0.25, got: %v", score)
+	}
+	scorer.Increment("peer1")
+	if score := scorer.Score("peer1"); score != -0.5 {
+		t.Errorf("Unexpected score, want: -0.5, got: %v", score)
+	}
+}
+
+func TestPeerScorer_BadResponses_ParamsThreshold(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	maxBadResponses := 2
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+	if scorer.Params().Threshold != maxBadResponses {
+		t.Errorf("Unexpected threshold, want: %d, got: %d", maxBadResponses, scorer.Params().Threshold)
+	}
+	if scorer.Params().Weight != peers.DefaultBadResponsesWeight {
+		t.Errorf("Unexpected weight, want: %v, got: %v", peers.DefaultBadResponsesWeight, scorer.Params().Weight)
+	}
+	if scorer.Params().DecayInterval != peers.DefaultBadResponsesDecayInterval {
+		t.Errorf("Unexpected decay interval, want: %v, got: %v", peers.DefaultBadResponsesDecayInterval, scorer.Params().DecayInterval)
+	}
+}
+
+func TestPeerScorer_BadResponses_Count(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+
+	pid := peer.ID("peer1")
+	if _, err := scorer.Count(pid); err != peers.ErrPeerUnknown {
+		t.Errorf("Unexpected error, want: %v, got: %v", peers.ErrPeerUnknown, err)
+	}
+
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	count, err := scorer.Count(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Unexpected count, want: 0, got: %d", count)
+	}
+}
+
+func TestPeerScorer_BadResponses_Decay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	maxBadResponses := 2
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+
+	// Peer 1 has 0 bad responses.
+	pid1 := peer.ID("peer1")
+	peerStatuses.Add(nil, pid1, nil, network.DirUnknown)
+
+	// Peer 2 has 1 bad response.
+	pid2 := peer.ID("peer2")
+	peerStatuses.Add(nil, pid2, nil, network.DirUnknown)
+	scorer.Increment(pid2)
+
+	// Peer 3 has 2 bad responses.
+	pid3 := peer.ID("peer3")
+	peerStatuses.Add(nil, pid3, nil, network.DirUnknown)
+	scorer.Increment(pid3)
+	scorer.Increment(pid3)
+
+	// Decay the values.
+	scorer.Decay()
+
+	want := map[peer.ID]int{pid1: 0, pid2: 0, pid3: 1}
+	for pid, wantCount := range want {
+		count, err := scorer.Count(pid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != wantCount {
+			t.Errorf("Unexpected bad responses for %s, want: %d, got: %d", pid, wantCount, count)
+		}
+	}
+}
+
+func TestPeerScorer_BadResponses_IsBadPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+	pid := peer.ID("peer1")
+	if scorer.IsBadPeer(pid) {
+		t.Error("Unknown peer must not be marked as bad")
+	}
+
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	if scorer.IsBadPeer(pid) {
+		t.Error("Peer must not be marked as bad")
+	}
+
+	for i := 0; i < scorer.Params().Threshold; i++ {
+		scorer.Increment(pid)
+		if i == scorer.Params().Threshold-1 {
+			if !scorer.IsBadPeer(pid) {
+				t.Errorf("Peer must be marked as bad after %d bad responses", i+1)
+			}
+		} else if scorer.IsBadPeer(pid) {
+			t.Errorf("Peer must not be marked as bad after %d bad responses", i+1)
+		}
+	}
+}
+
+func TestPeerScorer_BadResponses_BadPeers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+	scorer := peerStatuses.Scorers().BadResponsesScorer()
+	pids := []peer.ID{"peer1", "peer2", "peer3", "peer4", "peer5"}
+	for _, pid := range pids {
+		peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	}
+	for i := 0; i < scorer.Params().Threshold; i++ {
+		scorer.Increment(pids[1])
+		scorer.Increment(pids[2])
+		scorer.Increment(pids[4])
+	}
+
+	badPeers := scorer.BadPeers()
+	sort.Slice(badPeers, func(i, j int) bool {
+		return badPeers[i] < badPeers[j]
+	})
+	want := []peer.ID{pids[1], pids[2], pids[4]}
+	if len(badPeers) != len(want) {
+		t.Fatalf("Unexpected number of bad peers, want: %d, got: %d", len(want), len(badPeers))
+	}
+	for i := range want {
+		if badPeers[i] != want[i] {
+			t.Errorf("Unexpected bad peer, want: %s, got: %s", want[i], badPeers[i])
+		}
+	}
+}
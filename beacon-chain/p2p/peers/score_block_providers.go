@@ -0,0 +1,126 @@
+package peers
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultBlockProviderWeight is a default weight of the block provider usefulness on the overall score.
+	DefaultBlockProviderWeight = 1.0
+	// DefaultBlockProviderDecayInterval defines how often the block provider statistics are decayed.
+	// Every interval both requested and processed block counters are halved, so that recent behaviour
+	// dominates the score.
+	DefaultBlockProviderDecayInterval = 10 * time.Minute
+)
+
+// BlockProviderScorer represents block provider scoring service. It rewards peers which serve
+// most of the blocks requested from them.
+type BlockProviderScorer struct {
+	config *BlockProviderScorerConfig
+	store  *peerDataStore
+}
+
+// BlockProviderScorerConfig holds configuration parameters for block providers scoring service.
+type BlockProviderScorerConfig struct {
+	// Weight defines weight of processed/requested blocks ratio on overall score.
+	Weight float64
+	// DecayInterval specifies how often block provider stats should be decayed.
+	DecayInterval time.Duration
+}
+
+// newBlockProviderScorer creates block provider scoring service.
+func newBlockProviderScorer(store *peerDataStore, config *BlockProviderScorerConfig) *BlockProviderScorer {
+	if config == nil {
+		config = &BlockProviderScorerConfig{}
+	}
+	scorer := &BlockProviderScorer{
+		config: config,
+		store:  store,
+	}
+	if scorer.config.Weight == 0.0 {
+		scorer.config.Weight = DefaultBlockProviderWeight
+	}
+	if scorer.config.DecayInterval == 0 {
+		scorer.config.DecayInterval = DefaultBlockProviderDecayInterval
+	}
+	return scorer
+}
+
+// Score calculates and returns block provider score. Peers that have not yet been asked
+// for any blocks are given the maximum score, so that they get a chance to be tried.
+func (s *BlockProviderScorer) Score(pid peer.ID) float64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.score(pid)
+}
+
+// score is a lock-free version of Score.
+func (s *BlockProviderScorer) score(pid peer.ID) float64 {
+	status, ok := s.store.peers[pid]
+	if !ok || status.requestedBlocks == 0 {
+		return s.config.Weight
+	}
+	processed := status.processedBlocks
+	if processed > status.requestedBlocks {
+		processed = status.requestedBlocks
+	}
+	return float64(processed) / float64(status.requestedBlocks) * s.config.Weight
+}
+
+// Params exposes scorer's parameters.
+func (s *BlockProviderScorer) Params() *BlockProviderScorerConfig {
+	return s.config
+}
+
+// IncrementRequestedBlocks increments the number of blocks requested from the peer.
+func (s *BlockProviderScorer) IncrementRequestedBlocks(pid peer.ID, cnt uint64) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	status := s.store.fetch(pid)
+	status.requestedBlocks += cnt
+}
+
+// IncrementProcessedBlocks increments the number of blocks successfully served by the peer.
+func (s *BlockProviderScorer) IncrementProcessedBlocks(pid peer.ID, cnt uint64) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	status := s.store.fetch(pid)
+	status.processedBlocks += cnt
+}
+
+// RequestedBlocks returns the number of blocks requested from the peer.
+func (s *BlockProviderScorer) RequestedBlocks(pid peer.ID) uint64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	if status, ok := s.store.peers[pid]; ok {
+		return status.requestedBlocks
+	}
+	return 0
+}
+
+// ProcessedBlocks returns the number of blocks served by the peer.
+func (s *BlockProviderScorer) ProcessedBlocks(pid peer.ID) uint64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	if status, ok := s.store.peers[pid]; ok {
+		return status.processedBlocks
+	}
+	return 0
+}
+
+// Decay halves the block provider statistics of all peers.
+func (s *BlockProviderScorer) Decay() {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	for _, status := range s.store.peers {
+		status.requestedBlocks /= 2
+		status.processedBlocks /= 2
+	}
+}
@@ -0,0 +1,91 @@
+package peers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestPeerScorer_BlockProvider_Score(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name      string
+		requested uint64
+		processed uint64
+		want      float64
+	}{
+		{
+			name: "no blocks requested",
+			want: peers.DefaultBlockProviderWeight,
+		},
+		{
+			name:      "all requested blocks processed",
+			requested: 64,
+			processed: 64,
+			want:      peers.DefaultBlockProviderWeight,
+		},
+		{
+			name:      "half of requested blocks processed",
+			requested: 64,
+			processed: 32,
+			want:      peers.DefaultBlockProviderWeight / 2,
+		},
+		{
+			name:      "no requested blocks processed",
+			requested: 64,
+			want:      0,
+		},
+		{
+			name:      "more blocks processed than requested",
+			requested: 64,
+			processed: 128,
+			want:      peers.DefaultBlockProviderWeight,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+			scorer := peerStatuses.Scorers().BlockProviderScorer()
+			pid := peer.ID("peer1")
+			peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+			if tt.requested > 0 {
+				scorer.IncrementRequestedBlocks(pid, tt.requested)
+			}
+			if tt.processed > 0 {
+				scorer.IncrementProcessedBlocks(pid, tt.processed)
+			}
+			if score := scorer.Score(pid); score != tt.want {
+				t.Errorf("Unexpected score, want: %v, got: %v", tt.want, score)
+			}
+		})
+	}
+}
+
+func TestPeerScorer_BlockProvider_Decay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+	scorer := peerStatuses.Scorers().BlockProviderScorer()
+	pid := peer.ID("peer1")
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	scorer.IncrementRequestedBlocks(pid, 128)
+	scorer.IncrementProcessedBlocks(pid, 64)
+
+	scorer.Decay()
+	if requested := scorer.RequestedBlocks(pid); requested != 64 {
+		t.Errorf("Unexpected requested blocks, want: 64, got: %d", requested)
+	}
+	if processed := scorer.ProcessedBlocks(pid); processed != 32 {
+		t.Errorf("Unexpected processed blocks, want: 32, got: %d", processed)
+	}
+	if score := scorer.Score(pid); score != peers.DefaultBlockProviderWeight/2 {
+		t.Errorf("Decay must not change the ratio, want: %v, got: %v", peers.DefaultBlockProviderWeight/2, score)
+	}
+}
@@ -0,0 +1,124 @@
+package peers
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DefaultGossipThreshold defines how many invalid gossip messages to tolerate before peer is deemed bad.
+	DefaultGossipThreshold = 20
+	// DefaultGossipWeight is a default weight. Since score represents penalty, it has negative weight.
+	DefaultGossipWeight = -1.0
+	// DefaultGossipDecayInterval defines how often to decay previous statistics.
+	// Every interval invalid gossip messages counter will be decremented by 1.
+	DefaultGossipDecayInterval = 10 * time.Minute
+)
+
+// GossipScorer represents gossip validation scoring service. It penalizes peers that forward
+// gossip messages which fail our validation.
+type GossipScorer struct {
+	config *GossipScorerConfig
+	store  *peerDataStore
+}
+
+// GossipScorerConfig holds configuration parameters for gossip scoring service.
+type GossipScorerConfig struct {
+	// Threshold specifies number of invalid gossip messages tolerated, before peer is banned.
+	Threshold int
+	// Weight defines weight of invalid messages/threshold ratio on overall score.
+	Weight float64
+	// DecayInterval specifies how often invalid gossip stats should be decayed.
+	DecayInterval time.Duration
+}
+
+// newGossipScorer creates new gossip validation scoring service.
+func newGossipScorer(store *peerDataStore, config *GossipScorerConfig) *GossipScorer {
+	if config == nil {
+		config = &GossipScorerConfig{}
+	}
+	scorer := &GossipScorer{
+		config: config,
+		store:  store,
+	}
+	if scorer.config.Threshold == 0 {
+		scorer.config.Threshold = DefaultGossipThreshold
+	}
+	if scorer.config.Weight == 0.0 {
+		scorer.config.Weight = DefaultGossipWeight
+	}
+	if scorer.config.DecayInterval == 0 {
+		scorer.config.DecayInterval = DefaultGossipDecayInterval
+	}
+	return scorer
+}
+
+// Score calculates and returns gossip validation score.
+func (s *GossipScorer) Score(pid peer.ID) float64 {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.score(pid)
+}
+
+// score is a lock-free version of Score.
+func (s *GossipScorer) score(pid peer.ID) float64 {
+	status, ok := s.store.peers[pid]
+	if !ok || status.invalidGossipMessages <= 0 {
+		return 0
+	}
+	return float64(status.invalidGossipMessages) / float64(s.config.Threshold) * s.config.Weight
+}
+
+// Params exposes scorer's parameters.
+func (s *GossipScorer) Params() *GossipScorerConfig {
+	return s.config
+}
+
+// Count obtains the number of invalid gossip messages received from the given remote peer.
+// This will error if the peer does not exist.
+func (s *GossipScorer) Count(pid peer.ID) (int, error) {
+	s.store.RLock()
+	defer s.store.RUnlock()
+
+	if status, ok := s.store.peers[pid]; ok {
+		return status.invalidGossipMessages, nil
+	}
+	return -1, ErrPeerUnknown
+}
+
+// Increment increments the number of invalid gossip messages received from the given remote peer.
+func (s *GossipScorer) Increment(pid peer.ID) {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	status := s.store.fetch(pid)
+	status.invalidGossipMessages++
+}
+
+// IsBadPeer states if the peer has forwarded more invalid gossip messages than tolerated.
+func (s *GossipScorer) IsBadPeer(pid peer.ID) bool {
+	s.store.RLock()
+	defer s.store.RUnlock()
+	return s.isBadPeer(pid)
+}
+
+// isBadPeer is a lock-free version of IsBadPeer.
+func (s *GossipScorer) isBadPeer(pid peer.ID) bool {
+	if status, ok := s.store.peers[pid]; ok {
+		return status.invalidGossipMessages >= s.config.Threshold
+	}
+	return false
+}
+
+// Decay reduces the invalid gossip messages counter of all peers.
+func (s *GossipScorer) Decay() {
+	s.store.Lock()
+	defer s.store.Unlock()
+
+	for _, status := range s.store.peers {
+		if status.invalidGossipMessages > 0 {
+			status.invalidGossipMessages--
+		}
+	}
+}
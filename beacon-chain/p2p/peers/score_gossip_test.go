@@ -0,0 +1,51 @@
+package peers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestPeerScorer_Gossip_ScoreAndIsBadPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			GossipScorerConfig: &peers.GossipScorerConfig{
+				Threshold: 2,
+			},
+		},
+	})
+	scorer := peerStatuses.Scorers().GossipScorer()
+	pid := peer.ID("peer1")
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+
+	scorer.Increment(pid)
+	if score := scorer.Score(pid); score != -0.5 {
+		t.Errorf("Unexpected score, want: -0.5, got: %v", score)
+	}
+	if scorer.IsBadPeer(pid) {
+		t.Error("Peer must not be marked as bad")
+	}
+
+	scorer.Increment(pid)
+	if !scorer.IsBadPeer(pid) {
+		t.Error("Peer must be marked as bad")
+	}
+
+	scorer.Decay()
+	count, err := scorer.Count(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Unexpected invalid messages count after decay, want: 1, got: %d", count)
+	}
+	if scorer.IsBadPeer(pid) {
+		t.Error("Peer must not be marked as bad after decay")
+	}
+}
@@ -0,0 +1,129 @@
+package peers
+
+import (
+	"context"
+	"math"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/shared/runutil"
+)
+
+// DefaultBadPeerScoreThreshold defines the combined score at or below which a peer is considered bad.
+const DefaultBadPeerScoreThreshold = -1.0
+
+// PeerScorerManager keeps track of the peer scorers, and combines their weighted scores
+// into the overall peer score.
+type PeerScorerManager struct {
+	store   *peerDataStore
+	config  *PeerScorerConfig
+	scorers struct {
+		badResponsesScorer  *BadResponsesScorer
+		blockProviderScorer *BlockProviderScorer
+		gossipScorer        *GossipScorer
+	}
+}
+
+// PeerScorerConfig holds the configuration parameters of the scoring service.
+type PeerScorerConfig struct {
+	// BadPeerScoreThreshold is the combined score at or below which a peer is considered bad.
+	BadPeerScoreThreshold     float64
+	BadResponsesScorerConfig  *BadResponsesScorerConfig
+	BlockProviderScorerConfig *BlockProviderScorerConfig
+	GossipScorerConfig        *GossipScorerConfig
+}
+
+// newPeerScorerManager provides fully initialized peer scoring service, decay
+// routines of all the scorers are bound to the provided context.
+func newPeerScorerManager(ctx context.Context, store *peerDataStore, config *PeerScorerConfig) *PeerScorerManager {
+	if config == nil {
+		config = &PeerScorerConfig{}
+	}
+	if config.BadPeerScoreThreshold == 0 {
+		config.BadPeerScoreThreshold = DefaultBadPeerScoreThreshold
+	}
+	mgr := &PeerScorerManager{
+		store:  store,
+		config: config,
+	}
+	mgr.scorers.badResponsesScorer = newBadResponsesScorer(store, config.BadResponsesScorerConfig)
+	mgr.scorers.blockProviderScorer = newBlockProviderScorer(store, config.BlockProviderScorerConfig)
+	mgr.scorers.gossipScorer = newGossipScorer(store, config.GossipScorerConfig)
+
+	runutil.RunEvery(ctx, mgr.scorers.badResponsesScorer.Params().DecayInterval, mgr.scorers.badResponsesScorer.Decay)
+	runutil.RunEvery(ctx, mgr.scorers.blockProviderScorer.Params().DecayInterval, mgr.scorers.blockProviderScorer.Decay)
+	runutil.RunEvery(ctx, mgr.scorers.gossipScorer.Params().DecayInterval, mgr.scorers.gossipScorer.Decay)
+
+	return mgr
+}
+
+// BadResponsesScorer exposes the bad responses scoring service.
+func (m *PeerScorerManager) BadResponsesScorer() *BadResponsesScorer {
+	return m.scorers.badResponsesScorer
+}
+
+// BlockProviderScorer exposes the block provider scoring service.
+func (m *PeerScorerManager) BlockProviderScorer() *BlockProviderScorer {
+	return m.scorers.blockProviderScorer
+}
+
+// GossipScorer exposes the gossip validation scoring service.
+func (m *PeerScorerManager) GossipScorer() *GossipScorer {
+	return m.scorers.gossipScorer
+}
+
+// Params exposes the scoring service parameters.
+func (m *PeerScorerManager) Params() *PeerScorerConfig {
+	return m.config
+}
+
+// Score returns the combined peer score across all the tracked components.
+func (m *PeerScorerManager) Score(pid peer.ID) float64 {
+	m.store.RLock()
+	defer m.store.RUnlock()
+	return m.score(pid)
+}
+
+// score is a lock-free version of Score.
+func (m *PeerScorerManager) score(pid peer.ID) float64 {
+	if _, ok := m.store.peers[pid]; !ok {
+		return 0
+	}
+	score := float64(0)
+	score += m.scorers.badResponsesScorer.score(pid)
+	score += m.scorers.blockProviderScorer.score(pid)
+	score += m.scorers.gossipScorer.score(pid)
+	return math.Round(score*10000) / 10000
+}
+
+// IsBadPeer states if the peer is to be considered bad, either because one of the scorers
+// has seen it cross its threshold, or because its combined score is too low.
+func (m *PeerScorerManager) IsBadPeer(pid peer.ID) bool {
+	m.store.RLock()
+	defer m.store.RUnlock()
+	return m.isBadPeer(pid)
+}
+
+// isBadPeer is a lock-free version of IsBadPeer.
+func (m *PeerScorerManager) isBadPeer(pid peer.ID) bool {
+	if _, ok := m.store.peers[pid]; !ok {
+		return false
+	}
+	if m.scorers.badResponsesScorer.isBadPeer(pid) || m.scorers.gossipScorer.isBadPeer(pid) {
+		return true
+	}
+	return m.score(pid) <= m.config.BadPeerScoreThreshold
+}
+
+// BadPeers returns the peers that are considered bad.
+func (m *PeerScorerManager) BadPeers() []peer.ID {
+	m.store.RLock()
+	defer m.store.RUnlock()
+
+	badPeers := make([]peer.ID, 0)
+	for pid := range m.store.peers {
+		if m.isBadPeer(pid) {
+			badPeers = append(badPeers, pid)
+		}
+	}
+	return badPeers
+}
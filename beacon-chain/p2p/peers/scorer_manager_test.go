@@ -0,0 +1,142 @@
+package peers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestPeerScorer_PeerScorerManager_Init(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("default config", func(t *testing.T) {
+		peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{})
+		scorers := peerStatuses.Scorers()
+		if scorers.Params().BadPeerScoreThreshold != peers.DefaultBadPeerScoreThreshold {
+			t.Errorf("Unexpected threshold, want: %v, got: %v", peers.DefaultBadPeerScoreThreshold, scorers.Params().BadPeerScoreThreshold)
+		}
+		if scorers.BadResponsesScorer().Params().Threshold != peers.DefaultBadResponsesThreshold {
+			t.Errorf("Unexpected bad responses threshold, want: %v, got: %v",
+				peers.DefaultBadResponsesThreshold, scorers.BadResponsesScorer().Params().Threshold)
+		}
+		if scorers.BlockProviderScorer().Params().Weight != peers.DefaultBlockProviderWeight {
+			t.Errorf("Unexpected block provider weight, want: %v, got: %v",
+				peers.DefaultBlockProviderWeight, scorers.BlockProviderScorer().Params().Weight)
+		}
+		if scorers.GossipScorer().Params().Threshold != peers.DefaultGossipThreshold {
+			t.Errorf("Unexpected gossip threshold, want: %v, got: %v",
+				peers.DefaultGossipThreshold, scorers.GossipScorer().Params().Threshold)
+		}
+	})
+
+	t.Run("explicit config", func(t *testing.T) {
+		peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+			ScorerParams: &peers.PeerScorerConfig{
+				BadPeerScoreThreshold: -2.0,
+				BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+					Threshold: 2,
+					Weight:    -0.5,
+				},
+			},
+		})
+		scorers := peerStatuses.Scorers()
+		if scorers.Params().BadPeerScoreThreshold != -2.0 {
+			t.Errorf("Unexpected threshold, want: -2.0, got: %v", scorers.Params().BadPeerScoreThreshold)
+		}
+		if scorers.BadResponsesScorer().Params().Threshold != 2 {
+			t.Errorf("Unexpected bad responses threshold, want: 2, got: %v", scorers.BadResponsesScorer().Params().Threshold)
+		}
+		if scorers.BadResponsesScorer().Params().Weight != -0.5 {
+			t.Errorf("Unexpected bad responses weight, want: -0.5, got: %v", scorers.BadResponsesScorer().Params().Weight)
+		}
+	})
+}
+
+func TestPeerScorer_PeerScorerManager_Score(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 4,
+			},
+			GossipScorerConfig: &peers.GossipScorerConfig{
+				Threshold: 10,
+			},
+		},
+	})
+	scorers := peerStatuses.Scorers()
+	pid := peer.ID("peer1")
+
+	if score := scorers.Score(pid); score != 0 {
+		t.Errorf("Unexpected score for unknown peer, want: 0, got: %v", score)
+	}
+
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	// Fresh peer gets the full block provider score.
+	if score := scorers.Score(pid); score != 1.0 {
+		t.Errorf("Unexpected score, want: 1.0, got: %v", score)
+	}
+
+	// Half of requested blocks served: 0.5.
+	scorers.BlockProviderScorer().IncrementRequestedBlocks(pid, 64)
+	scorers.BlockProviderScorer().IncrementProcessedBlocks(pid, 32)
+	if score := scorers.Score(pid); score != 0.5 {
+		t.Errorf("Unexpected score, want: 0.5, got: %v", score)
+	}
+
+	// One bad response: 0.5 - 0.25.
+	scorers.BadResponsesScorer().Increment(pid)
+	if score := scorers.Score(pid); score != 0.25 {
+		t.Errorf("Unexpected score, want: 0.25, got: %v", score)
+	}
+
+	// One invalid gossip message: 0.5 - 0.25 - 0.1.
+	scorers.GossipScorer().Increment(pid)
+	if score := scorers.Score(pid); score != 0.15 {
+		t.Errorf("Unexpected score, want: 0.15, got: %v", score)
+	}
+}
+
+func TestPeerScorer_PeerScorerManager_IsBadPeer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	peerStatuses := peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 4,
+			},
+			GossipScorerConfig: &peers.GossipScorerConfig{
+				Threshold: 4,
+			},
+		},
+	})
+	scorers := peerStatuses.Scorers()
+	pid := peer.ID("peer1")
+	peerStatuses.Add(nil, pid, nil, network.DirUnknown)
+	scorers.BlockProviderScorer().IncrementRequestedBlocks(pid, 64)
+
+	// Neither component threshold is crossed, but the combined score is too low.
+	for i := 0; i < 3; i++ {
+		scorers.BadResponsesScorer().Increment(pid)
+		scorers.GossipScorer().Increment(pid)
+	}
+	if scorers.BadResponsesScorer().IsBadPeer(pid) || scorers.GossipScorer().IsBadPeer(pid) {
+		t.Fatal("Component scorers must not mark peer as bad")
+	}
+	if !scorers.IsBadPeer(pid) {
+		t.Errorf("Peer with score %v must be marked as bad", scorers.Score(pid))
+	}
+	if !peerStatuses.IsBad(pid) {
+		t.Error("Peer must be reported as bad by peer status")
+	}
+	if badPeers := scorers.BadPeers(); len(badPeers) != 1 || badPeers[0] != pid {
+		t.Errorf("Unexpected bad peers: %v", badPeers)
+	}
+}
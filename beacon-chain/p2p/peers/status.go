@@ -16,13 +16,14 @@
 // - inactive if we are disconnecting or disconnected
 //
 // Peer information is persistent for the run of the service.  This allows for collection of useful long-term statistics such as
-// number of bad responses obtained from the peer, giving the basis for decisions to not talk to known-bad peers.
+// number of bad responses obtained from the peer, giving the basis for decisions to not talk to known-bad peers.  These statistics
+// are combined into a single peer score by the peer scorers, see PeerScorerManager.
 package peers
 
 import (
+	"context"
 	"errors"
 	"sort"
-	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enr"
@@ -59,9 +60,14 @@ var (
 
 // Status is the structure holding the peer status information.
 type Status struct {
-	lock            sync.RWMutex
-	maxBadResponses int
-	status          map[peer.ID]*peerStatus
+	store   *peerDataStore
+	scorers *PeerScorerManager
+}
+
+// StatusConfig represents peer status service params.
+type StatusConfig struct {
+	// ScorerParams holds peer scorer configuration params.
+	ScorerParams *PeerScorerConfig
 }
 
 // peerStatus is the status of an individual peer at the protocol level.
@@ -74,28 +80,32 @@ type peerStatus struct {
 	metaData              *pb.MetaData
 	chainStateLastUpdated time.Time
 	badResponses          int
+	requestedBlocks       uint64
+	processedBlocks       uint64
+	invalidGossipMessages int
 }
 
 // NewStatus creates a new status entity.
-func NewStatus(maxBadResponses int) *Status {
+func NewStatus(ctx context.Context, config *StatusConfig) *Status {
+	store := newPeerDataStore()
 	return &Status{
-		maxBadResponses: maxBadResponses,
-		status:          make(map[peer.ID]*peerStatus),
+		store:   store,
+		scorers: newPeerScorerManager(ctx, store, config.ScorerParams),
 	}
 }
 
-// MaxBadResponses returns the maximum number of bad responses a peer can provide before it is considered bad.
-func (p *Status) MaxBadResponses() int {
-	return p.maxBadResponses
+// Scorers exposes the peer scoring service.
+func (p *Status) Scorers() *PeerScorerManager {
+	return p.scorers
 }
 
 // Add adds a peer.
 // If a peer already exists with this ID its address and direction are updated with the supplied data.
 func (p *Status) Add(record *enr.Record, pid peer.ID, address ma.Multiaddr, direction network.Direction) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
+	p.store.Lock()
+	defer p.store.Unlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		// Peer already exists, just update its address info.
 		status.address = address
 		status.direction = direction
@@ -113,16 +123,16 @@ func (p *Status) Add(record *enr.Record, pid peer.ID, address ma.Multiaddr, dire
 	if record != nil {
 		status.enr = record
 	}
-	p.status[pid] = status
+	p.store.peers[pid] = status
 }
 
 // Address returns the multiaddress of the given remote peer.
 // This will error if the peer does not exist.
 func (p *Status) Address(pid peer.ID) (ma.Multiaddr, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.address, nil
 	}
 	return nil, ErrPeerUnknown
@@ -131,10 +141,10 @@ func (p *Status) Address(pid peer.ID) (ma.Multiaddr, error) {
 // Direction returns the direction of the given remote peer.
 // This will error if the peer does not exist.
 func (p *Status) Direction(pid peer.ID) (network.Direction, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.direction, nil
 	}
 	return network.DirUnknown, ErrPeerUnknown
@@ -142,10 +152,10 @@ func (p *Status) Direction(pid peer.ID) (network.Direction, error) {
 
 // ENR returns the enr for the corresponding peer id.
 func (p *Status) ENR(pid peer.ID) (*enr.Record, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.enr, nil
 	}
 	return nil, ErrPeerUnknown
@@ -153,10 +163,10 @@ func (p *Status) ENR(pid peer.ID) (*enr.Record, error) {
 
 // SetChainState sets the chain state of the given remote peer.
 func (p *Status) SetChainState(pid peer.ID, chainState *pb.Status) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
+	p.store.Lock()
+	defer p.store.Unlock()
 
-	status := p.fetch(pid)
+	status := p.store.fetch(pid)
 	status.chainState = chainState
 	status.chainStateLastUpdated = roughtime.Now()
 }
@@ -165,10 +175,10 @@ func (p *Status) SetChainState(pid peer.ID, chainState *pb.Status) {
 // This can return nil if there is no known chain state for the peer.
 // This will error if the peer does not exist.
 func (p *Status) ChainState(pid peer.ID) (*pb.Status, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.chainState, nil
 	}
 	return nil, ErrPeerUnknown
@@ -176,29 +186,29 @@ func (p *Status) ChainState(pid peer.ID) (*pb.Status, error) {
 
 // IsActive checks if a peers is active and returns the result appropriately.
 func (p *Status) IsActive(pid peer.ID) bool {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	status, ok := p.status[pid]
+	status, ok := p.store.peers[pid]
 	return ok && (status.peerState == PeerConnected || status.peerState == PeerConnecting)
 }
 
 // SetMetadata sets the metadata of the given remote peer.
 func (p *Status) SetMetadata(pid peer.ID, metaData *pb.MetaData) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
+	p.store.Lock()
+	defer p.store.Unlock()
 
-	status := p.fetch(pid)
+	status := p.store.fetch(pid)
 	status.metaData = metaData
 }
 
 // Metadata returns a copy of the metadata corresponding to the provided
 // peer id.
 func (p *Status) Metadata(pid peer.ID) (*pb.MetaData, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return proto.Clone(status.metaData).(*pb.MetaData), nil
 	}
 	return nil, ErrPeerUnknown
@@ -206,10 +216,10 @@ func (p *Status) Metadata(pid peer.ID) (*pb.MetaData, error) {
 
 // CommitteeIndices retrieves the committee subnets the peer is subscribed to.
 func (p *Status) CommitteeIndices(pid peer.ID) ([]uint64, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		if status.enr == nil || status.metaData == nil {
 			return []uint64{}, nil
 		}
@@ -221,11 +231,11 @@ func (p *Status) CommitteeIndices(pid peer.ID) ([]uint64, error) {
 // SubscribedToSubnet retrieves the peers subscribed to the given
 // committee subnet.
 func (p *Status) SubscribedToSubnet(index uint64) []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		// look at active peers
 		connectedStatus := status.peerState == PeerConnecting || status.peerState == PeerConnected
 		if connectedStatus && status.metaData != nil && status.metaData.Attnets != nil {
@@ -243,20 +253,20 @@ func (p *Status) SubscribedToSubnet(index uint64) []peer.ID {
 
 // SetConnectionState sets the connection state of the given remote peer.
 func (p *Status) SetConnectionState(pid peer.ID, state PeerConnectionState) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
+	p.store.Lock()
+	defer p.store.Unlock()
 
-	status := p.fetch(pid)
+	status := p.store.fetch(pid)
 	status.peerState = state
 }
 
 // ConnectionState gets the connection state of the given remote peer.
 // This will error if the peer does not exist.
 func (p *Status) ConnectionState(pid peer.ID) (PeerConnectionState, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.peerState, nil
 	}
 	return PeerDisconnected, ErrPeerUnknown
@@ -265,54 +275,27 @@ func (p *Status) ConnectionState(pid peer.ID) (PeerConnectionState, error) {
 // ChainStateLastUpdated gets the last time the chain state of the given remote peer was updated.
 // This will error if the peer does not exist.
 func (p *Status) ChainStateLastUpdated(pid peer.ID) (time.Time, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 
-	if status, ok := p.status[pid]; ok {
+	if status, ok := p.store.peers[pid]; ok {
 		return status.chainStateLastUpdated, nil
 	}
 	return roughtime.Now(), ErrPeerUnknown
 }
 
-// IncrementBadResponses increments the number of bad responses we have received from the given remote peer.
-func (p *Status) IncrementBadResponses(pid peer.ID) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-
-	status := p.fetch(pid)
-	status.badResponses++
-}
-
-// BadResponses obtains the number of bad responses we have received from the given remote peer.
-// This will error if the peer does not exist.
-func (p *Status) BadResponses(pid peer.ID) (int, error) {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
-
-	if status, ok := p.status[pid]; ok {
-		return status.badResponses, nil
-	}
-	return -1, ErrPeerUnknown
-}
-
-// IsBad states if the peer is to be considered bad.
+// IsBad states if the peer is to be considered bad, as judged by the peer scorers.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (p *Status) IsBad(pid peer.ID) bool {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
-
-	if status, ok := p.status[pid]; ok {
-		return status.badResponses >= p.maxBadResponses
-	}
-	return false
+	return p.scorers.IsBadPeer(pid)
 }
 
 // Connecting returns the peers that are connecting.
 func (p *Status) Connecting() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerConnecting {
 			peers = append(peers, pid)
 		}
@@ -322,10 +305,10 @@ func (p *Status) Connecting() []peer.ID {
 
 // Connected returns the peers that are connected.
 func (p *Status) Connected() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerConnected {
 			peers = append(peers, pid)
 		}
@@ -335,10 +318,10 @@ func (p *Status) Connected() []peer.ID {
 
 // Active returns the peers that are connecting or connected.
 func (p *Status) Active() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerConnecting || status.peerState == PeerConnected {
 			peers = append(peers, pid)
 		}
@@ -348,10 +331,10 @@ func (p *Status) Active() []peer.ID {
 
 // Disconnecting returns the peers that are disconnecting.
 func (p *Status) Disconnecting() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerDisconnecting {
 			peers = append(peers, pid)
 		}
@@ -361,10 +344,10 @@ func (p *Status) Disconnecting() []peer.ID {
 
 // Disconnected returns the peers that are disconnected.
 func (p *Status) Disconnected() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerDisconnected {
 			peers = append(peers, pid)
 		}
@@ -374,10 +357,10 @@ func (p *Status) Disconnected() []peer.ID {
 
 // Inactive returns the peers that are disconnecting or disconnected.
 func (p *Status) Inactive() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
+	for pid, status := range p.store.peers {
 		if status.peerState == PeerDisconnecting || status.peerState == PeerDisconnected {
 			peers = append(peers, pid)
 		}
@@ -387,41 +370,20 @@ func (p *Status) Inactive() []peer.ID {
 
 // Bad returns the peers that are bad.
 func (p *Status) Bad() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
-	peers := make([]peer.ID, 0)
-	for pid, status := range p.status {
-		if status.badResponses >= p.maxBadResponses {
-			peers = append(peers, pid)
-		}
-	}
-	return peers
+	return p.scorers.BadPeers()
 }
 
 // All returns all the peers regardless of state.
 func (p *Status) All() []peer.ID {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
-	pids := make([]peer.ID, 0, len(p.status))
-	for pid := range p.status {
+	p.store.RLock()
+	defer p.store.RUnlock()
+	pids := make([]peer.ID, 0, len(p.store.peers))
+	for pid := range p.store.peers {
 		pids = append(pids, pid)
 	}
 	return pids
 }
 
-// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
-// This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
-// the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
-func (p *Status) Decay() {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-	for _, status := range p.status {
-		if status.badResponses > 0 {
-			status.badResponses--
-		}
-	}
-}
-
 // BestFinalized returns the highest finalized epoch equal to or higher than ours that is agreed upon by the majority of peers.
 // This method may not return the absolute highest finalized, but the finalized epoch in which most peers can serve blocks.
 // Ideally, all peers would be reporting the same finalized epoch but some may be behind due to their own latency, or because of
@@ -476,20 +438,12 @@ func (p *Status) BestFinalized(maxPeers int, ourFinalizedEpoch uint64) ([]byte,
 	return targetRoot[:], targetEpoch, potentialPIDs
 }
 
-// fetch is a helper function that fetches a peer status, possibly creating it.
-func (p *Status) fetch(pid peer.ID) *peerStatus {
-	if _, ok := p.status[pid]; !ok {
-		p.status[pid] = &peerStatus{}
-	}
-	return p.status[pid]
-}
-
 // CurrentEpoch returns the highest reported epoch amongst peers.
 func (p *Status) CurrentEpoch() uint64 {
-	p.lock.RLock()
-	defer p.lock.RUnlock()
+	p.store.RLock()
+	defer p.store.RUnlock()
 	var highestSlot uint64
-	for _, ps := range p.status {
+	for _, ps := range p.store.peers {
 		if ps != nil && ps.chainState != nil && ps.chainState.HeadSlot > highestSlot {
 			highestSlot = ps.chainState.HeadSlot
 		}
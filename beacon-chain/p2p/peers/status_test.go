@@ -2,6 +2,7 @@ package peers_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"fmt"
 	"reflect"
@@ -19,18 +20,30 @@ import (
 
 func TestStatus(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 	if p == nil {
 		t.Fatalf("p not created")
 	}
-	if p.MaxBadResponses() != maxBadResponses {
-		t.Errorf("maxBadResponses incorrect value: expected %v, received %v", maxBadResponses, p.MaxBadResponses())
+	if p.Scorers().BadResponsesScorer().Params().Threshold != maxBadResponses {
+		t.Errorf("maxBadResponses incorrect value: expected %v, received %v", maxBadResponses, p.Scorers().BadResponsesScorer().Params().Threshold)
 	}
 }
 
 func TestPeerExplicitAdd(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -86,7 +99,13 @@ func TestPeerExplicitAdd(t *testing.T) {
 
 func TestPeerNoENR(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -110,7 +129,13 @@ func TestPeerNoENR(t *testing.T) {
 
 func TestPeerNoOverwriteENR(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -138,7 +163,13 @@ func TestPeerNoOverwriteENR(t *testing.T) {
 
 func TestErrUnknownPeer(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -170,7 +201,7 @@ func TestErrUnknownPeer(t *testing.T) {
 		t.Errorf("Unexpected error: expected %v, received %v", peers.ErrPeerUnknown, err)
 	}
 
-	_, err = p.BadResponses(id)
+	_, err = p.Scorers().BadResponsesScorer().Count(id)
 	if err != peers.ErrPeerUnknown {
 		t.Errorf("Unexpected error: expected %v, received %v", peers.ErrPeerUnknown, err)
 	}
@@ -178,7 +209,13 @@ func TestErrUnknownPeer(t *testing.T) {
 
 func TestPeerCommitteeIndices(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -217,7 +254,13 @@ func TestPeerCommitteeIndices(t *testing.T) {
 
 func TestPeerSubscribedToSubnet(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	// Add some peers with different states
 	numPeers := 2
@@ -266,7 +309,13 @@ func TestPeerSubscribedToSubnet(t *testing.T) {
 
 func TestPeerImplicitAdd(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -288,7 +337,13 @@ func TestPeerImplicitAdd(t *testing.T) {
 
 func TestPeerChainState(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -328,7 +383,13 @@ func TestPeerChainState(t *testing.T) {
 
 func TestPeerBadResponses(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	id, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 	if err != nil {
@@ -353,7 +414,7 @@ func TestPeerBadResponses(t *testing.T) {
 	direction := network.DirInbound
 	p.Add(new(enr.Record), id, address, direction)
 
-	resBadResponses, err := p.BadResponses(id)
+	resBadResponses, err := p.Scorers().BadResponsesScorer().Count(id)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -364,8 +425,8 @@ func TestPeerBadResponses(t *testing.T) {
 		t.Error("Peer marked as bad when should be good")
 	}
 
-	p.IncrementBadResponses(id)
-	resBadResponses, err = p.BadResponses(id)
+	p.Scorers().BadResponsesScorer().Increment(id)
+	resBadResponses, err = p.Scorers().BadResponsesScorer().Count(id)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -376,8 +437,8 @@ func TestPeerBadResponses(t *testing.T) {
 		t.Error("Peer marked as bad when should be good")
 	}
 
-	p.IncrementBadResponses(id)
-	resBadResponses, err = p.BadResponses(id)
+	p.Scorers().BadResponsesScorer().Increment(id)
+	resBadResponses, err = p.Scorers().BadResponsesScorer().Count(id)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -388,8 +449,8 @@ func TestPeerBadResponses(t *testing.T) {
 		t.Error("Peer not marked as bad when it should be")
 	}
 
-	p.IncrementBadResponses(id)
-	resBadResponses, err = p.BadResponses(id)
+	p.Scorers().BadResponsesScorer().Increment(id)
+	resBadResponses, err = p.Scorers().BadResponsesScorer().Count(id)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -403,7 +464,13 @@ func TestPeerBadResponses(t *testing.T) {
 
 func TestAddMetaData(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	// Add some peers with different states
 	numPeers := 5
@@ -429,7 +496,13 @@ func TestAddMetaData(t *testing.T) {
 
 func TestPeerConnectionStatuses(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	// Add some peers with different states
 	numPeersDisconnected := 11
@@ -478,37 +551,43 @@ func TestPeerConnectionStatuses(t *testing.T) {
 
 func TestDecay(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	// Peer 1 has 0 bad responses.
 	pid1 := addPeer(t, p, peers.PeerConnected)
 	// Peer 2 has 1 bad response.
 	pid2 := addPeer(t, p, peers.PeerConnected)
-	p.IncrementBadResponses(pid2)
+	p.Scorers().BadResponsesScorer().Increment(pid2)
 	// Peer 3 has 2 bad response.
 	pid3 := addPeer(t, p, peers.PeerConnected)
-	p.IncrementBadResponses(pid3)
-	p.IncrementBadResponses(pid3)
+	p.Scorers().BadResponsesScorer().Increment(pid3)
+	p.Scorers().BadResponsesScorer().Increment(pid3)
 
 	// Decay the values
-	p.Decay()
+	p.Scorers().BadResponsesScorer().Decay()
 
 	// Ensure the new values are as expected
-	badResponses1, err := p.BadResponses(pid1)
+	badResponses1, err := p.Scorers().BadResponsesScorer().Count(pid1)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if badResponses1 != 0 {
 		t.Errorf("Unexpected bad responses for peer 0: expected 0, received %v", badResponses1)
 	}
-	badResponses2, err := p.BadResponses(pid2)
+	badResponses2, err := p.Scorers().BadResponsesScorer().Count(pid2)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if badResponses2 != 0 {
 		t.Errorf("Unexpected bad responses for peer 0: expected 0, received %v", badResponses2)
 	}
-	badResponses3, err := p.BadResponses(pid3)
+	badResponses3, err := p.Scorers().BadResponsesScorer().Count(pid3)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -518,7 +597,13 @@ func TestDecay(t *testing.T) {
 }
 
 func TestTrimmedOrderedPeers(t *testing.T) {
-	p := peers.NewStatus(1)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: 1,
+			},
+		},
+	})
 
 	expectedTarget := uint64(2)
 	maxPeers := 3
@@ -586,7 +671,13 @@ func TestBestPeer(t *testing.T) {
 	expectedFinEpoch := uint64(4)
 	expectedRoot := [32]byte{'t', 'e', 's', 't'}
 	junkRoot := [32]byte{'j', 'u', 'n', 'k'}
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	// Peer 1
 	pid1 := addPeer(t, p, peers.PeerConnected)
@@ -636,7 +727,13 @@ func TestBestPeer(t *testing.T) {
 func TestBestFinalized_returnsMaxValue(t *testing.T) {
 	maxBadResponses := 2
 	maxPeers := 10
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 
 	for i := 0; i <= maxPeers+100; i++ {
 		p.Add(new(enr.Record), peer.ID(i), nil, network.DirOutbound)
@@ -654,7 +751,13 @@ func TestBestFinalized_returnsMaxValue(t *testing.T) {
 
 func TestStatus_CurrentEpoch(t *testing.T) {
 	maxBadResponses := 2
-	p := peers.NewStatus(maxBadResponses)
+	p := peers.NewStatus(context.Background(), &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold: maxBadResponses,
+			},
+		},
+	})
 	// Peer 1
 	pid1 := addPeer(t, p, peers.PeerConnected)
 	p.SetChainState(pid1, &pb.Status{
@@ -0,0 +1,30 @@
+package peers
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// peerDataStore is a container for the data of all known peers. It is shared between
+// the peer status and the peer scorers, so that both are guarded by the same lock.
+type peerDataStore struct {
+	sync.RWMutex
+	peers map[peer.ID]*peerStatus
+}
+
+// newPeerDataStore creates an empty peer data store.
+func newPeerDataStore() *peerDataStore {
+	return &peerDataStore{
+		peers: make(map[peer.ID]*peerStatus),
+	}
+}
+
+// fetch is a helper function that fetches a peer status, possibly creating it.
+// The caller is expected to hold the write lock.
+func (s *peerDataStore) fetch(pid peer.ID) *peerStatus {
+	if _, ok := s.peers[pid]; !ok {
+		s.peers[pid] = &peerStatus{}
+	}
+	return s.peers[pid]
+}
@@ -167,7 +167,14 @@ func NewService(cfg *Config) (*Service, error) {
 	}
 	s.pubsub = gs
 
-	s.peers = peers.NewStatus(maxBadResponses)
+	s.peers = peers.NewStatus(ctx, &peers.StatusConfig{
+		ScorerParams: &peers.PeerScorerConfig{
+			BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+				Threshold:     maxBadResponses,
+				DecayInterval: time.Hour,
+			},
+		},
+	})
 
 	return s, nil
 }
@@ -232,7 +239,6 @@ func (s *Service) Start() {
 	runutil.RunEvery(s.ctx, 5*time.Second, func() {
 		ensurePeerConnections(s.ctx, s.host, peersToWatch...)
 	})
-	runutil.RunEvery(s.ctx, time.Hour, s.Peers().Decay)
 	runutil.RunEvery(s.ctx, 10*time.Second, s.updateMetrics)
 	runutil.RunEvery(s.ctx, refreshRate, func() {
 		s.RefreshENR()
@@ -527,7 +533,7 @@ func (s *Service) connectWithPeer(info peer.AddrInfo) error {
 		return nil
 	}
 	if err := s.host.Connect(s.ctx, info); err != nil {
-		s.Peers().IncrementBadResponses(info.ID)
+		s.Peers().Scorers().BadResponsesScorer().Increment(info.ID)
 		return err
 	}
 	return nil
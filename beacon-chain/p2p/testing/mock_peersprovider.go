@@ -1,6 +1,7 @@
 package testing
 
 import (
+	"context"
 	"sync"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -25,7 +26,13 @@ func (m *MockPeersProvider) Peers() *peers.Status {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	if m.peers == nil {
-		m.peers = peers.NewStatus(5 /* maxBadResponses */)
+		m.peers = peers.NewStatus(context.Background(), &peers.StatusConfig{
+			ScorerParams: &peers.PeerScorerConfig{
+				BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+					Threshold: 5,
+				},
+			},
+		})
 		// Pretend we are connected to two peers
 		id0, err := peer.IDB58Decode("16Uiu2HAkyWZ4Ni1TpvDS8dPxsozmHY85KaiFjodQuV6Tz5tkHVeR")
 		if err != nil {
@@ -56,7 +56,13 @@ func NewTestP2P(t *testing.T) *TestP2P {
 		t:      t,
 		BHost:  h,
 		pubsub: ps,
-		peers:  peers.NewStatus(5 /* maxBadResponses */),
+		peers: peers.NewStatus(context.Background(), &peers.StatusConfig{
+			ScorerParams: &peers.PeerScorerConfig{
+				BadResponsesScorerConfig: &peers.BadResponsesScorerConfig{
+					Threshold: 5,
+				},
+			},
+		}),
 	}
 }
 
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
+	"time"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
@@ -29,6 +31,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/debug"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/standardapi"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/validator"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
@@ -46,6 +49,10 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+// standardAPIShutdownTimeout bounds how long stopping the service waits for the
+// requests of the standard REST API in flight.
+const standardAPIShutdownTimeout = 5 * time.Second
+
 var log logrus.FieldLogger
 
 func init() {
@@ -86,6 +93,7 @@ type Service struct {
 	p2p                     p2p.Broadcaster
 	peersFetcher            p2p.PeersProvider
 	peerManager             p2p.PeerManager
+	metadataProvider        p2p.MetadataProvider
 	depositFetcher          depositcache.DepositFetcher
 	pendingDepositFetcher   depositcache.PendingDepositsFetcher
 	stateNotifier           statefeed.Notifier
@@ -98,6 +106,8 @@ type Service struct {
 	slasherClient           slashpb.SlasherClient
 	stateGen                *stategen.State
 	connectedRPCClients     map[net.Addr]bool
+	standardAPIAddress      string
+	standardAPIServer       *http.Server
 }
 
 // Config options for the beacon node RPC server.
@@ -126,6 +136,7 @@ type Config struct {
 	Broadcaster             p2p.Broadcaster
 	PeersFetcher            p2p.PeersProvider
 	PeerManager             p2p.PeerManager
+	MetadataProvider        p2p.MetadataProvider
 	DepositFetcher          depositcache.DepositFetcher
 	PendingDepositFetcher   depositcache.PendingDepositsFetcher
 	SlasherProvider         string
@@ -134,6 +145,7 @@ type Config struct {
 	BlockNotifier           blockfeed.Notifier
 	OperationNotifier       opfeed.Notifier
 	StateGen                *stategen.State
+	StandardAPIAddress      string
 }
 
 // NewService instantiates a new RPC service instance that will
@@ -155,6 +167,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		p2p:                     cfg.Broadcaster,
 		peersFetcher:            cfg.PeersFetcher,
 		peerManager:             cfg.PeerManager,
+		metadataProvider:        cfg.MetadataProvider,
 		powChainService:         cfg.POWChainService,
 		chainStartFetcher:       cfg.ChainStartFetcher,
 		mockEth1Votes:           cfg.MockEth1Votes,
@@ -178,6 +191,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		stateGen:                cfg.StateGen,
 		enableDebugRPCEndpoints: cfg.EnableDebugRPCEndpoints,
 		connectedRPCClients:     make(map[net.Addr]bool),
+		standardAPIAddress:      cfg.StandardAPIAddress,
 	}
 }
 
@@ -308,11 +322,46 @@ func (s *Service) Start() {
 			}
 		}
 	}()
+	if s.standardAPIAddress != "" {
+		s.startStandardAPI(&standardapi.Server{
+			Ctx:                 s.ctx,
+			BeaconServer:        beaconChainServer,
+			NodeServer:          nodeServer,
+			ValidatorServer:     validatorServer,
+			BeaconDB:            s.beaconDB,
+			HeadFetcher:         s.headFetcher,
+			FinalizationFetcher: s.finalizationFetcher,
+			GenesisTimeFetcher:  s.genesisTimeFetcher,
+			StateGen:            s.stateGen,
+			AttestationsPool:    s.attestationsPool,
+			SlashingsPool:       s.slashingsPool,
+			ExitPool:            s.exitPool,
+			SyncChecker:         s.syncService,
+			MetadataProvider:    s.metadataProvider,
+			StateNotifier:       s.stateNotifier,
+			OperationNotifier:   s.operationNotifier,
+		})
+	}
 	if featureconfig.Get().EnableSlasherConnection {
 		s.startSlasherClient()
 	}
 }
 
+// startStandardAPI serves the standard Eth2 REST API using the same server
+// implementations registered with the gRPC server.
+func (s *Service) startStandardAPI(apiServer *standardapi.Server) {
+	s.standardAPIServer = &http.Server{
+		Addr:    s.standardAPIAddress,
+		Handler: apiServer.Handler(),
+	}
+	log.WithField("address", s.standardAPIAddress).Info("Starting standard REST API server")
+	go func() {
+		if err := s.standardAPIServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Could not serve standard REST API: %v", err)
+		}
+	}()
+}
+
 func (s *Service) startSlasherClient() {
 	var dialOpt grpc.DialOption
 	if s.slasherCert != "" {
@@ -355,6 +404,13 @@ func (s *Service) Stop() error {
 		s.grpcServer.GracefulStop()
 		log.Debug("Initiated graceful stop of gRPC server")
 	}
+	if s.standardAPIServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), standardAPIShutdownTimeout)
+		defer cancel()
+		if err := s.standardAPIServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
 	if s.slasherConn != nil {
 		if err := s.slasherConn.Close(); err != nil {
 			return err
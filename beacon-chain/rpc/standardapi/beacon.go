@@ -0,0 +1,550 @@
+package standardapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func (s *Server) registerBeaconRoutes(rt *router) {
+	rt.get("/eth/v1/beacon/genesis", s.getGenesis)
+	rt.get("/eth/v1/beacon/states/{state_id}/root", s.getStateRoot)
+	rt.get("/eth/v1/beacon/states/{state_id}/fork", s.getStateFork)
+	rt.get("/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.getFinalityCheckpoints)
+	rt.get("/eth/v1/beacon/states/{state_id}/validators", s.listValidators)
+	rt.get("/eth/v1/beacon/states/{state_id}/validators/{validator_id}", s.getValidator)
+	rt.get("/eth/v1/beacon/states/{state_id}/validator_balances", s.listValidatorBalances)
+	rt.get("/eth/v1/beacon/states/{state_id}/committees", s.listCommittees)
+	rt.get("/eth/v1/beacon/headers", s.listBlockHeaders)
+	rt.get("/eth/v1/beacon/headers/{block_id}", s.getBlockHeader)
+	rt.post("/eth/v1/beacon/blocks", s.submitBlock)
+	rt.get("/eth/v1/beacon/blocks/{block_id}", s.getBlock)
+	rt.get("/eth/v1/beacon/blocks/{block_id}/root", s.getBlockRoot)
+	rt.get("/eth/v1/beacon/blocks/{block_id}/attestations", s.listBlockAttestations)
+	rt.get("/eth/v1/beacon/pool/attestations", s.listPoolAttestations)
+	rt.post("/eth/v1/beacon/pool/attestations", s.submitAttestations)
+	rt.get("/eth/v1/beacon/pool/attester_slashings", s.listPoolAttesterSlashings)
+	rt.post("/eth/v1/beacon/pool/attester_slashings", s.submitAttesterSlashing)
+	rt.get("/eth/v1/beacon/pool/proposer_slashings", s.listPoolProposerSlashings)
+	rt.post("/eth/v1/beacon/pool/proposer_slashings", s.submitProposerSlashing)
+	rt.get("/eth/v1/beacon/pool/voluntary_exits", s.listPoolVoluntaryExits)
+	rt.post("/eth/v1/beacon/pool/voluntary_exits", s.submitVoluntaryExit)
+}
+
+func (s *Server) getGenesis(r *http.Request, _ params) (interface{}, error) {
+	genesis, err := s.NodeServer.GetGenesis(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	if genesis.GenesisTime == nil || genesis.GenesisTime.Seconds == 0 {
+		return nil, notFound("Chain genesis info is not yet known")
+	}
+	return map[string]interface{}{
+		"genesis_time":            strconv.FormatInt(genesis.GenesisTime.Seconds, 10),
+		"genesis_validators_root": hexutil.Encode(genesis.GenesisValidatorsRoot),
+		"genesis_fork_version":    hexutil.Encode(params.BeaconConfig().GenesisForkVersion),
+	}, nil
+}
+
+func (s *Server) getStateRoot(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	root, err := st.HashTreeRoot(r.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute state root")
+	}
+	return map[string]interface{}{"root": hexutil.Encode(root[:])}, nil
+}
+
+func (s *Server) getStateFork(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(st.Fork()), nil
+}
+
+func (s *Server) getFinalityCheckpoints(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"previous_justified": encode(st.PreviousJustifiedCheckpoint()),
+		"current_justified":  encode(st.CurrentJustifiedCheckpoint()),
+		"finalized":          encode(st.FinalizedCheckpoint()),
+	}, nil
+}
+
+func (s *Server) listValidators(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	indices, err := validatorIndices(st, queryList(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		res = append(res, validatorResponse(st, idx))
+	}
+	return res, nil
+}
+
+func (s *Server) getValidator(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	indices, err := validatorIndices(st, []string{p["validator_id"]})
+	if err != nil {
+		return nil, err
+	}
+	if len(indices) == 0 {
+		return nil, notFound("Validator not found")
+	}
+	return validatorResponse(st, indices[0]), nil
+}
+
+func (s *Server) listValidatorBalances(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	indices, err := validatorIndices(st, queryList(r, "id"))
+	if err != nil {
+		return nil, err
+	}
+	balances := st.Balances()
+	res := make([]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		res = append(res, map[string]interface{}{
+			"index":   strconv.FormatUint(idx, 10),
+			"balance": strconv.FormatUint(balances[idx], 10),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) listCommittees(r *http.Request, p params) (interface{}, error) {
+	st, err := s.stateByID(r.Context(), p["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	epoch := helpers.CurrentEpoch(st)
+	if v := r.URL.Query().Get("epoch"); v != "" {
+		if epoch, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, badRequest("Invalid epoch: %s", v)
+		}
+	}
+	if epoch > helpers.NextEpoch(st) {
+		return nil, badRequest("Epoch %d is too far in the future of the requested state", epoch)
+	}
+	slotFilter, err := queryUint(r, "slot")
+	if err != nil {
+		return nil, err
+	}
+	indexFilter, err := queryUint(r, "index")
+	if err != nil {
+		return nil, err
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not count active validators")
+	}
+	committeesPerSlot := helpers.SlotCommitteeCount(activeCount)
+	startSlot := helpers.StartSlot(epoch)
+	res := make([]interface{}, 0)
+	for slot := startSlot; slot < startSlot+params.BeaconConfig().SlotsPerEpoch; slot++ {
+		if slotFilter != nil && *slotFilter != slot {
+			continue
+		}
+		for i := uint64(0); i < committeesPerSlot; i++ {
+			if indexFilter != nil && *indexFilter != i {
+				continue
+			}
+			committee, err := helpers.BeaconCommitteeFromState(st, slot, i)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not compute committee")
+			}
+			validators := make([]string, len(committee))
+			for j, idx := range committee {
+				validators[j] = strconv.FormatUint(idx, 10)
+			}
+			res = append(res, map[string]interface{}{
+				"index":      strconv.FormatUint(i, 10),
+				"slot":       strconv.FormatUint(slot, 10),
+				"validators": validators,
+			})
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) listBlockHeaders(r *http.Request, _ params) (interface{}, error) {
+	ctx := r.Context()
+	slot, err := queryUint(r, "slot")
+	if err != nil {
+		return nil, err
+	}
+	var blks []*ethpb.SignedBeaconBlock
+	if v := r.URL.Query().Get("parent_root"); v != "" {
+		parentRoot, err := decodeRoot(v)
+		if err != nil {
+			return nil, err
+		}
+		blks, err = s.BeaconDB.Blocks(ctx, filters.NewFilter().SetParentRoot(parentRoot))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not retrieve blocks")
+		}
+	} else if slot != nil {
+		blks, err = s.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(*slot).SetEndSlot(*slot))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not retrieve blocks")
+		}
+	} else {
+		blk, err := s.blockByID(ctx, "head")
+		if err != nil {
+			return nil, err
+		}
+		blks = []*ethpb.SignedBeaconBlock{blk}
+	}
+	res := make([]interface{}, 0, len(blks))
+	for _, blk := range blks {
+		if slot != nil && blk.Block.Slot != *slot {
+			continue
+		}
+		header, err := s.headerResponse(r, blk)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, header)
+	}
+	return res, nil
+}
+
+func (s *Server) getBlockHeader(r *http.Request, p params) (interface{}, error) {
+	blk, err := s.blockByID(r.Context(), p["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return s.headerResponse(r, blk)
+}
+
+func (s *Server) headerResponse(r *http.Request, blk *ethpb.SignedBeaconBlock) (interface{}, error) {
+	root, err := blockRoot(blk)
+	if err != nil {
+		return nil, err
+	}
+	bodyRoot, err := stateutil.BlockBodyRoot(blk.Block.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute block body root")
+	}
+	canonical, err := s.canonicalBlockAtSlot(r.Context(), blk.Block.Slot)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not determine canonical block")
+	}
+	isCanonical := false
+	if canonical != nil {
+		canonicalRoot, err := blockRoot(canonical)
+		if err != nil {
+			return nil, err
+		}
+		isCanonical = canonicalRoot == root
+	}
+	header := &ethpb.SignedBeaconBlockHeader{
+		Header: &ethpb.BeaconBlockHeader{
+			Slot:          blk.Block.Slot,
+			ProposerIndex: blk.Block.ProposerIndex,
+			ParentRoot:    blk.Block.ParentRoot,
+			StateRoot:     blk.Block.StateRoot,
+			BodyRoot:      bodyRoot[:],
+		},
+		Signature: blk.Signature,
+	}
+	return map[string]interface{}{
+		"root":      hexutil.Encode(root[:]),
+		"canonical": isCanonical,
+		"header":    encode(header),
+	}, nil
+}
+
+func (s *Server) submitBlock(r *http.Request, _ params) (interface{}, error) {
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := decode(r.Body, blk); err != nil {
+		return nil, badRequest("Invalid block: %v", err)
+	}
+	if blk.Block == nil || blk.Block.Body == nil {
+		return nil, badRequest("Invalid block: missing block body")
+	}
+	if _, err := s.ValidatorServer.ProposeBlock(r.Context(), blk); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) getBlock(r *http.Request, p params) (interface{}, error) {
+	blk, err := s.blockByID(r.Context(), p["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk), nil
+}
+
+func (s *Server) getBlockRoot(r *http.Request, p params) (interface{}, error) {
+	blk, err := s.blockByID(r.Context(), p["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	root, err := blockRoot(blk)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"root": hexutil.Encode(root[:])}, nil
+}
+
+func (s *Server) listBlockAttestations(r *http.Request, p params) (interface{}, error) {
+	blk, err := s.blockByID(r.Context(), p["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk.Block.Body.Attestations), nil
+}
+
+func (s *Server) listPoolAttestations(r *http.Request, _ params) (interface{}, error) {
+	slot, err := queryUint(r, "slot")
+	if err != nil {
+		return nil, err
+	}
+	committeeIndex, err := queryUint(r, "committee_index")
+	if err != nil {
+		return nil, err
+	}
+	atts := append(s.AttestationsPool.AggregatedAttestations(), s.AttestationsPool.UnaggregatedAttestations()...)
+	res := make([]*ethpb.Attestation, 0, len(atts))
+	for _, att := range atts {
+		if slot != nil && att.Data.Slot != *slot {
+			continue
+		}
+		if committeeIndex != nil && att.Data.CommitteeIndex != *committeeIndex {
+			continue
+		}
+		res = append(res, att)
+	}
+	return encode(res), nil
+}
+
+func (s *Server) submitAttestations(r *http.Request, _ params) (interface{}, error) {
+	var atts []*ethpb.Attestation
+	if err := decode(r.Body, &atts); err != nil {
+		return nil, badRequest("Invalid attestations: %v", err)
+	}
+	for i, att := range atts {
+		if att.Data == nil {
+			return nil, badRequest("Invalid attestation %d: missing data", i)
+		}
+		if _, err := s.ValidatorServer.ProposeAttestation(r.Context(), att); err != nil {
+			code, msg := httpStatus(err)
+			return nil, newAPIError(code, "Could not submit attestation %d: %s", i, msg)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolAttesterSlashings(r *http.Request, _ params) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(r.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve head state")
+	}
+	return encode(s.SlashingsPool.PendingAttesterSlashings(r.Context(), headState)), nil
+}
+
+func (s *Server) submitAttesterSlashing(r *http.Request, _ params) (interface{}, error) {
+	slashing := &ethpb.AttesterSlashing{}
+	if err := decode(r.Body, slashing); err != nil {
+		return nil, badRequest("Invalid attester slashing: %v", err)
+	}
+	if slashing.Attestation_1 == nil || slashing.Attestation_2 == nil {
+		return nil, badRequest("Invalid attester slashing: missing attestation")
+	}
+	if _, err := s.BeaconServer.SubmitAttesterSlashing(r.Context(), slashing); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolProposerSlashings(r *http.Request, _ params) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(r.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve head state")
+	}
+	return encode(s.SlashingsPool.PendingProposerSlashings(r.Context(), headState)), nil
+}
+
+func (s *Server) submitProposerSlashing(r *http.Request, _ params) (interface{}, error) {
+	slashing := &ethpb.ProposerSlashing{}
+	if err := decode(r.Body, slashing); err != nil {
+		return nil, badRequest("Invalid proposer slashing: %v", err)
+	}
+	if slashing.Header_1 == nil || slashing.Header_1.Header == nil ||
+		slashing.Header_2 == nil || slashing.Header_2.Header == nil {
+		return nil, badRequest("Invalid proposer slashing: missing header")
+	}
+	if _, err := s.BeaconServer.SubmitProposerSlashing(r.Context(), slashing); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolVoluntaryExits(r *http.Request, _ params) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(r.Context())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not retrieve head state")
+	}
+	return encode(s.ExitPool.PendingExits(headState, headState.Slot())), nil
+}
+
+func (s *Server) submitVoluntaryExit(r *http.Request, _ params) (interface{}, error) {
+	exit := &ethpb.SignedVoluntaryExit{}
+	if err := decode(r.Body, exit); err != nil {
+		return nil, badRequest("Invalid voluntary exit: %v", err)
+	}
+	if exit.Exit == nil {
+		return nil, badRequest("Invalid voluntary exit: missing message")
+	}
+	if _, err := s.ValidatorServer.ProposeExit(r.Context(), exit); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// validatorIndices resolves validator identifiers, given either as indices or as
+// 0x-prefixed public keys, to validator indices. All validators are returned if
+// no identifiers are given, and unknown identifiers are skipped.
+func validatorIndices(st *stateTrie.BeaconState, ids []string) ([]uint64, error) {
+	numVals := uint64(st.NumValidators())
+	if len(ids) == 0 {
+		indices := make([]uint64, numVals)
+		for i := range indices {
+			indices[i] = uint64(i)
+		}
+		return indices, nil
+	}
+	indices := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		if strings.HasPrefix(id, "0x") {
+			pubkey, err := hexutil.Decode(id)
+			if err != nil || len(pubkey) != params.BeaconConfig().BLSPubkeyLength {
+				return nil, badRequest("Invalid validator public key: %s", id)
+			}
+			if idx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubkey)); ok {
+				indices = append(indices, idx)
+			}
+			continue
+		}
+		idx, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, badRequest("Invalid validator ID: %s", id)
+		}
+		if idx < numVals {
+			indices = append(indices, idx)
+		}
+	}
+	return indices, nil
+}
+
+func validatorResponse(st *stateTrie.BeaconState, idx uint64) interface{} {
+	val, err := st.ValidatorAtIndex(idx)
+	if err != nil {
+		return nil
+	}
+	balance, err := st.BalanceAtIndex(idx)
+	if err != nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"index":     strconv.FormatUint(idx, 10),
+		"balance":   strconv.FormatUint(balance, 10),
+		"status":    validatorStatus(val, helpers.CurrentEpoch(st)),
+		"validator": encode(val),
+	}
+}
+
+// validatorStatus returns the standard API status of a validator at the given epoch.
+func validatorStatus(val *ethpb.Validator, epoch uint64) string {
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	switch {
+	case val.ActivationEpoch > epoch:
+		if val.ActivationEligibilityEpoch == farFuture {
+			return "pending_initialized"
+		}
+		return "pending_queued"
+	case val.ExitEpoch > epoch:
+		if val.Slashed {
+			return "active_slashed"
+		}
+		if val.ExitEpoch != farFuture {
+			return "active_exiting"
+		}
+		return "active_ongoing"
+	case val.WithdrawableEpoch > epoch:
+		if val.Slashed {
+			return "exited_slashed"
+		}
+		return "exited_unslashed"
+	default:
+		if val.EffectiveBalance != 0 {
+			return "withdrawal_possible"
+		}
+		return "withdrawal_done"
+	}
+}
+
+func blockRoot(blk *ethpb.SignedBeaconBlock) ([32]byte, error) {
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not compute block root")
+	}
+	return root, nil
+}
+
+// queryList returns the values of a query parameter which may be repeated or
+// given as a comma separated list.
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// queryUint parses an optional unsigned integer query parameter.
+func queryUint(r *http.Request, key string) (*uint64, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, badRequest("Invalid %s: %s", key, v)
+	}
+	return &n, nil
+}
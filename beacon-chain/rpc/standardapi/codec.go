@@ -0,0 +1,213 @@
+package standardapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// fieldNames overrides the JSON field names derived from the protobuf struct tags
+// wherever the standard API schema differs from the v1alpha1 definitions.
+var fieldNames = map[string]string{
+	"SignedBeaconBlock.Block":        "message",
+	"SignedBeaconBlockHeader.Header": "message",
+	"SignedVoluntaryExit.Exit":       "message",
+	"Validator.PublicKey":            "pubkey",
+	"Deposit_Data.PublicKey":         "pubkey",
+	"AttestationData.CommitteeIndex": "index",
+	"ProposerSlashing.Header_1":      "signed_header_1",
+	"ProposerSlashing.Header_2":      "signed_header_2",
+}
+
+// jsonName returns the standard API field name of a struct field, or false if the
+// field is internal to the protobuf implementation and should not be serialized.
+func jsonName(t reflect.Type, f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	if name, ok := fieldNames[t.Name()+"."+f.Name]; ok {
+		return name, true
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, true
+}
+
+// encodeValue converts a protobuf message into a value which marshals to the JSON
+// representation used by the standard API: integers are encoded as decimal strings
+// and byte slices, including bitfields, as 0x-prefixed hex strings.
+func encodeValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		t := v.Type()
+		obj := make(map[string]interface{}, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := jsonName(t, t.Field(i))
+			if !ok {
+				continue
+			}
+			obj[name] = encodeValue(v.Field(i))
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if v.Kind() == reflect.Array {
+				b := make([]byte, v.Len())
+				reflect.Copy(reflect.ValueOf(b), v)
+				return hexutil.Encode(b)
+			}
+			return hexutil.Encode(v.Bytes())
+		}
+		items := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items[i] = encodeValue(v.Index(i))
+		}
+		return items
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return strings.ToLower(s.String())
+		}
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return v.Interface()
+	}
+}
+
+// encode returns the standard API JSON representation of a protobuf message.
+func encode(msg interface{}) interface{} {
+	return encodeValue(reflect.ValueOf(msg))
+}
+
+// decode reads a standard API JSON document from r into the protobuf message
+// or slice of messages pointed to by dst.
+func decode(r io.Reader, dst interface{}) error {
+	var data interface{}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return errors.Wrap(err, "could not decode request body")
+	}
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("decode destination must be a non-nil pointer")
+	}
+	return decodeValue(data, v.Elem())
+}
+
+func decodeValue(data interface{}, v reflect.Value) error {
+	if data == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(data, v.Elem())
+	case reflect.Struct:
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected object for %s", v.Type().Name())
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := jsonName(t, t.Field(i))
+			if !ok {
+				continue
+			}
+			raw, ok := obj[name]
+			if !ok {
+				continue
+			}
+			if err := decodeValue(raw, v.Field(i)); err != nil {
+				return errors.Wrapf(err, "invalid field %s", name)
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := data.(string)
+			if !ok {
+				return errors.New("expected hex string")
+			}
+			b, err := hexutil.Decode(s)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		items, ok := data.([]interface{})
+		if !ok {
+			return errors.New("expected array")
+		}
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, slice.Index(i)); err != nil {
+				return errors.Wrapf(err, "invalid item %d", i)
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(numberString(data), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(numberString(data), 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Bool:
+		b, ok := data.(bool)
+		if !ok {
+			return errors.New("expected boolean")
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.String:
+		s, ok := data.(string)
+		if !ok {
+			return errors.New("expected string")
+		}
+		v.SetString(s)
+		return nil
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// numberString accepts both quoted and bare JSON integers.
+func numberString(data interface{}) string {
+	switch n := data.(type) {
+	case string:
+		return n
+	case json.Number:
+		return n.String()
+	default:
+		return fmt.Sprint(n)
+	}
+}
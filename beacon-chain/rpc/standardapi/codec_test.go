@@ -0,0 +1,88 @@
+package standardapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+func TestEncode_UsesStandardFieldNames(t *testing.T) {
+	blk := &ethpb.SignedBeaconBlock{
+		Block: &ethpb.BeaconBlock{
+			Slot:       5,
+			ParentRoot: []byte{0xab, 0xcd},
+			Body: &ethpb.BeaconBlockBody{
+				Attestations: []*ethpb.Attestation{{
+					AggregationBits: bitfield.Bitlist{0x03},
+					Data:            &ethpb.AttestationData{CommitteeIndex: 2},
+				}},
+			},
+		},
+		Signature: []byte{0x01},
+	}
+	enc, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"message":{`,
+		`"slot":"5"`,
+		`"parent_root":"0xabcd"`,
+		`"aggregation_bits":"0x03"`,
+		`"index":"2"`,
+		`"signature":"0x01"`,
+	} {
+		if !strings.Contains(string(enc), want) {
+			t.Errorf("Expected %s in %s", want, enc)
+		}
+	}
+	if strings.Contains(string(enc), "XXX_") {
+		t.Errorf("Internal protobuf fields were encoded: %s", enc)
+	}
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	slashing := &ethpb.ProposerSlashing{
+		Header_1: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{Slot: 1, ProposerIndex: 3, BodyRoot: []byte{0x01}},
+			Signature: []byte{0x02},
+		},
+		Header_2: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{Slot: 1, ProposerIndex: 3, BodyRoot: []byte{0x03}},
+			Signature: []byte{0x04},
+		},
+	}
+	enc, err := json.Marshal(encode(slashing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(enc), `"signed_header_1"`) {
+		t.Errorf("Expected signed_header_1 in %s", enc)
+	}
+	decoded := &ethpb.ProposerSlashing{}
+	if err := decode(bytes.NewReader(enc), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(slashing, decoded) {
+		t.Errorf("Wanted %v, received %v", slashing, decoded)
+	}
+}
+
+func TestDecode_AcceptsNumbersAndRejectsBadHex(t *testing.T) {
+	var indices []uint64
+	if err := decode(strings.NewReader(`["1", 2]`), &indices); err != nil {
+		t.Fatal(err)
+	}
+	if len(indices) != 2 || indices[0] != 1 || indices[1] != 2 {
+		t.Errorf("Unexpected indices %v", indices)
+	}
+	att := &ethpb.Attestation{}
+	if err := decode(strings.NewReader(`{"signature": "zz"}`), att); err == nil {
+		t.Error("Expected error decoding invalid hex")
+	}
+}
@@ -0,0 +1,380 @@
+package standardapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+const (
+	topicHead                = "head"
+	topicBlock               = "block"
+	topicAttestation         = "attestation"
+	topicVoluntaryExit       = "voluntary_exit"
+	topicFinalizedCheckpoint = "finalized_checkpoint"
+	topicChainReorg          = "chain_reorg"
+
+	// subscriberBufferSize is the number of events buffered for each subscriber
+	// before it is considered too slow and dropped.
+	subscriberBufferSize = 256
+	// keepaliveInterval is how often an idle stream receives a comment line so that
+	// proxies and load balancers do not close the connection.
+	keepaliveInterval = 15 * time.Second
+	// maxReorgWalk bounds the number of blocks walked back to find the common
+	// ancestor of the old and new head of a reorg.
+	maxReorgWalk = 64
+)
+
+var eventTopics = map[string]bool{
+	topicHead:                true,
+	topicBlock:               true,
+	topicAttestation:         true,
+	topicVoluntaryExit:       true,
+	topicFinalizedCheckpoint: true,
+	topicChainReorg:          true,
+}
+
+type sseEvent struct {
+	topic string
+	data  interface{}
+}
+
+type subscriber struct {
+	topics  map[string]bool
+	events  chan *sseEvent
+	dropped chan struct{}
+}
+
+// eventBroker fans out events from the beacon node's internal feeds to the
+// subscribers of the event stream. Every subscriber has its own buffer, and a
+// subscriber whose buffer is full is dropped rather than blocking the feeds.
+type eventBroker struct {
+	server      *Server
+	lock        sync.RWMutex
+	subscribers map[*subscriber]bool
+	done        chan struct{} // Closed when the broker stops, ending every stream.
+}
+
+func newEventBroker(s *Server) *eventBroker {
+	return &eventBroker{
+		server:      s,
+		subscribers: make(map[*subscriber]bool),
+		done:        make(chan struct{}),
+	}
+}
+
+func (b *eventBroker) subscribe(topics []string) *subscriber {
+	sub := &subscriber{
+		topics:  make(map[string]bool, len(topics)),
+		events:  make(chan *sseEvent, subscriberBufferSize),
+		dropped: make(chan struct{}),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = true
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[sub] = true
+	eventSubscribersGauge.Set(float64(len(b.subscribers)))
+	return sub
+}
+
+func (b *eventBroker) unsubscribe(sub *subscriber) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.subscribers, sub)
+	eventSubscribersGauge.Set(float64(len(b.subscribers)))
+}
+
+// hasSubscribers returns true if anyone is subscribed to the topic, so the cost of
+// building events nobody listens to can be avoided.
+func (b *eventBroker) hasSubscribers(topic string) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for sub := range b.subscribers {
+		if sub.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *eventBroker) publish(topic string, data interface{}) {
+	ev := &sseEvent{topic: topic, data: data}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for sub := range b.subscribers {
+		if !sub.topics[topic] {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.dropped)
+			droppedSubscribersCount.Inc()
+			log.WithField("topic", topic).Warn("Dropping slow event stream subscriber")
+		}
+	}
+	eventSubscribersGauge.Set(float64(len(b.subscribers)))
+	eventsPublishedCount.WithLabelValues(topic).Inc()
+}
+
+// run forwards events from the state and operation feeds until the context is canceled.
+func (b *eventBroker) run(ctx context.Context) {
+	defer close(b.done)
+	s := b.server
+	stateChannel := make(chan *feed.Event, params.BeaconConfig().DefaultBufferSize)
+	stateSub := s.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	opsChannel := make(chan *feed.Event, params.BeaconConfig().DefaultBufferSize)
+	opsSub := s.OperationNotifier.OperationFeed().Subscribe(opsChannel)
+	defer opsSub.Unsubscribe()
+
+	var finalizedEpoch uint64
+	if cp := s.FinalizationFetcher.FinalizedCheckpt(); cp != nil {
+		finalizedEpoch = cp.Epoch
+	}
+	for {
+		select {
+		case ev := <-stateChannel:
+			switch ev.Type {
+			case statefeed.BlockProcessed:
+				data, ok := ev.Data.(*statefeed.BlockProcessedData)
+				if !ok {
+					continue
+				}
+				if err := b.handleBlockProcessed(ctx, data); err != nil {
+					log.WithError(err).Error("Could not publish block events")
+				}
+				if cp := s.FinalizationFetcher.FinalizedCheckpt(); cp != nil && cp.Epoch > finalizedEpoch {
+					finalizedEpoch = cp.Epoch
+					if err := b.handleFinalized(ctx, cp.Epoch, bytesutil.ToBytes32(cp.Root)); err != nil {
+						log.WithError(err).Error("Could not publish finalized checkpoint event")
+					}
+				}
+			case statefeed.Reorg:
+				data, ok := ev.Data.(*statefeed.ReorgData)
+				if !ok {
+					continue
+				}
+				if err := b.handleReorg(ctx, data); err != nil {
+					log.WithError(err).Error("Could not publish chain reorg event")
+				}
+			}
+		case ev := <-opsChannel:
+			switch ev.Type {
+			case opfeed.UnaggregatedAttReceived:
+				if data, ok := ev.Data.(*opfeed.UnAggregatedAttReceivedData); ok && b.hasSubscribers(topicAttestation) {
+					b.publish(topicAttestation, encode(data.Attestation))
+				}
+			case opfeed.AggregatedAttReceived:
+				if data, ok := ev.Data.(*opfeed.AggregatedAttReceivedData); ok && b.hasSubscribers(topicAttestation) {
+					b.publish(topicAttestation, encode(data.Attestation.Aggregate))
+				}
+			case opfeed.ExitReceived:
+				if data, ok := ev.Data.(*opfeed.ExitReceivedData); ok && b.hasSubscribers(topicVoluntaryExit) {
+					b.publish(topicVoluntaryExit, encode(data.Exit))
+				}
+			}
+		case <-stateSub.Err():
+			log.Debug("State feed subscription closed")
+			return
+		case <-opsSub.Err():
+			log.Debug("Operation feed subscription closed")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *eventBroker) handleBlockProcessed(ctx context.Context, data *statefeed.BlockProcessedData) error {
+	if b.hasSubscribers(topicBlock) {
+		b.publish(topicBlock, map[string]interface{}{
+			"slot":  strconv.FormatUint(data.Slot, 10),
+			"block": hexutil.Encode(data.BlockRoot[:]),
+		})
+	}
+	if !b.hasSubscribers(topicHead) {
+		return nil
+	}
+	headRoot, err := b.server.HeadFetcher.HeadRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve head root")
+	}
+	if bytesutil.ToBytes32(headRoot) != data.BlockRoot {
+		return nil
+	}
+	headBlock, err := b.server.HeadFetcher.HeadBlock(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve head block")
+	}
+	if headBlock == nil || headBlock.Block == nil {
+		return errors.New("head block is nil")
+	}
+	b.publish(topicHead, map[string]interface{}{
+		"slot":             strconv.FormatUint(data.Slot, 10),
+		"block":            hexutil.Encode(data.BlockRoot[:]),
+		"state":            hexutil.Encode(headBlock.Block.StateRoot),
+		"epoch_transition": helpers.IsEpochStart(data.Slot),
+	})
+	return nil
+}
+
+func (b *eventBroker) handleFinalized(ctx context.Context, epoch uint64, root [32]byte) error {
+	if !b.hasSubscribers(topicFinalizedCheckpoint) {
+		return nil
+	}
+	blk, err := b.server.BeaconDB.Block(ctx, root)
+	if err != nil {
+		return errors.Wrap(err, "could not retrieve finalized block")
+	}
+	if blk == nil || blk.Block == nil {
+		return errors.New("finalized block is nil")
+	}
+	b.publish(topicFinalizedCheckpoint, map[string]interface{}{
+		"block": hexutil.Encode(root[:]),
+		"state": hexutil.Encode(blk.Block.StateRoot),
+		"epoch": strconv.FormatUint(epoch, 10),
+	})
+	return nil
+}
+
+func (b *eventBroker) handleReorg(ctx context.Context, data *statefeed.ReorgData) error {
+	if !b.hasSubscribers(topicChainReorg) {
+		return nil
+	}
+	ancestorSlot, err := b.commonAncestorSlot(ctx, data.OldHeadRoot, data.NewHeadRoot)
+	if err != nil {
+		return err
+	}
+	depth := uint64(0)
+	if data.OldSlot > ancestorSlot {
+		depth = data.OldSlot - ancestorSlot
+	}
+	b.publish(topicChainReorg, map[string]interface{}{
+		"slot":           strconv.FormatUint(data.NewSlot, 10),
+		"depth":          strconv.FormatUint(depth, 10),
+		"old_head_block": hexutil.Encode(data.OldHeadRoot[:]),
+		"new_head_block": hexutil.Encode(data.NewHeadRoot[:]),
+		"epoch":          strconv.FormatUint(helpers.SlotToEpoch(data.NewSlot), 10),
+	})
+	return nil
+}
+
+// commonAncestorSlot walks back from both heads of a reorg until their chains meet.
+func (b *eventBroker) commonAncestorSlot(ctx context.Context, oldRoot [32]byte, newRoot [32]byte) (uint64, error) {
+	db := b.server.BeaconDB
+	oldBlk, err := db.Block(ctx, oldRoot)
+	if err != nil {
+		return 0, err
+	}
+	newBlk, err := db.Block(ctx, newRoot)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < maxReorgWalk; i++ {
+		if oldBlk == nil || oldBlk.Block == nil || newBlk == nil || newBlk.Block == nil {
+			return 0, errors.New("could not find common ancestor of reorg")
+		}
+		if oldRoot == newRoot {
+			return oldBlk.Block.Slot, nil
+		}
+		if oldBlk.Block.Slot >= newBlk.Block.Slot {
+			oldRoot = bytesutil.ToBytes32(oldBlk.Block.ParentRoot)
+			if oldBlk, err = db.Block(ctx, oldRoot); err != nil {
+				return 0, err
+			}
+		} else {
+			newRoot = bytesutil.ToBytes32(newBlk.Block.ParentRoot)
+			if newBlk, err = db.Block(ctx, newRoot); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return 0, fmt.Errorf("reorg is deeper than %d blocks", maxReorgWalk)
+}
+
+// streamEvents serves the requested topics as server-sent events until the client
+// disconnects or falls too far behind, or the server stops.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, _ params) {
+	topics := queryList(r, "topics")
+	if len(topics) == 0 {
+		writeError(w, badRequest("No topics requested"))
+		return
+	}
+	for _, topic := range topics {
+		if !eventTopics[topic] {
+			writeError(w, badRequest("Invalid topic %s, expected one of: %s", topic, eventTopicList()))
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, newAPIError(http.StatusInternalServerError, "Streaming is not supported"))
+		return
+	}
+	sub := s.broker.subscribe(topics)
+	defer s.broker.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev := <-sub.events:
+			enc, err := json.Marshal(ev.data)
+			if err != nil {
+				log.WithError(err).Error("Could not marshal event")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.topic, enc); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ":\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-sub.dropped:
+			return
+		case <-s.broker.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// eventTopicList is used in error messages listing the supported topics.
+func eventTopicList() string {
+	topics := make([]string, 0, len(eventTopics))
+	for topic := range eventTopics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return strings.Join(topics, ", ")
+}
@@ -0,0 +1,193 @@
+package standardapi
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+)
+
+func TestEventBroker_FiltersTopics(t *testing.T) {
+	b := newEventBroker(&Server{})
+	sub := b.subscribe([]string{topicHead})
+	defer b.unsubscribe(sub)
+
+	b.publish(topicBlock, "block")
+	b.publish(topicHead, "head")
+	select {
+	case ev := <-sub.events:
+		if ev.topic != topicHead {
+			t.Errorf("Wanted topic %s, received %s", topicHead, ev.topic)
+		}
+	default:
+		t.Fatal("Expected a head event")
+	}
+	if len(sub.events) != 0 {
+		t.Errorf("Expected no further events, have %d", len(sub.events))
+	}
+}
+
+func TestEventBroker_DropsSlowSubscriber(t *testing.T) {
+	b := newEventBroker(&Server{})
+	slow := b.subscribe([]string{topicBlock})
+	fast := b.subscribe([]string{topicBlock})
+	defer b.unsubscribe(fast)
+
+	for i := 0; i < subscriberBufferSize+1; i++ {
+		b.publish(topicBlock, i)
+		if i < subscriberBufferSize {
+			<-fast.events
+		}
+	}
+	select {
+	case <-slow.dropped:
+	default:
+		t.Fatal("Expected slow subscriber to be dropped")
+	}
+	select {
+	case <-fast.dropped:
+		t.Fatal("Did not expect fast subscriber to be dropped")
+	default:
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	if b.subscribers[slow] || !b.subscribers[fast] {
+		t.Error("Unexpected subscriber set after dropping slow subscriber")
+	}
+}
+
+func TestServer_StreamEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chain := &mock.ChainService{}
+	stateFeed := chain.StateNotifier().StateFeed()
+	opFeed := chain.OperationNotifier().OperationFeed()
+	s := &Server{
+		Ctx:                 ctx,
+		HeadFetcher:         chain,
+		FinalizationFetcher: chain,
+		StateNotifier:       chain.StateNotifier(),
+		OperationNotifier:   chain.OperationNotifier(),
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/eth/v1/events?topics=unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("Wanted status %d for unknown topic, received %d", http.StatusBadRequest, res.StatusCode)
+	}
+	if err := res.Body.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = http.Get(srv.URL + "/eth/v1/events?topics=block,voluntary_exit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Wanted content type text/event-stream, received %s", ct)
+	}
+
+	// The broker subscribes to the feeds asynchronously, so wait for it.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sent := stateFeed.Send(&feed.Event{
+			Type: statefeed.BlockProcessed,
+			Data: &statefeed.BlockProcessedData{Slot: 7, BlockRoot: [32]byte{0x0a}},
+		})
+		if sent > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Event broker did not subscribe to the state feed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	opFeed.Send(&feed.Event{
+		Type: opfeed.ExitReceived,
+		Data: &opfeed.ExitReceivedData{Exit: &ethpb.SignedVoluntaryExit{
+			Exit: &ethpb.VoluntaryExit{Epoch: 2, ValidatorIndex: 3},
+		}},
+	})
+
+	reader := bufio.NewReader(res.Body)
+	var lines []string
+	for len(lines) < 4 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{
+		"event: block",
+		`data: {"block":"0x0a00000000000000000000000000000000000000000000000000000000000000","slot":"7"}`,
+		"event: voluntary_exit",
+		`data: {"message":{"epoch":"2","validator_index":"3"},"signature":"0x"}`,
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("Wanted line %q, received %q", want[i], lines[i])
+		}
+	}
+}
+
+func TestServer_StreamEvents_EndsWhenServerStops(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chain := &mock.ChainService{}
+	s := &Server{
+		Ctx:                 ctx,
+		HeadFetcher:         chain,
+		FinalizationFetcher: chain,
+		StateNotifier:       chain.StateNotifier(),
+		OperationNotifier:   chain.OperationNotifier(),
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/eth/v1/events?topics=head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := ioutil.ReadAll(res.Body)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Event stream did not end when the server stopped")
+	}
+}
@@ -0,0 +1,201 @@
+package standardapi
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// stateByID resolves a state identifier, which is one of head, genesis, finalized,
+// justified, a slot number or a 0x-prefixed state root.
+func (s *Server) stateByID(ctx context.Context, id string) (*stateTrie.BeaconState, error) {
+	var st *stateTrie.BeaconState
+	var err error
+	switch id {
+	case "head":
+		st, err = s.HeadFetcher.HeadState(ctx)
+	case "genesis":
+		st, err = s.BeaconDB.GenesisState(ctx)
+	case "finalized":
+		st, err = s.stateByCheckpoint(ctx, s.FinalizationFetcher.FinalizedCheckpt())
+	case "justified":
+		st, err = s.stateByCheckpoint(ctx, s.FinalizationFetcher.CurrentJustifiedCheckpt())
+	default:
+		if strings.HasPrefix(id, "0x") {
+			root, decodeErr := decodeRoot(id)
+			if decodeErr != nil {
+				return nil, decodeErr
+			}
+			st, err = s.stateByRoot(ctx, root)
+			break
+		}
+		slot, parseErr := strconv.ParseUint(id, 10, 64)
+		if parseErr != nil {
+			return nil, badRequest("Invalid state ID: %s", id)
+		}
+		if slot > s.HeadFetcher.HeadSlot() {
+			return nil, notFound("State not found for slot %d", slot)
+		}
+		st, err = s.StateGen.StateBySlot(ctx, slot)
+	}
+	if err != nil {
+		if _, ok := err.(*apiError); ok {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "could not retrieve state")
+	}
+	if st == nil {
+		return nil, notFound("State not found")
+	}
+	return st, nil
+}
+
+func (s *Server) stateByCheckpoint(ctx context.Context, cp *ethpb.Checkpoint) (*stateTrie.BeaconState, error) {
+	if cp == nil || bytes.Equal(cp.Root, params.BeaconConfig().ZeroHash[:]) {
+		return s.BeaconDB.GenesisState(ctx)
+	}
+	return s.StateGen.StateByRoot(ctx, bytesutil.ToBytes32(cp.Root))
+}
+
+// stateByRoot looks up a state by its state root. Only the head state and the
+// states whose roots are still recorded in the head state's state roots can be
+// resolved this way.
+func (s *Server) stateByRoot(ctx context.Context, root []byte) (*stateTrie.BeaconState, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headRoot, err := headState.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(headRoot[:], root) {
+		return headState, nil
+	}
+	roots := headState.StateRoots()
+	historyLen := uint64(len(roots))
+	headSlot := headState.Slot()
+	lowest := uint64(0)
+	if headSlot > historyLen {
+		lowest = headSlot - historyLen
+	}
+	for slot := headSlot; slot > lowest; slot-- {
+		if bytes.Equal(roots[(slot-1)%historyLen], root) {
+			return s.StateGen.StateBySlot(ctx, slot-1)
+		}
+	}
+	return nil, notFound("State not found for root %#x", root)
+}
+
+// blockByID resolves a block identifier, which is one of head, genesis, finalized,
+// a slot number or a 0x-prefixed block root.
+func (s *Server) blockByID(ctx context.Context, id string) (*ethpb.SignedBeaconBlock, error) {
+	var blk *ethpb.SignedBeaconBlock
+	var err error
+	switch id {
+	case "head":
+		blk, err = s.HeadFetcher.HeadBlock(ctx)
+	case "genesis":
+		blk, err = s.BeaconDB.GenesisBlock(ctx)
+	case "finalized":
+		cp := s.FinalizationFetcher.FinalizedCheckpt()
+		if cp == nil || bytes.Equal(cp.Root, params.BeaconConfig().ZeroHash[:]) {
+			blk, err = s.BeaconDB.GenesisBlock(ctx)
+			break
+		}
+		blk, err = s.BeaconDB.Block(ctx, bytesutil.ToBytes32(cp.Root))
+	default:
+		if strings.HasPrefix(id, "0x") {
+			root, decodeErr := decodeRoot(id)
+			if decodeErr != nil {
+				return nil, decodeErr
+			}
+			blk, err = s.BeaconDB.Block(ctx, bytesutil.ToBytes32(root))
+			break
+		}
+		slot, parseErr := strconv.ParseUint(id, 10, 64)
+		if parseErr != nil {
+			return nil, badRequest("Invalid block ID: %s", id)
+		}
+		blk, err = s.canonicalBlockAtSlot(ctx, slot)
+	}
+	if err != nil {
+		if _, ok := err.(*apiError); ok {
+			return nil, err
+		}
+		return nil, errors.Wrap(err, "could not retrieve block")
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, notFound("Block not found")
+	}
+	return blk, nil
+}
+
+// canonicalBlockAtSlot returns the block at the given slot on the chain of the current
+// head, or nil if the slot was skipped.
+func (s *Server) canonicalBlockAtSlot(ctx context.Context, slot uint64) (*ethpb.SignedBeaconBlock, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	headSlot := headState.Slot()
+	if slot > headSlot {
+		return nil, nil
+	}
+	if slot == headSlot {
+		blk, err := s.HeadFetcher.HeadBlock(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if blk != nil && blk.Block != nil && blk.Block.Slot == slot {
+			return blk, nil
+		}
+		return nil, nil
+	}
+	if headSlot-slot <= params.BeaconConfig().SlotsPerHistoricalRoot {
+		root, err := headState.BlockRootAtIndex(slot % params.BeaconConfig().SlotsPerHistoricalRoot)
+		if err != nil {
+			return nil, err
+		}
+		blk, err := s.BeaconDB.Block(ctx, bytesutil.ToBytes32(root))
+		if err != nil {
+			return nil, err
+		}
+		if blk == nil || blk.Block == nil || blk.Block.Slot != slot {
+			return nil, nil
+		}
+		return blk, nil
+	}
+	// Older slots are finalized, so the only block stored at the slot is canonical.
+	blks, err := s.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+	if err != nil {
+		return nil, err
+	}
+	for _, blk := range blks {
+		root, err := blockRoot(blk)
+		if err != nil {
+			return nil, err
+		}
+		if s.BeaconDB.IsFinalizedBlock(ctx, root) {
+			return blk, nil
+		}
+	}
+	return nil, nil
+}
+
+func decodeRoot(id string) ([]byte, error) {
+	root, err := hexutil.Decode(id)
+	if err != nil || len(root) != 32 {
+		return nil, badRequest("Invalid root: %s", id)
+	}
+	return root, nil
+}
@@ -0,0 +1,5 @@
+package standardapi
+
+import "github.com/sirupsen/logrus"
+
+var log = logrus.WithField("prefix", "standard-api")
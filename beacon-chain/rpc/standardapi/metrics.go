@@ -0,0 +1,21 @@
+package standardapi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	eventSubscribersGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "standard_api_event_subscribers",
+		Help: "The number of clients subscribed to the event stream.",
+	})
+	droppedSubscribersCount = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "standard_api_event_subscribers_dropped_total",
+		Help: "The number of event stream subscribers dropped for not keeping up with events.",
+	})
+	eventsPublishedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "standard_api_events_published_total",
+		Help: "The number of events published to the event stream, by topic.",
+	}, []string{"topic"})
+)
@@ -0,0 +1,101 @@
+package standardapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func (s *Server) registerNodeRoutes(rt *router) {
+	rt.get("/eth/v1/node/identity", s.getIdentity)
+	rt.get("/eth/v1/node/peers", s.listPeers)
+	rt.get("/eth/v1/node/peers/{peer_id}", s.getPeer)
+	rt.get("/eth/v1/node/version", s.getVersion)
+	rt.get("/eth/v1/node/syncing", s.getSyncStatus)
+	rt.add(http.MethodGet, "/eth/v1/node/health", s.getHealth)
+}
+
+func (s *Server) getIdentity(r *http.Request, _ params) (interface{}, error) {
+	host, err := s.NodeServer.GetHost(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{
+		"peer_id":             host.PeerId,
+		"enr":                 host.Enr,
+		"p2p_addresses":       host.Addresses,
+		"discovery_addresses": []string{},
+	}
+	if s.MetadataProvider != nil {
+		if md := s.MetadataProvider.Metadata(); md != nil {
+			res["metadata"] = map[string]interface{}{
+				"seq_number": strconv.FormatUint(md.SeqNumber, 10),
+				"attnets":    hexutil.Encode(md.Attnets),
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) listPeers(r *http.Request, _ params) (interface{}, error) {
+	peers, err := s.NodeServer.ListPeers(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]interface{}, len(peers.Peers))
+	for i, p := range peers.Peers {
+		res[i] = peerResponse(p)
+	}
+	return res, nil
+}
+
+func (s *Server) getPeer(r *http.Request, p params) (interface{}, error) {
+	peer, err := s.NodeServer.GetPeer(r.Context(), &ethpb.PeerRequest{PeerId: p["peer_id"]})
+	if err != nil {
+		return nil, err
+	}
+	return peerResponse(peer), nil
+}
+
+func peerResponse(p *ethpb.Peer) interface{} {
+	return map[string]interface{}{
+		"peer_id":               p.PeerId,
+		"enr":                   p.Enr,
+		"last_seen_p2p_address": p.Address,
+		"state":                 strings.ToLower(p.ConnectionState.String()),
+		"direction":             strings.ToLower(p.Direction.String()),
+	}
+}
+
+func (s *Server) getVersion(r *http.Request, _ params) (interface{}, error) {
+	v, err := s.NodeServer.GetVersion(r.Context(), &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"version": v.Version}, nil
+}
+
+func (s *Server) getSyncStatus(r *http.Request, _ params) (interface{}, error) {
+	headSlot := s.HeadFetcher.HeadSlot()
+	distance := uint64(0)
+	if currentSlot := s.GenesisTimeFetcher.CurrentSlot(); currentSlot > headSlot {
+		distance = currentSlot - headSlot
+	}
+	return map[string]interface{}{
+		"head_slot":     strconv.FormatUint(headSlot, 10),
+		"sync_distance": strconv.FormatUint(distance, 10),
+	}, nil
+}
+
+// getHealth responds with 200 once the node is synced and 206 while it is syncing.
+func (s *Server) getHealth(w http.ResponseWriter, _ *http.Request, _ params) {
+	if s.SyncChecker.Syncing() {
+		w.WriteHeader(http.StatusPartialContent)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
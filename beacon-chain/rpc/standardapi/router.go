@@ -0,0 +1,72 @@
+package standardapi
+
+import (
+	"net/http"
+	"strings"
+)
+
+// params holds the values of the named path segments of a matched route.
+type params map[string]string
+
+type route struct {
+	method   string
+	segments []string
+	handle   func(w http.ResponseWriter, r *http.Request, p params)
+}
+
+// router is a minimal path router supporting named segments in the form
+// of {name}, which is all the standard API paths require.
+type router struct {
+	routes []*route
+}
+
+func (rt *router) add(method string, path string, handle func(w http.ResponseWriter, r *http.Request, p params)) {
+	rt.routes = append(rt.routes, &route{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		handle:   handle,
+	})
+}
+
+// ServeHTTP dispatches the request to the first route matching its method and path.
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathMatched := false
+	for _, rte := range rt.routes {
+		p, ok := rte.match(segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rte.method != r.Method {
+			continue
+		}
+		rte.handle(w, r, p)
+		return
+	}
+	if pathMatched {
+		writeError(w, newAPIError(http.StatusMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	writeError(w, newAPIError(http.StatusNotFound, "Endpoint not found"))
+}
+
+func (rte *route) match(segments []string) (params, bool) {
+	if len(segments) != len(rte.segments) {
+		return nil, false
+	}
+	p := make(params)
+	for i, seg := range rte.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			p[seg[1:len(seg)-1]] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
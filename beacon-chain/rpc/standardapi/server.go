@@ -0,0 +1,153 @@
+// Package standardapi implements the standardized Eth2 beacon node REST API
+// (/eth/v1/...) on top of the beacon node's gRPC server implementations.
+package standardapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/validator"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the standard Eth2 REST API. Requests are answered from the
+// same beacon, node and validator gRPC servers used by the v1alpha1 API, with
+// state and block identifiers resolved through the database and stategen.
+type Server struct {
+	Ctx                 context.Context
+	BeaconServer        *beacon.Server
+	NodeServer          *node.Server
+	ValidatorServer     *validator.Server
+	BeaconDB            db.ReadOnlyDatabase
+	HeadFetcher         blockchain.HeadFetcher
+	FinalizationFetcher blockchain.FinalizationFetcher
+	GenesisTimeFetcher  blockchain.TimeFetcher
+	StateGen            *stategen.State
+	AttestationsPool    attestations.Pool
+	SlashingsPool       *slashings.Pool
+	ExitPool            *voluntaryexits.Pool
+	SyncChecker         sync.Checker
+	MetadataProvider    p2p.MetadataProvider
+	StateNotifier       statefeed.Notifier
+	OperationNotifier   opfeed.Notifier
+	broker              *eventBroker
+}
+
+// handler is an API endpoint returning the value of the response's data field.
+type handler func(r *http.Request, p params) (interface{}, error)
+
+// Handler returns the HTTP handler serving all standard API endpoints. If the
+// state and operation notifiers are set, it also starts forwarding their events
+// to the event stream until the server's context is canceled.
+func (s *Server) Handler() http.Handler {
+	rt := &router{}
+	s.registerBeaconRoutes(rt)
+	s.registerNodeRoutes(rt)
+	s.registerValidatorRoutes(rt)
+	if s.StateNotifier != nil && s.OperationNotifier != nil {
+		s.broker = newEventBroker(s)
+		go s.broker.run(s.Ctx)
+		rt.add(http.MethodGet, "/eth/v1/events", s.streamEvents)
+	}
+	return rt
+}
+
+// get registers a JSON endpoint for GET requests.
+func (rt *router) get(path string, h handler) {
+	rt.add(http.MethodGet, path, serve(h))
+}
+
+// post registers a JSON endpoint for POST requests.
+func (rt *router) post(path string, h handler) {
+	rt.add(http.MethodPost, path, serve(h))
+}
+
+func serve(h handler) func(w http.ResponseWriter, r *http.Request, p params) {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		data, err := h(r, p)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if data == nil {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+	}
+}
+
+// apiError is an error carrying the HTTP status code to respond with.
+type apiError struct {
+	code    int
+	message string
+}
+
+func newAPIError(code int, format string, args ...interface{}) *apiError {
+	return &apiError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return newAPIError(http.StatusBadRequest, format, args...)
+}
+
+func notFound(format string, args ...interface{}) error {
+	return newAPIError(http.StatusNotFound, format, args...)
+}
+
+// httpStatus maps errors returned by handlers and by the gRPC servers to HTTP
+// status codes.
+func httpStatus(err error) (int, string) {
+	if e, ok := err.(*apiError); ok {
+		return e.code, e.message
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError, err.Error()
+	}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest, st.Message()
+	case codes.NotFound:
+		return http.StatusNotFound, st.Message()
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable, st.Message()
+	default:
+		return http.StatusInternalServerError, st.Message()
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code, msg := httpStatus(err)
+	writeJSON(w, code, map[string]interface{}{
+		"code":    code,
+		"message": msg,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
@@ -0,0 +1,153 @@
+package standardapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func newTestServer(t *testing.T) *Server {
+	db := dbTest.SetupDB(t)
+	headState, _ := testutil.DeterministicGenesisState(t, 16)
+	if err := headState.SetSlot(3); err != nil {
+		t.Fatal(err)
+	}
+	chain := &mock.ChainService{State: headState}
+	return &Server{
+		BeaconDB:            db,
+		HeadFetcher:         chain,
+		FinalizationFetcher: chain,
+		GenesisTimeFetcher:  chain,
+		StateGen:            stategen.New(db, cache.NewStateSummaryCache()),
+		SyncChecker:         &mockSync.Sync{IsSyncing: false},
+	}
+}
+
+func doRequest(t *testing.T, s *Server, method string, path string) (int, map[string]interface{}) {
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	res := make(map[string]interface{})
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatalf("Could not decode response %q: %v", rec.Body.String(), err)
+		}
+	}
+	return rec.Code, res
+}
+
+func TestServer_StateRoot_ByID(t *testing.T) {
+	s := newTestServer(t)
+	headState, err := s.HeadFetcher.HeadState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	headRoot, err := headState.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hexutil.Encode(headRoot[:])
+
+	for _, id := range []string{"head", want} {
+		code, res := doRequest(t, s, http.MethodGet, fmt.Sprintf("/eth/v1/beacon/states/%s/root", id))
+		if code != http.StatusOK {
+			t.Fatalf("Unexpected status %d for state ID %s: %v", code, id, res)
+		}
+		data, ok := res["data"].(map[string]interface{})
+		if !ok || data["root"] != want {
+			t.Errorf("Wanted root %s for state ID %s, received %v", want, id, res)
+		}
+	}
+
+	unknown := hexutil.Encode(bytes.Repeat([]byte{0x01}, 32))
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/"+unknown+"/root"); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d for unknown root, received %d", http.StatusNotFound, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/foo/root"); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d for invalid state ID, received %d", http.StatusBadRequest, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/100/root"); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d for future slot, received %d", http.StatusNotFound, code)
+	}
+}
+
+func TestServer_BlockRoot_InvalidOrUnknownID(t *testing.T) {
+	s := newTestServer(t)
+
+	unknown := hexutil.Encode(bytes.Repeat([]byte{0x01}, 32))
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/"+unknown+"/root"); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d for unknown root, received %d", http.StatusNotFound, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/0x01/root"); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d for invalid root, received %d", http.StatusBadRequest, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/foo/root"); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d for invalid block ID, received %d", http.StatusBadRequest, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/100/root"); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d for future slot, received %d", http.StatusNotFound, code)
+	}
+}
+
+func TestServer_ListValidators_FilterByID(t *testing.T) {
+	s := newTestServer(t)
+	headState, err := s.HeadFetcher.HeadState(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey := headState.PubkeyAtIndex(5)
+	path := fmt.Sprintf("/eth/v1/beacon/states/head/validators?id=1,%s&id=99", hexutil.Encode(pubkey[:]))
+	code, res := doRequest(t, s, http.MethodGet, path)
+	if code != http.StatusOK {
+		t.Fatalf("Unexpected status %d: %v", code, res)
+	}
+	vals, ok := res["data"].([]interface{})
+	if !ok || len(vals) != 2 {
+		t.Fatalf("Wanted 2 validators, received %v", res["data"])
+	}
+	for i, wantIndex := range []string{"1", "5"} {
+		val := vals[i].(map[string]interface{})
+		if val["index"] != wantIndex {
+			t.Errorf("Wanted index %s, received %v", wantIndex, val["index"])
+		}
+		if val["status"] != "active_ongoing" {
+			t.Errorf("Wanted status active_ongoing, received %v", val["status"])
+		}
+	}
+	validator := vals[1].(map[string]interface{})["validator"].(map[string]interface{})
+	if validator["pubkey"] != hexutil.Encode(pubkey[:]) {
+		t.Errorf("Wanted pubkey %#x, received %v", pubkey, validator["pubkey"])
+	}
+}
+
+func TestServer_Routing(t *testing.T) {
+	s := newTestServer(t)
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/unknown"); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodPost, "/eth/v1/node/version"); code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted status %d, received %d", http.StatusMethodNotAllowed, code)
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	s := newTestServer(t)
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/node/health"); code != http.StatusOK {
+		t.Errorf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	s.SyncChecker = &mockSync.Sync{IsSyncing: true}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/node/health"); code != http.StatusPartialContent {
+		t.Errorf("Wanted status %d, received %d", http.StatusPartialContent, code)
+	}
+}
@@ -0,0 +1,260 @@
+package standardapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+// committeeSubscription is a single item of a beacon committee subscription request.
+type committeeSubscription struct {
+	ValidatorIndex   uint64 `json:"validator_index"`
+	CommitteeIndex   uint64 `json:"committee_index"`
+	CommitteesAtSlot uint64 `json:"committees_at_slot"`
+	Slot             uint64 `json:"slot"`
+	IsAggregator     bool   `json:"is_aggregator"`
+}
+
+func (s *Server) registerValidatorRoutes(rt *router) {
+	rt.post("/eth/v1/validator/duties/attester/{epoch}", s.getAttesterDuties)
+	rt.get("/eth/v1/validator/duties/proposer/{epoch}", s.getProposerDuties)
+	rt.get("/eth/v1/validator/blocks/{slot}", s.produceBlock)
+	rt.get("/eth/v1/validator/attestation_data", s.produceAttestationData)
+	rt.get("/eth/v1/validator/aggregate_attestation", s.getAggregateAttestation)
+	rt.post("/eth/v1/validator/aggregate_and_proofs", s.submitAggregateAndProofs)
+	rt.post("/eth/v1/validator/beacon_committee_subscriptions", s.submitCommitteeSubscriptions)
+}
+
+// dutiesState returns a state from which the duties of the given epoch can be computed.
+func (s *Server) dutiesState(r *http.Request, epochParam string) (*stateTrie.BeaconState, uint64, error) {
+	epoch, err := strconv.ParseUint(epochParam, 10, 64)
+	if err != nil {
+		return nil, 0, badRequest("Invalid epoch: %s", epochParam)
+	}
+	st, err := s.HeadFetcher.HeadState(r.Context())
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "could not retrieve head state")
+	}
+	if epoch > helpers.NextEpoch(st) {
+		return nil, 0, badRequest("Epoch %d is too far in the future", epoch)
+	}
+	if epoch < helpers.CurrentEpoch(st) {
+		st, err = s.StateGen.StateBySlot(r.Context(), helpers.StartSlot(epoch))
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "could not retrieve state")
+		}
+	}
+	// Computing assignments advances the slot of the state it is given.
+	return st.Copy(), epoch, nil
+}
+
+func (s *Server) getAttesterDuties(r *http.Request, p params) (interface{}, error) {
+	var indices []uint64
+	if err := decode(r.Body, &indices); err != nil {
+		return nil, badRequest("Invalid validator indices: %v", err)
+	}
+	st, epoch, err := s.dutiesState(r, p["epoch"])
+	if err != nil {
+		return nil, err
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not count active validators")
+	}
+	committeesAtSlot := helpers.SlotCommitteeCount(activeCount)
+	assignments, _, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute committee assignments")
+	}
+	res := make([]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		if idx >= uint64(st.NumValidators()) {
+			return nil, badRequest("Invalid validator index: %d", idx)
+		}
+		assignment, ok := assignments[idx]
+		if !ok {
+			continue
+		}
+		position := 0
+		for i, member := range assignment.Committee {
+			if member == idx {
+				position = i
+				break
+			}
+		}
+		pubkey := st.PubkeyAtIndex(idx)
+		res = append(res, map[string]interface{}{
+			"pubkey":                    hexutil.Encode(pubkey[:]),
+			"validator_index":           strconv.FormatUint(idx, 10),
+			"committee_index":           strconv.FormatUint(assignment.CommitteeIndex, 10),
+			"committee_length":          strconv.Itoa(len(assignment.Committee)),
+			"committees_at_slot":        strconv.FormatUint(committeesAtSlot, 10),
+			"validator_committee_index": strconv.Itoa(position),
+			"slot":                      strconv.FormatUint(assignment.AttesterSlot, 10),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) getProposerDuties(r *http.Request, p params) (interface{}, error) {
+	st, epoch, err := s.dutiesState(r, p["epoch"])
+	if err != nil {
+		return nil, err
+	}
+	_, proposers, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute proposer assignments")
+	}
+	res := make([]interface{}, 0)
+	for slot := helpers.StartSlot(epoch); slot < helpers.StartSlot(epoch+1); slot++ {
+		for idx, slots := range proposers {
+			for _, proposerSlot := range slots {
+				if proposerSlot != slot {
+					continue
+				}
+				pubkey := st.PubkeyAtIndex(idx)
+				res = append(res, map[string]interface{}{
+					"pubkey":          hexutil.Encode(pubkey[:]),
+					"validator_index": strconv.FormatUint(idx, 10),
+					"slot":            strconv.FormatUint(slot, 10),
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+func (s *Server) produceBlock(r *http.Request, p params) (interface{}, error) {
+	slot, err := strconv.ParseUint(p["slot"], 10, 64)
+	if err != nil {
+		return nil, badRequest("Invalid slot: %s", p["slot"])
+	}
+	randaoReveal, err := hexutil.Decode(r.URL.Query().Get("randao_reveal"))
+	if err != nil {
+		return nil, badRequest("Invalid randao reveal: %v", err)
+	}
+	var graffiti []byte
+	if v := r.URL.Query().Get("graffiti"); v != "" {
+		if graffiti, err = hexutil.Decode(v); err != nil {
+			return nil, badRequest("Invalid graffiti: %v", err)
+		}
+	}
+	blk, err := s.ValidatorServer.GetBlock(r.Context(), &ethpb.BlockRequest{
+		Slot:         slot,
+		RandaoReveal: randaoReveal,
+		Graffiti:     graffiti,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk), nil
+}
+
+func (s *Server) produceAttestationData(r *http.Request, _ params) (interface{}, error) {
+	slot, err := queryUint(r, "slot")
+	if err != nil {
+		return nil, err
+	}
+	committeeIndex, err := queryUint(r, "committee_index")
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil || committeeIndex == nil {
+		return nil, badRequest("Both slot and committee_index are required")
+	}
+	data, err := s.ValidatorServer.GetAttestationData(r.Context(), &ethpb.AttestationDataRequest{
+		Slot:           *slot,
+		CommitteeIndex: *committeeIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encode(data), nil
+}
+
+func (s *Server) getAggregateAttestation(r *http.Request, _ params) (interface{}, error) {
+	slot, err := queryUint(r, "slot")
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, badRequest("Slot is required")
+	}
+	dataRoot, err := decodeRoot(r.URL.Query().Get("attestation_data_root"))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AttestationsPool.AggregateUnaggregatedAttestations(); err != nil {
+		return nil, errors.Wrap(err, "could not aggregate attestations")
+	}
+	var best *ethpb.Attestation
+	for _, att := range s.AttestationsPool.AggregatedAttestations() {
+		if att.Data.Slot != *slot {
+			continue
+		}
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute attestation data root")
+		}
+		if root != bytesutil.ToBytes32(dataRoot) {
+			continue
+		}
+		if best == nil || att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	if best == nil {
+		return nil, notFound("No matching aggregate attestation found")
+	}
+	return encode(best), nil
+}
+
+func (s *Server) submitAggregateAndProofs(r *http.Request, _ params) (interface{}, error) {
+	var aggregates []*ethpb.SignedAggregateAttestationAndProof
+	if err := decode(r.Body, &aggregates); err != nil {
+		return nil, badRequest("Invalid aggregate and proofs: %v", err)
+	}
+	for i, agg := range aggregates {
+		if agg.Message == nil || agg.Message.Aggregate == nil || agg.Message.Aggregate.Data == nil {
+			return nil, badRequest("Invalid aggregate and proof %d: missing message", i)
+		}
+		if _, err := s.ValidatorServer.SubmitSignedAggregateSelectionProof(r.Context(), &ethpb.SignedAggregateSubmitRequest{
+			SignedAggregateAndProof: agg,
+		}); err != nil {
+			code, msg := httpStatus(err)
+			return nil, newAPIError(code, "Could not submit aggregate and proof %d: %s", i, msg)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) submitCommitteeSubscriptions(r *http.Request, _ params) (interface{}, error) {
+	var subs []*committeeSubscription
+	if err := decode(r.Body, &subs); err != nil {
+		return nil, badRequest("Invalid subscriptions: %v", err)
+	}
+	if len(subs) == 0 {
+		return nil, badRequest("No subscriptions provided")
+	}
+	req := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        make([]uint64, len(subs)),
+		CommitteeIds: make([]uint64, len(subs)),
+		IsAggregator: make([]bool, len(subs)),
+	}
+	for i, sub := range subs {
+		req.Slots[i] = sub.Slot
+		req.CommitteeIds[i] = sub.CommitteeIndex
+		req.IsAggregator[i] = sub.IsAggregator
+	}
+	if _, err := s.ValidatorServer.SubscribeCommitteeSubnets(r.Context(), req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
@@ -58,6 +59,16 @@ func (s *State) loadColdStateBySlot(ctx context.Context, slot uint64) (*state.Be
 		return s.beaconDB.GenesisState(ctx)
 	}
 
+	if featureconfig.Get().EnableColdStateDiffs {
+		diffState, err := s.loadColdStateFromDiffs(ctx, slot)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load cold state from diffs")
+		}
+		if diffState != nil {
+			return s.processStateUpTo(ctx, diffState, slot)
+		}
+	}
+
 	archivedState, err := s.archivedState(ctx, slot)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,449 @@
+package stategen
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// stateDiffVersion is the first byte of an encoded state diff, bumped when the encoding changes.
+const stateDiffVersion = 1
+
+// stateDiff is the difference between a cold state and the state of an earlier block of the
+// canonical chain, its base. The large lists of the state are diffed element by element, the
+// other fields are small and kept whole in the header.
+type stateDiff struct {
+	baseSlot        uint64
+	baseRoot        [32]byte
+	header          []byte // The encoded state without the diffed lists.
+	blockRoots      *listDiff
+	stateRoots      *listDiff
+	historicalRoots *listDiff
+	randaoMixes     *listDiff
+	slashings       *listDiff
+	validators      *listDiff
+	balances        *listDiff
+}
+
+// listDiff holds the length of a list and the elements which differ from the base list.
+type listDiff struct {
+	length  uint64
+	indices []uint64
+	values  [][]byte
+}
+
+// This computes the diff of the target state from the base state of the block root.
+func computeStateDiff(base *pb.BeaconState, baseRoot [32]byte, target *pb.BeaconState) (*stateDiff, error) {
+	header := *target
+	header.BlockRoots = nil
+	header.StateRoots = nil
+	header.HistoricalRoots = nil
+	header.RandaoMixes = nil
+	header.Slashings = nil
+	header.Validators = nil
+	header.Balances = nil
+	enc, err := proto.Marshal(&header)
+	if err != nil {
+		return nil, err
+	}
+	baseValidators, err := marshalValidators(base.Validators)
+	if err != nil {
+		return nil, err
+	}
+	targetValidators, err := marshalValidators(target.Validators)
+	if err != nil {
+		return nil, err
+	}
+	return &stateDiff{
+		baseSlot:        base.Slot,
+		baseRoot:        baseRoot,
+		header:          enc,
+		blockRoots:      diffLists(base.BlockRoots, target.BlockRoots),
+		stateRoots:      diffLists(base.StateRoots, target.StateRoots),
+		historicalRoots: diffLists(base.HistoricalRoots, target.HistoricalRoots),
+		randaoMixes:     diffLists(base.RandaoMixes, target.RandaoMixes),
+		slashings:       diffLists(uint64sToBytes(base.Slashings), uint64sToBytes(target.Slashings)),
+		validators:      diffLists(baseValidators, targetValidators),
+		balances:        diffLists(uint64sToBytes(base.Balances), uint64sToBytes(target.Balances)),
+	}, nil
+}
+
+// This applies the diff to its base state, the base state is not modified.
+func (d *stateDiff) apply(base *pb.BeaconState) (*pb.BeaconState, error) {
+	if base.Slot != d.baseSlot {
+		return nil, errMalformedStateDiff
+	}
+	s := &pb.BeaconState{}
+	if err := proto.Unmarshal(d.header, s); err != nil {
+		return nil, err
+	}
+	var err error
+	if s.BlockRoots, err = d.blockRoots.apply(base.BlockRoots); err != nil {
+		return nil, err
+	}
+	if s.StateRoots, err = d.stateRoots.apply(base.StateRoots); err != nil {
+		return nil, err
+	}
+	if s.HistoricalRoots, err = d.historicalRoots.apply(base.HistoricalRoots); err != nil {
+		return nil, err
+	}
+	if s.RandaoMixes, err = d.randaoMixes.apply(base.RandaoMixes); err != nil {
+		return nil, err
+	}
+	slashings, err := d.slashings.apply(uint64sToBytes(base.Slashings))
+	if err != nil {
+		return nil, err
+	}
+	s.Slashings = bytesToUint64s(slashings)
+	balances, err := d.balances.apply(uint64sToBytes(base.Balances))
+	if err != nil {
+		return nil, err
+	}
+	s.Balances = bytesToUint64s(balances)
+
+	// Only the changed validators are decoded, the others are shared with the base state.
+	if err := d.validators.covers(uint64(len(base.Validators))); err != nil {
+		return nil, err
+	}
+	s.Validators = make([]*ethpb.Validator, d.validators.length)
+	copy(s.Validators, base.Validators)
+	for i, index := range d.validators.indices {
+		v := &ethpb.Validator{}
+		if err := proto.Unmarshal(d.validators.values[i], v); err != nil {
+			return nil, err
+		}
+		s.Validators[index] = v
+	}
+	return s, nil
+}
+
+// This returns the diff of the target list from the base list.
+func diffLists(base [][]byte, target [][]byte) *listDiff {
+	d := &listDiff{length: uint64(len(target))}
+	for i, v := range target {
+		if i < len(base) && bytes.Equal(base[i], v) {
+			continue
+		}
+		d.indices = append(d.indices, uint64(i))
+		d.values = append(d.values, v)
+	}
+	return d
+}
+
+// This applies the diff to the base list, returning a new list.
+func (d *listDiff) apply(base [][]byte) ([][]byte, error) {
+	if err := d.covers(uint64(len(base))); err != nil {
+		return nil, err
+	}
+	list := make([][]byte, d.length)
+	copy(list, base)
+	for i, index := range d.indices {
+		list[index] = d.values[i]
+	}
+	return list, nil
+}
+
+// This checks the diff sets every element past the end of a base list of the given length.
+func (d *listDiff) covers(baseLength uint64) error {
+	if len(d.indices) != len(d.values) {
+		return errMalformedStateDiff
+	}
+	appended := uint64(0)
+	last := uint64(0)
+	for i, index := range d.indices {
+		if index >= d.length || (i > 0 && index <= last) {
+			return errMalformedStateDiff
+		}
+		if index >= baseLength {
+			appended++
+		}
+		last = index
+	}
+	if d.length > baseLength && appended != d.length-baseLength {
+		return errMalformedStateDiff
+	}
+	return nil
+}
+
+// This encodes the diff as its version, base slot and root, then the header and the list diffs
+// each prefixed by their length.
+func (d *stateDiff) marshal() []byte {
+	buf := []byte{stateDiffVersion}
+	buf = appendUint64(buf, d.baseSlot)
+	buf = append(buf, d.baseRoot[:]...)
+	buf = appendBytes(buf, d.header)
+	for _, l := range d.lists() {
+		buf = appendUint64(buf, l.length)
+		buf = appendUint64(buf, uint64(len(l.indices)))
+		for i, index := range l.indices {
+			buf = appendUint64(buf, index)
+			buf = appendBytes(buf, l.values[i])
+		}
+	}
+	return buf
+}
+
+// This decodes a diff encoded with marshal.
+func unmarshalStateDiff(enc []byte) (*stateDiff, error) {
+	if len(enc) == 0 || enc[0] != stateDiffVersion {
+		return nil, errMalformedStateDiff
+	}
+	r := &diffReader{buf: enc[1:]}
+	d := &stateDiff{
+		baseSlot:        r.uint64(),
+		baseRoot:        bytesutil.ToBytes32(r.next(32)),
+		header:          r.bytes(),
+		blockRoots:      &listDiff{},
+		stateRoots:      &listDiff{},
+		historicalRoots: &listDiff{},
+		randaoMixes:     &listDiff{},
+		slashings:       &listDiff{},
+		validators:      &listDiff{},
+		balances:        &listDiff{},
+	}
+	for _, l := range d.lists() {
+		l.length = r.uint64()
+		count := r.uint64()
+		// Each element takes at least 16 bytes, bound the count before allocating.
+		if count > uint64(len(r.buf))/16 {
+			return nil, errMalformedStateDiff
+		}
+		l.indices = make([]uint64, 0, count)
+		l.values = make([][]byte, 0, count)
+		for i := uint64(0); i < count; i++ {
+			l.indices = append(l.indices, r.uint64())
+			l.values = append(l.values, r.bytes())
+		}
+	}
+	if r.err != nil || len(r.buf) != 0 {
+		return nil, errMalformedStateDiff
+	}
+	return d, nil
+}
+
+func (d *stateDiff) lists() []*listDiff {
+	return []*listDiff{
+		d.blockRoots,
+		d.stateRoots,
+		d.historicalRoots,
+		d.randaoMixes,
+		d.slashings,
+		d.validators,
+		d.balances,
+	}
+}
+
+// This saves the diffs of the states of the canonical chain from the split slot to the finalized
+// block, each state is diffed from the previous one. The states themselves are left for
+// MigrateToCold to delete, so that only archived points keep a full state.
+func (s *State) saveColdStateDiffs(ctx context.Context, splitSlot uint64, finalizedRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.saveColdStateDiffs")
+	defer span.End()
+
+	// Walk back the canonical chain from the finalized block, collecting the blocks with a state.
+	var roots [][32]byte
+	root := finalizedRoot
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		b, err := s.beaconDB.Block(ctx, root)
+		if err != nil {
+			return err
+		}
+		if b == nil || b.Block.Slot < splitSlot {
+			break
+		}
+		if s.beaconDB.HasState(ctx, root) {
+			roots = append([][32]byte{root}, roots...)
+		}
+		if b.Block.Slot == 0 {
+			break
+		}
+		root = bytesutil.ToBytes32(b.Block.ParentRoot)
+	}
+
+	for i, root := range roots {
+		st, err := s.beaconDB.State(ctx, root)
+		if err != nil {
+			return err
+		}
+		if st == nil || st.Slot() == 0 {
+			continue
+		}
+		// The diff of the first state was saved by the previous migration, if it was finalized then.
+		existing, err := s.beaconDB.StateDiff(ctx, st.Slot())
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			continue
+		}
+
+		var baseRoot [32]byte
+		if i > 0 {
+			baseRoot = roots[i-1]
+		} else {
+			baseRoot = s.beaconDB.LastArchivedIndexRoot(ctx)
+		}
+		base, err := s.beaconDB.State(ctx, baseRoot)
+		if err != nil {
+			return err
+		}
+		if base == nil || base.Slot() >= st.Slot() {
+			if baseRoot, err = s.genesisRoot(ctx); err != nil {
+				return err
+			}
+			if base, err = s.beaconDB.State(ctx, baseRoot); err != nil {
+				return err
+			}
+		}
+		if base == nil {
+			log.WithField("slot", st.Slot()).Warn("No base state to diff cold state from")
+			continue
+		}
+
+		diff, err := computeStateDiff(base.InnerStateUnsafe(), baseRoot, st.InnerStateUnsafe())
+		if err != nil {
+			return err
+		}
+		if err := s.beaconDB.SaveStateDiff(ctx, st.Slot(), diff.marshal()); err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"slot":     st.Slot(),
+			"root":     hex.EncodeToString(bytesutil.Trunc(root[:])),
+			"baseSlot": base.Slot(),
+		}).Debug("Saved cold state diff")
+	}
+	return nil
+}
+
+// This rebuilds the cold state of the highest slot at or below the input slot by applying the
+// saved diffs on the closest full state. It returns nil if there is no diff closer to the slot than
+// its archived point, or if the diffs do not lead back to a full state.
+func (s *State) loadColdStateFromDiffs(ctx context.Context, slot uint64) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.loadColdStateFromDiffs")
+	defer span.End()
+
+	diffSlot, enc, err := s.beaconDB.HighestStateDiffBelow(ctx, slot+1)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil || diffSlot < slot-slot%s.slotsPerArchivedPoint {
+		return nil, nil
+	}
+
+	var diffs []*stateDiff
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		diff, err := unmarshalStateDiff(enc)
+		if err != nil {
+			return nil, err
+		}
+		if diff.baseSlot >= diffSlot {
+			return nil, errMalformedStateDiff
+		}
+		diffs = append(diffs, diff)
+
+		base, err := s.beaconDB.State(ctx, diff.baseRoot)
+		if err != nil {
+			return nil, err
+		}
+		if base != nil {
+			st := base.CloneInnerState()
+			for i := len(diffs) - 1; i >= 0; i-- {
+				if st, err = diffs[i].apply(st); err != nil {
+					return nil, err
+				}
+			}
+			return state.InitializeFromProtoUnsafe(st)
+		}
+
+		diffSlot = diff.baseSlot
+		enc, err = s.beaconDB.StateDiff(ctx, diffSlot)
+		if err != nil {
+			return nil, err
+		}
+		if enc == nil {
+			log.WithField("slot", diffSlot).Warn("Missing cold state diff, replaying blocks instead")
+			return nil, nil
+		}
+	}
+}
+
+func marshalValidators(vals []*ethpb.Validator) ([][]byte, error) {
+	enc := make([][]byte, len(vals))
+	for i, v := range vals {
+		var err error
+		if enc[i], err = proto.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return enc, nil
+}
+
+func uint64sToBytes(list []uint64) [][]byte {
+	enc := make([][]byte, len(list))
+	for i, v := range list {
+		enc[i] = bytesutil.Bytes8(v)
+	}
+	return enc
+}
+
+func bytesToUint64s(list [][]byte) []uint64 {
+	dec := make([]uint64, len(list))
+	for i, v := range list {
+		dec[i] = bytesutil.FromBytes8(v)
+	}
+	return dec
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var enc [8]byte
+	binary.LittleEndian.PutUint64(enc[:], v)
+	return append(buf, enc[:]...)
+}
+
+func appendBytes(buf []byte, v []byte) []byte {
+	return append(appendUint64(buf, uint64(len(v))), v...)
+}
+
+// diffReader decodes the fields of an encoded diff, keeping the first error.
+type diffReader struct {
+	buf []byte
+	err error
+}
+
+func (r *diffReader) next(n uint64) []byte {
+	if r.err != nil || n > uint64(len(r.buf)) {
+		r.err = errMalformedStateDiff
+		return nil
+	}
+	v := r.buf[:n]
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *diffReader) uint64() uint64 {
+	v := r.next(8)
+	if v == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(v)
+}
+
+func (r *diffReader) bytes() []byte {
+	return r.next(r.uint64())
+}
@@ -0,0 +1,163 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStateDiff_RoundTrip(t *testing.T) {
+	base, _ := testutil.DeterministicGenesisState(t, 32)
+	target := base.Copy()
+	if err := target.SetSlot(64); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.UpdateBlockRootAtIndex(3, [32]byte{'a'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.UpdateRandaoMixesAtIndex(5, []byte{'b'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.AppendHistoricalRoots([32]byte{'c'}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.UpdateBalancesAtIndex(1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.AppendValidator(&ethpb.Validator{PublicKey: []byte{'d'}, EffectiveBalance: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.AppendBalance(10); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := computeStateDiff(base.InnerStateUnsafe(), [32]byte{'e'}, target.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.blockRoots.indices) != 1 || len(diff.randaoMixes.indices) != 1 {
+		t.Error("Diff holds unchanged roots")
+	}
+	if len(diff.validators.indices) != 1 || len(diff.balances.indices) != 2 {
+		t.Error("Diff holds unchanged validators or balances")
+	}
+
+	decoded, err := unmarshalStateDiff(diff.marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.baseSlot != 0 || decoded.baseRoot != [32]byte{'e'} {
+		t.Error("Did not decode base of the diff")
+	}
+	applied, err := decoded.apply(base.CloneInnerState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(applied, target.InnerStateUnsafe()) {
+		t.Error("Applied diff did not give the target state")
+	}
+}
+
+func TestUnmarshalStateDiff_Malformed(t *testing.T) {
+	base, _ := testutil.DeterministicGenesisState(t, 32)
+	diff, err := computeStateDiff(base.InnerStateUnsafe(), [32]byte{}, base.InnerStateUnsafe())
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := diff.marshal()
+	if _, err := unmarshalStateDiff(enc[:len(enc)-1]); err != errMalformedStateDiff {
+		t.Errorf("Wanted error %v, got %v", errMalformedStateDiff, err)
+	}
+	if _, err := unmarshalStateDiff(nil); err != errMalformedStateDiff {
+		t.Errorf("Wanted error %v, got %v", errMalformedStateDiff, err)
+	}
+}
+
+func TestMigrateToCold_SavesStateDiffs(t *testing.T) {
+	resetCfg := featureconfig.InitWithReset(&featureconfig.Flags{EnableColdStateDiffs: true})
+	defer resetCfg()
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 1024
+
+	genesisState, _ := testutil.DeterministicGenesisState(t, 32)
+	genesis := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{}}
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, genesisState, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save two epoch boundary blocks with their states, the second one is finalized.
+	parentRoot := genesisRoot
+	var roots [][32]byte
+	var states []*pb.BeaconState
+	for i, slot := range []uint64{8, 16} {
+		b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot, ParentRoot: parentRoot[:]}}
+		if err := db.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		st := genesisState.Copy()
+		if err := st.SetSlot(slot); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.UpdateBalancesAtIndex(uint64(i), slot); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveState(ctx, st, root); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveStateSummary(ctx, &pb.StateSummary{Root: root[:], Slot: slot}); err != nil {
+			t.Fatal(err)
+		}
+		roots = append(roots, root)
+		states = append(states, st.CloneInnerState())
+		parentRoot = root
+	}
+
+	if err := service.MigrateToCold(ctx, 16, roots[1]); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasState(ctx, roots[0]) {
+		t.Error("Did not delete the state diffed during migration")
+	}
+	for _, slot := range []uint64{8, 16} {
+		diff, err := db.StateDiff(ctx, slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff == nil {
+			t.Errorf("Did not save state diff at slot %d", slot)
+		}
+	}
+
+	loadedState, err := service.loadColdStateBySlot(ctx, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(loadedState.InnerStateUnsafe(), states[0]) {
+		t.Error("Did not rebuild the state from its diff")
+	}
+}
@@ -7,3 +7,4 @@ var errUnknownArchivedState = errors.New("unknown archived state")
 var errUnknownBoundaryState = errors.New("unknown boundary state")
 var errUnknownState = errors.New("unknown state")
 var errUnknownBlock = errors.New("unknown block")
+var errMalformedStateDiff = errors.New("malformed state diff")
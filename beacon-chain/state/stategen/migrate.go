@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/hex"
 
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
 )
@@ -31,6 +33,13 @@ func (s *State) MigrateToCold(ctx context.Context, finalizedSlot uint64, finaliz
 	}
 	s.stateSummaryCache.Clear()
 
+	// Diff the states before the loop below deletes them.
+	if featureconfig.Get().EnableColdStateDiffs {
+		if err := s.saveColdStateDiffs(ctx, currentSplitSlot, finalizedRoot); err != nil {
+			return errors.Wrap(err, "could not save cold state diffs")
+		}
+	}
+
 	lastArchivedIndex, err := s.beaconDB.LastArchivedIndex(ctx)
 	if err != nil {
 		return err
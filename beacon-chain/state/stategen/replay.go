@@ -305,6 +305,10 @@ func (s *State) genesisRoot(ctx context.Context) ([32]byte, error) {
 	if err != nil {
 		return [32]byte{}, err
 	}
+	// A node started from a finalized checkpoint has no genesis block until it is backfilled.
+	if b == nil || b.Block == nil {
+		return [32]byte{}, errUnknownBlock
+	}
 	return stateutil.BlockRoot(b.Block)
 }
 
@@ -3,7 +3,9 @@ package stategen
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"go.opencensus.io/trace"
 )
 
@@ -21,6 +23,34 @@ func (s *State) SaveState(ctx context.Context, root [32]byte, state *state.Beaco
 	return s.saveHotState(ctx, root, state)
 }
 
+// SaveAnchorState saves the state a node was started from when it was synced from a trusted
+// finalized checkpoint instead of genesis. The state is saved in full as the last archived point
+// and becomes the split point, so states are regenerated from it rather than from genesis.
+func (s *State) SaveAnchorState(ctx context.Context, root [32]byte, state *state.BeaconState) error {
+	ctx, span := trace.StartSpan(ctx, "stateGen.SaveAnchorState")
+	defer span.End()
+
+	if err := s.beaconDB.SaveState(ctx, state, root); err != nil {
+		return errors.Wrap(err, "could not save anchor state")
+	}
+	if err := s.beaconDB.SaveStateSummary(ctx, &pb.StateSummary{
+		Slot: state.Slot(),
+		Root: root[:],
+	}); err != nil {
+		return errors.Wrap(err, "could not save anchor state summary")
+	}
+	archivedPointIndex := state.Slot() / s.slotsPerArchivedPoint
+	if err := s.beaconDB.SaveArchivedPointRoot(ctx, root, archivedPointIndex); err != nil {
+		return errors.Wrap(err, "could not save anchor archived point root")
+	}
+	if err := s.beaconDB.SaveLastArchivedIndex(ctx, archivedPointIndex); err != nil {
+		return errors.Wrap(err, "could not save anchor archived point index")
+	}
+	s.splitInfo = &splitSlotAndRoot{slot: state.Slot(), root: root}
+
+	return nil
+}
+
 // DeleteHotStateInCache deletes the hot state entry from the cache.
 func (s *State) DeleteHotStateInCache(root [32]byte) {
 	s.hotStateCache.Delete(root)
@@ -100,3 +100,37 @@ func TestSaveState_HotStateCached(t *testing.T) {
 	}
 	testutil.AssertLogsDoNotContain(t, hook, "Saved full state on epoch boundary")
 }
+
+func TestSaveAnchorState_SetsSplitAndArchivedPoint(t *testing.T) {
+	ctx := context.Background()
+	db := testDB.SetupDB(t)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 64
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+	slot := uint64(200)
+	if err := beaconState.SetSlot(slot); err != nil {
+		t.Fatal(err)
+	}
+
+	r := [32]byte{'a'}
+	if err := service.SaveAnchorState(ctx, r, beaconState); err != nil {
+		t.Fatal(err)
+	}
+
+	if !service.beaconDB.HasState(ctx, r) {
+		t.Error("Should have saved the state")
+	}
+	if !service.beaconDB.HasStateSummary(ctx, r) {
+		t.Error("Should have saved the state summary")
+	}
+	if service.beaconDB.ArchivedPointRoot(ctx, 3) != r {
+		t.Error("Did not save archived point root")
+	}
+	if service.beaconDB.LastArchivedIndexRoot(ctx) != r {
+		t.Error("Did not save last archived index")
+	}
+	if service.splitInfo.slot != slot || service.splitInfo.root != r {
+		t.Errorf("Unexpected split info %v", service.splitInfo)
+	}
+}
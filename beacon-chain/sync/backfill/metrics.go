@@ -0,0 +1,27 @@
+package backfill
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	backfillOldestSlot = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "backfill_oldest_block_slot",
+			Help: "The slot of the oldest block in the database, backfill is complete when it reaches 0.",
+		},
+	)
+	backfillBlocksCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "backfill_blocks_total",
+			Help: "Count of historical blocks saved by backfill.",
+		},
+	)
+	backfillFailedBatchesCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "backfill_failed_batches_total",
+			Help: "Count of backfill batches which could not be fetched or verified.",
+		},
+	)
+)
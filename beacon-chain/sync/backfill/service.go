@@ -0,0 +1,300 @@
+// Package backfill fetches and saves the historical blocks before the anchor block of
+// a node started from a finalized checkpoint, walking backwards from the oldest block
+// in the database until genesis.
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = shared.Service(&Service{})
+
+const (
+	// noPeersDelay is the time to wait before retrying when there are no peers to request blocks from.
+	noPeersDelay = 5 * time.Second
+	// failedBatchDelay is the time to wait before retrying a batch that could not be fetched or verified.
+	failedBatchDelay = time.Second
+)
+
+var (
+	errNotAncestor  = errors.New("batch does not end with the parent of the oldest block")
+	errInvalidBatch = errors.New("batch does not form a chain of blocks in the requested range")
+)
+
+// Config to set up the backfill service.
+type Config struct {
+	P2P p2p.P2P
+	DB  db.NoHeadAccessDatabase
+}
+
+// Service fetches the blocks before the oldest block in the database from peers.
+type Service struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	p2p           p2p.P2P
+	db            db.NoHeadAccessDatabase
+	batchSize     uint64
+	requestBlocks func(ctx context.Context, req *p2ppb.BeaconBlocksByRangeRequest, pid peer.ID) ([]*eth.SignedBeaconBlock, error)
+}
+
+// NewService configures the backfill service.
+func NewService(ctx context.Context, cfg *Config) *Service {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:       ctx,
+		cancel:    cancel,
+		p2p:       cfg.P2P,
+		db:        cfg.DB,
+		batchSize: uint64(flags.Get().BlockBatchLimit),
+	}
+	s.requestBlocks = s.requestBlocksFromPeer
+	return s
+}
+
+// Start backfilling blocks, if the node was started from a finalized checkpoint.
+func (s *Service) Start() {
+	oldest, err := s.oldestBlock(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("Could not get oldest block")
+		return
+	}
+	if oldest == nil || oldest.Block.Slot == 0 {
+		return
+	}
+	go s.run(oldest)
+}
+
+// Stop the backfill service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the backfill service.
+func (s *Service) Status() error {
+	return nil
+}
+
+// oldestBlock returns the oldest block of the contiguous chain of blocks in the database,
+// which is the oldest backfilled block or the checkpoint sync anchor. It returns nil if the
+// node was started from genesis.
+func (s *Service) oldestBlock(ctx context.Context) (*eth.SignedBeaconBlock, error) {
+	oldest, err := s.db.BackfillBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if oldest != nil {
+		return oldest, nil
+	}
+	return s.db.AnchorBlock(ctx)
+}
+
+// run walks backwards from the oldest block until the genesis block is saved. Progress is saved
+// with every batch, so backfill resumes from the oldest saved block after a restart.
+func (s *Service) run(oldest *eth.SignedBeaconBlock) {
+	log.WithField("slot", oldest.Block.Slot).Info("Backfilling blocks before the oldest block")
+	backfillOldestSlot.Set(float64(oldest.Block.Slot))
+	// The end of the next batch. It moves past the oldest block when a batch contains only skipped slots.
+	end := oldest.Block.Slot
+	for {
+		if s.ctx.Err() != nil {
+			return
+		}
+		pid, err := s.selectPeer()
+		if err != nil {
+			log.WithError(err).Debug("Could not select peer to backfill from")
+			s.wait(noPeersDelay)
+			continue
+		}
+		start := uint64(0)
+		if end > s.batchSize {
+			start = end - s.batchSize
+		}
+		req := &p2ppb.BeaconBlocksByRangeRequest{
+			StartSlot: start,
+			Count:     end - start,
+			Step:      1,
+		}
+		blks, err := s.requestBlocks(s.ctx, req, pid)
+		if err != nil {
+			log.WithError(err).WithField("peer", pid).Debug("Could not request blocks")
+			backfillFailedBatchesCounter.Inc()
+			s.wait(failedBatchDelay)
+			continue
+		}
+		saved, err := s.saveBatch(s.ctx, blks, oldest, start, end)
+		switch {
+		case err == errInvalidBatch:
+			s.p2p.Peers().Scorers().BadResponsesScorer().Increment(pid)
+			fallthrough
+		case err == errNotAncestor:
+			// Either the peer returned a bad batch, or an earlier peer did not return blocks it
+			// should have, search again from the oldest block.
+			log.WithError(err).WithField("peer", pid).Debug("Could not verify blocks")
+			backfillFailedBatchesCounter.Inc()
+			end = oldest.Block.Slot
+			s.wait(failedBatchDelay)
+			continue
+		case err != nil:
+			log.WithError(err).Error("Could not save backfilled blocks")
+			backfillFailedBatchesCounter.Inc()
+			s.wait(failedBatchDelay)
+			continue
+		}
+		if saved == nil {
+			if start == 0 {
+				// The genesis block is always in the range starting at slot 0.
+				end = oldest.Block.Slot
+				backfillFailedBatchesCounter.Inc()
+				s.wait(failedBatchDelay)
+				continue
+			}
+			end = start
+			continue
+		}
+
+		oldest = saved
+		end = oldest.Block.Slot
+		backfillOldestSlot.Set(float64(oldest.Block.Slot))
+		backfillBlocksCounter.Add(float64(len(blks)))
+		log.WithFields(logrus.Fields{
+			"slot":   oldest.Block.Slot,
+			"blocks": len(blks),
+		}).Debug("Backfilled blocks")
+		if oldest.Block.Slot == 0 {
+			if err := s.saveGenesis(s.ctx, oldest); err != nil {
+				log.WithError(err).Error("Could not save genesis block root")
+				return
+			}
+			log.Info("Backfill complete")
+			return
+		}
+	}
+}
+
+// saveBatch verifies that the batch of blocks in the range [start, end) hash-chains to the
+// parent root of the oldest block, and saves it. It returns the oldest saved block, or nil
+// if the batch is empty.
+func (s *Service) saveBatch(
+	ctx context.Context,
+	blks []*eth.SignedBeaconBlock,
+	oldest *eth.SignedBeaconBlock,
+	start, end uint64,
+) (*eth.SignedBeaconBlock, error) {
+	if len(blks) == 0 {
+		return nil, nil
+	}
+	for i, blk := range blks {
+		if blk == nil || blk.Block == nil || blk.Block.Slot < start || blk.Block.Slot >= end {
+			return nil, errInvalidBatch
+		}
+		if i > 0 && blk.Block.Slot <= blks[i-1].Block.Slot {
+			return nil, errInvalidBatch
+		}
+	}
+	expectedRoot := oldest.Block.ParentRoot
+	var oldestRoot [32]byte
+	for i := len(blks) - 1; i >= 0; i-- {
+		root, err := stateutil.BlockRoot(blks[i].Block)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(root[:], expectedRoot) {
+			if i == len(blks)-1 {
+				return nil, errNotAncestor
+			}
+			return nil, errInvalidBatch
+		}
+		expectedRoot = blks[i].Block.ParentRoot
+		oldestRoot = root
+	}
+
+	if err := s.db.SaveBlocks(ctx, blks); err != nil {
+		return nil, err
+	}
+	if err := s.db.SaveBackfillBlockRoot(ctx, oldestRoot); err != nil {
+		return nil, err
+	}
+	return blks[0], nil
+}
+
+func (s *Service) saveGenesis(ctx context.Context, genesis *eth.SignedBeaconBlock) error {
+	root, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		return err
+	}
+	return s.db.SaveGenesisBlockRoot(ctx, root)
+}
+
+// selectPeer returns a random connected peer which is not considered bad.
+func (s *Service) selectPeer() (peer.ID, error) {
+	peers := make([]peer.ID, 0)
+	for _, pid := range s.p2p.Peers().Connected() {
+		if !s.p2p.Peers().IsBad(pid) {
+			peers = append(peers, pid)
+		}
+	}
+	if len(peers) == 0 {
+		return "", errors.New("no suitable peers")
+	}
+	randGen := rand.New(rand.NewSource(roughtime.Now().Unix()))
+	return peers[randGen.Intn(len(peers))], nil
+}
+
+func (s *Service) requestBlocksFromPeer(
+	ctx context.Context,
+	req *p2ppb.BeaconBlocksByRangeRequest,
+	pid peer.ID,
+) ([]*eth.SignedBeaconBlock, error) {
+	stream, err := s.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Reset(); err != nil {
+			log.WithError(err).Errorf("Failed to close stream with protocol %s", stream.Protocol())
+		}
+	}()
+
+	resp := make([]*eth.SignedBeaconBlock, 0, req.Count)
+	for i := uint64(0); ; i++ {
+		blk, err := prysmsync.ReadChunkedBlock(stream, s.p2p)
+		if err == io.EOF {
+			break
+		}
+		if i >= req.Count || i >= params.BeaconNetworkConfig().MaxRequestBlocks {
+			return nil, fmt.Errorf("peer %s returned more than %d blocks", pid, req.Count)
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, blk)
+	}
+	return resp, nil
+}
+
+func (s *Service) wait(d time.Duration) {
+	select {
+	case <-s.ctx.Done():
+	case <-time.After(d):
+	}
+}
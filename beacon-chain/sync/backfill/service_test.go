@@ -0,0 +1,235 @@
+package backfill
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+	p2pt "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	beaconsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// makeChain returns a chain of blocks from genesis up to the given slot, skipping every fifth slot.
+func makeChain(t *testing.T, headSlot uint64) []*eth.SignedBeaconBlock {
+	blks := make([]*eth.SignedBeaconBlock, 0)
+	parentRoot := make([]byte, 32)
+	for slot := uint64(0); slot <= headSlot; slot++ {
+		if slot%5 == 4 {
+			continue
+		}
+		blk := testutil.NewBeaconBlock()
+		blk.Block.Slot = slot
+		blk.Block.ParentRoot = parentRoot
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parentRoot = root[:]
+		blks = append(blks, blk)
+	}
+	return blks
+}
+
+// setupAnchor saves the last block of the chain as the checkpoint sync anchor.
+func setupAnchor(t *testing.T, beaconDB db.Database, chain []*eth.SignedBeaconBlock) {
+	ctx := context.Background()
+	anchor := chain[len(chain)-1]
+	root, err := stateutil.BlockRoot(anchor.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveBlock(ctx, anchor); err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveAnchorBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := beaconDB.SaveFinalizedCheckpoint(ctx, &eth.Checkpoint{Root: root[:]}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// connectPeer connects a peer which serves blocks by range from the given chain.
+func connectPeer(t *testing.T, host *p2pt.TestP2P, chain []*eth.SignedBeaconBlock) {
+	remote := p2pt.NewTestP2P(t)
+	remote.SetStreamHandler(p2p.RPCBlocksByRangeTopic+remote.Encoding().ProtocolSuffix(), func(stream network.Stream) {
+		defer func() {
+			if err := stream.Close(); err != nil {
+				t.Log(err)
+			}
+		}()
+		req := &p2ppb.BeaconBlocksByRangeRequest{}
+		if err := remote.Encoding().DecodeWithLength(stream, req); err != nil {
+			t.Error(err)
+			return
+		}
+		for _, blk := range chain {
+			if blk.Block.Slot < req.StartSlot || blk.Block.Slot >= req.StartSlot+req.Count {
+				continue
+			}
+			if err := beaconsync.WriteChunk(stream, remote.Encoding(), blk); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+	remote.Connect(host)
+	host.Peers().Add(new(enr.Record), remote.PeerID(), nil, network.DirOutbound)
+	host.Peers().SetConnectionState(remote.PeerID(), peers.PeerConnected)
+}
+
+func TestService_BackfillsToGenesis(t *testing.T) {
+	ctx := context.Background()
+	beaconDB := dbtest.SetupDB(t)
+	chain := makeChain(t, 100)
+	setupAnchor(t, beaconDB, chain)
+	host := p2pt.NewTestP2P(t)
+	connectPeer(t, host, chain)
+
+	s := NewService(ctx, &Config{P2P: host, DB: beaconDB})
+	s.batchSize = 16
+	oldest, err := s.oldestBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.run(oldest)
+
+	for _, blk := range chain {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !beaconDB.HasBlock(ctx, root) {
+			t.Errorf("Block at slot %d was not backfilled", blk.Block.Slot)
+		}
+		if !beaconDB.IsFinalizedBlock(ctx, root) {
+			t.Errorf("Block at slot %d was not indexed as finalized", blk.Block.Slot)
+		}
+	}
+	genesis, err := beaconDB.GenesisBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if genesis == nil || genesis.Block.Slot != 0 {
+		t.Errorf("Unexpected genesis block %v", genesis)
+	}
+	oldest, err = s.oldestBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldest.Block.Slot != 0 {
+		t.Errorf("Expected backfill to resume from genesis, received slot %d", oldest.Block.Slot)
+	}
+}
+
+func TestService_SaveBatch(t *testing.T) {
+	ctx := context.Background()
+	chain := makeChain(t, 40)
+	anchor := chain[len(chain)-1]
+	// The blocks in the range [20, 40).
+	var batch []*eth.SignedBeaconBlock
+	for _, blk := range chain[:len(chain)-1] {
+		if blk.Block.Slot >= 20 {
+			batch = append(batch, blk)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		batch   func() []*eth.SignedBeaconBlock
+		wantErr error
+	}{
+		{
+			name: "empty",
+			batch: func() []*eth.SignedBeaconBlock {
+				return nil
+			},
+		},
+		{
+			name: "not ancestor",
+			batch: func() []*eth.SignedBeaconBlock {
+				return batch[:len(batch)-1]
+			},
+			wantErr: errNotAncestor,
+		},
+		{
+			name: "broken chain",
+			batch: func() []*eth.SignedBeaconBlock {
+				broken := stateTrie.CopySignedBeaconBlock(batch[0])
+				broken.Block.ParentRoot = bytesutil.PadTo([]byte("bad"), 32)
+				return append([]*eth.SignedBeaconBlock{broken}, batch[2:]...)
+			},
+			wantErr: errInvalidBatch,
+		},
+		{
+			name: "out of range",
+			batch: func() []*eth.SignedBeaconBlock {
+				return append([]*eth.SignedBeaconBlock{chain[0]}, batch...)
+			},
+			wantErr: errInvalidBatch,
+		},
+		{
+			name: "valid",
+			batch: func() []*eth.SignedBeaconBlock {
+				return batch
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			beaconDB := dbtest.SetupDB(t)
+			setupAnchor(t, beaconDB, chain)
+			s := NewService(ctx, &Config{P2P: p2pt.NewTestP2P(t), DB: beaconDB})
+			blks := tt.batch()
+			saved, err := s.saveBatch(ctx, blks, anchor, 20, anchor.Block.Slot)
+			if err != tt.wantErr {
+				t.Fatalf("Expected error %v, received %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(blks) == 0 {
+				if saved != nil {
+					t.Errorf("Expected no saved block, received %v", saved)
+				}
+				return
+			}
+			if saved != blks[0] {
+				t.Errorf("Expected oldest saved block at slot %d", blks[0].Block.Slot)
+			}
+			backfilled, err := beaconDB.BackfillBlock(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if backfilled.Block.Slot != blks[0].Block.Slot {
+				t.Errorf("Expected backfill progress at slot %d, received %d", blks[0].Block.Slot, backfilled.Block.Slot)
+			}
+		})
+	}
+}
+
+func TestService_SelectPeer_NoPeers(t *testing.T) {
+	s := NewService(context.Background(), &Config{P2P: p2pt.NewTestP2P(t), DB: dbtest.SetupDB(t)})
+	pid, err := s.selectPeer()
+	if err == nil {
+		t.Errorf("Expected error, selected peer %s", pid)
+	}
+	if pid != peer.ID("") {
+		t.Errorf("Expected empty peer id, received %s", pid)
+	}
+}
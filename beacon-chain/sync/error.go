@@ -23,17 +23,34 @@ var responseCodeInvalidRequest = byte(0x01)
 var responseCodeServerError = byte(0x02)
 
 func (s *Service) generateErrorResponse(code byte, reason string) ([]byte, error) {
+	return createErrorResponse(code, reason, s.p2p.Encoding())
+}
+
+func createErrorResponse(code byte, reason string, encoding encoder.NetworkEncoding) ([]byte, error) {
 	buf := bytes.NewBuffer([]byte{code})
 	resp := &pb.ErrorResponse{
 		Message: []byte(reason),
 	}
-	if _, err := s.p2p.Encoding().EncodeWithLength(buf, resp); err != nil {
+	if _, err := encoding.EncodeWithLength(buf, resp); err != nil {
 		return nil, err
 	}
 
 	return buf.Bytes(), nil
 }
 
+// writeErrorResponse encodes the error response with the provided encoding and
+// writes it to the stream, logging any failure along the way.
+func writeErrorResponse(code byte, reason string, stream io.Writer, encoding encoder.NetworkEncoding) {
+	resp, err := createErrorResponse(code, reason, encoding)
+	if err != nil {
+		log.WithError(err).Error("Failed to generate a response error")
+	} else {
+		if _, err := stream.Write(resp); err != nil {
+			log.WithError(err).Errorf("Failed to write to stream")
+		}
+	}
+}
+
 // ReadStatusCode response from a RPC stream.
 func ReadStatusCode(stream io.Reader, encoding encoder.NetworkEncoding) (uint8, string, error) {
 	b := make([]byte, 1)
@@ -459,6 +459,7 @@ func (f *blocksFetcher) requestBlocks(
 	}
 	f.rateLimiter.Add(pid.String(), int64(req.Count))
 	l.Unlock()
+	f.p2p.Peers().Scorers().BlockProviderScorer().IncrementRequestedBlocks(pid, req.Count)
 	stream, err := f.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
 	if err != nil {
 		return nil, err
@@ -484,6 +485,7 @@ func (f *blocksFetcher) requestBlocks(
 		}
 		resp = append(resp, blk)
 	}
+	f.p2p.Peers().Scorers().BlockProviderScorer().IncrementProcessedBlocks(pid, uint64(len(resp)))
 
 	return resp, nil
 }
@@ -573,6 +575,21 @@ func (f *blocksFetcher) filterPeers(peers []peer.ID, peersPercentage float64) []
 		peers[i], peers[j] = peers[j], peers[i]
 	})
 
+	// Drop bad peers, and order the rest by their score, so that the sub-sample
+	// selected below is made of the most useful peers. Peers with the same score
+	// keep their random order.
+	scorers := f.p2p.Peers().Scorers()
+	filtered := peers[:0]
+	for _, pid := range peers {
+		if !scorers.IsBadPeer(pid) {
+			filtered = append(filtered, pid)
+		}
+	}
+	peers = filtered
+	sort.SliceStable(peers, func(i, j int) bool {
+		return scorers.Score(peers[i]) > scorers.Score(peers[j])
+	})
+
 	// Select sub-sample from peers (honoring min-max invariants).
 	required := params.BeaconConfig().MaxPeersToSync
 	if flags.Get().MinimumSyncPeers < required {
@@ -742,7 +742,9 @@ func TestBlocksFetcher_filterPeers(t *testing.T) {
 		peers           []weightedPeer
 		peersPercentage float64
 	}
-	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{})
+	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{
+		p2p: p2pt.NewTestP2P(t),
+	})
 	tests := []struct {
 		name string
 		args args
@@ -821,6 +823,33 @@ func TestBlocksFetcher_filterPeers(t *testing.T) {
 	}
 }
 
+func TestBlocksFetcher_filterScoredPeers(t *testing.T) {
+	p1 := p2pt.NewTestP2P(t)
+	fetcher := newBlocksFetcher(context.Background(), &blocksFetcherConfig{p2p: p1})
+	// Equal remaining capacity for all peers, so that only scores affect the ordering.
+	fetcher.rateLimiter = leakybucket.NewCollector(0.000001, 100, false)
+
+	scorers := p1.Peers().Scorers()
+	// Peer serving all the requested blocks.
+	scorers.BlockProviderScorer().IncrementRequestedBlocks("abc", 64)
+	scorers.BlockProviderScorer().IncrementProcessedBlocks("abc", 64)
+	// Peer serving half of the requested blocks.
+	scorers.BlockProviderScorer().IncrementRequestedBlocks("def", 64)
+	scorers.BlockProviderScorer().IncrementProcessedBlocks("def", 32)
+	// Peer serving no blocks at all.
+	scorers.BlockProviderScorer().IncrementRequestedBlocks("ghi", 64)
+	// Bad peer, which is expected to be filtered out.
+	for i := 0; i < scorers.BadResponsesScorer().Params().Threshold; i++ {
+		scorers.BadResponsesScorer().Increment("xyz")
+	}
+
+	got := fetcher.filterPeers([]peer.ID{"ghi", "xyz", "def", "abc"}, 1.0)
+	want := []peer.ID{"abc", "def", "ghi"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterPeers() got = %#v, want %#v", got, want)
+	}
+}
+
 func TestBlocksFetcher_RequestBlocksRateLimitingLocks(t *testing.T) {
 	p1 := p2pt.NewTestP2P(t)
 	p2 := p2pt.NewTestP2P(t)
@@ -39,6 +39,13 @@ var (
 		},
 		[]string{"topic"},
 	)
+	rateLimitedRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_rpc_rate_limited_total",
+			Help: "Count of incoming rpc requests rejected for exceeding the peer's rate limit.",
+		},
+		[]string{"topic"},
+	)
 	numberOfTimesResyncedCounter = promauto.NewCounter(
 		prometheus.CounterOpts{
 			Name: "number_of_times_resynced",
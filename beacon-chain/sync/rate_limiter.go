@@ -0,0 +1,118 @@
+package sync
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+)
+
+// limiter keeps a token bucket per peer for each of the req/resp topics we
+// serve, so that a single peer cannot make us respond faster than the
+// configured rate on any given topic.
+type limiter struct {
+	limiterMap map[string]*leakybucket.Collector
+	p2p        p2p.P2P
+	sync.RWMutex
+}
+
+// newRateLimiter initializes the per-topic collectors. Block topics are
+// bounded by the block batch limit, as their cost is the number of requested
+// blocks, while every other topic is charged one token per request.
+func newRateLimiter(p2pProvider p2p.P2P) *limiter {
+	allowedBlocksPerSecond := float64(flags.Get().BlockBatchLimit)
+	allowedBlocksBurst := int64(flags.Get().BlockBatchLimitBurstFactor * flags.Get().BlockBatchLimit)
+	allowedRequestsPerSecond := float64(flags.Get().RPCRequestRateLimit)
+	allowedRequestsBurst := int64(flags.Get().RPCRequestBurstLimit)
+
+	topicMap := map[string]*leakybucket.Collector{
+		p2p.RPCStatusTopic:        leakybucket.NewCollector(allowedRequestsPerSecond, allowedRequestsBurst, false /* deleteEmptyBuckets */),
+		p2p.RPCGoodByeTopic:       leakybucket.NewCollector(allowedRequestsPerSecond, allowedRequestsBurst, false /* deleteEmptyBuckets */),
+		p2p.RPCPingTopic:          leakybucket.NewCollector(allowedRequestsPerSecond, allowedRequestsBurst, false /* deleteEmptyBuckets */),
+		p2p.RPCMetaDataTopic:      leakybucket.NewCollector(allowedRequestsPerSecond, allowedRequestsBurst, false /* deleteEmptyBuckets */),
+		p2p.RPCBlocksByRangeTopic: leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		p2p.RPCBlocksByRootTopic:  leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+	}
+	return &limiter{limiterMap: topicMap, p2p: p2pProvider}
+}
+
+// validateRequest checks whether the remote peer of the stream still has enough
+// capacity on the topic to be served a request of the given cost. A peer over
+// its limit receives an error response and has its bad responses incremented,
+// so that repeat offenders end up being disconnected.
+func (l *limiter) validateRequest(stream network.Stream, topic string, amt uint64) error {
+	l.RLock()
+	defer l.RUnlock()
+
+	collector, err := l.retrieveCollector(topic)
+	if err != nil {
+		return err
+	}
+	// Treat each request as a minimum of 1.
+	if amt == 0 {
+		amt = 1
+	}
+	id := stream.Conn().RemotePeer()
+	if int64(amt) <= collector.Remaining(id.String()) {
+		return nil
+	}
+	rateLimitedRequestCounter.WithLabelValues(topic).Inc()
+	l.p2p.Peers().Scorers().BadResponsesScorer().Increment(id)
+	if l.p2p.Peers().IsBad(id) {
+		log.WithField("peer", id).Debug("Disconnecting bad peer")
+		defer func() {
+			if err := l.p2p.Disconnect(id); err != nil {
+				log.WithError(err).Error("Failed to disconnect peer")
+			}
+		}()
+	}
+	writeErrorResponse(responseCodeInvalidRequest, rateLimitedError, stream, l.p2p.Encoding())
+	return errors.New(rateLimitedError)
+}
+
+// add charges the given cost to the remote peer's bucket for the topic.
+func (l *limiter) add(stream network.Stream, topic string, amt int64) {
+	l.RLock()
+	defer l.RUnlock()
+
+	collector, err := l.retrieveCollector(topic)
+	if err != nil {
+		log.WithError(err).Error("Could not charge rate limiter")
+		return
+	}
+	collector.Add(stream.Conn().RemotePeer().String(), amt)
+}
+
+// remaining returns the capacity the remote peer has left on the topic.
+func (l *limiter) remaining(stream network.Stream, topic string) int64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	collector, err := l.retrieveCollector(topic)
+	if err != nil {
+		return 0
+	}
+	return collector.Remaining(stream.Conn().RemotePeer().String())
+}
+
+// free releases all the collectors held by the limiter.
+func (l *limiter) free() {
+	l.Lock()
+	defer l.Unlock()
+
+	for t, collector := range l.limiterMap {
+		collector.Free()
+		delete(l.limiterMap, t)
+	}
+}
+
+func (l *limiter) retrieveCollector(topic string) (*leakybucket.Collector, error) {
+	collector, ok := l.limiterMap[topic]
+	if !ok {
+		return nil, errors.New("collector does not exist for topic " + topic)
+	}
+	return collector, nil
+}
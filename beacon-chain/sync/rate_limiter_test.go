@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kevinms/leakybucket-go"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/encoder"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	rlimiter := newRateLimiter(p2ptest.NewTestP2P(t))
+	topics := []string{
+		p2p.RPCStatusTopic,
+		p2p.RPCGoodByeTopic,
+		p2p.RPCBlocksByRangeTopic,
+		p2p.RPCBlocksByRootTopic,
+		p2p.RPCPingTopic,
+		p2p.RPCMetaDataTopic,
+	}
+	if len(rlimiter.limiterMap) != len(topics) {
+		t.Errorf("Expected %d collectors, got %d", len(topics), len(rlimiter.limiterMap))
+	}
+	for _, topic := range topics {
+		if _, err := rlimiter.retrieveCollector(topic); err != nil {
+			t.Errorf("No collector for topic %s: %v", topic, err)
+		}
+	}
+	if _, err := rlimiter.retrieveCollector("/testing/foobar/1"); err == nil {
+		t.Error("Expected error retrieving collector of unknown topic")
+	}
+}
+
+func TestRateLimiter_ExceedCapacity(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	p1.Peers().Add(nil, p2.PeerID(), p2.BHost.Addrs()[0], network.DirOutbound)
+
+	rlimiter := newRateLimiter(p1)
+	capacity := int64(3)
+	rlimiter.limiterMap[p2p.RPCPingTopic] = leakybucket.NewCollector(0.000001, capacity, false)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	pcl := protocol.ID("/testing")
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {
+		defer wg.Done()
+		code, errMsg, err := ReadStatusCode(stream, &encoder.SszNetworkEncoder{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != responseCodeInvalidRequest {
+			t.Errorf("Unexpected response code, want %d, got %d", responseCodeInvalidRequest, code)
+		}
+		if errMsg != rateLimitedError {
+			t.Errorf("Unexpected error message, want %q, got %q", rateLimitedError, errMsg)
+		}
+	})
+	stream, err := p1.BHost.NewStream(context.Background(), p2.PeerID(), pcl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := int64(0); i < capacity; i++ {
+		if err := rlimiter.validateRequest(stream, p2p.RPCPingTopic, 1); err != nil {
+			t.Fatalf("Request %d unexpectedly rate limited: %v", i, err)
+		}
+		rlimiter.add(stream, p2p.RPCPingTopic, 1)
+	}
+	if remaining := rlimiter.remaining(stream, p2p.RPCPingTopic); remaining != 0 {
+		t.Errorf("Expected no remaining capacity, got %d", remaining)
+	}
+
+	err = rlimiter.validateRequest(stream, p2p.RPCPingTopic, 1)
+	if err == nil || err.Error() != rateLimitedError {
+		t.Errorf("Expected error not thrown, want: %v, got: %v", rateLimitedError, err)
+	}
+	if testutil.WaitTimeout(&wg, 1*time.Second) {
+		t.Fatal("Did not receive stream within 1 sec")
+	}
+
+	badResponses, err := p1.Peers().Scorers().BadResponsesScorer().Count(p2.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badResponses != 1 {
+		t.Errorf("Expected 1 bad response, got %d", badResponses)
+	}
+
+	// Other topics keep their own allowance.
+	if err := rlimiter.validateRequest(stream, p2p.RPCBlocksByRootTopic, 1); err != nil {
+		t.Errorf("Unexpected error on separate topic: %v", err)
+	}
+}
+
+func TestRateLimiter_Free(t *testing.T) {
+	rlimiter := newRateLimiter(p2ptest.NewTestP2P(t))
+	rlimiter.free()
+	if len(rlimiter.limiterMap) != 0 {
+		t.Errorf("Expected all collectors to be removed, got %d", len(rlimiter.limiterMap))
+	}
+}
@@ -65,8 +65,8 @@ func (s *Service) registerRPCHandlers() {
 }
 
 // registerRPC for a given topic with an expected protobuf message type.
-func (s *Service) registerRPC(topic string, base interface{}, handle rpcHandler) {
-	topic += s.p2p.Encoding().ProtocolSuffix()
+func (s *Service) registerRPC(baseTopic string, base interface{}, handle rpcHandler) {
+	topic := baseTopic + s.p2p.Encoding().ProtocolSuffix()
 	log := log.WithField("topic", topic)
 	s.p2p.SetStreamHandler(topic, func(stream network.Stream) {
 		ctx, cancel := context.WithTimeout(context.Background(), ttfbTimeout)
@@ -90,6 +90,17 @@ func (s *Service) registerRPC(topic string, base interface{}, handle rpcHandler)
 		// Increment message received counter.
 		messageReceivedCounter.WithLabelValues(topic).Inc()
 
+		// Block requests are charged per requested block within their handlers, any
+		// other request costs a single token of the peer's allowance for the topic.
+		if s.rateLimiter != nil && baseTopic != p2p.RPCBlocksByRangeTopic && baseTopic != p2p.RPCBlocksByRootTopic {
+			if err := s.rateLimiter.validateRequest(stream, baseTopic, 1); err != nil {
+				log.WithError(err).Debug("Rejected p2p RPC")
+				traceutil.AnnotateError(span, err)
+				return
+			}
+			s.rateLimiter.add(stream, baseTopic, 1)
+		}
+
 		// since metadata requests do not have any data in the payload, we
 		// do not decode anything.
 		if strings.Contains(topic, p2p.RPCMetaDataTopic) {
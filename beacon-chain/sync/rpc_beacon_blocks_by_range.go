@@ -9,6 +9,7 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/params"
@@ -28,7 +29,6 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 	setRPCStreamDeadlines(stream)
-	log := log.WithField("handler", "beacon_blocks_by_range")
 
 	// Ticker to stagger out large requests.
 	ticker := time.NewTicker(time.Second)
@@ -52,7 +52,7 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	// The final requested slot from remote peer.
 	endReqSlot := startSlot + (m.Step * (m.Count - 1))
 
-	remainingBucketCapacity := s.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String())
+	remainingBucketCapacity := s.rateLimiter.remaining(stream, p2p.RPCBlocksByRangeTopic)
 	span.AddAttributes(
 		trace.Int64Attribute("start", int64(startSlot)),
 		trace.Int64Attribute("end", int64(endReqSlot)),
@@ -63,19 +63,9 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	)
 	maxRequestBlocks := params.BeaconNetworkConfig().MaxRequestBlocks
 	for startSlot <= endReqSlot {
-		remainingBucketCapacity = s.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String())
-		if int64(allowedBlocksPerSecond) > remainingBucketCapacity {
-			s.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
-			if s.p2p.Peers().IsBad(stream.Conn().RemotePeer()) {
-				log.Debug("Disconnecting bad peer")
-				defer func() {
-					if err := s.p2p.Disconnect(stream.Conn().RemotePeer()); err != nil {
-						log.WithError(err).Error("Failed to disconnect peer")
-					}
-				}()
-			}
-			s.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
-			return errors.New(rateLimitedError)
+		if err := s.rateLimiter.validateRequest(stream, p2p.RPCBlocksByRangeTopic, allowedBlocksPerSecond); err != nil {
+			traceutil.AnnotateError(span, err)
+			return err
 		}
 
 		// TODO(3147): Update this with reasonable constraints.
@@ -92,8 +82,7 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 
 		// Decrease allowed blocks capacity by the number of streamed blocks.
 		if startSlot <= endSlot {
-			s.blocksRateLimiter.Add(
-				stream.Conn().RemotePeer().String(), int64(1+(endSlot-startSlot)/m.Step))
+			s.rateLimiter.add(stream, p2p.RPCBlocksByRangeTopic, int64(1+(endSlot-startSlot)/m.Step))
 		}
 
 		// Recalculate start and end slots for the next batch to be returned to the remote peer.
@@ -142,8 +131,11 @@ func (s *Service) writeBlockRangeToStream(ctx context.Context, startSlot, endSlo
 			traceutil.AnnotateError(span, err)
 			return err
 		}
-		blks = append([]*ethpb.SignedBeaconBlock{genBlock}, blks...)
-		roots = append([][32]byte{genRoot}, roots...)
+		// A node started from a finalized checkpoint may not have the genesis block yet.
+		if genBlock != nil {
+			blks = append([]*ethpb.SignedBeaconBlock{genBlock}, blks...)
+			roots = append([][32]byte{genRoot}, roots...)
+		}
 	}
 	// Filter and sort our retrieved blocks, so that
 	// we only return valid sets of blocks.
@@ -180,14 +172,7 @@ func (s *Service) writeBlockRangeToStream(ctx context.Context, startSlot, endSlo
 }
 
 func (s *Service) writeErrorResponseToStream(responseCode byte, reason string, stream libp2pcore.Stream) {
-	resp, err := s.generateErrorResponse(responseCode, reason)
-	if err != nil {
-		log.WithError(err).Error("Failed to generate a response error")
-	} else {
-		if _, err := stream.Write(resp); err != nil {
-			log.WithError(err).Errorf("Failed to write to stream")
-		}
-	}
+	writeErrorResponse(responseCode, reason, stream, s.p2p.Encoding())
 }
 
 func (s *Service) retrieveGenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, [32]byte, error) {
@@ -195,6 +180,9 @@ func (s *Service) retrieveGenesisBlock(ctx context.Context) (*ethpb.SignedBeacon
 	if err != nil {
 		return nil, [32]byte{}, err
 	}
+	if genBlock == nil || genBlock.Block == nil {
+		return nil, [32]byte{}, nil
+	}
 	genRoot, err := stateutil.BlockRoot(genBlock.Block)
 	if err != nil {
 		return nil, [32]byte{}, err
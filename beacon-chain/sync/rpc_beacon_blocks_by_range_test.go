@@ -13,6 +13,7 @@ import (
 	chainMock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
 	db "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -43,8 +44,8 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 
 	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, int64(req.Count*10), false),
-		chain: &chainMock.ChainService{}}
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+	r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(0.000001, int64(req.Count*10), false)
 	pcl := protocol.ID("/testing")
 
 	var wg sync.WaitGroup
@@ -74,7 +75,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 
 	// Make sure that rate limiter doesn't limit capacity exceedingly.
-	remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+	remainingCapacity := r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic].Remaining(p2.PeerID().String())
 	expectedCapacity := int64(req.Count*10 - req.Count)
 	if remainingCapacity != expectedCapacity {
 		t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -109,8 +110,8 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsSortedBlocks(t *testing.T) {
 	}
 
 	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, int64(req.Count*10), false),
-		chain: &chainMock.ChainService{}}
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+	r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(0.000001, int64(req.Count*10), false)
 	pcl := protocol.ID("/testing")
 
 	var wg sync.WaitGroup
@@ -178,7 +179,8 @@ func TestRPCBeaconBlocksByRange_ReturnsGenesisBlock(t *testing.T) {
 		}
 	}
 
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(10000, 10000, false), chain: &chainMock.ChainService{}}
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+	r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(10000, 10000, false)
 	pcl := protocol.ID("/testing")
 
 	var wg sync.WaitGroup
@@ -273,7 +275,8 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * 3)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+		r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(0.000001, capacity, false)
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -288,7 +291,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 		testutil.AssertLogsDoNotContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic].Remaining(p2.PeerID().String())
 		expectedCapacity := int64(0) // Whole capacity is used, but no overflow.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -304,7 +307,8 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * 3)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+		r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(0.000001, capacity, false)
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -314,7 +318,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		saveBlocks(req)
 
 		hook.Reset()
-		for i := 0; i < p2.Peers().MaxBadResponses(); i++ {
+		for i := 0; i < p2.Peers().Scorers().BadResponsesScorer().Params().Threshold; i++ {
 			err := sendRequest(p1, p2, r, req, false)
 			if err == nil || err.Error() != rateLimitedError {
 				t.Errorf("Expected error not thrown, want: %v, got: %v", rateLimitedError, err)
@@ -323,7 +327,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		// Make sure that we were blocked indeed.
 		testutil.AssertLogsContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic].Remaining(p2.PeerID().String())
 		expectedCapacity := int64(0) // Whole capacity is used.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -339,7 +343,8 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * flags.Get().BlockBatchLimitBurstFactor)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(p1), chain: &chainMock.ChainService{}}
+		r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic] = leakybucket.NewCollector(0.000001, capacity, false)
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -358,7 +363,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 
 		// One more request should result in overflow.
 		hook.Reset()
-		for i := 0; i < p2.Peers().MaxBadResponses(); i++ {
+		for i := 0; i < p2.Peers().Scorers().BadResponsesScorer().Params().Threshold; i++ {
 			err := sendRequest(p1, p2, r, req, false)
 			if err == nil || err.Error() != rateLimitedError {
 				t.Errorf("Expected error not thrown, want: %v, got: %v", rateLimitedError, err)
@@ -366,7 +371,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 		testutil.AssertLogsContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.limiterMap[p2p.RPCBlocksByRangeTopic].Remaining(p2.PeerID().String())
 		expectedCapacity := int64(0) // Whole capacity is used.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -86,25 +86,8 @@ func (s *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 		return errors.New("no block roots provided")
 	}
 
-	if int64(len(req.BlockRoots)) > s.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String()) {
-		s.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
-		if s.p2p.Peers().IsBad(stream.Conn().RemotePeer()) {
-			log.Debug("Disconnecting bad peer")
-			defer func() {
-				if err := s.p2p.Disconnect(stream.Conn().RemotePeer()); err != nil {
-					log.WithError(err).Error("Failed to disconnect peer")
-				}
-			}()
-		}
-		resp, err := s.generateErrorResponse(responseCodeInvalidRequest, rateLimitedError)
-		if err != nil {
-			log.WithError(err).Error("Failed to generate a response error")
-		} else {
-			if _, err := stream.Write(resp); err != nil {
-				log.WithError(err).Errorf("Failed to write to stream")
-			}
-		}
-		return errors.New(rateLimitedError)
+	if err := s.rateLimiter.validateRequest(stream, p2p.RPCBlocksByRootTopic, uint64(len(req.BlockRoots))); err != nil {
+		return err
 	}
 
 	if uint64(len(req.BlockRoots)) > params.BeaconNetworkConfig().MaxRequestBlocks {
@@ -119,7 +102,7 @@ func (s *Service) beaconBlocksRootRPCHandler(ctx context.Context, msg interface{
 		return errors.New("requested more than the max block limit")
 	}
 
-	s.blocksRateLimiter.Add(stream.Conn().RemotePeer().String(), int64(len(req.BlockRoots)))
+	s.rateLimiter.add(stream, p2p.RPCBlocksByRootTopic, int64(len(req.BlockRoots)))
 
 	for _, root := range req.BlockRoots {
 		blk, err := s.db.Block(ctx, bytesutil.ToBytes32(root))
@@ -61,6 +61,7 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.MaxGoroutines,
 			cmd.ForceClearDB,
 			cmd.ClearDB,
+			flags.DBMigrateDryRunFlag,
 			cmd.ConfigFileFlag,
 			cmd.ChainConfigFileFlag,
 			cmd.GrpcMaxCallRecvMsgSizeFlag,
@@ -92,6 +93,9 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisableGRPCGateway,
 			flags.GRPCGatewayHost,
 			flags.GRPCGatewayPort,
+			flags.DisableStandardAPI,
+			flags.StandardAPIHost,
+			flags.StandardAPIPort,
 			flags.HTTPWeb3ProviderFlag,
 			flags.SetGCPercent,
 			flags.UnsafeSync,
@@ -101,6 +105,8 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisableDiscv5,
 			flags.BlockBatchLimit,
 			flags.BlockBatchLimitBurstFactor,
+			flags.RPCRequestRateLimit,
+			flags.RPCRequestBurstLimit,
 			flags.EnableDebugRPCEndpoints,
 			flags.SlotsPerArchivedPoint,
 		},
@@ -142,6 +148,16 @@ var appHelpFlagGroups = []flagGroup{
 			flags.InteropNumValidatorsFlag,
 		},
 	},
+	{
+		Name: "checkpoint-sync",
+		Flags: []cli.Flag{
+			flags.CheckpointStateFlag,
+			flags.CheckpointBlockFlag,
+			flags.CheckpointSyncProviderFlag,
+			flags.CheckpointBlockRootFlag,
+			flags.CheckpointEpochFlag,
+		},
+	},
 	{
 		Name: "archive",
 		Flags: []cli.Flag{
@@ -151,6 +167,13 @@ var appHelpFlagGroups = []flagGroup{
 			flags.ArchiveAttestationsFlag,
 		},
 	},
+	{
+		Name: "monitor",
+		Flags: []cli.Flag{
+			flags.MonitorIndicesFlag,
+			flags.MonitorPublicKeysFlag,
+		},
+	},
 }
 
 func init() {
@@ -5,6 +5,7 @@ package ethereum_beacon_rpc_v1
 
 import (
 	context "context"
+	encoding_binary "encoding/binary"
 	fmt "fmt"
 	proto "github.com/gogo/protobuf/proto"
 	types "github.com/gogo/protobuf/types"
@@ -626,6 +627,7 @@ type DebugPeerResponse_PeerInfo struct {
 	ProtocolVersion      string       `protobuf:"bytes,4,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
 	AgentVersion         string       `protobuf:"bytes,5,opt,name=agent_version,json=agentVersion,proto3" json:"agent_version,omitempty"`
 	PeerLatency          uint64       `protobuf:"varint,6,opt,name=peer_latency,json=peerLatency,proto3" json:"peer_latency,omitempty"`
+	PeerScore            float64      `protobuf:"fixed64,7,opt,name=peer_score,json=peerScore,proto3" json:"peer_score,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -706,6 +708,13 @@ func (m *DebugPeerResponse_PeerInfo) GetPeerLatency() uint64 {
 	return 0
 }
 
+func (m *DebugPeerResponse_PeerInfo) GetPeerScore() float64 {
+	if m != nil {
+		return m.PeerScore
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("ethereum.beacon.rpc.v1.LoggingLevelRequest_Level", LoggingLevelRequest_Level_name, LoggingLevelRequest_Level_value)
 	proto.RegisterType((*BeaconStateRequest)(nil), "ethereum.beacon.rpc.v1.BeaconStateRequest")
@@ -723,79 +732,79 @@ func init() {
 func init() { proto.RegisterFile("proto/beacon/rpc/v1/debug.proto", fileDescriptor_851e5cb2de3d61dd) }
 
 var fileDescriptor_851e5cb2de3d61dd = []byte{
-	// 1150 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xcd, 0x6e, 0x1c, 0x45,
-	0x10, 0xce, 0xd8, 0xbb, 0xb6, 0xa7, 0x76, 0x59, 0x3b, 0x9d, 0xc8, 0x59, 0x36, 0x89, 0xe3, 0x4c,
-	0xa2, 0xfc, 0x21, 0x66, 0xe4, 0x85, 0x03, 0x8a, 0x90, 0x90, 0xff, 0xe2, 0x58, 0x32, 0x49, 0x18,
-	0x27, 0x1c, 0x88, 0xd0, 0xa8, 0x3d, 0x53, 0xbb, 0x3b, 0x78, 0xdc, 0x3d, 0xe9, 0xee, 0x31, 0x38,
-	0xdc, 0x22, 0x44, 0x8e, 0x1c, 0x78, 0x19, 0x1e, 0x81, 0x23, 0x12, 0x2f, 0x80, 0x2c, 0x9e, 0x82,
-	0x13, 0xea, 0xee, 0x9d, 0xfd, 0x91, 0x77, 0x83, 0x41, 0xdc, 0xba, 0xbe, 0xaa, 0xfa, 0xaa, 0xbb,
-	0xaa, 0xba, 0xab, 0xe1, 0x46, 0x2e, 0xb8, 0xe2, 0xc1, 0x01, 0xd2, 0x98, 0xb3, 0x40, 0xe4, 0x71,
-	0x70, 0xbc, 0x16, 0x24, 0x78, 0x50, 0x74, 0x7d, 0xa3, 0x21, 0xcb, 0xa8, 0x7a, 0x28, 0xb0, 0x38,
-	0xf2, 0xad, 0x8d, 0x2f, 0xf2, 0xd8, 0x3f, 0x5e, 0x6b, 0x5d, 0x41, 0xd5, 0x0b, 0x8e, 0xd7, 0x68,
-	0x96, 0xf7, 0xe8, 0x5a, 0xc0, 0x78, 0x82, 0xd6, 0xa1, 0xe5, 0x8d, 0x31, 0xe6, 0xed, 0x5c, 0x33,
-	0x1e, 0xa1, 0x94, 0xb4, 0x8b, 0xb2, 0x6f, 0x73, 0xad, 0xcb, 0x79, 0x37, 0xc3, 0x80, 0xe6, 0x69,
-	0x40, 0x19, 0xe3, 0x8a, 0xaa, 0x94, 0xb3, 0x52, 0x7b, 0xb5, 0xaf, 0x35, 0xd2, 0x41, 0xd1, 0x09,
-	0xf0, 0x28, 0x57, 0x27, 0x56, 0xe9, 0xbd, 0x04, 0xb2, 0x61, 0xa8, 0xf7, 0x15, 0x55, 0x18, 0xe2,
-	0xab, 0x02, 0xa5, 0x22, 0x97, 0xa1, 0x22, 0x33, 0xae, 0x9a, 0xce, 0xaa, 0x73, 0xaf, 0xf2, 0xf8,
-	0x42, 0x68, 0x24, 0x72, 0x03, 0xe0, 0x20, 0xe3, 0xf1, 0x61, 0x24, 0x38, 0x57, 0xcd, 0x99, 0x55,
-	0xe7, 0x5e, 0xfd, 0xf1, 0x85, 0xd0, 0x35, 0x58, 0xc8, 0xb9, 0xda, 0x68, 0x40, 0xfd, 0x55, 0x81,
-	0xe2, 0x24, 0xea, 0xa4, 0x99, 0x42, 0xe1, 0x7d, 0x08, 0xf5, 0x0d, 0xa3, 0xec, 0xd3, 0x5e, 0x1f,
-	0x23, 0xd0, 0xe4, 0xf5, 0x11, 0x77, 0xef, 0x2e, 0xd4, 0xf6, 0xf7, 0xbf, 0x0a, 0x51, 0xe6, 0x9c,
-	0x49, 0x24, 0x4d, 0x98, 0x47, 0x16, 0xf3, 0x04, 0x93, 0xbe, 0x69, 0x29, 0x7a, 0x6f, 0x1d, 0xb8,
-	0xb4, 0xc7, 0xbb, 0xdd, 0x94, 0x75, 0xf7, 0xf0, 0x18, 0xb3, 0x92, 0x7f, 0x07, 0xaa, 0x99, 0x96,
-	0x8d, 0x7d, 0xa3, 0xbd, 0xe6, 0x4f, 0x4e, 0xb6, 0x3f, 0xc1, 0xd7, 0xb7, 0x82, 0xf5, 0xf7, 0xee,
-	0x42, 0xd5, 0xc8, 0x64, 0x01, 0x2a, 0xbb, 0x4f, 0x1e, 0x3d, 0x5d, 0xba, 0x40, 0x5c, 0xa8, 0x6e,
-	0x6d, 0x6f, 0xbc, 0xd8, 0x59, 0x72, 0xf4, 0xf2, 0x79, 0xb8, 0xbe, 0xb9, 0xbd, 0x34, 0xe3, 0xfd,
-	0x38, 0x0b, 0xd7, 0x9e, 0xe9, 0x44, 0xae, 0x0b, 0x41, 0x4f, 0x1e, 0x71, 0x71, 0xb8, 0xd9, 0xe3,
-	0x69, 0x8c, 0x83, 0x43, 0xdc, 0x85, 0xc5, 0x5c, 0x14, 0x0c, 0x23, 0xd5, 0x13, 0x28, 0x7b, 0x3c,
-	0xb3, 0x87, 0xa9, 0x84, 0x0d, 0x03, 0x3f, 0x2f, 0x51, 0x6d, 0xf8, 0x4d, 0x21, 0x55, 0xda, 0x49,
-	0x31, 0x89, 0x30, 0xe7, 0x71, 0xcf, 0x64, 0xb8, 0x12, 0x36, 0x06, 0xf0, 0xb6, 0x46, 0xb5, 0x61,
-	0x27, 0x65, 0x34, 0x4b, 0x5f, 0x0f, 0x0c, 0x67, 0xad, 0xe1, 0x00, 0xb6, 0x86, 0x21, 0x5c, 0x34,
-	0x35, 0x8e, 0xa8, 0xde, 0x5b, 0xa4, 0x7b, 0x4a, 0x36, 0x2b, 0xab, 0xb3, 0xf7, 0x6a, 0xed, 0x3b,
-	0xd3, 0x32, 0x33, 0x3c, 0xcb, 0x13, 0x9e, 0x60, 0xb8, 0x98, 0x8f, 0xc9, 0x92, 0xbc, 0x84, 0xf9,
-	0x94, 0x25, 0x69, 0x8c, 0xb2, 0x59, 0x35, 0x4c, 0xeb, 0xff, 0xcc, 0x74, 0x36, 0x2b, 0xfe, 0xae,
-	0xe5, 0xd8, 0x66, 0x4a, 0x9c, 0x84, 0x25, 0x63, 0xeb, 0x21, 0xd4, 0x47, 0x15, 0x64, 0x09, 0x66,
-	0x0f, 0xf1, 0xc4, 0xe4, 0xcb, 0x0d, 0xf5, 0x92, 0x5c, 0x86, 0xea, 0x31, 0xcd, 0x0a, 0xec, 0xa7,
-	0xc6, 0x0a, 0x0f, 0x67, 0x3e, 0x71, 0xbc, 0x37, 0x33, 0xd0, 0x18, 0xdf, 0x3c, 0x21, 0xa3, 0x4d,
-	0xdc, 0x6f, 0x61, 0x02, 0x95, 0x61, 0xf3, 0x86, 0x66, 0x4d, 0x96, 0x61, 0x2e, 0xa7, 0x02, 0x99,
-	0xea, 0xe7, 0xb1, 0x2f, 0x4d, 0xaa, 0x48, 0xe5, 0xbc, 0x15, 0xa9, 0x4e, 0xac, 0xc8, 0x32, 0xcc,
-	0x7d, 0x8b, 0x69, 0xb7, 0xa7, 0x9a, 0x73, 0x36, 0x92, 0x95, 0xcc, 0xbd, 0x40, 0xa9, 0xa2, 0xb8,
-	0x97, 0x66, 0x49, 0x73, 0xde, 0xe8, 0x5c, 0x8d, 0x6c, 0x6a, 0x40, 0xf3, 0x1b, 0x75, 0x82, 0x32,
-	0x46, 0x96, 0x50, 0xa6, 0x9a, 0x0b, 0x96, 0x5f, 0xc3, 0x5b, 0x03, 0xd4, 0xfb, 0x1a, 0xc8, 0x96,
-	0x7e, 0x6b, 0x9e, 0x21, 0x8a, 0x32, 0xd7, 0x92, 0xec, 0x80, 0x2b, 0x4a, 0xa1, 0xe9, 0x98, 0xaa,
-	0xdd, 0x9f, 0x56, 0xb5, 0x33, 0xee, 0xe1, 0xd0, 0xd7, 0xfb, 0xa5, 0x0a, 0x17, 0xcf, 0x18, 0x90,
-	0x00, 0x2e, 0x65, 0xa9, 0x54, 0xc8, 0x52, 0xd6, 0x8d, 0x68, 0x92, 0x08, 0x94, 0x65, 0x20, 0x37,
-	0x24, 0x03, 0xd5, 0x7a, 0xa9, 0x21, 0x1b, 0xe0, 0x26, 0xa9, 0xc0, 0x58, 0xbf, 0x51, 0xa6, 0x10,
-	0x8d, 0xf6, 0xed, 0xe1, 0x7e, 0x50, 0xf5, 0xfc, 0xf2, 0x1d, 0xf4, 0x75, 0xa0, 0xad, 0xd2, 0x36,
-	0x1c, 0xba, 0x91, 0x2f, 0x60, 0x29, 0xe6, 0x8c, 0x59, 0x29, 0x92, 0xfa, 0xed, 0x32, 0xd5, 0x6b,
-	0x8c, 0xb6, 0xf6, 0x18, 0xd5, 0xe6, 0xc0, 0xdc, 0xbe, 0x74, 0x8b, 0xf1, 0x38, 0x40, 0xae, 0xc0,
-	0x7c, 0x8e, 0x28, 0xa2, 0x34, 0x31, 0x65, 0x76, 0xc3, 0x39, 0x2d, 0xee, 0x26, 0xba, 0x0d, 0x91,
-	0x09, 0x53, 0x52, 0x37, 0xd4, 0x4b, 0xf2, 0x14, 0x5c, 0x6b, 0xca, 0x3a, 0xdc, 0x94, 0xb2, 0xd6,
-	0x6e, 0x9f, 0x3b, 0xa3, 0xe6, 0x50, 0xbb, 0xac, 0xc3, 0xc3, 0x85, 0xbc, 0xbf, 0x22, 0x9f, 0x41,
-	0xcd, 0x10, 0xea, 0x83, 0x14, 0xd2, 0x74, 0x40, 0xad, 0xbd, 0x72, 0x86, 0x32, 0x6f, 0xe7, 0x9a,
-	0x72, 0xdf, 0x58, 0x85, 0xa0, 0x5d, 0xec, 0x9a, 0xdc, 0x84, 0x7a, 0x46, 0xa5, 0x8a, 0x8a, 0x3c,
-	0xa1, 0x0a, 0x93, 0x7e, 0x7f, 0xd4, 0x34, 0xf6, 0xc2, 0x42, 0xad, 0xbf, 0x1c, 0x58, 0x28, 0x43,
-	0x93, 0x4f, 0x61, 0xe1, 0x08, 0x15, 0x4d, 0xa8, 0xa2, 0xe6, 0x7e, 0xd4, 0xda, 0xab, 0xd3, 0xa2,
-	0x7d, 0x8e, 0x8a, 0x6e, 0x51, 0x45, 0xc3, 0x81, 0x07, 0xb9, 0x06, 0xae, 0x79, 0x18, 0x62, 0x9e,
-	0xc9, 0xe6, 0x8c, 0x29, 0xf4, 0x10, 0x20, 0x37, 0xa0, 0xd6, 0xa1, 0x45, 0xa6, 0xa2, 0x98, 0x17,
-	0x83, 0x4b, 0x05, 0x06, 0xda, 0xd4, 0x08, 0xb9, 0x0f, 0x4b, 0xa5, 0x75, 0x74, 0x8c, 0x42, 0xea,
-	0x3e, 0xb0, 0x29, 0x5f, 0x2c, 0xf1, 0x2f, 0x2d, 0x4c, 0x6e, 0xc1, 0x7b, 0xb4, 0x8b, 0x4c, 0x0d,
-	0xec, 0x6c, 0x15, 0xea, 0x06, 0x2c, 0x8d, 0x6e, 0x42, 0xdd, 0x64, 0x2f, 0xa3, 0x0a, 0x59, 0x7c,
-	0xd2, 0xbf, 0x5c, 0x26, 0xa3, 0x7b, 0x16, 0x6a, 0xbf, 0x9d, 0x83, 0xaa, 0xa9, 0x04, 0xf9, 0xc1,
-	0x81, 0xc6, 0x0e, 0xaa, 0x91, 0xa1, 0x47, 0x1e, 0x4c, 0xab, 0xdd, 0xd9, 0xc9, 0xd8, 0xba, 0x35,
-	0xcd, 0x76, 0x64, 0x72, 0x79, 0x37, 0xdf, 0xfc, 0xfe, 0xe7, 0xcf, 0x33, 0x57, 0xc9, 0xfb, 0xc1,
-	0xd8, 0x54, 0x37, 0xff, 0x80, 0xc0, 0x34, 0x2b, 0xf9, 0x0e, 0x16, 0xf4, 0x2e, 0xf4, 0xec, 0x23,
-	0xb7, 0xa7, 0xc6, 0x1f, 0x19, 0x9e, 0xff, 0x43, 0x64, 0x33, 0x69, 0xc9, 0xf7, 0xb0, 0xb8, 0x8f,
-	0x6a, 0x74, 0x04, 0x92, 0x0f, 0xfe, 0xc5, 0xa0, 0x6c, 0x2d, 0xfb, 0xf6, 0x3f, 0xe1, 0x97, 0xff,
-	0x09, 0x7f, 0x5b, 0xff, 0x27, 0xbc, 0x5b, 0x26, 0xf4, 0x75, 0xef, 0xea, 0xa4, 0xd0, 0x99, 0x25,
-	0x22, 0x3f, 0x39, 0x70, 0x65, 0x07, 0xd5, 0xa4, 0xe1, 0x40, 0xa6, 0x10, 0xb7, 0x3e, 0xfe, 0x2f,
-	0x23, 0xc6, 0xbb, 0x63, 0xb6, 0xb3, 0x4a, 0x56, 0x26, 0x6d, 0xa7, 0xc3, 0xc5, 0x61, 0x6c, 0xa3,
-	0x0a, 0x70, 0xf7, 0x52, 0xa9, 0xf4, 0xcd, 0x90, 0x53, 0xb7, 0xf0, 0xe0, 0xdc, 0xb7, 0x5b, 0xbe,
-	0xbb, 0x04, 0xb9, 0x09, 0xf3, 0x1a, 0xe6, 0x75, 0x12, 0x10, 0x05, 0xf1, 0xde, 0xf1, 0xf2, 0x95,
-	0x19, 0x3f, 0xff, 0x6b, 0xed, 0xad, 0x9a, 0xe0, 0x2d, 0xd2, 0x9c, 0x16, 0x7c, 0xa3, 0xfe, 0xeb,
-	0xe9, 0x8a, 0xf3, 0xdb, 0xe9, 0x8a, 0xf3, 0xc7, 0xe9, 0x8a, 0x73, 0x30, 0x67, 0x0e, 0xfa, 0xd1,
-	0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xb0, 0xb3, 0x9f, 0x03, 0xb8, 0x0a, 0x00, 0x00,
+	// 1148 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xad, 0x56, 0x4b, 0x6f, 0xdb, 0x46,
+	0x10, 0x8e, 0x64, 0xc9, 0x92, 0x46, 0xaa, 0xac, 0x6c, 0x0a, 0x5b, 0x95, 0x1d, 0x47, 0xa1, 0x83,
+	0xbc, 0x8a, 0x92, 0xb0, 0xda, 0x43, 0x11, 0x14, 0x28, 0x2c, 0xdb, 0x71, 0x0d, 0xb8, 0x79, 0x50,
+	0x49, 0x0f, 0x0d, 0x0a, 0x82, 0x22, 0x57, 0x12, 0x6b, 0x9a, 0x64, 0xf9, 0x70, 0xab, 0xf4, 0x16,
+	0x14, 0xcd, 0xb1, 0x87, 0x9e, 0xfa, 0x8f, 0x7a, 0x2c, 0xd0, 0x43, 0xaf, 0x45, 0xd1, 0x1f, 0xd2,
+	0xd9, 0x59, 0x52, 0x0f, 0x58, 0x4a, 0xdd, 0x22, 0x07, 0x02, 0x3b, 0xdf, 0x7c, 0x3b, 0xb3, 0xf3,
+	0xd8, 0x1d, 0xc2, 0x8d, 0x20, 0xf4, 0x63, 0x5f, 0xeb, 0x73, 0xd3, 0xf2, 0x3d, 0x2d, 0x0c, 0x2c,
+	0xed, 0x7c, 0x57, 0xb3, 0x79, 0x3f, 0x19, 0xaa, 0xa4, 0x61, 0xeb, 0x3c, 0x1e, 0xf1, 0x90, 0x27,
+	0x67, 0xaa, 0xe4, 0xa8, 0xc8, 0x51, 0xcf, 0x77, 0x5b, 0x1b, 0x88, 0x23, 0xd7, 0x74, 0x83, 0x91,
+	0xb9, 0xab, 0x79, 0xbe, 0xcd, 0xe5, 0x86, 0x96, 0x32, 0x67, 0x31, 0xe8, 0x04, 0xc2, 0xe2, 0x19,
+	0x8f, 0x22, 0x73, 0xc8, 0xa3, 0x94, 0xb3, 0x35, 0xf4, 0xfd, 0xa1, 0xcb, 0x35, 0x33, 0x70, 0x34,
+	0xd3, 0xf3, 0xfc, 0xd8, 0x8c, 0x1d, 0xdf, 0xcb, 0xb4, 0x9b, 0xa9, 0x96, 0xa4, 0x7e, 0x32, 0xd0,
+	0xf8, 0x59, 0x10, 0x8f, 0xa5, 0x52, 0x79, 0x01, 0xac, 0x4b, 0xa6, 0x7b, 0xb8, 0x89, 0xeb, 0xfc,
+	0x9b, 0x84, 0x47, 0x31, 0x7b, 0x17, 0x0a, 0x91, 0xeb, 0xc7, 0xcd, 0x5c, 0x3b, 0x77, 0xb7, 0xf0,
+	0xd9, 0x15, 0x9d, 0x24, 0x76, 0x03, 0xa0, 0xef, 0xfa, 0xd6, 0xa9, 0x11, 0xfa, 0xa8, 0xcb, 0xa3,
+	0xae, 0x86, 0xba, 0x0a, 0x61, 0x3a, 0x42, 0xdd, 0x3a, 0xd4, 0x70, 0x7f, 0x38, 0x36, 0x06, 0x8e,
+	0x1b, 0xf3, 0x50, 0xf9, 0x00, 0x6a, 0x5d, 0x52, 0xa6, 0x66, 0xaf, 0xcf, 0x19, 0x10, 0xc6, 0x6b,
+	0x33, 0xdb, 0x95, 0x3b, 0x50, 0xed, 0xf5, 0xbe, 0xd4, 0x79, 0x14, 0xe0, 0xe1, 0x39, 0x6b, 0x42,
+	0x89, 0x7b, 0x16, 0x66, 0xc2, 0x4e, 0xa9, 0x99, 0xa8, 0xbc, 0xce, 0xc1, 0xb5, 0x13, 0x7f, 0x38,
+	0x74, 0xbc, 0xe1, 0x09, 0x3f, 0xe7, 0x6e, 0x66, 0xff, 0x08, 0x8a, 0xae, 0x90, 0x89, 0x5f, 0xef,
+	0xec, 0xaa, 0x8b, 0x93, 0xad, 0x2e, 0xd8, 0xab, 0x4a, 0x41, 0xee, 0xc7, 0x93, 0x14, 0x49, 0x66,
+	0x65, 0x28, 0x1c, 0x3f, 0x7a, 0xf8, 0xb8, 0x71, 0x85, 0x55, 0xa0, 0x78, 0x70, 0xd8, 0x7d, 0x7e,
+	0xd4, 0xc8, 0x89, 0xe5, 0x33, 0x7d, 0x6f, 0xff, 0xb0, 0x91, 0x57, 0x7e, 0x5c, 0x81, 0xad, 0x27,
+	0x22, 0x91, 0x7b, 0x61, 0x68, 0x8e, 0x1f, 0xfa, 0xe1, 0xe9, 0xfe, 0xc8, 0x77, 0x2c, 0x3e, 0x09,
+	0xe2, 0x0e, 0xac, 0x05, 0x61, 0xe2, 0x71, 0x23, 0x1e, 0x85, 0x3c, 0x1a, 0xf9, 0xae, 0x0c, 0xa6,
+	0xa0, 0xd7, 0x09, 0x7e, 0x96, 0xa1, 0x82, 0xf8, 0x75, 0x12, 0xc5, 0xce, 0xc0, 0xe1, 0xb6, 0xc1,
+	0x03, 0xdf, 0x1a, 0x51, 0x86, 0x91, 0x38, 0x81, 0x0f, 0x05, 0x2a, 0x88, 0x03, 0xc7, 0x33, 0x5d,
+	0xe7, 0xe5, 0x84, 0xb8, 0x22, 0x89, 0x13, 0x58, 0x12, 0x75, 0xb8, 0x4a, 0x35, 0x36, 0x4c, 0x71,
+	0x36, 0x43, 0xf4, 0x54, 0xd4, 0x2c, 0xb4, 0x57, 0xee, 0x56, 0x3b, 0xb7, 0x97, 0x65, 0x66, 0x1a,
+	0xcb, 0x23, 0xa4, 0xeb, 0x6b, 0xc1, 0x9c, 0x1c, 0xb1, 0x17, 0x50, 0x72, 0x3c, 0x1b, 0x03, 0x8c,
+	0x9a, 0x45, 0xb2, 0xb4, 0xf7, 0xef, 0x96, 0x2e, 0x66, 0x45, 0x3d, 0x96, 0x36, 0x0e, 0xbd, 0x38,
+	0x1c, 0xeb, 0x99, 0xc5, 0xd6, 0x03, 0xa8, 0xcd, 0x2a, 0x58, 0x03, 0x56, 0x4e, 0xf9, 0x98, 0xf2,
+	0x55, 0xd1, 0xc5, 0x12, 0xfb, 0xb2, 0x78, 0x6e, 0xba, 0x09, 0x4f, 0x53, 0x23, 0x85, 0x07, 0xf9,
+	0x8f, 0x73, 0xca, 0xab, 0x3c, 0xd4, 0xe7, 0x0f, 0xcf, 0xd8, 0x6c, 0x13, 0xa7, 0x2d, 0x8c, 0xd8,
+	0xb4, 0x79, 0x75, 0x5a, 0xb3, 0x75, 0x58, 0x0d, 0xcc, 0x90, 0x7b, 0x71, 0x9a, 0xc7, 0x54, 0x5a,
+	0x54, 0x91, 0xc2, 0x65, 0x2b, 0x52, 0x5c, 0x58, 0x11, 0xf4, 0xf4, 0x2d, 0x77, 0x86, 0xa3, 0xb8,
+	0xb9, 0x2a, 0x3d, 0x49, 0x89, 0xee, 0x05, 0xf6, 0xa0, 0x61, 0x8d, 0x1c, 0xec, 0x8f, 0x12, 0xe9,
+	0x2a, 0x02, 0xd9, 0x17, 0x80, 0xb0, 0x4f, 0x6a, 0x2c, 0x80, 0xc5, 0x3d, 0xdb, 0xc4, 0x93, 0x96,
+	0xa5, 0x7d, 0x01, 0x1f, 0x4c, 0x50, 0xe5, 0x2b, 0x60, 0x07, 0xe2, 0xad, 0x79, 0xc2, 0x79, 0x98,
+	0xe5, 0x3a, 0xc2, 0x5b, 0x51, 0x09, 0x33, 0x01, 0x93, 0x21, 0xaa, 0x76, 0x6f, 0x59, 0xd5, 0x2e,
+	0x6c, 0xd7, 0xa7, 0x7b, 0x95, 0x3f, 0x8a, 0x70, 0xf5, 0x02, 0x81, 0x69, 0x70, 0xcd, 0x75, 0xa2,
+	0x98, 0x7b, 0x78, 0xa3, 0x0c, 0xd3, 0xb6, 0x91, 0x9f, 0x39, 0xaa, 0xe8, 0x6c, 0xa2, 0xda, 0xcb,
+	0x34, 0xac, 0x0b, 0x15, 0xdb, 0x09, 0xb9, 0x25, 0xde, 0x28, 0x2a, 0x44, 0xbd, 0x73, 0x6b, 0x7a,
+	0x1e, 0x5c, 0xa8, 0xd9, 0x3b, 0xa8, 0x0a, 0x47, 0x07, 0x19, 0x57, 0x9f, 0x6e, 0x63, 0x4f, 0xa1,
+	0x81, 0xa7, 0xf6, 0xa4, 0x64, 0x44, 0xe2, 0xed, 0xa2, 0xea, 0xd5, 0x67, 0x5b, 0x7b, 0xce, 0xd4,
+	0xfe, 0x84, 0x2e, 0x5f, 0xba, 0x35, 0x6b, 0x1e, 0x60, 0x1b, 0x50, 0x0a, 0xd0, 0x9d, 0xe1, 0xd8,
+	0x54, 0xe6, 0x0a, 0xf6, 0x01, 0x8a, 0xc7, 0xb6, 0x68, 0x43, 0xee, 0x85, 0x54, 0x52, 0x6c, 0x43,
+	0x5c, 0xb2, 0xc7, 0x50, 0x91, 0x54, 0x6f, 0xe0, 0x53, 0x29, 0xab, 0x9d, 0xce, 0xa5, 0x33, 0x4a,
+	0x41, 0x1d, 0xe3, 0x4e, 0xbd, 0x1c, 0xa4, 0x2b, 0xf6, 0x29, 0x54, 0xc9, 0xa0, 0x08, 0x24, 0x89,
+	0xa8, 0x03, 0xaa, 0x9d, 0xed, 0x0b, 0x26, 0xf1, 0xf5, 0x17, 0x26, 0x7b, 0xc4, 0xd2, 0x41, 0x6c,
+	0x91, 0x6b, 0x76, 0x13, 0x6a, 0xae, 0x89, 0x2d, 0x92, 0x04, 0x36, 0xc6, 0x62, 0xa7, 0xfd, 0x51,
+	0x15, 0xd8, 0x73, 0x09, 0xb5, 0x7e, 0xc9, 0x43, 0x39, 0x73, 0xcd, 0x3e, 0x81, 0xf2, 0x19, 0x8f,
+	0x4d, 0xd4, 0x98, 0x74, 0x3f, 0xaa, 0x9d, 0xf6, 0x32, 0x6f, 0x9f, 0x23, 0xef, 0x00, 0x79, 0xfa,
+	0x64, 0x07, 0xdb, 0xc2, 0xf8, 0xc5, 0x5d, 0xb3, 0x7c, 0x37, 0xc2, 0x0a, 0x8a, 0x42, 0x4f, 0x01,
+	0x1c, 0x13, 0xd5, 0x81, 0x99, 0xb8, 0xd8, 0xce, 0x7e, 0x32, 0xb9, 0x54, 0x40, 0xd0, 0xbe, 0x40,
+	0xd8, 0x3d, 0x68, 0x64, 0x6c, 0xe3, 0x9c, 0x87, 0x91, 0xe8, 0x03, 0x99, 0xf2, 0xb5, 0x0c, 0xff,
+	0x42, 0xc2, 0x6c, 0x07, 0xde, 0xc1, 0x39, 0xe7, 0xc5, 0x13, 0x9e, 0xac, 0x42, 0x8d, 0xc0, 0x8c,
+	0x84, 0xc1, 0x53, 0xf6, 0x5c, 0x8c, 0xd3, 0xb3, 0xc6, 0xe9, 0xe5, 0xa2, 0x8c, 0x9e, 0x48, 0x48,
+	0xdc, 0x30, 0x99, 0x60, 0xcb, 0x0f, 0x39, 0xe5, 0x37, 0xa7, 0x53, 0x0d, 0x7b, 0x02, 0xe8, 0xbc,
+	0x5e, 0xc5, 0xd7, 0x5d, 0x14, 0x8a, 0xfd, 0x90, 0x83, 0xfa, 0x11, 0x8f, 0x67, 0x66, 0x22, 0xbb,
+	0xbf, 0xac, 0xb4, 0x17, 0x07, 0x67, 0x6b, 0x67, 0x19, 0x77, 0x66, 0xb0, 0x29, 0x37, 0x5f, 0xfd,
+	0xfe, 0xf7, 0xcf, 0xf9, 0x4d, 0xf6, 0x9e, 0x36, 0x37, 0xf4, 0xe9, 0x37, 0x41, 0xa3, 0x5e, 0x66,
+	0xdf, 0x41, 0x59, 0x9c, 0x42, 0x8c, 0x46, 0x76, 0x6b, 0xa9, 0xff, 0x99, 0xd9, 0xfa, 0x16, 0x3c,
+	0xd3, 0x20, 0x66, 0xdf, 0xc3, 0x5a, 0x8f, 0xc7, 0xb3, 0x13, 0x92, 0xbd, 0xff, 0x1f, 0xe6, 0x68,
+	0x6b, 0x5d, 0x95, 0xbf, 0x1b, 0x6a, 0xf6, 0xbb, 0xa1, 0x1e, 0x8a, 0xdf, 0x0d, 0x65, 0x87, 0x5c,
+	0x5f, 0x57, 0x36, 0x17, 0xb9, 0x76, 0xa5, 0x21, 0xf6, 0x53, 0x0e, 0x36, 0x30, 0xee, 0x45, 0xb3,
+	0x83, 0x2d, 0x31, 0xdc, 0xfa, 0xe8, 0xff, 0x4c, 0x20, 0xe5, 0x36, 0x1d, 0xa7, 0xcd, 0xb6, 0x17,
+	0x1d, 0x67, 0x80, 0x7c, 0x4b, 0x7a, 0x0d, 0xa1, 0x72, 0x82, 0x4f, 0x98, 0xb8, 0x38, 0xd1, 0xd2,
+	0x23, 0xdc, 0xbf, 0xf4, 0xe5, 0x8f, 0xde, 0x5c, 0x82, 0x80, 0xdc, 0xbc, 0x84, 0x92, 0x48, 0x02,
+	0xae, 0x99, 0xf2, 0x86, 0x87, 0x31, 0xcb, 0xf8, 0xe5, 0x1f, 0x73, 0xa5, 0x4d, 0xce, 0x5b, 0xac,
+	0xb9, 0xcc, 0x79, 0xb7, 0xf6, 0xeb, 0x5f, 0xdb, 0xb9, 0xdf, 0xf0, 0xfb, 0x13, 0xbf, 0xfe, 0x2a,
+	0x05, 0xfa, 0xe1, 0x3f, 0x3b, 0x84, 0x23, 0xa8, 0xd7, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1518,6 +1527,12 @@ func (m *DebugPeerResponse_PeerInfo) MarshalToSizedBuffer(dAtA []byte) (int, err
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.PeerScore != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.PeerScore))))
+		i--
+		dAtA[i] = 0x39
+	}
 	if m.PeerLatency != 0 {
 		i = encodeVarintDebug(dAtA, i, uint64(m.PeerLatency))
 		i--
@@ -1823,6 +1838,9 @@ func (m *DebugPeerResponse_PeerInfo) Size() (n int) {
 	if m.PeerLatency != 0 {
 		n += 1 + sovDebug(uint64(m.PeerLatency))
 	}
+	if m.PeerScore != 0 {
+		n += 9
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -3236,6 +3254,17 @@ func (m *DebugPeerResponse_PeerInfo) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PeerScore", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.PeerScore = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipDebug(dAtA[iNdEx:])
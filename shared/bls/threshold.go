@@ -0,0 +1,100 @@
+package bls
+
+import (
+	"fmt"
+	"strconv"
+
+	bls12 "github.com/herumi/bls-eth-go-binary/bls"
+	"github.com/pkg/errors"
+)
+
+// SplitSecretKey splits a secret key into total Shamir shares, any threshold of which can
+// produce signatures of the key. Share i, starting at 0, has the share id i+1. The
+// verification vector holds the public keys of the coefficients of the sharing polynomial,
+// starting with the public key of the secret key, and lets anyone compute the public key of
+// a share with PublicKeyShare.
+func SplitSecretKey(secretKey *SecretKey, threshold uint64, total uint64) ([]*SecretKey, []*PublicKey, error) {
+	if threshold == 0 || threshold > total {
+		return nil, nil, fmt.Errorf("threshold %d must be between 1 and the number of shares %d", threshold, total)
+	}
+	msk := secretKey.p.GetMasterSecretKey(int(threshold))
+	shares := make([]*SecretKey, total)
+	for i := uint64(0); i < total; i++ {
+		id, err := shareID(i + 1)
+		if err != nil {
+			return nil, nil, err
+		}
+		share := &bls12.SecretKey{}
+		if err := share.Set(msk, id); err != nil {
+			return nil, nil, errors.Wrapf(err, "could not compute share %d", i+1)
+		}
+		shares[i] = &SecretKey{p: share}
+	}
+	mpk := bls12.GetMasterPublicKey(msk)
+	verificationVector := make([]*PublicKey, len(mpk))
+	for i := range mpk {
+		verificationVector[i] = &PublicKey{p: &mpk[i]}
+	}
+	return shares, verificationVector, nil
+}
+
+// PublicKeyShare computes the public key of the share with the given id from the verification
+// vector of a split secret key.
+func PublicKeyShare(verificationVector []*PublicKey, id uint64) (*PublicKey, error) {
+	if len(verificationVector) == 0 {
+		return nil, errors.New("empty verification vector")
+	}
+	point, err := shareID(id)
+	if err != nil {
+		return nil, err
+	}
+	mpk := make([]bls12.PublicKey, len(verificationVector))
+	for i, p := range verificationVector {
+		mpk[i] = *p.p
+	}
+	pub := &bls12.PublicKey{}
+	if err := pub.Set(mpk, point); err != nil {
+		return nil, errors.Wrapf(err, "could not compute public key of share %d", id)
+	}
+	return &PublicKey{p: pub}, nil
+}
+
+// RecoverSignature combines signatures of the same message by the shares with the given ids
+// into the signature of the split secret key, using Lagrange interpolation. The signatures of
+// at least threshold shares are required, otherwise the result is not a valid signature.
+func RecoverSignature(sigs []*Signature, ids []uint64) (*Signature, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("no signatures to recover from")
+	}
+	if len(sigs) != len(ids) {
+		return nil, fmt.Errorf("received %d signatures but %d share ids", len(sigs), len(ids))
+	}
+	sigVec := make([]bls12.Sign, len(sigs))
+	idVec := make([]bls12.ID, len(ids))
+	for i := range sigs {
+		sigVec[i] = *sigs[i].s
+		id, err := shareID(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		idVec[i] = *id
+	}
+	sig := &bls12.Sign{}
+	if err := sig.Recover(sigVec, idVec); err != nil {
+		return nil, errors.Wrap(err, "could not recover signature")
+	}
+	return &Signature{s: sig}, nil
+}
+
+// shareID converts a share id to the point the sharing polynomial is evaluated at. Id 0 is
+// the secret key itself, so it is not a valid share id.
+func shareID(id uint64) (*bls12.ID, error) {
+	if id == 0 {
+		return nil, errors.New("share id must not be 0")
+	}
+	point := &bls12.ID{}
+	if err := point.SetDecString(strconv.FormatUint(id, 10)); err != nil {
+		return nil, errors.Wrapf(err, "invalid share id %d", id)
+	}
+	return point, nil
+}
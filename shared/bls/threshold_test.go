@@ -0,0 +1,79 @@
+package bls_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func TestSplitSecretKey_RecoverSignature(t *testing.T) {
+	secretKey := bls.RandKey()
+	shares, verificationVector, err := bls.SplitSecretKey(secretKey, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("Wanted 5 shares, received %d", len(shares))
+	}
+	if len(verificationVector) != 3 {
+		t.Fatalf("Wanted verification vector of length 3, received %d", len(verificationVector))
+	}
+	if !bytes.Equal(verificationVector[0].Marshal(), secretKey.PublicKey().Marshal()) {
+		t.Error("Verification vector does not start with the public key of the secret key")
+	}
+
+	msg := []byte("hello")
+	want := secretKey.Sign(msg).Marshal()
+	for _, ids := range [][]uint64{{1, 2, 3}, {5, 3, 1}, {2, 3, 4, 5}} {
+		sigs := make([]*bls.Signature, len(ids))
+		for i, id := range ids {
+			sigs[i] = shares[id-1].Sign(msg)
+			pub, err := bls.PublicKeyShare(verificationVector, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(pub.Marshal(), shares[id-1].PublicKey().Marshal()) {
+				t.Errorf("Wrong public key of share %d", id)
+			}
+		}
+		sig, err := bls.RecoverSignature(sigs, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig.Marshal(), want) {
+			t.Errorf("Signature recovered from shares %v differs from the signature of the secret key", ids)
+		}
+	}
+
+	// Fewer signatures than the threshold do not recover the signature.
+	sig, err := bls.RecoverSignature([]*bls.Signature{shares[0].Sign(msg), shares[1].Sign(msg)}, []uint64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.Verify(secretKey.PublicKey(), msg) {
+		t.Error("Signature recovered from fewer shares than the threshold verified")
+	}
+}
+
+func TestSplitSecretKey_InvalidThreshold(t *testing.T) {
+	if _, _, err := bls.SplitSecretKey(bls.RandKey(), 0, 3); err == nil {
+		t.Error("Expected error for threshold 0")
+	}
+	if _, _, err := bls.SplitSecretKey(bls.RandKey(), 4, 3); err == nil {
+		t.Error("Expected error for threshold above the number of shares")
+	}
+}
+
+func TestRecoverSignature_InvalidInput(t *testing.T) {
+	sig := bls.RandKey().Sign([]byte("hello"))
+	if _, err := bls.RecoverSignature(nil, nil); err == nil {
+		t.Error("Expected error without signatures")
+	}
+	if _, err := bls.RecoverSignature([]*bls.Signature{sig}, []uint64{1, 2}); err == nil {
+		t.Error("Expected error for mismatched ids")
+	}
+	if _, err := bls.RecoverSignature([]*bls.Signature{sig}, []uint64{0}); err == nil {
+		t.Error("Expected error for share id 0")
+	}
+}
@@ -3,8 +3,11 @@ package bytesutil
 
 import (
 	"encoding/binary"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/bits"
+	"strings"
 )
 
 // ToBytes returns integer x to bytes in little-endian format at the specified length.
@@ -173,6 +176,15 @@ func FromBytes48Array(x [][48]byte) [][]byte {
 	return y
 }
 
+// PubKeyFromHex decodes a hex encoded public key, with or without a 0x prefix.
+func PubKeyFromHex(s string) ([48]byte, error) {
+	pubKey, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(pubKey) != 48 {
+		return [48]byte{}, fmt.Errorf("invalid public key %s", s)
+	}
+	return ToBytes48(pubKey), nil
+}
+
 // Trunc truncates the byte slices to 6 bytes.
 func Trunc(x []byte) []byte {
 	if len(x) > 6 {
@@ -2,6 +2,7 @@ package bytesutil_test
 
 import (
 	"bytes"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -405,3 +406,21 @@ func TestHighestBitIndexBelow(t *testing.T) {
 		}
 	}
 }
+
+func TestPubKeyFromHex(t *testing.T) {
+	want := bytesutil.ToBytes48([]byte{1, 2, 3})
+	for _, s := range []string{fmt.Sprintf("%#x", want), fmt.Sprintf("%x", want)} {
+		pubKey, err := bytesutil.PubKeyFromHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubKey != want {
+			t.Errorf("PubKeyFromHex(%s) = %#x, want %#x", s, pubKey, want)
+		}
+	}
+	for _, s := range []string{"0x0102", "0xzz", ""} {
+		if _, err := bytesutil.PubKeyFromHex(s); err == nil {
+			t.Errorf("Expected PubKeyFromHex(%q) to fail", s)
+		}
+	}
+}
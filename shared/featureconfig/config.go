@@ -60,6 +60,7 @@ type Flags struct {
 	SkipRegenHistoricalStates                  bool // SkipRegenHistoricalState skips regenerating historical states from genesis to last finalized. This enables a quick switch over to using new-state-mgmt.
 	EnableInitSyncWeightedRoundRobin           bool // EnableInitSyncWeightedRoundRobin enables weighted round robin fetching optimization in initial syncing.
 	ReduceAttesterStateCopy                    bool // ReduceAttesterStateCopy reduces head state copies for attester rpc.
+	EnableColdStateDiffs                       bool // EnableColdStateDiffs saves diffs between finalized epoch boundary states, so cold states are rebuilt from the diffs instead of replaying blocks.
 	// DisableForkChoice disables using LMD-GHOST fork choice to update
 	// the head of the chain based on attestations and instead accepts any valid received block
 	// as the chain head. UNSAFE, use with caution.
@@ -208,6 +209,10 @@ func ConfigureBeaconChain(ctx *cli.Context) {
 		log.Warn("Enabling skipping of historical states regen")
 		cfg.SkipRegenHistoricalStates = true
 	}
+	if ctx.Bool(enableColdStateDiffs.Name) {
+		log.Warn("Enabling diffs of cold states")
+		cfg.EnableColdStateDiffs = true
+	}
 	cfg.EnableInitSyncWeightedRoundRobin = true
 	if ctx.Bool(disableInitSyncWeightedRoundRobin.Name) {
 		log.Warn("Disabling weighted round robin in initial syncing")
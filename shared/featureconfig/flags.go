@@ -148,6 +148,12 @@ var (
 		Name:  "skip-regen-historical-states",
 		Usage: "Skips regeneration and saving of historical states from genesis to last finalized. This enables a quick switch-over to using `--enable-new-state-mgmt`",
 	}
+	enableColdStateDiffs = &cli.BoolFlag{
+		Name: "enable-cold-state-diffs",
+		Usage: "Saves compact diffs between the finalized epoch boundary states, so that cold states are rebuilt " +
+			"by applying diffs instead of replaying blocks. Full states are only kept at archived points, see " +
+			"`--slots-per-archive-point`",
+	}
 	disableReduceAttesterStateCopy = &cli.BoolFlag{
 		Name:  "disable-reduce-attester-state-copy",
 		Usage: "Disables the feature to reduce the amount of state copies for attester rpc",
@@ -545,6 +551,7 @@ var BeaconChainFlags = append(deprecatedFlags, []cli.Flag{
 	disableInitSyncBatchSaveBlocks,
 	waitForSyncedFlag,
 	skipRegenHistoricalStates,
+	enableColdStateDiffs,
 	disableInitSyncWeightedRoundRobin,
 	disableStateRefCopy,
 	disableNewStateMgmt,
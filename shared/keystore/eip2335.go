@@ -0,0 +1,300 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/sha256-simd"
+	"github.com/pborman/uuid"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// EIP2335Version is the keystore version defined by EIP-2335.
+	EIP2335Version = 4
+	// KDFScrypt is the identifier of the scrypt key derivation function.
+	KDFScrypt = "scrypt"
+	// KDFPBKDF2 is the identifier of the PBKDF2 key derivation function.
+	KDFPBKDF2 = "pbkdf2"
+
+	eip2335ScryptN      = 1 << 18
+	eip2335ScryptR      = 8
+	eip2335ScryptP      = 1
+	eip2335PBKDF2C      = 1 << 18
+	eip2335PBKDF2PRF    = "hmac-sha256"
+	eip2335DKLen        = 32
+	eip2335Cipher       = "aes-128-ctr"
+	eip2335ChecksumFunc = "sha256"
+)
+
+var (
+	// ErrEIP2335Checksum is returned when the checksum of an EIP-2335 keystore does not match,
+	// which most likely means the password is wrong.
+	ErrEIP2335Checksum = errors.New("invalid checksum, wrong password or corrupted keystore")
+)
+
+// EIP2335Keystore is the JSON representation of an EIP-2335 keystore.
+type EIP2335Keystore struct {
+	Crypto      *EIP2335Crypto `json:"crypto"`
+	Description string         `json:"description,omitempty"`
+	PublicKey   string         `json:"pubkey"`
+	Path        string         `json:"path"`
+	ID          string         `json:"uuid"`
+	Version     uint           `json:"version"`
+}
+
+// EIP2335Crypto holds the kdf, checksum and cipher modules of an EIP-2335 keystore.
+type EIP2335Crypto struct {
+	KDF      *EIP2335Module `json:"kdf"`
+	Checksum *EIP2335Module `json:"checksum"`
+	Cipher   *EIP2335Module `json:"cipher"`
+}
+
+// EIP2335Module is a single EIP-2335 crypto module.
+type EIP2335Module struct {
+	Function string                 `json:"function"`
+	Params   map[string]interface{} `json:"params"`
+	Message  string                 `json:"message"`
+}
+
+// EncryptKeyEIP2335 encrypts a key into an EIP-2335 JSON blob, using the given key derivation
+// function. The path is the EIP-2334 derivation path of the key and may be empty.
+func EncryptKeyEIP2335(key *Key, password string, path string, kdf string) ([]byte, error) {
+	crypto, err := EncryptSecretEIP2335(key.SecretKey.Marshal(), password, kdf)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(&EIP2335Keystore{
+		Crypto:    crypto,
+		PublicKey: hex.EncodeToString(key.PublicKey.Marshal()),
+		Path:      path,
+		ID:        key.ID.String(),
+		Version:   EIP2335Version,
+	}, "", "  ")
+}
+
+// DecryptKeyEIP2335 decrypts a key from an EIP-2335 JSON blob. If the keystore holds a public key,
+// it is checked against the decrypted secret key.
+func DecryptKeyEIP2335(keyJSON []byte, password string) (*Key, error) {
+	ks := &EIP2335Keystore{}
+	if err := json.Unmarshal(keyJSON, ks); err != nil {
+		return nil, err
+	}
+	if ks.Version != EIP2335Version {
+		return nil, fmt.Errorf("unsupported keystore version: %d", ks.Version)
+	}
+	if ks.Crypto == nil {
+		return nil, errors.New("keystore is missing the crypto section")
+	}
+	secret, err := DecryptSecretEIP2335(ks.Crypto, password)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := bls.SecretKeyFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := secretKey.PublicKey()
+	if ks.PublicKey != "" {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(ks.PublicKey, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(pubKey, publicKey.Marshal()) {
+			return nil, errors.New("decrypted secret key does not match the keystore public key")
+		}
+	}
+	id := uuid.Parse(ks.ID)
+	if id == nil {
+		id = uuid.NewRandom()
+	}
+	return &Key{
+		ID:        id,
+		PublicKey: publicKey,
+		SecretKey: secretKey,
+	}, nil
+}
+
+// EncryptSecretEIP2335 encrypts an arbitrary secret into the crypto section of an EIP-2335 keystore.
+func EncryptSecretEIP2335(secret []byte, password string, kdf string) (*EIP2335Crypto, error) {
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.New("reading from crypto/rand failed: " + err.Error())
+	}
+	kdfModule := &EIP2335Module{
+		Function: kdf,
+		Params:   make(map[string]interface{}),
+	}
+	kdfModule.Params["dklen"] = eip2335DKLen
+	kdfModule.Params["salt"] = hex.EncodeToString(salt)
+	switch kdf {
+	case KDFScrypt:
+		kdfModule.Params["n"] = eip2335ScryptN
+		kdfModule.Params["r"] = eip2335ScryptR
+		kdfModule.Params["p"] = eip2335ScryptP
+	case KDFPBKDF2:
+		kdfModule.Params["c"] = eip2335PBKDF2C
+		kdfModule.Params["prf"] = eip2335PBKDF2PRF
+	default:
+		return nil, fmt.Errorf("unsupported KDF: %s", kdf)
+	}
+	derivedKey, err := eip2335DerivedKey(kdfModule, password)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, errors.New("reading from crypto/rand failed: " + err.Error())
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], secret, iv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EIP2335Crypto{
+		KDF: kdfModule,
+		Checksum: &EIP2335Module{
+			Function: eip2335ChecksumFunc,
+			Params:   make(map[string]interface{}),
+			Message:  hex.EncodeToString(eip2335Checksum(derivedKey, cipherText)),
+		},
+		Cipher: &EIP2335Module{
+			Function: eip2335Cipher,
+			Params:   map[string]interface{}{"iv": hex.EncodeToString(iv)},
+			Message:  hex.EncodeToString(cipherText),
+		},
+	}, nil
+}
+
+// DecryptSecretEIP2335 decrypts the secret held in the crypto section of an EIP-2335 keystore,
+// after validating its checksum.
+func DecryptSecretEIP2335(crypto *EIP2335Crypto, password string) ([]byte, error) {
+	if crypto.KDF == nil || crypto.Checksum == nil || crypto.Cipher == nil {
+		return nil, errors.New("incomplete crypto section")
+	}
+	if crypto.Checksum.Function != eip2335ChecksumFunc {
+		return nil, fmt.Errorf("unsupported checksum function: %s", crypto.Checksum.Function)
+	}
+	if crypto.Cipher.Function != eip2335Cipher {
+		return nil, fmt.Errorf("cipher not supported: %v", crypto.Cipher.Function)
+	}
+	derivedKey, err := eip2335DerivedKey(crypto.KDF, password)
+	if err != nil {
+		return nil, err
+	}
+	if len(derivedKey) < 32 {
+		return nil, errors.New("derived key is too short")
+	}
+	cipherText, err := hex.DecodeString(crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	checksum, err := hex.DecodeString(crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(eip2335Checksum(derivedKey, cipherText), checksum) {
+		return nil, ErrEIP2335Checksum
+	}
+	rawIV, ok := crypto.Cipher.Params["iv"].(string)
+	if !ok {
+		return nil, errors.New("cipher iv is not a string")
+	}
+	iv, err := hex.DecodeString(rawIV)
+	if err != nil {
+		return nil, err
+	}
+	return aesCTRXOR(derivedKey[:16], cipherText, iv)
+}
+
+// eip2335DerivedKey runs the key derivation function described by the kdf module.
+func eip2335DerivedKey(kdf *EIP2335Module, password string) ([]byte, error) {
+	rawSalt, ok := kdf.Params["salt"].(string)
+	if !ok {
+		return nil, errors.New("KDF salt is not a string")
+	}
+	salt, err := hex.DecodeString(rawSalt)
+	if err != nil {
+		return nil, err
+	}
+	dkLen, err := eip2335Param(kdf.Params, "dklen")
+	if err != nil {
+		return nil, err
+	}
+	auth := eip2335Password(password)
+
+	switch kdf.Function {
+	case KDFScrypt:
+		n, err := eip2335Param(kdf.Params, "n")
+		if err != nil {
+			return nil, err
+		}
+		r, err := eip2335Param(kdf.Params, "r")
+		if err != nil {
+			return nil, err
+		}
+		p, err := eip2335Param(kdf.Params, "p")
+		if err != nil {
+			return nil, err
+		}
+		return scrypt.Key(auth, salt, n, r, p, dkLen)
+	case KDFPBKDF2:
+		prf, ok := kdf.Params["prf"].(string)
+		if !ok {
+			return nil, errors.New("KDF prf is not a string")
+		}
+		if prf != eip2335PBKDF2PRF {
+			return nil, fmt.Errorf("unsupported PBKDF2 PRF: %s", prf)
+		}
+		c, err := eip2335Param(kdf.Params, "c")
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(auth, salt, c, dkLen, sha256.New), nil
+	}
+	return nil, fmt.Errorf("unsupported KDF: %s", kdf.Function)
+}
+
+// eip2335Checksum computes SHA256(DK[16:32] | cipher message).
+func eip2335Checksum(derivedKey []byte, cipherText []byte) []byte {
+	preimage := make([]byte, 0, 16+len(cipherText))
+	preimage = append(preimage, derivedKey[16:32]...)
+	preimage = append(preimage, cipherText...)
+	checksum := sha256.Sum256(preimage)
+	return checksum[:]
+}
+
+// eip2335Param reads an integer kdf parameter, which is a float64 once decoded from JSON.
+func eip2335Param(params map[string]interface{}, name string) (int, error) {
+	switch v := params[name].(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("missing or invalid KDF parameter %q", name)
+	}
+}
+
+// eip2335Password processes the password as required by EIP-2335: it is NFKD normalized,
+// and C0, C1 and Delete control codes are stripped.
+func eip2335Password(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, normalized))
+}
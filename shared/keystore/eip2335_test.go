@@ -0,0 +1,193 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+// Test vectors from https://eips.ethereum.org/EIPS/eip-2335#test-cases
+const (
+	eip2335TestPassword = "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑"
+	eip2335TestSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	eip2335TestPubKey   = "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07"
+
+	eip2335ScryptVector = `{
+    "crypto": {
+        "kdf": {
+            "function": "scrypt",
+            "params": {
+                "dklen": 32,
+                "n": 262144,
+                "p": 1,
+                "r": 8,
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"
+        }
+    },
+    "description": "This is a test keystore that uses scrypt to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/3141592653/589793238",
+    "uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+    "version": 4
+}`
+
+	eip2335PBKDF2Vector = `{
+    "crypto": {
+        "kdf": {
+            "function": "pbkdf2",
+            "params": {
+                "dklen": 32,
+                "c": 262144,
+                "prf": "hmac-sha256",
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+        }
+    },
+    "description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/0/0",
+    "uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+    "version": 4
+}`
+)
+
+func TestDecryptKeyEIP2335_TestVectors(t *testing.T) {
+	secret, err := hex.DecodeString(eip2335TestSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := hex.DecodeString(eip2335TestPubKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		vector string
+		id     string
+	}{
+		{name: "scrypt", vector: eip2335ScryptVector, id: "1d85ae20-35c5-4611-98e8-aa14a633906f"},
+		{name: "pbkdf2", vector: eip2335PBKDF2Vector, id: "64625def-3331-4eea-ab6f-782f3ed16a83"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := DecryptKeyEIP2335([]byte(tt.vector), eip2335TestPassword)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(key.SecretKey.Marshal(), secret) {
+				t.Errorf("Unexpected secret key, want %#x, got %#x", secret, key.SecretKey.Marshal())
+			}
+			if !bytes.Equal(key.PublicKey.Marshal(), pubKey) {
+				t.Errorf("Unexpected public key, want %#x, got %#x", pubKey, key.PublicKey.Marshal())
+			}
+			if key.ID.String() != tt.id {
+				t.Errorf("Unexpected id, want %s, got %s", tt.id, key.ID.String())
+			}
+
+			if _, err := DecryptKeyEIP2335([]byte(tt.vector), "testpassword"); err != ErrEIP2335Checksum {
+				t.Errorf("Expected %v, got %v", ErrEIP2335Checksum, err)
+			}
+		})
+	}
+}
+
+func TestEncryptDecryptKeyEIP2335(t *testing.T) {
+	key, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kdf := range []string{KDFScrypt, KDFPBKDF2} {
+		t.Run(kdf, func(t *testing.T) {
+			keyJSON, err := EncryptKeyEIP2335(key, "password", "m/12381/3600/0/0/0", kdf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ks := &EIP2335Keystore{}
+			if err := json.Unmarshal(keyJSON, ks); err != nil {
+				t.Fatal(err)
+			}
+			if ks.Crypto.KDF.Function != kdf {
+				t.Errorf("Unexpected KDF, want %s, got %s", kdf, ks.Crypto.KDF.Function)
+			}
+			if ks.Path != "m/12381/3600/0/0/0" {
+				t.Errorf("Unexpected path %s", ks.Path)
+			}
+
+			decrypted, err := DecryptKeyEIP2335(keyJSON, "password")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decrypted.SecretKey.Marshal(), key.SecretKey.Marshal()) {
+				t.Error("Decrypted secret key does not match")
+			}
+			if !bytes.Equal(decrypted.ID, key.ID) {
+				t.Errorf("Unexpected id, want %s, got %s", key.ID, decrypted.ID)
+			}
+			if _, err := DecryptKeyEIP2335(keyJSON, "wrong password"); err != ErrEIP2335Checksum {
+				t.Errorf("Expected %v, got %v", ErrEIP2335Checksum, err)
+			}
+		})
+	}
+}
+
+func TestDecryptKeyEIP2335_PublicKeyMismatch(t *testing.T) {
+	keystoreJSON := []byte(eip2335PBKDF2Vector)
+	ks := &EIP2335Keystore{}
+	if err := json.Unmarshal(keystoreJSON, ks); err != nil {
+		t.Fatal(err)
+	}
+	ks.PublicKey = hex.EncodeToString(bls.RandKey().PublicKey().Marshal())
+	tampered, err := json.Marshal(ks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptKeyEIP2335(tampered, eip2335TestPassword); err == nil {
+		t.Error("Expected error on public key mismatch")
+	}
+}
+
+func TestEncryptSecretEIP2335_UnsupportedKDF(t *testing.T) {
+	if _, err := EncryptSecretEIP2335([]byte{0x01}, "password", "argon2"); err == nil {
+		t.Error("Expected error on unsupported KDF")
+	}
+}
+
+func TestEIP2335Password(t *testing.T) {
+	want := []byte("testpassword🔑")
+	if got := eip2335Password(eip2335TestPassword); !bytes.Equal(got, want) {
+		t.Errorf("Unexpected processed password, want %q, got %q", want, got)
+	}
+	if got := eip2335Password("pass\x00word\x7f\u0085"); !bytes.Equal(got, []byte("password")) {
+		t.Errorf("Control codes not stripped, got %q", got)
+	}
+}
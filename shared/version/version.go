@@ -37,3 +37,8 @@ func GetBuildData() string {
 	}
 	return fmt.Sprintf("Prysm/%s/%s", gitTag, gitCommit)
 }
+
+// GetTag returns the git tag of the current build.
+func GetTag() string {
+	return gitTag
+}
@@ -132,6 +132,60 @@ func NewKVStore(dirPath string, cfg *Config) (*Store, error) {
 	return kv, err
 }
 
+// NewReadOnlyKVStore opens the boltDB key-value store at the directory path specified without
+// write access, so that it can be inspected without modifying it. Spans are read from the
+// database as the span cache is disabled.
+func NewReadOnlyKVStore(dirPath string, cfg *Config) (*Store, error) {
+	datafile := path.Join(dirPath, databaseFileName)
+	if _, err := os.Stat(datafile); err != nil {
+		return nil, err
+	}
+	boltDB, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	kv := &Store{db: boltDB, databasePath: datafile}
+	kv.EnableSpanCache(false)
+	spanCache, err := cache.NewEpochSpansCache(cfg.SpanCacheSize, persistSpanMapsOnEviction(kv))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create new cache")
+	}
+	kv.spanCache = spanCache
+	flatSpanCache, err := cache.NewEpochFlatSpansCache(cfg.SpanCacheSize, persistFlatSpanMapsOnEviction(kv))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create new flat cache")
+	}
+	kv.flatSpanCache = flatSpanCache
+	return kv, nil
+}
+
+// BucketStats describes the content of a bucket of the database.
+type BucketStats struct {
+	Name  string
+	Keys  int
+	Bytes int // Bytes used by the keys and values of the bucket.
+}
+
+// Buckets returns the statistics of each bucket of the database, ordered by name.
+func (db *Store) Buckets() ([]*BucketStats, error) {
+	var stats []*BucketStats
+	err := db.view(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			s := b.Stats()
+			stats = append(stats, &BucketStats{
+				Name:  string(name),
+				Keys:  s.KeyN,
+				Bytes: s.BranchInuse + s.LeafInuse,
+			})
+			return nil
+		})
+	})
+	return stats, err
+}
+
 // Size returns the db size in bytes.
 func (db *Store) Size() (int64, error) {
 	var size int64
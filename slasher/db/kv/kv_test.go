@@ -1,6 +1,7 @@
 package kv
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
@@ -61,3 +62,53 @@ func setupDBDiffCacheSize(t testing.TB, cacheSize int) *Store {
 	})
 	return db
 }
+
+func TestNewReadOnlyKVStore(t *testing.T) {
+	p := path.Join(testutil.TempDir(), "readonly")
+	ctx := context.Background()
+	db, err := NewKVStore(p, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(p); err != nil {
+			t.Fatalf("Failed to remove directory: %v", err)
+		}
+	})
+	pubKey := []byte("hello")
+	if err := db.SavePubKey(ctx, 1, pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	readOnly, err := NewReadOnlyKVStore(p, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := readOnly.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	received, err := readOnly.ValidatorPubKey(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(received) != string(pubKey) {
+		t.Errorf("Wanted public key %x, received %x", pubKey, received)
+	}
+	if err := readOnly.SavePubKey(ctx, 2, pubKey); err == nil {
+		t.Error("Expected saving to a read-only database to fail")
+	}
+	buckets, err := readOnly.Buckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range buckets {
+		if b.Name == string(validatorsPublicKeysBucket) && b.Keys != 1 {
+			t.Errorf("Wanted 1 key in the public keys bucket, received %d", b.Keys)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+func openBeaconDB(cliCtx *cli.Context) (*kv.Store, error) {
+	return kv.NewReadOnlyKVStore(cliCtx.String(dataDirFlag.Name), cache.NewStateSummaryCache())
+}
+
+func closeBeaconDB(db *kv.Store) {
+	if err := db.Close(); err != nil {
+		log.WithError(err).Error("Could not close database")
+	}
+}
+
+// blockRoots returns the root given with the root flag, or the roots of the blocks at the slot
+// given with the slot flag.
+func blockRoots(ctx context.Context, cliCtx *cli.Context, db *kv.Store) ([][32]byte, error) {
+	if cliCtx.IsSet(rootFlag.Name) {
+		root, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(rootFlag.Name), "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if len(root) != 32 {
+			return nil, fmt.Errorf("root has %d bytes, wanted 32", len(root))
+		}
+		return [][32]byte{bytesutil.ToBytes32(root)}, nil
+	}
+	if cliCtx.IsSet(slotFlag.Name) {
+		slot := cliCtx.Uint64(slotFlag.Name)
+		roots, err := db.BlockRoots(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+		if err != nil {
+			return nil, err
+		}
+		if len(roots) == 0 {
+			return nil, fmt.Errorf("no block at slot %d", slot)
+		}
+		return roots, nil
+	}
+	return nil, fmt.Errorf("one of --%s or --%s is required", rootFlag.Name, slotFlag.Name)
+}
+
+func beaconBuckets(cliCtx *cli.Context) error {
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+	buckets, err := db.Buckets()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tBYTES")
+	for _, b := range buckets {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", b.Name, b.Keys, b.Bytes)
+	}
+	return w.Flush()
+}
+
+func dumpBlocks(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+	roots, err := blockRoots(ctx, cliCtx, db)
+	if err != nil {
+		return err
+	}
+	var objs []proto.Message
+	for _, root := range roots {
+		blk, err := db.Block(ctx, root)
+		if err != nil {
+			return err
+		}
+		if blk == nil {
+			return fmt.Errorf("no block with root %#x", root)
+		}
+		objs = append(objs, blk)
+	}
+	return dump(cliCtx, objs)
+}
+
+func dumpStates(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+	roots, err := blockRoots(ctx, cliCtx, db)
+	if err != nil {
+		return err
+	}
+	var sg *stategen.State
+	summaries := cache.NewStateSummaryCache()
+	var objs []proto.Message
+	for _, root := range roots {
+		st, err := db.State(ctx, root)
+		if err != nil {
+			return err
+		}
+		if st == nil {
+			// The state is not saved, regenerate it from the closest saved state.
+			if sg == nil {
+				sg = stategen.New(db, summaries)
+				if _, err := sg.Resume(ctx); err != nil {
+					return err
+				}
+			}
+			if err := cacheStateSummary(ctx, db, summaries, root); err != nil {
+				return err
+			}
+			st, err = sg.StateByRoot(ctx, root)
+			if errors.Is(err, bolt.ErrTxNotWritable) {
+				return fmt.Errorf("state of block %#x cannot be regenerated from a read-only database: %v", root, err)
+			}
+			if err != nil {
+				return fmt.Errorf("could not regenerate state of block %#x: %v", root, err)
+			}
+		}
+		if st == nil {
+			return fmt.Errorf("no state for block root %#x", root)
+		}
+		objs = append(objs, st.InnerStateUnsafe())
+	}
+	return dump(cliCtx, objs)
+}
+
+// cacheStateSummary puts the state summary of the block in the cache, building it from the block
+// if the database has none. State regeneration would otherwise save the summary it recovers, which
+// the read-only database does not allow.
+func cacheStateSummary(ctx context.Context, db *kv.Store, summaries *cache.StateSummaryCache, root [32]byte) error {
+	summary, err := db.StateSummary(ctx, root)
+	if err != nil {
+		return err
+	}
+	if summary == nil {
+		blk, err := db.Block(ctx, root)
+		if err != nil {
+			return err
+		}
+		if blk == nil || blk.Block == nil {
+			return fmt.Errorf("no block for block root %#x", root)
+		}
+		summary = &pb.StateSummary{Slot: blk.Block.Slot, Root: root[:]}
+	}
+	summaries.Put(root, summary)
+	return nil
+}
+
+func dumpStateSummaries(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+	roots, err := blockRoots(ctx, cliCtx, db)
+	if err != nil {
+		return err
+	}
+	var objs []proto.Message
+	for _, root := range roots {
+		summary, err := db.StateSummary(ctx, root)
+		if err != nil {
+			return err
+		}
+		if summary == nil {
+			return fmt.Errorf("no state summary for block root %#x", root)
+		}
+		objs = append(objs, summary)
+	}
+	return dump(cliCtx, objs)
+}
+
+// forkTree lists the blocks between the start and end slots with their parent, marking the blocks
+// of the canonical chain, the finalized blocks and the leaves of the tree.
+func forkTree(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	startSlot := cliCtx.Uint64(startSlotFlag.Name)
+	endSlot := cliCtx.Uint64(endSlotFlag.Name)
+	if startSlot > endSlot {
+		return errors.New("start slot is after end slot")
+	}
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+
+	blks, err := db.Blocks(ctx, filters.NewFilter().SetStartSlot(startSlot).SetEndSlot(endSlot))
+	if err != nil {
+		return err
+	}
+	nodes := make([]*treeNode, 0, len(blks))
+	for _, blk := range blks {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, &treeNode{
+			root:       root,
+			parentRoot: bytesutil.ToBytes32(blk.Block.ParentRoot),
+			slot:       blk.Block.Slot,
+			finalized:  db.IsFinalizedBlock(ctx, root),
+		})
+	}
+
+	// Mark the canonical chain by walking back from the head block.
+	canonical := make(map[[32]byte]bool)
+	head, err := db.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if head != nil {
+		for blk := head.Block; blk != nil && blk.Slot >= startSlot; {
+			root, err := stateutil.BlockRoot(blk)
+			if err != nil {
+				return err
+			}
+			canonical[root] = true
+			if blk.Slot == 0 {
+				break
+			}
+			parent, err := db.Block(ctx, bytesutil.ToBytes32(blk.ParentRoot))
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				break
+			}
+			blk = parent.Block
+		}
+	}
+	for _, n := range nodes {
+		n.canonical = canonical[n.root]
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "SLOT\tROOT\tPARENT\tCHILDREN\tFLAGS")
+	for _, n := range buildTree(nodes) {
+		fmt.Fprintf(w, "%d\t%#x\t%#x\t%d\t%s\n", n.slot, n.root, n.parentRoot, n.children, strings.Join(n.flags(), ","))
+	}
+	return w.Flush()
+}
+
+type treeNode struct {
+	root       [32]byte
+	parentRoot [32]byte
+	slot       uint64
+	children   int
+	canonical  bool
+	finalized  bool
+}
+
+func (n *treeNode) flags() []string {
+	var flags []string
+	if n.canonical {
+		flags = append(flags, "canonical")
+	}
+	if n.finalized {
+		flags = append(flags, "finalized")
+	}
+	if n.children == 0 {
+		flags = append(flags, "leaf")
+	}
+	return flags
+}
+
+// buildTree counts the children of the nodes and orders them by slot, then by root.
+func buildTree(nodes []*treeNode) []*treeNode {
+	children := make(map[[32]byte]int)
+	for _, n := range nodes {
+		children[n.parentRoot]++
+	}
+	for _, n := range nodes {
+		n.children = children[n.root]
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].slot != nodes[j].slot {
+			return nodes[i].slot < nodes[j].slot
+		}
+		return bytes.Compare(nodes[i].root[:], nodes[j].root[:]) < 0
+	})
+	return nodes
+}
+
+func beaconMetadata(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	db, err := openBeaconDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeBeaconDB(db)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	version, err := db.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Schema version\t%d (latest %d)\n", version, kv.LatestSchemaVersion())
+
+	genesis, err := db.GenesisBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if genesis != nil {
+		root, err := stateutil.BlockRoot(genesis.Block)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Genesis block\t%#x\n", root)
+	}
+	head, err := db.HeadBlock(ctx)
+	if err != nil {
+		return err
+	}
+	if head != nil {
+		root, err := stateutil.BlockRoot(head.Block)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Head block\t%#x at slot %d\n", root, head.Block.Slot)
+	}
+	justified, err := db.JustifiedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Justified checkpoint\t%#x at epoch %d\n", justified.Root, justified.Epoch)
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Finalized checkpoint\t%#x at epoch %d\n", finalized.Root, finalized.Epoch)
+
+	lastIndex, err := db.LastArchivedIndex(ctx)
+	if err != nil {
+		return err
+	}
+	archivedPoints := 0
+	for i := uint64(0); i <= lastIndex; i++ {
+		if db.HasArchivedPoint(ctx, i) {
+			archivedPoints++
+		}
+	}
+	fmt.Fprintf(w, "Last archived point\t%d with root %#x\n", lastIndex, db.LastArchivedIndexRoot(ctx))
+	fmt.Fprintf(w, "Archived points\t%d\n", archivedPoints)
+
+	addr, err := db.DepositContractAddress(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Deposit contract\t%#x\n", addr)
+	return w.Flush()
+}
@@ -0,0 +1,151 @@
+/**
+ * Database inspection
+ *
+ * Inspects the beacon node and slasher databases offline: bucket sizes, blocks, states, the fork
+ * tree, checkpoints, slasher spans and slashings. Databases are opened read-only, so that the tool
+ * can run against a copy of the database of a running node without modifying it.
+ */
+package main
+
+import (
+	"os"
+
+	"github.com/prysmaticlabs/prysm/shared/version"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	prefixed "github.com/x-cray/logrus-prefixed-formatter"
+)
+
+var (
+	dataDirFlag = &cli.StringFlag{
+		Name:     "datadir",
+		Usage:    "Path to the directory of the database, such as $DATADIR/beaconchaindata or $DATADIR/slasherdata",
+		Required: true,
+	}
+	rootFlag = &cli.StringFlag{
+		Name:  "root",
+		Usage: "Hex encoded block root of the object to dump",
+	}
+	slotFlag = &cli.Uint64Flag{
+		Name:  "slot",
+		Usage: "Slot of the object to dump, all objects at the slot are dumped",
+	}
+	formatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format of the dumped objects: json|ssz",
+		Value: "json",
+	}
+	outputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "File to write the dumped object to instead of the standard output",
+	}
+	startSlotFlag = &cli.Uint64Flag{
+		Name:     "start-slot",
+		Usage:    "First slot of the fork tree",
+		Required: true,
+	}
+	endSlotFlag = &cli.Uint64Flag{
+		Name:     "end-slot",
+		Usage:    "Last slot of the fork tree",
+		Required: true,
+	}
+	validatorIndexFlag = &cli.Uint64Flag{
+		Name:     "validator-index",
+		Usage:    "Index of the validator to dump the records of",
+		Required: true,
+	}
+	startEpochFlag = &cli.Uint64Flag{
+		Name:  "start-epoch",
+		Usage: "First epoch of the dumped spans",
+	}
+	endEpochFlag = &cli.Uint64Flag{
+		Name:     "end-epoch",
+		Usage:    "Last epoch of the dumped spans",
+		Required: true,
+	}
+)
+
+func main() {
+	customFormatter := new(prefixed.TextFormatter)
+	customFormatter.TimestampFormat = "2006-01-02 15:04:05"
+	customFormatter.FullTimestamp = true
+	log.SetFormatter(customFormatter)
+
+	app := cli.App{}
+	app.Name = "db-inspect"
+	app.Usage = "inspects beacon node and slasher databases offline, opening them read-only"
+	app.Version = version.GetVersion()
+	app.Commands = []*cli.Command{
+		{
+			Name:  "beacon",
+			Usage: "inspects a beacon node database",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "buckets",
+					Usage:  "shows the number of keys and the size of each bucket",
+					Flags:  []cli.Flag{dataDirFlag},
+					Action: beaconBuckets,
+				},
+				{
+					Name:   "block",
+					Usage:  "dumps the block with a root or the blocks at a slot",
+					Flags:  []cli.Flag{dataDirFlag, rootFlag, slotFlag, formatFlag, outputFlag},
+					Action: dumpBlocks,
+				},
+				{
+					Name: "state",
+					Usage: "dumps the state of a block root or of the blocks at a slot, states which are " +
+						"not saved are regenerated from the closest saved state",
+					Flags:  []cli.Flag{dataDirFlag, rootFlag, slotFlag, formatFlag, outputFlag},
+					Action: dumpStates,
+				},
+				{
+					Name:   "state-summary",
+					Usage:  "dumps the state summary of a block root or of the blocks at a slot",
+					Flags:  []cli.Flag{dataDirFlag, rootFlag, slotFlag, formatFlag, outputFlag},
+					Action: dumpStateSummaries,
+				},
+				{
+					Name:   "tree",
+					Usage:  "lists the fork tree of the blocks between two slots",
+					Flags:  []cli.Flag{dataDirFlag, startSlotFlag, endSlotFlag},
+					Action: forkTree,
+				},
+				{
+					Name:   "metadata",
+					Usage:  "shows the schema version, head, genesis, checkpoints and archived points",
+					Flags:  []cli.Flag{dataDirFlag},
+					Action: beaconMetadata,
+				},
+			},
+		},
+		{
+			Name:  "slasher",
+			Usage: "inspects a slasher database",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "buckets",
+					Usage:  "shows the number of keys and the size of each bucket",
+					Flags:  []cli.Flag{dataDirFlag},
+					Action: slasherBuckets,
+				},
+				{
+					Name:   "spans",
+					Usage:  "dumps the min and max spans of a validator for a range of epochs",
+					Flags:  []cli.Flag{dataDirFlag, validatorIndexFlag, startEpochFlag, endEpochFlag},
+					Action: dumpSpans,
+				},
+				{
+					Name:   "slashings",
+					Usage:  "dumps the proposer and attester slashings of a validator",
+					Flags:  []cli.Flag{dataDirFlag, validatorIndexFlag},
+					Action: dumpSlashings,
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
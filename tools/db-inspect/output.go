@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/prysmaticlabs/go-ssz"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var marshaler = &jsonpb.Marshaler{Indent: "  "}
+
+// dump writes the objects in the format given with the format flag, to the output file if given
+// or to the standard output. Only a single object can be written as SSZ.
+func dump(cliCtx *cli.Context, objs []proto.Message) error {
+	format := cliCtx.String(formatFlag.Name)
+	if format != "json" && format != "ssz" {
+		return fmt.Errorf("unknown format %s", format)
+	}
+	if format == "ssz" && len(objs) != 1 {
+		return fmt.Errorf("found %d objects, select one with --%s to write it as SSZ", len(objs), rootFlag.Name)
+	}
+
+	var w io.Writer = os.Stdout
+	if outputPath := cliCtx.String(outputFlag.Name); outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.WithError(err).Error("Could not close output file")
+			}
+		}()
+		w = f
+	}
+
+	if format == "ssz" {
+		enc, err := ssz.Marshal(objs[0])
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(enc)
+		return err
+	}
+	for _, obj := range objs {
+		if err := marshaler.Marshal(w, obj); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
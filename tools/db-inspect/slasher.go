@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/prysmaticlabs/prysm/shared/sliceutil"
+	slasherkv "github.com/prysmaticlabs/prysm/slasher/db/kv"
+	"github.com/prysmaticlabs/prysm/slasher/db/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var slashingStatuses = []types.SlashingStatus{types.Active, types.Included, types.Reverted}
+
+func openSlasherDB(cliCtx *cli.Context) (*slasherkv.Store, error) {
+	return slasherkv.NewReadOnlyKVStore(cliCtx.String(dataDirFlag.Name), &slasherkv.Config{})
+}
+
+func closeSlasherDB(db *slasherkv.Store) {
+	if err := db.Close(); err != nil {
+		log.WithError(err).Error("Could not close database")
+	}
+}
+
+func slasherBuckets(cliCtx *cli.Context) error {
+	db, err := openSlasherDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeSlasherDB(db)
+	buckets, err := db.Buckets()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tBYTES")
+	for _, b := range buckets {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", b.Name, b.Keys, b.Bytes)
+	}
+	return w.Flush()
+}
+
+func dumpSpans(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	index := cliCtx.Uint64(validatorIndexFlag.Name)
+	startEpoch := cliCtx.Uint64(startEpochFlag.Name)
+	endEpoch := cliCtx.Uint64(endEpochFlag.Name)
+	if startEpoch > endEpoch {
+		return fmt.Errorf("start epoch %d is after end epoch %d", startEpoch, endEpoch)
+	}
+	db, err := openSlasherDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeSlasherDB(db)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "EPOCH\tMIN SPAN\tMAX SPAN\tATTESTED\tSIGNATURE BYTES")
+	for epoch := startEpoch; epoch <= endEpoch; epoch++ {
+		es, err := db.EpochSpans(ctx, epoch, types.UseDB)
+		if err != nil {
+			return err
+		}
+		span, err := es.GetValidatorSpan(index)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%t\t%#x\n", epoch, span.MinSpan, span.MaxSpan, span.HasAttested, span.SigBytes)
+	}
+	return w.Flush()
+}
+
+// dumpSlashings writes the proposer slashings of the validator and the attester slashings it is
+// slashed by as JSON, logging the status of each.
+func dumpSlashings(cliCtx *cli.Context) error {
+	ctx := context.Background()
+	index := cliCtx.Uint64(validatorIndexFlag.Name)
+	db, err := openSlasherDB(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer closeSlasherDB(db)
+
+	found := 0
+	for _, status := range slashingStatuses {
+		proposerSlashings, err := db.ProposalSlashingsByStatus(ctx, status)
+		if err != nil {
+			return err
+		}
+		for _, slashing := range proposerSlashings {
+			if slashing.Header_1 == nil || slashing.Header_1.Header == nil || slashing.Header_1.Header.ProposerIndex != index {
+				continue
+			}
+			log.WithField("status", status).Info("Proposer slashing")
+			if err := marshaler.Marshal(os.Stdout, slashing); err != nil {
+				return err
+			}
+			fmt.Println()
+			found++
+		}
+
+		attesterSlashings, err := db.AttesterSlashings(ctx, status)
+		if err != nil {
+			return err
+		}
+		for _, slashing := range attesterSlashings {
+			if slashing.Attestation_1 == nil || slashing.Attestation_2 == nil {
+				continue
+			}
+			slashed := sliceutil.IntersectionUint64(slashing.Attestation_1.AttestingIndices, slashing.Attestation_2.AttestingIndices)
+			if !sliceutil.IsInUint64(index, slashed) {
+				continue
+			}
+			log.WithField("status", status).Info("Attester slashing")
+			if err := marshaler.Marshal(os.Stdout, slashing); err != nil {
+				return err
+			}
+			fmt.Println()
+			found++
+		}
+	}
+	log.WithFields(log.Fields{
+		"validatorIndex": index,
+		"slashings":      found,
+	}).Info("Dumped slashings")
+	return nil
+}
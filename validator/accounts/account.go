@@ -232,6 +232,19 @@ func HandleEmptyKeystoreFlags(cliCtx *cli.Context, confirmPassword bool) (string
 	return path, passphrase, nil
 }
 
+// HandleEmptyPasswordFlag returns the value of the given password flag, or asks the user to enter it if empty.
+func HandleEmptyPasswordFlag(cliCtx *cli.Context, flag *cli.StringFlag, prompt string, confirmPassword bool) (string, error) {
+	if password := cliCtx.String(flag.Name); password != "" {
+		return password, nil
+	}
+	log.Info(prompt)
+	password, err := cmd.EnterPassword(confirmPassword, cmd.StdInPasswordReader{})
+	if err != nil {
+		return "", errors.Wrap(err, "could not read entered password")
+	}
+	return password, nil
+}
+
 // Merge merges data from validator databases in sourceDirectories into a new store, which is created in targetDirectory.
 func Merge(ctx context.Context, sourceDirectories []string, targetDirectory string) (err error) {
 	var sourceStores []*db.Store
@@ -0,0 +1,57 @@
+package accounts
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/sirupsen/logrus"
+)
+
+// VerifyAuditLog checks that the hash chain of the signing audit log in auditDir is intact and has
+// no gaps, and logs a summary of it.
+func VerifyAuditLog(auditDir string) error {
+	res, err := auditlog.Verify(auditDir)
+	if err != nil {
+		return errors.Wrap(err, "audit log verification failed")
+	}
+	log.WithFields(logrus.Fields{
+		"files":     res.Files,
+		"entries":   res.Entries,
+		"firstHash": res.FirstHash,
+		"lastHash":  res.LastHash,
+	}).Info("Verified audit log")
+	return nil
+}
+
+// RebuildSlashingProtection merges the blocks and attestations recorded in the signing audit log in
+// auditDir into the validator database in dataDir, for the chain identified by
+// genesisValidatorsRoot. The database is created if it was lost. The log is verified first, and
+// nothing is imported from a log whose hash chain is broken.
+func RebuildSlashingProtection(ctx context.Context, dataDir string, auditDir string, genesisValidatorsRoot []byte) (err error) {
+	interchange, err := auditlog.Interchange(auditDir, genesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not rebuild slashing protection history from the audit log")
+	}
+
+	store, err := db.NewKVStore(dataDir, [][48]byte{})
+	if err != nil {
+		return errors.Wrapf(err, "could not open the validator database in %s", dataDir)
+	}
+	defer func() {
+		if deferErr := store.Close(); deferErr != nil {
+			if err != nil {
+				err = errors.Wrap(err, errFailedToCloseDb.Error())
+			} else {
+				err = errors.Wrap(deferErr, errFailedToCloseDb.Error())
+			}
+		}
+	}()
+
+	if err := store.ImportInterchange(ctx, interchange); err != nil {
+		return errors.Wrap(err, "could not import slashing protection history")
+	}
+	log.WithField("validators", len(interchange.Data)).Info("Rebuilt slashing protection history from the audit log")
+	return nil
+}
@@ -0,0 +1,123 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+func TestRebuildSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := filepath.Join(testutil.TempDir(), "auditlog")
+	auditDir := filepath.Join(tmpDir, "audit")
+	dataDir := filepath.Join(tmpDir, "data")
+	interchangeFile := filepath.Join(tmpDir, "interchange.json")
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+
+	pubKey := fmt.Sprintf("%#x", [48]byte{1})
+	w, err := auditlog.NewWriter(auditDir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := []*auditlog.Entry{
+		{Type: auditlog.TypeBlock, Slot: 101, Epoch: 3},
+		{Type: auditlog.TypeAttestation, Slot: 102, Epoch: 3, SourceEpoch: 2, TargetEpoch: 3},
+		{Type: auditlog.TypeRandao, Epoch: 3},
+	}
+	for _, e := range entries {
+		e.PublicKey = pubKey
+		e.SigningRoot = fmt.Sprintf("%#x", [32]byte{byte(e.Slot)})
+		if err := w.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAuditLog(auditDir); err != nil {
+		t.Fatal(err)
+	}
+
+	genesisValidatorsRoot := bytes.Repeat([]byte{1}, 32)
+	if err := RebuildSlashingProtection(ctx, dataDir, auditDir, genesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportSlashingProtection(ctx, dataDir, interchangeFile, genesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	enc, err := ioutil.ReadFile(interchangeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interchange := &db.Interchange{}
+	if err := json.Unmarshal(enc, interchange); err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 || interchange.Data[0].PubKey != pubKey {
+		t.Fatalf("Expected history of %s, received %v", pubKey, interchange.Data)
+	}
+	history := interchange.Data[0]
+	if len(history.SignedBlocks) != 1 || history.SignedBlocks[0].Slot != 101 {
+		t.Errorf("Expected a block at slot 101, received %v", history.SignedBlocks)
+	}
+	if len(history.SignedAttestations) != 1 || history.SignedAttestations[0].TargetEpoch != 3 {
+		t.Errorf("Expected an attestation targeting epoch 3, received %v", history.SignedAttestations)
+	}
+}
+
+func TestVerifyAuditLog_Tampered(t *testing.T) {
+	tmpDir := filepath.Join(testutil.TempDir(), "auditlogtampered")
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	w, err := auditlog.NewWriter(tmpDir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slot := uint64(1); slot <= 2; slot++ {
+		if err := w.Append(&auditlog.Entry{
+			Type:        auditlog.TypeBlock,
+			PublicKey:   fmt.Sprintf("%#x", [48]byte{1}),
+			SigningRoot: fmt.Sprintf("%#x", [32]byte{byte(slot)}),
+			Slot:        slot,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(tmpDir, "*.log"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected 1 log file, received %v: %v", files, err)
+	}
+	enc, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc = bytes.Replace(enc, []byte(`"slot":2`), []byte(`"slot":1`), 1)
+	if err := ioutil.WriteFile(files[0], enc, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyAuditLog(tmpDir); err == nil {
+		t.Error("Expected verification of a tampered audit log to fail")
+	}
+	if err := RebuildSlashingProtection(context.Background(), filepath.Join(tmpDir, "data"), tmpDir, bytes.Repeat([]byte{1}, 32)); err == nil {
+		t.Error("Expected rebuilding from a tampered audit log to fail")
+	}
+}
@@ -0,0 +1,129 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// DepositSigner signs the root of a deposit message with a validating key, in the given
+// signature domain.
+type DepositSigner func(pubKey [48]byte, messageRoot [32]byte, domain []byte) (*bls.Signature, error)
+
+// DepositData is an entry of a deposit_data-*.json file, as accepted by the staking launchpad.
+// Byte fields are hex encoded without a 0x prefix.
+type DepositData struct {
+	PubKey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                uint64 `json:"amount"`
+	Signature             string `json:"signature"`
+	DepositMessageRoot    string `json:"deposit_message_root"`
+	DepositDataRoot       string `json:"deposit_data_root"`
+	ForkVersion           string `json:"fork_version"`
+}
+
+// WithdrawalCredentials returns the BLS withdrawal credentials of a withdrawal public key.
+func WithdrawalCredentials(withdrawalPubKey []byte) []byte {
+	h := hashutil.Hash(withdrawalPubKey)
+	return append([]byte{params.BeaconConfig().BLSWithdrawalPrefixByte}, h[1:]...)
+}
+
+// GenerateDepositData signs the deposits of the public keys and verifies the signatures. A nil
+// fork version defaults to the genesis fork version of the beacon chain config.
+func GenerateDepositData(
+	sign DepositSigner,
+	pubKeys [][48]byte,
+	withdrawalCredentials []byte,
+	amountInGwei uint64,
+	forkVersion []byte,
+) ([]*DepositData, error) {
+	if len(withdrawalCredentials) != 32 {
+		return nil, fmt.Errorf("invalid withdrawal credentials length %d", len(withdrawalCredentials))
+	}
+	cfg := params.BeaconConfig()
+	if amountInGwei < cfg.MinDepositAmount || amountInGwei > cfg.MaxEffectiveBalance {
+		return nil, fmt.Errorf("deposit amount %d Gwei is not between %d and %d Gwei", amountInGwei, cfg.MinDepositAmount, cfg.MaxEffectiveBalance)
+	}
+	if forkVersion == nil {
+		forkVersion = cfg.GenesisForkVersion
+	}
+	if len(forkVersion) != 4 {
+		return nil, fmt.Errorf("invalid fork version length %d", len(forkVersion))
+	}
+	// Deposits are valid on any chain of the fork version, so the genesis validators root is empty.
+	domain, err := helpers.ComputeDomain(cfg.DomainDeposit, forkVersion, nil /*genesisValidatorsRoot*/)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute deposit domain")
+	}
+
+	deposits := make([]*DepositData, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		data := &ethpb.Deposit_Data{
+			PublicKey:             pubKey[:],
+			WithdrawalCredentials: withdrawalCredentials,
+			Amount:                amountInGwei,
+		}
+		// The signing root of the deposit data excludes the signature, which makes it the
+		// root of the deposit message.
+		messageRoot, err := ssz.SigningRoot(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute deposit message root")
+		}
+		signingRoot, err := ssz.HashTreeRoot(&pb.SigningData{ObjectRoot: messageRoot[:], Domain: domain})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute deposit signing root")
+		}
+		sig, err := sign(pubKey, messageRoot, domain)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not sign deposit of public key %#x", pubKey)
+		}
+		pub, err := bls.PublicKeyFromBytes(pubKey[:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid public key %#x", pubKey)
+		}
+		if !sig.Verify(pub, signingRoot[:]) {
+			return nil, fmt.Errorf("generated invalid deposit signature for public key %#x", pubKey)
+		}
+		data.Signature = sig.Marshal()
+		dataRoot, err := ssz.HashTreeRoot(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute deposit data root")
+		}
+		deposits[i] = &DepositData{
+			PubKey:                hex.EncodeToString(data.PublicKey),
+			WithdrawalCredentials: hex.EncodeToString(withdrawalCredentials),
+			Amount:                amountInGwei,
+			Signature:             hex.EncodeToString(data.Signature),
+			DepositMessageRoot:    hex.EncodeToString(messageRoot[:]),
+			DepositDataRoot:       hex.EncodeToString(dataRoot[:]),
+			ForkVersion:           hex.EncodeToString(forkVersion),
+		}
+	}
+	return deposits, nil
+}
+
+// WriteDepositData writes the deposits to a deposit_data-<timestamp>.json file in outputDir and
+// returns its path.
+func WriteDepositData(outputDir string, deposits []*DepositData) (string, error) {
+	enc, err := json.MarshalIndent(deposits, "", "  ")
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode deposit data")
+	}
+	path := filepath.Join(outputDir, fmt.Sprintf("deposit_data-%d.json", time.Now().Unix()))
+	if err := ioutil.WriteFile(path, enc, 0644); err != nil {
+		return "", errors.Wrapf(err, "could not write deposit data to %s", path)
+	}
+	return path, nil
+}
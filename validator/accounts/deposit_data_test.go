@@ -0,0 +1,93 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/prysmaticlabs/go-ssz"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func testDepositSigner(key *bls.SecretKey) DepositSigner {
+	return func(_ [48]byte, messageRoot [32]byte, domain []byte) (*bls.Signature, error) {
+		root, err := ssz.HashTreeRoot(&pb.SigningData{ObjectRoot: messageRoot[:], Domain: domain})
+		if err != nil {
+			return nil, err
+		}
+		return key.Sign(root[:]), nil
+	}
+}
+
+func TestGenerateDepositData_MatchesDepositInput(t *testing.T) {
+	depositKey, err := keystore.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	withdrawalKey, err := keystore.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	amount := params.BeaconConfig().MaxEffectiveBalance
+	want, wantRoot, err := keystore.DepositInput(depositKey, withdrawalKey, amount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := bytesutil.ToBytes48(depositKey.PublicKey.Marshal())
+	deposits, err := GenerateDepositData(
+		testDepositSigner(depositKey.SecretKey),
+		[][48]byte{pubKey},
+		WithdrawalCredentials(withdrawalKey.PublicKey.Marshal()),
+		amount,
+		nil, /*forkVersion*/
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deposits) != 1 {
+		t.Fatalf("Wanted 1 deposit, received %d", len(deposits))
+	}
+	got := deposits[0]
+	if got.WithdrawalCredentials != hex.EncodeToString(want.WithdrawalCredentials) {
+		t.Errorf("Wanted withdrawal credentials %x, received %s", want.WithdrawalCredentials, got.WithdrawalCredentials)
+	}
+	if got.Signature != hex.EncodeToString(want.Signature) {
+		t.Errorf("Wanted signature %x, received %s", want.Signature, got.Signature)
+	}
+	if got.DepositDataRoot != hex.EncodeToString(wantRoot[:]) {
+		t.Errorf("Wanted deposit data root %x, received %s", wantRoot, got.DepositDataRoot)
+	}
+	if got.Amount != amount || got.ForkVersion != "00000000" {
+		t.Errorf("Unexpected amount %d or fork version %s", got.Amount, got.ForkVersion)
+	}
+}
+
+func TestGenerateDepositData_RejectsInvalidSignature(t *testing.T) {
+	key := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(key.PublicKey().Marshal())
+	// Signing with another key must be caught.
+	_, err := GenerateDepositData(
+		testDepositSigner(bls.RandKey()),
+		[][48]byte{pubKey},
+		make([]byte, 32),
+		params.BeaconConfig().MaxEffectiveBalance,
+		[]byte{0, 0, 0, 1},
+	)
+	if err == nil {
+		t.Error("Expected a signature of another key to be rejected")
+	}
+}
+
+func TestGenerateDepositData_RejectsInvalidAmount(t *testing.T) {
+	key := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(key.PublicKey().Marshal())
+	for _, amount := range []uint64{params.BeaconConfig().MinDepositAmount - 1, params.BeaconConfig().MaxEffectiveBalance + 1} {
+		if _, err := GenerateDepositData(testDepositSigner(key), [][48]byte{pubKey}, make([]byte, 32), amount, nil); err == nil {
+			t.Errorf("Expected deposit amount %d to be rejected", amount)
+		}
+	}
+}
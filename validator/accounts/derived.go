@@ -0,0 +1,354 @@
+package accounts
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pborman/uuid"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/sirupsen/logrus"
+	"github.com/tyler-smith/go-bip39"
+	util "github.com/wealdtech/go-eth2-util"
+)
+
+const (
+	// DerivedRootPath is the EIP-2334 path under which all validator keys are derived.
+	DerivedRootPath = "m/12381/3600"
+	// DerivedWithdrawalKeyPath is the EIP-2334 path format of the withdrawal key for an account index.
+	DerivedWithdrawalKeyPath = DerivedRootPath + "/%d/0"
+	// DerivedValidatingKeyPath is the EIP-2334 path format of the signing key for an account index.
+	DerivedValidatingKeyPath = DerivedRootPath + "/%d/0/0"
+
+	derivedSeedFileName      = "seed.json"
+	derivedKeystorePrefix    = "keystore-"
+	derivedKeystoreExtension = ".json"
+	mnemonicEntropyBits      = 256
+)
+
+// ErrNoDerivedWallet is returned when the wallet directory does not hold an encrypted seed.
+var ErrNoDerivedWallet = errors.New("no derived wallet found, please use validator accounts derived create or recover")
+
+// DerivedAccount describes a pair of withdrawal and validating keys derived at a given account index.
+type DerivedAccount struct {
+	Index               uint64
+	WithdrawalKeyPath   string
+	WithdrawalPublicKey []byte
+	ValidatingKeyPath   string
+	ValidatingPublicKey []byte
+}
+
+// NewMnemonic generates a new 24 words BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", errors.Wrap(err, "could not generate entropy")
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// CreateDerivedWallet generates a new mnemonic, stores its encrypted seed in the wallet
+// directory and derives the requested number of accounts from it. The mnemonic is returned
+// so that it can be shown to the user, it is never written to disk.
+func CreateDerivedWallet(walletDir string, passphrase string, numAccounts uint64) (string, []*DerivedAccount, error) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		return "", nil, err
+	}
+	accounts, err := RecoverDerivedWallet(walletDir, mnemonic, passphrase, numAccounts)
+	if err != nil {
+		return "", nil, err
+	}
+	return mnemonic, accounts, nil
+}
+
+// RecoverDerivedWallet stores the encrypted seed of the given mnemonic in the wallet directory
+// and re-derives the first numAccounts accounts from it.
+func RecoverDerivedWallet(walletDir string, mnemonic string, passphrase string, numAccounts uint64) ([]*DerivedAccount, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase is not allowed")
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic")
+	}
+	if _, err := os.Stat(filepath.Join(walletDir, derivedSeedFileName)); err == nil {
+		return nil, fmt.Errorf("a derived wallet already exists at path %s", walletDir)
+	}
+	if err := os.MkdirAll(walletDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "could not create wallet directory %s", walletDir)
+	}
+	seed := bip39.NewSeed(mnemonic, "" /* no BIP-39 password */)
+	if err := writeDerivedKeystore(
+		filepath.Join(walletDir, derivedSeedFileName), seed, nil, DerivedRootPath, passphrase,
+	); err != nil {
+		return nil, errors.Wrap(err, "could not store wallet seed")
+	}
+	return deriveAccounts(walletDir, seed, passphrase, 0, numAccounts)
+}
+
+// CreateDerivedAccounts derives numAccounts new accounts, following the highest existing
+// account index, from the seed stored in the wallet directory.
+func CreateDerivedAccounts(walletDir string, passphrase string, numAccounts uint64) ([]*DerivedAccount, error) {
+	seed, err := decryptDerivedSeed(walletDir, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ListDerivedAccounts(walletDir)
+	if err != nil {
+		return nil, err
+	}
+	nextIndex := uint64(0)
+	if len(existing) > 0 {
+		nextIndex = existing[len(existing)-1].Index + 1
+	}
+	return deriveAccounts(walletDir, seed, passphrase, nextIndex, numAccounts)
+}
+
+// ListDerivedAccounts lists the accounts of the wallet directory, ordered by account index.
+// Only public data is read, so no passphrase is required.
+func ListDerivedAccounts(walletDir string) ([]*DerivedAccount, error) {
+	if _, err := os.Stat(filepath.Join(walletDir, derivedSeedFileName)); err != nil {
+		return nil, ErrNoDerivedWallet
+	}
+	files, err := filepath.Glob(filepath.Join(walletDir, derivedKeystorePrefix+"*"+derivedKeystoreExtension))
+	if err != nil {
+		return nil, err
+	}
+	byIndex := make(map[uint64]*DerivedAccount)
+	for _, file := range files {
+		ks, err := readDerivedKeystore(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore %s", file)
+		}
+		index, validating, err := parseDerivedPath(ks.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected path in keystore %s", file)
+		}
+		pubKey, err := hex.DecodeString(ks.PublicKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decode public key in keystore %s", file)
+		}
+		account, ok := byIndex[index]
+		if !ok {
+			account = &DerivedAccount{Index: index}
+			byIndex[index] = account
+		}
+		if validating {
+			account.ValidatingKeyPath = ks.Path
+			account.ValidatingPublicKey = pubKey
+		} else {
+			account.WithdrawalKeyPath = ks.Path
+			account.WithdrawalPublicKey = pubKey
+		}
+	}
+	accounts := make([]*DerivedAccount, 0, len(byIndex))
+	for _, account := range byIndex {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Index < accounts[j].Index
+	})
+	return accounts, nil
+}
+
+// DecryptDerivedValidatingKeys decrypts the validating keys of all the accounts in the wallet directory.
+func DecryptDerivedValidatingKeys(walletDir string, passphrase string) ([]*bls.SecretKey, error) {
+	accounts, err := ListDerivedAccounts(walletDir)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*bls.SecretKey, 0, len(accounts))
+	for _, account := range accounts {
+		if account.ValidatingKeyPath == "" {
+			continue
+		}
+		ks, err := readDerivedKeystore(filepath.Join(walletDir, derivedKeystoreFileName(account.ValidatingKeyPath)))
+		if err != nil {
+			return nil, err
+		}
+		secret, err := keystore.DecryptSecretEIP2335(ks.Crypto, passphrase)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt validating key of account %d", account.Index)
+		}
+		key, err := bls.SecretKeyFromBytes(secret)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ReadMnemonic reads a mnemonic from the given file, or from the standard input if no file is provided.
+func ReadMnemonic(mnemonicFile string) (string, error) {
+	if mnemonicFile != "" {
+		data, err := ioutil.ReadFile(mnemonicFile)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not read mnemonic file %s", mnemonicFile)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	log.Info("Please enter the mnemonic of the wallet to recover")
+	text, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", errors.Wrap(err, "could not read mnemonic")
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// PrintDerivedAccounts prints the public keys and key paths of the given accounts.
+func PrintDerivedAccounts(accounts []*DerivedAccount) {
+	for _, account := range accounts {
+		fmt.Printf("Account %d\n", account.Index)
+		fmt.Printf("  Validating public key: %#x (%s)\n", account.ValidatingPublicKey, account.ValidatingKeyPath)
+		fmt.Printf("  Withdrawal public key: %#x (%s)\n", account.WithdrawalPublicKey, account.WithdrawalKeyPath)
+	}
+}
+
+// deriveAccounts derives numAccounts accounts starting at the given index and stores
+// their keys as EIP-2335 keystores.
+func deriveAccounts(walletDir string, seed []byte, passphrase string, from uint64, numAccounts uint64) ([]*DerivedAccount, error) {
+	accounts := make([]*DerivedAccount, 0, numAccounts)
+	for i := from; i < from+numAccounts; i++ {
+		account := &DerivedAccount{
+			Index:             i,
+			WithdrawalKeyPath: fmt.Sprintf(DerivedWithdrawalKeyPath, i),
+			ValidatingKeyPath: fmt.Sprintf(DerivedValidatingKeyPath, i),
+		}
+		withdrawalKey, err := deriveKey(seed, account.WithdrawalKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		validatingKey, err := deriveKey(seed, account.ValidatingKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		account.WithdrawalPublicKey = withdrawalKey.PublicKey().Marshal()
+		account.ValidatingPublicKey = validatingKey.PublicKey().Marshal()
+
+		if err := writeDerivedKeystore(
+			filepath.Join(walletDir, derivedKeystoreFileName(account.WithdrawalKeyPath)),
+			withdrawalKey.Marshal(),
+			account.WithdrawalPublicKey,
+			account.WithdrawalKeyPath,
+			passphrase,
+		); err != nil {
+			return nil, errors.Wrapf(err, "could not store withdrawal key of account %d", i)
+		}
+		if err := writeDerivedKeystore(
+			filepath.Join(walletDir, derivedKeystoreFileName(account.ValidatingKeyPath)),
+			validatingKey.Marshal(),
+			account.ValidatingPublicKey,
+			account.ValidatingKeyPath,
+			passphrase,
+		); err != nil {
+			return nil, errors.Wrapf(err, "could not store validating key of account %d", i)
+		}
+		log.WithFields(logrus.Fields{
+			"index":     i,
+			"publicKey": fmt.Sprintf("%#x", account.ValidatingPublicKey),
+		}).Info("Derived validator account")
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// deriveKey derives the EIP-2333 secret key at the given path.
+func deriveKey(seed []byte, path string) (*bls.SecretKey, error) {
+	derived, err := util.PrivateKeyFromSeedAndPath(seed, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not derive key at path %s", path)
+	}
+	return bls.SecretKeyFromBytes(derived.Marshal())
+}
+
+func decryptDerivedSeed(walletDir string, passphrase string) ([]byte, error) {
+	seedFile := filepath.Join(walletDir, derivedSeedFileName)
+	if _, err := os.Stat(seedFile); err != nil {
+		return nil, ErrNoDerivedWallet
+	}
+	ks, err := readDerivedKeystore(seedFile)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := keystore.DecryptSecretEIP2335(ks.Crypto, passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decrypt wallet seed")
+	}
+	return seed, nil
+}
+
+func writeDerivedKeystore(file string, secret []byte, pubKey []byte, path string, passphrase string) error {
+	crypto, err := keystore.EncryptSecretEIP2335(secret, passphrase, keystore.KDFScrypt)
+	if err != nil {
+		return err
+	}
+	ks := &keystore.EIP2335Keystore{
+		Crypto:    crypto,
+		PublicKey: hex.EncodeToString(pubKey),
+		Path:      path,
+		ID:        uuid.NewRandom().String(),
+		Version:   keystore.EIP2335Version,
+	}
+	if pubKey == nil {
+		ks.Description = "Encrypted seed of a derived validator wallet"
+	}
+	encoded, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, encoded, 0600)
+}
+
+func readDerivedKeystore(file string) (*keystore.EIP2335Keystore, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	ks := &keystore.EIP2335Keystore{}
+	if err := json.Unmarshal(data, ks); err != nil {
+		return nil, err
+	}
+	if ks.Crypto == nil {
+		return nil, errors.New("keystore is missing the crypto section")
+	}
+	return ks, nil
+}
+
+// derivedKeystoreFileName returns the keystore file name of a key path, i.e. m/12381/3600/0/0/0
+// is stored as keystore-m_12381_3600_0_0_0.json.
+func derivedKeystoreFileName(path string) string {
+	return derivedKeystorePrefix + strings.Replace(path, "/", "_", -1) + derivedKeystoreExtension
+}
+
+// parseDerivedPath extracts the account index out of an EIP-2334 withdrawal or validating key
+// path, and states whether the path is a validating key one.
+func parseDerivedPath(path string) (uint64, bool, error) {
+	if !strings.HasPrefix(path, DerivedRootPath+"/") {
+		return 0, false, fmt.Errorf("path %q is not under %s", path, DerivedRootPath)
+	}
+	parts := strings.Split(strings.TrimPrefix(path, DerivedRootPath+"/"), "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false, fmt.Errorf("unexpected path %q", path)
+	}
+	for _, part := range parts[1:] {
+		if part != "0" {
+			return 0, false, fmt.Errorf("unexpected path %q", path)
+		}
+	}
+	index, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return index, len(parts) == 3, nil
+}
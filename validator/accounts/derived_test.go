@@ -0,0 +1,155 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestDeriveKey_EIP2333TestVector(t *testing.T) {
+	// Test case 0 from https://eips.ethereum.org/EIPS/eip-2333#test-case-0
+	seed, err := hex.DecodeString("c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04")
+	if err != nil {
+		t.Fatal(err)
+	}
+	childSK, ok := new(big.Int).SetString("20397789859736650942317412262472558107875392172444076792671091975210932703118", 10)
+	if !ok {
+		t.Fatal("Could not parse expected child key")
+	}
+	key, err := deriveKey(seed, "m/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 32)
+	copy(want[32-len(childSK.Bytes()):], childSK.Bytes())
+	if !bytes.Equal(key.Marshal(), want) {
+		t.Errorf("Unexpected derived key, want %#x, got %#x", want, key.Marshal())
+	}
+}
+
+func TestParseDerivedPath(t *testing.T) {
+	tests := []struct {
+		path       string
+		index      uint64
+		validating bool
+		wantErr    bool
+	}{
+		{path: "m/12381/3600/0/0", index: 0},
+		{path: "m/12381/3600/0/0/0", index: 0, validating: true},
+		{path: "m/12381/3600/42/0/0", index: 42, validating: true},
+		{path: "m/12381/3600", wantErr: true},
+		{path: "m/12381/3600/1/1/0", wantErr: true},
+		{path: "m/12381/60/0/0", wantErr: true},
+		{path: "m/12381/3600/a/0", wantErr: true},
+	}
+	for _, tt := range tests {
+		index, validating, err := parseDerivedPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDerivedPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if index != tt.index || validating != tt.validating {
+			t.Errorf("parseDerivedPath(%q) = (%d, %v), want (%d, %v)", tt.path, index, validating, tt.index, tt.validating)
+		}
+	}
+}
+
+func TestDerivedWallet_CreateListRecover(t *testing.T) {
+	tmpDir := testutil.TempDir()
+	walletDir := filepath.Join(tmpDir, "derived")
+	recoveredDir := filepath.Join(tmpDir, "recovered")
+	defer func() {
+		if err := os.RemoveAll(walletDir); err != nil {
+			t.Log(err)
+		}
+		if err := os.RemoveAll(recoveredDir); err != nil {
+			t.Log(err)
+		}
+	}()
+	passphrase := "passw0rd"
+
+	mnemonic, created, err := CreateDerivedWallet(walletDir, passphrase, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("Expected 2 accounts, got %d", len(created))
+	}
+	if _, _, err := CreateDerivedWallet(walletDir, passphrase, 1); err == nil {
+		t.Error("Expected error when creating a wallet over an existing one")
+	}
+
+	more, err := CreateDerivedAccounts(walletDir, passphrase, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(more) != 1 || more[0].Index != 2 {
+		t.Fatalf("Expected a single account at index 2, got %v", more)
+	}
+	created = append(created, more...)
+
+	listed, err := ListDerivedAccounts(walletDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != len(created) {
+		t.Fatalf("Expected %d accounts, got %d", len(created), len(listed))
+	}
+	for i := range created {
+		if !bytes.Equal(listed[i].ValidatingPublicKey, created[i].ValidatingPublicKey) {
+			t.Errorf("Account %d: unexpected validating key", i)
+		}
+		if !bytes.Equal(listed[i].WithdrawalPublicKey, created[i].WithdrawalPublicKey) {
+			t.Errorf("Account %d: unexpected withdrawal key", i)
+		}
+		if listed[i].ValidatingKeyPath != created[i].ValidatingKeyPath {
+			t.Errorf("Account %d: unexpected path, want %s, got %s", i, created[i].ValidatingKeyPath, listed[i].ValidatingKeyPath)
+		}
+	}
+
+	keys, err := DecryptDerivedValidatingKeys(walletDir, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys {
+		if !bytes.Equal(key.PublicKey().Marshal(), created[i].ValidatingPublicKey) {
+			t.Errorf("Account %d: decrypted key does not match public key", i)
+		}
+	}
+	if _, err := DecryptDerivedValidatingKeys(walletDir, "wrong"); err == nil {
+		t.Error("Expected error decrypting keys with wrong passphrase")
+	}
+
+	recovered, err := RecoverDerivedWallet(recoveredDir, mnemonic, "another passphrase", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range created {
+		if !bytes.Equal(recovered[i].ValidatingPublicKey, created[i].ValidatingPublicKey) {
+			t.Errorf("Account %d: recovered validating key does not match", i)
+		}
+		if !bytes.Equal(recovered[i].WithdrawalPublicKey, created[i].WithdrawalPublicKey) {
+			t.Errorf("Account %d: recovered withdrawal key does not match", i)
+		}
+	}
+}
+
+func TestRecoverDerivedWallet_InvalidMnemonic(t *testing.T) {
+	walletDir := filepath.Join(testutil.TempDir(), "invalid")
+	defer func() {
+		if err := os.RemoveAll(walletDir); err != nil {
+			t.Log(err)
+		}
+	}()
+	if _, err := RecoverDerivedWallet(walletDir, "not a valid mnemonic", "passw0rd", 1); err == nil {
+		t.Error("Expected error recovering from invalid mnemonic")
+	}
+	if _, err := ListDerivedAccounts(walletDir); err != ErrNoDerivedWallet {
+		t.Errorf("Expected %v, got %v", ErrNoDerivedWallet, err)
+	}
+}
@@ -0,0 +1,112 @@
+package accounts
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// eip2335FilePrefix is the file name prefix of exported EIP-2335 keystores.
+const eip2335FilePrefix = "keystore-"
+
+// ImportEIP2335Keystores imports the EIP-2335 keystores found at keysPath, which can either be a single
+// keystore file or a directory of keystores, into the validator keystore at keystorePath. Imported keys
+// are encrypted with the keystore passphrase, so that the keystore keymanager is able to load them.
+func ImportEIP2335Keystores(keysPath string, keysPassword string, keystorePath string, passphrase string) ([]*keystore.Key, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase is not allowed")
+	}
+	files, err := eip2335Files(keysPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no keystore found at path %s", keysPath)
+	}
+	if err := os.MkdirAll(keystorePath, 0700); err != nil {
+		return nil, errors.Wrapf(err, "could not create keystore directory %s", keystorePath)
+	}
+
+	ks := keystore.NewKeystore(keystorePath)
+	imported := make([]*keystore.Key, 0, len(files))
+	for _, file := range files {
+		keyJSON, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read keystore %s", file)
+		}
+		key, err := keystore.DecryptKeyEIP2335(keyJSON, keysPassword)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt keystore %s", file)
+		}
+		keyFile := filepath.Join(keystorePath, params.BeaconConfig().ValidatorPrivkeyFileName+hex.EncodeToString(key.PublicKey.Marshal())[:12])
+		if err := ks.StoreKey(keyFile, key, passphrase); err != nil {
+			return nil, errors.Wrapf(err, "could not store key from %s", file)
+		}
+		log.WithField("publicKey", fmt.Sprintf("%#x", key.PublicKey.Marshal())).Info("Imported validator key")
+		imported = append(imported, key)
+	}
+	return imported, nil
+}
+
+// ExportEIP2335Keystores exports the validator keys of the keystore at keystorePath as EIP-2335
+// keystores in outputDir, encrypted with the given password and key derivation function.
+func ExportEIP2335Keystores(keystorePath string, passphrase string, outputDir string, outputPassword string, kdf string) ([]string, error) {
+	if outputPassword == "" {
+		return nil, errors.New("empty password is not allowed")
+	}
+	keys, err := DecryptKeysFromKeystore(keystorePath, params.BeaconConfig().ValidatorPrivkeyFileName, passphrase)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not decrypt keys from keystore in path %s", keystorePath)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no validator keys found in keystore at path %s", keystorePath)
+	}
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "could not create output directory %s", outputDir)
+	}
+
+	files := make([]string, 0, len(keys))
+	for _, key := range keys {
+		keyJSON, err := keystore.EncryptKeyEIP2335(key, outputPassword, "" /* path unknown */, kdf)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not encrypt key")
+		}
+		file := filepath.Join(outputDir, eip2335FilePrefix+hex.EncodeToString(key.PublicKey.Marshal())+".json")
+		if err := ioutil.WriteFile(file, keyJSON, 0600); err != nil {
+			return nil, errors.Wrapf(err, "could not write keystore %s", file)
+		}
+		log.WithField("path", file).Info("Exported validator key")
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// eip2335Files lists the JSON files at the given path, which can either be a file or a directory.
+func eip2335Files(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		files = append(files, filepath.Join(path, entry.Name()))
+	}
+	return files, nil
+}
@@ -0,0 +1,88 @@
+package accounts
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestExportImportEIP2335Keystores(t *testing.T) {
+	tmpDir := testutil.TempDir()
+	sourceDir := filepath.Join(tmpDir, "sourcekeystore")
+	exportDir := filepath.Join(tmpDir, "eip2335keystores")
+	targetDir := filepath.Join(tmpDir, "targetkeystore")
+	defer func() {
+		for _, dir := range []string{sourceDir, exportDir, targetDir} {
+			if err := os.RemoveAll(dir); err != nil {
+				t.Logf("Could not remove directory: %v", err)
+			}
+		}
+	}()
+
+	validatorKey, err := keystore.NewKey()
+	if err != nil {
+		t.Fatalf("Cannot create new key: %v", err)
+	}
+	ks := keystore.NewKeystore(sourceDir)
+	if err := ks.StoreKey(sourceDir+params.BeaconConfig().ValidatorPrivkeyFileName, validatorKey, "source"); err != nil {
+		t.Fatalf("Unable to store key %v", err)
+	}
+
+	exported, err := ExportEIP2335Keystores(sourceDir, "source", exportDir, "exported", keystore.KDFPBKDF2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 1 {
+		t.Fatalf("Expected 1 exported keystore, got %d", len(exported))
+	}
+	keyJSON, err := ioutil.ReadFile(exported[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := keystore.DecryptKeyEIP2335(keyJSON, "exported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted.SecretKey.Marshal(), validatorKey.SecretKey.Marshal()) {
+		t.Error("Exported key does not match the keystore key")
+	}
+
+	if _, err := ImportEIP2335Keystores(exportDir, "wrong", targetDir, "target"); err == nil {
+		t.Error("Expected error importing keystores with wrong password")
+	}
+	imported, err := ImportEIP2335Keystores(exportDir, "exported", targetDir, "target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("Expected 1 imported key, got %d", len(imported))
+	}
+	pubKeys, err := ExtractPublicKeysFromKeyStore(targetDir, "target")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKeys) != 1 || !bytes.Equal(pubKeys[0], validatorKey.PublicKey.Marshal()) {
+		t.Errorf("Imported key not found in keystore, got %#x", pubKeys)
+	}
+}
+
+func TestImportEIP2335Keystores_NoKeystores(t *testing.T) {
+	emptyDir := filepath.Join(testutil.TempDir(), "emptyeip2335")
+	if err := os.MkdirAll(emptyDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(emptyDir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	if _, err := ImportEIP2335Keystores(emptyDir, "password", filepath.Join(emptyDir, "target"), "target"); err == nil {
+		t.Error("Expected error importing from an empty directory")
+	}
+}
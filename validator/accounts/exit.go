@@ -0,0 +1,121 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// ExitSigner signs a voluntary exit with a validating key, in the given signature domain.
+type ExitSigner func(pubKey [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error)
+
+// ExitResult is the outcome of exiting a validator. Err is nil if the exit was submitted.
+type ExitResult struct {
+	PublicKey []byte
+	Index     uint64
+	Err       error
+}
+
+// CheckExitEligibility splits the validators into those which may exit at the current epoch and
+// the results of those which may not. Only active validators which have been active for at
+// least the shard committee period may exit.
+func CheckExitEligibility(statuses []ValidatorStatusMetadata, currentEpoch uint64) ([]ValidatorStatusMetadata, []*ExitResult) {
+	var eligible []ValidatorStatusMetadata
+	var ineligible []*ExitResult
+	for _, st := range statuses {
+		var err error
+		switch {
+		case st.Metadata == nil || st.Metadata.Status != ethpb.ValidatorStatus_ACTIVE:
+			status := ethpb.ValidatorStatus_UNKNOWN_STATUS
+			if st.Metadata != nil {
+				status = st.Metadata.Status
+			}
+			err = fmt.Errorf("validator is not active, status %s", status)
+		case currentEpoch < st.Metadata.ActivationEpoch+params.BeaconConfig().ShardCommitteePeriod:
+			err = fmt.Errorf(
+				"validator has not been active long enough to exit, eligible at epoch %d",
+				st.Metadata.ActivationEpoch+params.BeaconConfig().ShardCommitteePeriod,
+			)
+		}
+		if err != nil {
+			ineligible = append(ineligible, &ExitResult{PublicKey: st.PublicKey, Index: st.Index, Err: err})
+			continue
+		}
+		eligible = append(eligible, st)
+	}
+	return eligible, ineligible
+}
+
+// SubmitExits signs voluntary exits of the validators at the given epoch and submits them to the
+// beacon node. A failure to exit one validator does not prevent exiting the others.
+func SubmitExits(
+	ctx context.Context,
+	validatorClient ethpb.BeaconNodeValidatorClient,
+	sign ExitSigner,
+	validators []ValidatorStatusMetadata,
+	epoch uint64,
+) []*ExitResult {
+	ctx, span := trace.StartSpan(ctx, "accounts.SubmitExits")
+	defer span.End()
+
+	results := make([]*ExitResult, len(validators))
+	for i, v := range validators {
+		results[i] = &ExitResult{
+			PublicKey: v.PublicKey,
+			Index:     v.Index,
+			Err:       submitExit(ctx, validatorClient, sign, v, epoch),
+		}
+	}
+	return results
+}
+
+func submitExit(
+	ctx context.Context,
+	validatorClient ethpb.BeaconNodeValidatorClient,
+	sign ExitSigner,
+	v ValidatorStatusMetadata,
+	epoch uint64,
+) error {
+	exit := &ethpb.VoluntaryExit{Epoch: epoch, ValidatorIndex: v.Index}
+	domain, err := validatorClient.DomainData(ctx, &ethpb.DomainRequest{
+		Epoch:  epoch,
+		Domain: params.BeaconConfig().DomainVoluntaryExit[:],
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not get exit domain")
+	}
+	var pubKey [48]byte
+	copy(pubKey[:], v.PublicKey)
+	sig, err := sign(pubKey, exit, domain.SignatureDomain)
+	if err != nil {
+		return errors.Wrap(err, "could not sign exit")
+	}
+	if _, err := validatorClient.ProposeExit(ctx, &ethpb.SignedVoluntaryExit{
+		Exit:      exit,
+		Signature: sig.Marshal(),
+	}); err != nil {
+		return errors.Wrap(err, "could not submit exit")
+	}
+	return nil
+}
+
+// PrintExitResults logs the outcome of exiting each validator.
+func PrintExitResults(results []*ExitResult) {
+	for _, r := range results {
+		log := log.WithFields(logrus.Fields{
+			"publicKey": fmt.Sprintf("%#x", r.PublicKey),
+			"index":     r.Index,
+		})
+		if r.Err != nil {
+			log.WithError(r.Err).Error("Validator not exited")
+			continue
+		}
+		log.Info("Submitted voluntary exit")
+	}
+}
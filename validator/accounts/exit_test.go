@@ -0,0 +1,67 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestCheckExitEligibility(t *testing.T) {
+	period := params.BeaconConfig().ShardCommitteePeriod
+	statuses := []ValidatorStatusMetadata{
+		{PublicKey: []byte{1}, Index: 1, Metadata: &ethpb.ValidatorStatusResponse{Status: ethpb.ValidatorStatus_ACTIVE, ActivationEpoch: 10}},
+		{PublicKey: []byte{2}, Index: 2, Metadata: &ethpb.ValidatorStatusResponse{Status: ethpb.ValidatorStatus_ACTIVE, ActivationEpoch: 11}},
+		{PublicKey: []byte{3}, Index: 3, Metadata: &ethpb.ValidatorStatusResponse{Status: ethpb.ValidatorStatus_EXITING, ActivationEpoch: 0}},
+		{PublicKey: []byte{4}, Index: 4, Metadata: &ethpb.ValidatorStatusResponse{Status: ethpb.ValidatorStatus_PENDING}},
+	}
+	eligible, ineligible := CheckExitEligibility(statuses, 10+period)
+	if len(eligible) != 1 || eligible[0].Index != 1 {
+		t.Errorf("Wanted only validator 1 to be eligible, received %v", eligible)
+	}
+	if len(ineligible) != 3 {
+		t.Fatalf("Wanted 3 ineligible validators, received %d", len(ineligible))
+	}
+	for i, r := range ineligible {
+		if r.Index != uint64(i+2) || r.Err == nil {
+			t.Errorf("Unexpected result for ineligible validator %d: %v", r.Index, r.Err)
+		}
+	}
+}
+
+func TestSubmitExits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	key := bls.RandKey()
+	sign := func(_ [48]byte, _ *ethpb.VoluntaryExit, _ []byte) (*bls.Signature, error) {
+		return key.Sign([]byte("exit")), nil
+	}
+	domain := params.BeaconConfig().DomainVoluntaryExit[:]
+	client.EXPECT().DomainData(
+		gomock.Any(),
+		&ethpb.DomainRequest{Epoch: 300, Domain: domain},
+	).Times(2).Return(&ethpb.DomainResponse{SignatureDomain: make([]byte, 32)}, nil)
+	client.EXPECT().ProposeExit(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ptypes.Empty{}, nil)
+	client.EXPECT().ProposeExit(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, errors.New("rejected"))
+
+	results := SubmitExits(context.Background(), client, sign, []ValidatorStatusMetadata{
+		{PublicKey: []byte{1}, Index: 1},
+		{PublicKey: []byte{2}, Index: 2},
+	}, 300)
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("Wanted the first exit to be submitted and the second to fail, received %v and %v", results[0].Err, results[1].Err)
+	}
+}
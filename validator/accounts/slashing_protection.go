@@ -0,0 +1,101 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+// ExportSlashingProtection exports the slashing protection history of the validator database in
+// dataDir to outputFile, in the slashing protection interchange format. The genesis validators root
+// saved in the database is used; genesisValidatorsRoot is only required if the database has none.
+func ExportSlashingProtection(ctx context.Context, dataDir string, outputFile string, genesisValidatorsRoot []byte) (err error) {
+	store, err := db.GetKVStore(dataDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the validator database")
+	}
+	if store == nil {
+		return fmt.Errorf("no validator database found in %s", dataDir)
+	}
+	defer func() {
+		if deferErr := store.Close(); deferErr != nil {
+			if err != nil {
+				err = errors.Wrap(err, errFailedToCloseDb.Error())
+			} else {
+				err = errors.Wrap(deferErr, errFailedToCloseDb.Error())
+			}
+		}
+	}()
+
+	savedRoot, err := store.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not read the genesis validators root")
+	}
+	if savedRoot != nil {
+		if len(genesisValidatorsRoot) != 0 && !bytes.Equal(savedRoot, genesisValidatorsRoot) {
+			return errors.Wrapf(
+				db.ErrGenesisValidatorsRootMismatch,
+				"saved %#x, got %#x",
+				savedRoot,
+				genesisValidatorsRoot,
+			)
+		}
+		genesisValidatorsRoot = savedRoot
+	}
+	if len(genesisValidatorsRoot) == 0 {
+		return errors.New("the validator database has no genesis validators root, please provide one")
+	}
+
+	interchange, err := store.ExportInterchange(ctx, genesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not export slashing protection history")
+	}
+	enc, err := json.MarshalIndent(interchange, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode slashing protection history")
+	}
+	if err := ioutil.WriteFile(outputFile, enc, 0600); err != nil {
+		return errors.Wrapf(err, "could not write slashing protection history to %s", outputFile)
+	}
+	log.WithField("validators", len(interchange.Data)).Info("Exported slashing protection history")
+	return nil
+}
+
+// ImportSlashingProtection merges the slashing protection history of inputFile, in the slashing
+// protection interchange format, into the validator database in dataDir. The database is created
+// if it does not exist.
+func ImportSlashingProtection(ctx context.Context, dataDir string, inputFile string) (err error) {
+	enc, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return errors.Wrapf(err, "could not read slashing protection history from %s", inputFile)
+	}
+	interchange := &db.Interchange{}
+	if err := json.Unmarshal(enc, interchange); err != nil {
+		return errors.Wrap(err, "could not decode slashing protection history")
+	}
+
+	store, err := db.NewKVStore(dataDir, [][48]byte{})
+	if err != nil {
+		return errors.Wrapf(err, "could not open the validator database in %s", dataDir)
+	}
+	defer func() {
+		if deferErr := store.Close(); deferErr != nil {
+			if err != nil {
+				err = errors.Wrap(err, errFailedToCloseDb.Error())
+			} else {
+				err = errors.Wrap(deferErr, errFailedToCloseDb.Error())
+			}
+		}
+	}()
+
+	if err := store.ImportInterchange(ctx, interchange); err != nil {
+		return errors.Wrap(err, "could not import slashing protection history")
+	}
+	log.WithField("validators", len(interchange.Data)).Info("Imported slashing protection history")
+	return nil
+}
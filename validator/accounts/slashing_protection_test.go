@@ -0,0 +1,73 @@
+package accounts
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+func TestExportImportSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := filepath.Join(testutil.TempDir(), "slashingprotection")
+	sourceDir := filepath.Join(tmpDir, "source")
+	targetDir := filepath.Join(tmpDir, "target")
+	interchangeFile := filepath.Join(tmpDir, "interchange.json")
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+
+	pubKey := [48]byte{1}
+	store, err := db.NewKVStore(sourceDir, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(5, true)
+	if err := store.SaveProposalHistoryForEpoch(ctx, pubKey[:], 3, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExportSlashingProtection(ctx, sourceDir, interchangeFile, nil); err == nil {
+		t.Fatal("Expected error exporting without a genesis validators root")
+	}
+	genesisValidatorsRoot := bytes.Repeat([]byte{1}, 32)
+	if err := ExportSlashingProtection(ctx, sourceDir, interchangeFile, genesisValidatorsRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportSlashingProtection(ctx, targetDir, interchangeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := db.GetKVStore(targetDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	importedBits, err := target.ProposalHistoryForEpoch(ctx, pubKey[:], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(importedBits, slotBits) {
+		t.Errorf("Expected proposal history %v, received %v", slotBits, importedBits)
+	}
+	// Exporting the imported history for another chain is refused.
+	if err := target.Close(); err != nil {
+		t.Fatal(err)
+	}
+	err = ExportSlashingProtection(ctx, targetDir, interchangeFile, bytes.Repeat([]byte{2}, 32))
+	if errors.Cause(err) != db.ErrGenesisValidatorsRootMismatch {
+		t.Errorf("Expected %v, received %v", db.ErrGenesisValidatorsRootMismatch, err)
+	}
+}
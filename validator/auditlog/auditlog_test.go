@@ -0,0 +1,186 @@
+package auditlog
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func setupDir(t *testing.T) string {
+	dir := filepath.Join(testutil.TempDir(), fmt.Sprintf("auditlog-%s", t.Name()))
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return dir
+}
+
+func testEntry(typ string, slot uint64) *Entry {
+	e := &Entry{
+		Type:        typ,
+		PublicKey:   fmt.Sprintf("%#x", bytes.Repeat([]byte{0x01}, 48)),
+		Domain:      fmt.Sprintf("%#x", make([]byte, 32)),
+		SigningRoot: fmt.Sprintf("%#x", bytes.Repeat([]byte{byte(slot)}, 32)),
+		Slot:        slot,
+		Epoch:       slot / 32,
+	}
+	if typ == TypeAttestation {
+		e.SourceEpoch = slot/32 - 1
+		e.TargetEpoch = slot / 32
+	}
+	return e
+}
+
+// writeEntries appends n entries to the log in dir, alternating blocks and attestations.
+func writeEntries(t *testing.T, dir string, maxFileSize int64, n int) {
+	w, err := NewWriter(dir, maxFileSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		typ := TypeBlock
+		if i%2 == 1 {
+			typ = TypeAttestation
+		}
+		if err := w.Append(testEntry(typ, uint64(64+i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriter_AppendVerify(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 1<<20, 10)
+	res, err := Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Files != 1 || res.Entries != 10 {
+		t.Errorf("Wanted 1 file with 10 entries, received %d files with %d entries", res.Files, res.Entries)
+	}
+}
+
+func TestWriter_RotatesAndResumes(t *testing.T) {
+	dir := setupDir(t)
+	// Small files hold a couple of entries each.
+	writeEntries(t, dir, 700, 5)
+	writeEntries(t, dir, 700, 5)
+	res, err := Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 10 {
+		t.Errorf("Wanted 10 entries, received %d", res.Entries)
+	}
+	if res.Files < 3 {
+		t.Errorf("Wanted the log to be rotated into several files, received %d files", res.Files)
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 1<<20, 5)
+	path := filePath(dir, 1)
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(enc), "\n")
+	lines[2] = strings.Replace(lines[2], `"slot":66`, `"slot":67`, 1)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(dir); err == nil || !strings.Contains(err.Error(), "entry 2 was modified") {
+		t.Errorf("Wanted modified entry 2 to be detected, received %v", err)
+	}
+}
+
+func TestVerify_DetectsMissingEntries(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 1<<20, 5)
+	path := filePath(dir, 1)
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(string(enc), "\n")
+	lines = append(lines[:1], lines[3:]...)
+	if err := ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(dir); err == nil || !strings.Contains(err.Error(), "entries 1 to 2 are missing") {
+		t.Errorf("Wanted missing entries to be detected, received %v", err)
+	}
+}
+
+func TestVerify_DetectsMissingFile(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 700, 10)
+	if err := os.Remove(filePath(dir, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(dir); err == nil || !strings.Contains(err.Error(), "audit log files 2 to 2 are missing") {
+		t.Errorf("Wanted missing file to be detected, received %v", err)
+	}
+}
+
+func TestVerify_PrunedOldestFiles(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 700, 10)
+	if err := os.Remove(filePath(dir, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(dir); err != nil {
+		t.Errorf("Wanted the remaining chain to verify, received %v", err)
+	}
+}
+
+func TestInterchange(t *testing.T) {
+	dir := setupDir(t)
+	writeEntries(t, dir, 1<<20, 4)
+	w, err := NewWriter(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Append(testEntry(TypeRandao, 70)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Interchange(dir, []byte{0x01}); err == nil {
+		t.Error("Expected an error for an invalid genesis validators root")
+	}
+	interchange, err := Interchange(dir, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 {
+		t.Fatalf("Wanted history for 1 key, received %d", len(interchange.Data))
+	}
+	data := interchange.Data[0]
+	if len(data.SignedBlocks) != 2 || len(data.SignedAttestations) != 2 {
+		t.Fatalf("Wanted 2 blocks and 2 attestations, received %d and %d", len(data.SignedBlocks), len(data.SignedAttestations))
+	}
+	if data.SignedBlocks[1].Slot != 66 {
+		t.Errorf("Wanted second block at slot 66, received %d", data.SignedBlocks[1].Slot)
+	}
+	if data.SignedAttestations[0].TargetEpoch != 2 || data.SignedAttestations[0].SourceEpoch != 1 {
+		t.Errorf("Wanted attestation from epoch 1 to 2, received %d to %d",
+			data.SignedAttestations[0].SourceEpoch, data.SignedAttestations[0].TargetEpoch)
+	}
+}
@@ -0,0 +1,274 @@
+// Package auditlog keeps a log of every signature made by the validator client. Entries are
+// appended as JSON lines to files in a directory, and each entry holds the hash of the previous
+// one, so that removing, reordering or changing entries breaks the chain. Files are rotated once
+// they reach a maximum size, and the chain continues across files.
+//
+// The chain is not keyed, so it only detects accidental edits, lost entries and truncation of the
+// start of the log. Anyone able to write to the log can recompute the hashes of a forged chain, and
+// dropping the last entries leaves a valid chain; detecting either requires comparing the last hash
+// reported by Verify with one recorded elsewhere.
+package auditlog
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/hashutil"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "auditlog")
+
+// Types of signed objects recorded in the log.
+const (
+	TypeBlock             = "block"
+	TypeAttestation       = "attestation"
+	TypeRandao            = "randao"
+	TypeSelectionProof    = "selection_proof"
+	TypeAggregateAndProof = "aggregate_and_proof"
+	TypeVoluntaryExit     = "voluntary_exit"
+	TypeDeposit           = "deposit"
+	TypeGeneric           = "generic"
+)
+
+const (
+	filePrefix = "audit-"
+	fileSuffix = ".log"
+)
+
+// genesisHash is the previous hash of the first entry of the log.
+var genesisHash = fmt.Sprintf("%#x", make([]byte, 32))
+
+// Entry is a signature recorded in the log. Byte fields are hex encoded with a 0x prefix. Slot and
+// epochs are only set for the types which sign them: blocks have a slot and epoch, attestations a
+// slot, source epoch and target epoch.
+type Entry struct {
+	Index       uint64 `json:"index"`
+	Timestamp   string `json:"timestamp"`
+	Type        string `json:"type"`
+	PublicKey   string `json:"pubkey"`
+	Domain      string `json:"domain"`
+	SigningRoot string `json:"signing_root"`
+	Slot        uint64 `json:"slot"`
+	Epoch       uint64 `json:"epoch"`
+	SourceEpoch uint64 `json:"source_epoch"`
+	TargetEpoch uint64 `json:"target_epoch"`
+	PrevHash    string `json:"prev_hash"`
+	Hash        string `json:"hash"`
+}
+
+// computeHash returns the hash of the entry, which covers every field but the hash itself.
+func (e *Entry) computeHash() (string, error) {
+	unhashed := *e
+	unhashed.Hash = ""
+	enc, err := json.Marshal(&unhashed)
+	if err != nil {
+		return "", err
+	}
+	h := hashutil.Hash(enc)
+	return fmt.Sprintf("%#x", h), nil
+}
+
+// Writer appends entries to the log in a directory.
+type Writer struct {
+	dir         string
+	maxFileSize int64
+	lock        sync.Mutex
+	file        *os.File
+	fileSeq     uint64
+	fileSize    int64
+	nextIndex   uint64
+	prevHash    string
+}
+
+// NewWriter opens the log in dir, continuing the chain of the entries already in it. Files are
+// rotated once they reach maxFileSize bytes.
+func NewWriter(dir string, maxFileSize int64) (*Writer, error) {
+	if maxFileSize <= 0 {
+		return nil, fmt.Errorf("invalid maximum audit log file size %d", maxFileSize)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create audit log directory")
+	}
+	w := &Writer{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		fileSeq:     1,
+		prevHash:    genesisHash,
+	}
+	seqs, err := fileSeqs(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Continue after the last entry, skipping files which were created but never written.
+	for i := len(seqs) - 1; i >= 0; i-- {
+		last, size, err := lastEntry(filePath(dir, seqs[i]))
+		if err != nil {
+			return nil, err
+		}
+		w.fileSeq = seqs[i]
+		w.fileSize = size
+		if last != nil {
+			w.nextIndex = last.Index + 1
+			w.prevHash = last.Hash
+			break
+		}
+	}
+	return w, nil
+}
+
+// Append records the entry at the end of the log, setting its index, chain hashes and, if unset,
+// its timestamp. The entry is synced to disk before Append returns.
+func (w *Writer) Append(e *Entry) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.fileSize >= w.maxFileSize {
+		if w.file != nil {
+			if err := w.file.Close(); err != nil {
+				return errors.Wrap(err, "could not close audit log file")
+			}
+			w.file = nil
+		}
+		w.fileSeq++
+		w.fileSize = 0
+	}
+	if w.file == nil {
+		f, err := os.OpenFile(filePath(w.dir, w.fileSeq), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return errors.Wrap(err, "could not open audit log file")
+		}
+		w.file = f
+	}
+
+	e.Index = w.nextIndex
+	if e.Timestamp == "" {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	e.PrevHash = w.prevHash
+	hash, err := e.computeHash()
+	if err != nil {
+		return errors.Wrap(err, "could not hash audit log entry")
+	}
+	e.Hash = hash
+	enc, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "could not encode audit log entry")
+	}
+	enc = append(enc, '\n')
+	if _, err := w.file.Write(enc); err != nil {
+		return errors.Wrap(err, "could not write audit log entry")
+	}
+	if err := w.file.Sync(); err != nil {
+		return errors.Wrap(err, "could not sync audit log entry")
+	}
+	w.fileSize += int64(len(enc))
+	w.nextIndex++
+	w.prevHash = e.Hash
+	return nil
+}
+
+// Close closes the current log file.
+func (w *Writer) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func filePath(dir string, seq uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%06d%s", filePrefix, seq, fileSuffix))
+}
+
+// fileSeqs returns the sequence numbers of the log files in dir, in ascending order.
+func fileSeqs(dir string) ([]uint64, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list audit log directory")
+	}
+	var seqs []uint64
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+// readEntries calls fn with each entry of a log file and its line number.
+func readEntries(path string, fn func(line int, e *Entry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "could not open audit log file")
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.WithError(err).Error("Could not close audit log file")
+		}
+	}()
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		e := &Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return fmt.Errorf("%s line %d: could not decode entry: %v", path, line, err)
+		}
+		if err := fn(line, e); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// lastEntry returns the last entry of a log file, if any, and the size of the file.
+func lastEntry(path string) (*Entry, int64, error) {
+	var last *Entry
+	if err := readEntries(path, func(_ int, e *Entry) error {
+		last = e
+		return nil
+	}); err != nil {
+		return nil, 0, errors.Wrap(err, "could not read the end of the audit log, verify it before appending")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return last, info.Size(), nil
+}
+
+func decodeHex(s string, length int) ([]byte, error) {
+	if len(s) < 2 || s[:2] != "0x" {
+		return nil, fmt.Errorf("%q is not 0x prefixed", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != length {
+		return nil, fmt.Errorf("%q is not %d bytes", s, length)
+	}
+	return b, nil
+}
@@ -0,0 +1,149 @@
+package auditlog
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+// VerifyResult summarizes a verified log.
+type VerifyResult struct {
+	Files     int
+	Entries   uint64
+	FirstHash string
+	LastHash  string
+}
+
+// Verify checks that the log in dir is complete and unchanged: log files are numbered without
+// gaps, entry indices increase one by one, every entry hashes to its recorded hash, and every entry
+// links to the hash of the entry before it. If the oldest log files were pruned, the chain is
+// checked from the first remaining entry. Removing entries from the end of the log cannot be
+// detected from the log alone, so the last hash should be recorded elsewhere when it matters.
+func Verify(dir string) (*VerifyResult, error) {
+	res := &VerifyResult{}
+	files, err := forEachEntry(dir, func(e *Entry) error {
+		res.Entries++
+		if res.FirstHash == "" {
+			res.FirstHash = e.Hash
+		}
+		res.LastHash = e.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Files = files
+	return res, nil
+}
+
+// Interchange rebuilds the slashing protection history recorded in the log in dir, for the
+// chain identified by genesisValidatorsRoot. The log is verified while it is read, and nothing is
+// returned if its hash chain is broken.
+func Interchange(dir string, genesisValidatorsRoot []byte) (*db.Interchange, error) {
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, fmt.Errorf("invalid genesis validators root length %d", len(genesisValidatorsRoot))
+	}
+	interchange := &db.Interchange{
+		Metadata: &db.InterchangeMetadata{
+			InterchangeFormatVersion: db.InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: []*db.InterchangeData{},
+	}
+	dataByPubKey := make(map[string]*db.InterchangeData)
+	dataForPubKey := func(pubKey string) *db.InterchangeData {
+		data, ok := dataByPubKey[pubKey]
+		if !ok {
+			data = &db.InterchangeData{
+				PubKey:             pubKey,
+				SignedBlocks:       []*db.InterchangeBlock{},
+				SignedAttestations: []*db.InterchangeAttestation{},
+			}
+			dataByPubKey[pubKey] = data
+			interchange.Data = append(interchange.Data, data)
+		}
+		return data
+	}
+	_, err := forEachEntry(dir, func(e *Entry) error {
+		switch e.Type {
+		case TypeBlock:
+			data := dataForPubKey(e.PublicKey)
+			data.SignedBlocks = append(data.SignedBlocks, &db.InterchangeBlock{
+				Slot:        e.Slot,
+				SigningRoot: e.SigningRoot,
+			})
+		case TypeAttestation:
+			data := dataForPubKey(e.PublicKey)
+			data.SignedAttestations = append(data.SignedAttestations, &db.InterchangeAttestation{
+				SourceEpoch: e.SourceEpoch,
+				TargetEpoch: e.TargetEpoch,
+				SigningRoot: e.SigningRoot,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return interchange, nil
+}
+
+// forEachEntry verifies the log in dir while calling fn with each entry in order, and returns the
+// number of log files.
+func forEachEntry(dir string, fn func(e *Entry) error) (int, error) {
+	seqs, err := fileSeqs(dir)
+	if err != nil {
+		return 0, err
+	}
+	var prev *Entry
+	for i, seq := range seqs {
+		if i > 0 && seq != seqs[i-1]+1 {
+			return 0, fmt.Errorf("audit log files %d to %d are missing", seqs[i-1]+1, seq-1)
+		}
+		path := filePath(dir, seq)
+		if err := readEntries(path, func(line int, e *Entry) error {
+			if err := verifyEntry(e, prev); err != nil {
+				return errors.Wrapf(err, "%s line %d", path, line)
+			}
+			prev = e
+			return fn(e)
+		}); err != nil {
+			return 0, err
+		}
+	}
+	return len(seqs), nil
+}
+
+// verifyEntry checks that the entry is intact and follows the previous entry, if any.
+func verifyEntry(e *Entry, prev *Entry) error {
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	if hash != e.Hash {
+		return fmt.Errorf("entry %d was modified: its hash is %s but %s is recorded", e.Index, hash, e.Hash)
+	}
+	if _, err := decodeHex(e.PublicKey, 48); err != nil {
+		return errors.Wrapf(err, "entry %d has an invalid public key", e.Index)
+	}
+	if _, err := decodeHex(e.SigningRoot, 32); err != nil {
+		return errors.Wrapf(err, "entry %d has an invalid signing root", e.Index)
+	}
+	if prev == nil {
+		if e.Index == 0 && e.PrevHash != genesisHash {
+			return errors.New("first entry does not start the chain")
+		}
+		return nil
+	}
+	if e.Index <= prev.Index {
+		return fmt.Errorf("entry %d follows entry %d", e.Index, prev.Index)
+	}
+	if e.Index != prev.Index+1 {
+		return fmt.Errorf("entries %d to %d are missing", prev.Index+1, e.Index-1)
+	}
+	if e.PrevHash != prev.Hash {
+		return fmt.Errorf("entry %d does not link to entry %d", e.Index, prev.Index)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+// Package doppelganger checks whether the validating keys of a validator client are
+// already in use by another validator client before any duties are performed with
+// them. Running the same keys from two machines at once is the most common cause of
+// slashing, typically after a botched failover.
+package doppelganger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/client/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "doppelganger")
+
+// Config for doppelganger detection.
+type Config struct {
+	BeaconClient ethpb.BeaconChainClient
+	GenesisTime  uint64
+	Epochs       uint64
+	PubKeys      [][48]byte
+}
+
+type detector struct {
+	beaconClient ethpb.BeaconChainClient
+	startEpoch   uint64
+	keys         map[uint64][48]byte // Validator index to public key.
+	detected     map[[48]byte]bool
+	lock         sync.Mutex
+}
+
+// Detect watches the chain for the configured number of epochs and returns the
+// validating keys seen signing during that time. A key is detected if an attestation
+// of it is included on chain, as reported by GetIndividualVotes, or if it shows up in
+// the attestation or block streams of the beacon node.
+//
+// The epoch the detection starts in is not watched, as this client may have signed
+// in it before being restarted. Attestations for an epoch can be included on chain
+// until the end of the following epoch, so the votes of the last watched epoch are
+// only checked one epoch after it ends and detection takes up to two epochs longer
+// than configured.
+func Detect(ctx context.Context, cfg *Config) (map[[48]byte]bool, error) {
+	d := &detector{
+		beaconClient: cfg.BeaconClient,
+		keys:         make(map[uint64][48]byte),
+		detected:     make(map[[48]byte]bool),
+	}
+	if cfg.Epochs == 0 || len(cfg.PubKeys) == 0 {
+		return d.detected, nil
+	}
+	d.startEpoch = slotutil.EpochsSinceGenesis(time.Unix(int64(cfg.GenesisTime), 0))
+	if err := d.fetchIndices(ctx, cfg.PubKeys); err != nil {
+		return nil, err
+	}
+	if len(d.keys) == 0 {
+		return d.detected, nil
+	}
+	log.WithFields(logrus.Fields{
+		"epochs": cfg.Epochs,
+		"keys":   len(d.keys),
+	}).Info("Checking whether validating keys are in use by another validator client before performing duties")
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go d.watchAttestations(streamCtx)
+	go d.watchBlocks(streamCtx)
+
+	lastEpoch := d.startEpoch + cfg.Epochs
+	for epoch := d.startEpoch + 1; epoch <= lastEpoch; epoch++ {
+		// Attestations for an epoch can be included until the end of the next epoch, so
+		// its votes are only complete in the state at the start of the epoch after that.
+		// The state is queried one slot in to give the beacon node time to process the
+		// epoch transition.
+		if err := waitForSlot(ctx, cfg.GenesisTime, helpers.StartSlot(epoch+2)+1); err != nil {
+			return nil, err
+		}
+		if err := d.checkVotes(ctx, epoch); err != nil {
+			return nil, err
+		}
+		if d.allDetected() {
+			break
+		}
+		log.WithFields(logrus.Fields{
+			"epoch":           epoch,
+			"epochsRemaining": lastEpoch - epoch,
+		}).Info("Finished checking epoch for doppelgangers")
+	}
+	return d.result(), nil
+}
+
+// Filter returns the public keys which were not detected.
+func Filter(pubKeys [][48]byte, detected map[[48]byte]bool) [][48]byte {
+	if len(detected) == 0 {
+		return pubKeys
+	}
+	filtered := make([][48]byte, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		if !detected[pubKey] {
+			filtered = append(filtered, pubKey)
+		}
+	}
+	return filtered
+}
+
+// fetchIndices resolves the validator indices of the public keys. Keys which are not
+// yet in the beacon state cannot have signed anything and are not watched.
+func (d *detector) fetchIndices(ctx context.Context, pubKeys [][48]byte) error {
+	res, err := d.beaconClient.GetIndividualVotes(ctx, &ethpb.IndividualVotesRequest{
+		Epoch:      d.startEpoch,
+		PublicKeys: bytesutil.FromBytes48Array(pubKeys),
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not get validator indices")
+	}
+	for _, vote := range res.IndividualVotes {
+		if vote.ValidatorIndex == ^uint64(0) || len(vote.PublicKey) != 48 {
+			continue
+		}
+		d.keys[vote.ValidatorIndex] = bytesutil.ToBytes48(vote.PublicKey)
+	}
+	return nil
+}
+
+// checkVotes marks the keys with an attestation for the given epoch included on chain,
+// including attestations included late in the following epoch.
+func (d *detector) checkVotes(ctx context.Context, epoch uint64) error {
+	indices := make([]uint64, 0, len(d.keys))
+	for index := range d.keys {
+		indices = append(indices, index)
+	}
+	res, err := d.beaconClient.GetIndividualVotes(ctx, &ethpb.IndividualVotesRequest{
+		Epoch:   epoch + 2,
+		Indices: indices,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not get votes for epoch %d", epoch)
+	}
+	for _, vote := range res.IndividualVotes {
+		if !vote.IsPreviousEpochAttester {
+			continue
+		}
+		if pubKey, ok := d.keys[vote.ValidatorIndex]; ok {
+			d.markDetected(pubKey, "included attestation", epoch)
+		}
+	}
+	return nil
+}
+
+func (d *detector) watchAttestations(ctx context.Context) {
+	stream, err := d.beaconClient.StreamIndexedAttestations(ctx, &ptypes.Empty{})
+	if err != nil {
+		log.WithError(err).Warn("Could not watch attestations, relying on included attestations only")
+		return
+	}
+	for {
+		att, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("Could not receive attestations, relying on included attestations only")
+			return
+		}
+		d.handleAttestation(att)
+	}
+}
+
+func (d *detector) watchBlocks(ctx context.Context) {
+	stream, err := d.beaconClient.StreamBlocks(ctx, &ptypes.Empty{})
+	if err != nil {
+		log.WithError(err).Warn("Could not watch blocks, relying on included attestations only")
+		return
+	}
+	for {
+		blk, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.WithError(err).Warn("Could not receive blocks, relying on included attestations only")
+			return
+		}
+		d.handleBlock(blk)
+	}
+}
+
+func (d *detector) handleAttestation(att *ethpb.IndexedAttestation) {
+	if att.Data == nil || att.Data.Target == nil || att.Data.Target.Epoch <= d.startEpoch {
+		return
+	}
+	for _, index := range att.AttestingIndices {
+		if pubKey, ok := d.keys[index]; ok {
+			d.markDetected(pubKey, "attestation", att.Data.Target.Epoch)
+		}
+	}
+}
+
+func (d *detector) handleBlock(blk *ethpb.SignedBeaconBlock) {
+	if blk.Block == nil {
+		return
+	}
+	epoch := helpers.SlotToEpoch(blk.Block.Slot)
+	if epoch <= d.startEpoch {
+		return
+	}
+	if pubKey, ok := d.keys[blk.Block.ProposerIndex]; ok {
+		d.markDetected(pubKey, "block", epoch)
+	}
+}
+
+func (d *detector) markDetected(pubKey [48]byte, source string, epoch uint64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.detected[pubKey] {
+		return
+	}
+	d.detected[pubKey] = true
+	metrics.ValidatorDoppelgangerDetectedVec.WithLabelValues(fmt.Sprintf("%#x", pubKey[:])).Set(1)
+	log.WithFields(logrus.Fields{
+		"publicKey": fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])),
+		"source":    source,
+		"epoch":     epoch,
+	}).Error("Validating key is in use by another validator client, refusing to perform its duties")
+}
+
+func (d *detector) allDetected() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return len(d.detected) == len(d.keys)
+}
+
+func (d *detector) result() map[[48]byte]bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	detected := make(map[[48]byte]bool, len(d.detected))
+	for pubKey := range d.detected {
+		detected[pubKey] = true
+	}
+	return detected
+}
+
+func waitForSlot(ctx context.Context, genesisTime uint64, slot uint64) error {
+	wait := roughtime.Until(slotutil.SlotStartTime(genesisTime, slot))
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package doppelganger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"google.golang.org/grpc"
+)
+
+type fakeBeaconClient struct {
+	ethpb.BeaconChainClient
+	votes    map[uint64][]*ethpb.IndividualVotesRespond_IndividualVote
+	requests []*ethpb.IndividualVotesRequest
+}
+
+func (c *fakeBeaconClient) GetIndividualVotes(_ context.Context, req *ethpb.IndividualVotesRequest, _ ...grpc.CallOption) (*ethpb.IndividualVotesRespond, error) {
+	c.requests = append(c.requests, req)
+	return &ethpb.IndividualVotesRespond{IndividualVotes: c.votes[req.Epoch]}, nil
+}
+
+func testKeys() [][48]byte {
+	return [][48]byte{{'a'}, {'b'}, {'c'}}
+}
+
+func TestDetect_Disabled(t *testing.T) {
+	client := &fakeBeaconClient{}
+	detected, err := Detect(context.Background(), &Config{
+		BeaconClient: client,
+		PubKeys:      testKeys(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detected) != 0 || len(client.requests) != 0 {
+		t.Errorf("Expected no detection when disabled, detected %d with %d requests", len(detected), len(client.requests))
+	}
+}
+
+func TestDetect_SkipsUnknownKeys(t *testing.T) {
+	keys := testKeys()
+	client := &fakeBeaconClient{votes: map[uint64][]*ethpb.IndividualVotesRespond_IndividualVote{
+		0: {
+			{PublicKey: keys[0][:], ValidatorIndex: ^uint64(0)},
+			{PublicKey: keys[1][:], ValidatorIndex: ^uint64(0)},
+		},
+	}}
+	genesis := uint64(roughtime.Now().Add(time.Hour).Unix())
+	detected, err := Detect(context.Background(), &Config{
+		BeaconClient: client,
+		GenesisTime:  genesis,
+		Epochs:       2,
+		PubKeys:      keys[:2],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detected) != 0 || len(client.requests) != 1 {
+		t.Errorf("Expected keys not in the state to be skipped, detected %d with %d requests", len(detected), len(client.requests))
+	}
+}
+
+func TestDetector_CheckVotes(t *testing.T) {
+	keys := testKeys()
+	client := &fakeBeaconClient{votes: map[uint64][]*ethpb.IndividualVotesRespond_IndividualVote{
+		7: {
+			{ValidatorIndex: 1, IsPreviousEpochAttester: true},
+			{ValidatorIndex: 2, IsCurrentEpochAttester: true},
+		},
+	}}
+	d := &detector{
+		beaconClient: client,
+		startEpoch:   4,
+		keys:         map[uint64][48]byte{1: keys[0], 2: keys[1]},
+		detected:     make(map[[48]byte]bool),
+	}
+	if err := d.checkVotes(context.Background(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if client.requests[0].Epoch != 7 {
+		t.Errorf("Wanted votes for epoch 5 requested from the state of epoch 7, requested %d", client.requests[0].Epoch)
+	}
+	if !d.detected[keys[0]] || d.detected[keys[1]] {
+		t.Errorf("Expected only the previous epoch attester to be detected, detected %v", d.detected)
+	}
+	if d.allDetected() {
+		t.Error("Did not expect all keys to be detected")
+	}
+}
+
+func TestDetector_CheckVotes_LateInclusion(t *testing.T) {
+	keys := testKeys()
+	// The attestation for epoch 5 is only included during epoch 6, so it is missing from
+	// the state at the start of epoch 6.
+	client := &fakeBeaconClient{votes: map[uint64][]*ethpb.IndividualVotesRespond_IndividualVote{
+		6: {{ValidatorIndex: 1}},
+		7: {{ValidatorIndex: 1, IsPreviousEpochAttester: true}},
+	}}
+	d := &detector{
+		beaconClient: client,
+		startEpoch:   4,
+		keys:         map[uint64][48]byte{1: keys[0]},
+		detected:     make(map[[48]byte]bool),
+	}
+	if err := d.checkVotes(context.Background(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if !d.detected[keys[0]] {
+		t.Error("Expected an attestation included in the following epoch to be detected")
+	}
+}
+
+func TestDetector_HandleAttestation(t *testing.T) {
+	keys := testKeys()
+	d := &detector{
+		startEpoch: 4,
+		keys:       map[uint64][48]byte{1: keys[0], 2: keys[1]},
+		detected:   make(map[[48]byte]bool),
+	}
+	// Attestations in the start epoch may have been signed by this client before a restart.
+	d.handleAttestation(&ethpb.IndexedAttestation{
+		AttestingIndices: []uint64{1},
+		Data:             &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 4}},
+	})
+	if len(d.detected) != 0 {
+		t.Fatalf("Did not expect an attestation in the start epoch to be detected, detected %v", d.detected)
+	}
+	d.handleAttestation(&ethpb.IndexedAttestation{
+		AttestingIndices: []uint64{2, 3},
+		Data:             &ethpb.AttestationData{Target: &ethpb.Checkpoint{Epoch: 5}},
+	})
+	if d.detected[keys[0]] || !d.detected[keys[1]] {
+		t.Errorf("Expected only the attesting key to be detected, detected %v", d.detected)
+	}
+}
+
+func TestDetector_HandleBlock(t *testing.T) {
+	keys := testKeys()
+	d := &detector{
+		startEpoch: 4,
+		keys:       map[uint64][48]byte{1: keys[0]},
+		detected:   make(map[[48]byte]bool),
+	}
+	startSlot := 4 * params.BeaconConfig().SlotsPerEpoch
+	d.handleBlock(&ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: startSlot, ProposerIndex: 1}})
+	if len(d.detected) != 0 {
+		t.Fatalf("Did not expect a block in the start epoch to be detected, detected %v", d.detected)
+	}
+	d.handleBlock(&ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: startSlot + params.BeaconConfig().SlotsPerEpoch, ProposerIndex: 1}})
+	if !d.detected[keys[0]] || !d.allDetected() {
+		t.Errorf("Expected the proposer to be detected, detected %v", d.detected)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	keys := testKeys()
+	filtered := Filter(keys, map[[48]byte]bool{keys[1]: true})
+	if len(filtered) != 2 || filtered[0] != keys[0] || filtered[1] != keys[2] {
+		t.Errorf("Unexpected filtered keys %v", filtered)
+	}
+}
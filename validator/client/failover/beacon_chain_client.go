@@ -0,0 +1,270 @@
+package failover
+
+import (
+	"context"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"google.golang.org/grpc"
+)
+
+// beaconChainClient implements ethpb.BeaconChainClient by routing each request to the
+// active beacon node of the pool.
+type beaconChainClient struct {
+	pool *Pool
+}
+
+var _ = ethpb.BeaconChainClient(&beaconChainClient{})
+
+// AttestationPool calls AttestationPool on the active beacon node.
+func (c *beaconChainClient) AttestationPool(ctx context.Context, in *ethpb.AttestationPoolRequest, opts ...grpc.CallOption) (*ethpb.AttestationPoolResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.AttestationPool(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.AttestationPoolResponse), nil
+}
+
+// GetBeaconConfig calls GetBeaconConfig on the active beacon node.
+func (c *beaconChainClient) GetBeaconConfig(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.BeaconConfig, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetBeaconConfig(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.BeaconConfig), nil
+}
+
+// GetChainHead calls GetChainHead on the active beacon node.
+func (c *beaconChainClient) GetChainHead(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.ChainHead, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetChainHead(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ChainHead), nil
+}
+
+// GetIndividualVotes calls GetIndividualVotes on the active beacon node.
+func (c *beaconChainClient) GetIndividualVotes(ctx context.Context, in *ethpb.IndividualVotesRequest, opts ...grpc.CallOption) (*ethpb.IndividualVotesRespond, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetIndividualVotes(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.IndividualVotesRespond), nil
+}
+
+// GetValidator calls GetValidator on the active beacon node.
+func (c *beaconChainClient) GetValidator(ctx context.Context, in *ethpb.GetValidatorRequest, opts ...grpc.CallOption) (*ethpb.Validator, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetValidator(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Validator), nil
+}
+
+// GetValidatorActiveSetChanges calls GetValidatorActiveSetChanges on the active beacon node.
+func (c *beaconChainClient) GetValidatorActiveSetChanges(ctx context.Context, in *ethpb.GetValidatorActiveSetChangesRequest, opts ...grpc.CallOption) (*ethpb.ActiveSetChanges, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetValidatorActiveSetChanges(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ActiveSetChanges), nil
+}
+
+// GetValidatorParticipation calls GetValidatorParticipation on the active beacon node.
+func (c *beaconChainClient) GetValidatorParticipation(ctx context.Context, in *ethpb.GetValidatorParticipationRequest, opts ...grpc.CallOption) (*ethpb.ValidatorParticipationResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetValidatorParticipation(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorParticipationResponse), nil
+}
+
+// GetValidatorPerformance calls GetValidatorPerformance on the active beacon node.
+func (c *beaconChainClient) GetValidatorPerformance(ctx context.Context, in *ethpb.ValidatorPerformanceRequest, opts ...grpc.CallOption) (*ethpb.ValidatorPerformanceResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetValidatorPerformance(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorPerformanceResponse), nil
+}
+
+// GetValidatorQueue calls GetValidatorQueue on the active beacon node.
+func (c *beaconChainClient) GetValidatorQueue(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.ValidatorQueue, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.GetValidatorQueue(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorQueue), nil
+}
+
+// ListAttestations calls ListAttestations on the active beacon node.
+func (c *beaconChainClient) ListAttestations(ctx context.Context, in *ethpb.ListAttestationsRequest, opts ...grpc.CallOption) (*ethpb.ListAttestationsResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListAttestations(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ListAttestationsResponse), nil
+}
+
+// ListBeaconCommittees calls ListBeaconCommittees on the active beacon node.
+func (c *beaconChainClient) ListBeaconCommittees(ctx context.Context, in *ethpb.ListCommitteesRequest, opts ...grpc.CallOption) (*ethpb.BeaconCommittees, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListBeaconCommittees(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.BeaconCommittees), nil
+}
+
+// ListBlocks calls ListBlocks on the active beacon node.
+func (c *beaconChainClient) ListBlocks(ctx context.Context, in *ethpb.ListBlocksRequest, opts ...grpc.CallOption) (*ethpb.ListBlocksResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListBlocks(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ListBlocksResponse), nil
+}
+
+// ListIndexedAttestations calls ListIndexedAttestations on the active beacon node.
+func (c *beaconChainClient) ListIndexedAttestations(ctx context.Context, in *ethpb.ListIndexedAttestationsRequest, opts ...grpc.CallOption) (*ethpb.ListIndexedAttestationsResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListIndexedAttestations(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ListIndexedAttestationsResponse), nil
+}
+
+// ListValidatorAssignments calls ListValidatorAssignments on the active beacon node.
+func (c *beaconChainClient) ListValidatorAssignments(ctx context.Context, in *ethpb.ListValidatorAssignmentsRequest, opts ...grpc.CallOption) (*ethpb.ValidatorAssignments, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListValidatorAssignments(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorAssignments), nil
+}
+
+// ListValidatorBalances calls ListValidatorBalances on the active beacon node.
+func (c *beaconChainClient) ListValidatorBalances(ctx context.Context, in *ethpb.ListValidatorBalancesRequest, opts ...grpc.CallOption) (*ethpb.ValidatorBalances, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListValidatorBalances(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorBalances), nil
+}
+
+// ListValidators calls ListValidators on the active beacon node.
+func (c *beaconChainClient) ListValidators(ctx context.Context, in *ethpb.ListValidatorsRequest, opts ...grpc.CallOption) (*ethpb.Validators, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.ListValidators(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Validators), nil
+}
+
+// StreamAttestations calls StreamAttestations on the active beacon node.
+func (c *beaconChainClient) StreamAttestations(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconChain_StreamAttestationsClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.StreamAttestations(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconChain_StreamAttestationsClient), nil
+}
+
+// StreamBlocks calls StreamBlocks on the active beacon node.
+func (c *beaconChainClient) StreamBlocks(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconChain_StreamBlocksClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.StreamBlocks(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconChain_StreamBlocksClient), nil
+}
+
+// StreamChainHead calls StreamChainHead on the active beacon node.
+func (c *beaconChainClient) StreamChainHead(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconChain_StreamChainHeadClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.StreamChainHead(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconChain_StreamChainHeadClient), nil
+}
+
+// StreamIndexedAttestations calls StreamIndexedAttestations on the active beacon node.
+func (c *beaconChainClient) StreamIndexedAttestations(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconChain_StreamIndexedAttestationsClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.StreamIndexedAttestations(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconChain_StreamIndexedAttestationsClient), nil
+}
+
+// StreamValidatorsInfo calls StreamValidatorsInfo on the active beacon node.
+func (c *beaconChainClient) StreamValidatorsInfo(ctx context.Context, opts ...grpc.CallOption) (ethpb.BeaconChain_StreamValidatorsInfoClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.StreamValidatorsInfo(ctx, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconChain_StreamValidatorsInfoClient), nil
+}
+
+// SubmitAttesterSlashing calls SubmitAttesterSlashing on the active beacon node.
+func (c *beaconChainClient) SubmitAttesterSlashing(ctx context.Context, in *ethpb.AttesterSlashing, opts ...grpc.CallOption) (*ethpb.SubmitSlashingResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.SubmitAttesterSlashing(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.SubmitSlashingResponse), nil
+}
+
+// SubmitProposerSlashing calls SubmitProposerSlashing on the active beacon node.
+func (c *beaconChainClient) SubmitProposerSlashing(ctx context.Context, in *ethpb.ProposerSlashing, opts ...grpc.CallOption) (*ethpb.SubmitSlashingResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.beaconClient.SubmitProposerSlashing(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.SubmitSlashingResponse), nil
+}
@@ -0,0 +1,27 @@
+package failover
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	nodeHealthyGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_healthy",
+			Help:      "Whether a beacon node is reachable and synced: 1 healthy, 0 unhealthy or syncing",
+		},
+		[]string{
+			// Beacon node endpoint.
+			"endpoint",
+		},
+	)
+	failoverCount = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_failovers_total",
+			Help:      "Number of times the validator client switched to another beacon node",
+		},
+	)
+)
@@ -0,0 +1,94 @@
+package failover
+
+import (
+	"context"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"google.golang.org/grpc"
+)
+
+// nodeClient implements ethpb.NodeClient by routing each request to the
+// active beacon node of the pool.
+type nodeClient struct {
+	pool *Pool
+}
+
+var _ = ethpb.NodeClient(&nodeClient{})
+
+// GetGenesis calls GetGenesis on the active beacon node.
+func (c *nodeClient) GetGenesis(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.Genesis, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.GetGenesis(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Genesis), nil
+}
+
+// GetHost calls GetHost on the active beacon node.
+func (c *nodeClient) GetHost(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.HostData, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.GetHost(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.HostData), nil
+}
+
+// GetPeer calls GetPeer on the active beacon node.
+func (c *nodeClient) GetPeer(ctx context.Context, in *ethpb.PeerRequest, opts ...grpc.CallOption) (*ethpb.Peer, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.GetPeer(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Peer), nil
+}
+
+// GetSyncStatus calls GetSyncStatus on the active beacon node.
+func (c *nodeClient) GetSyncStatus(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.SyncStatus, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.GetSyncStatus(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.SyncStatus), nil
+}
+
+// GetVersion calls GetVersion on the active beacon node.
+func (c *nodeClient) GetVersion(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.Version, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.GetVersion(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Version), nil
+}
+
+// ListImplementedServices calls ListImplementedServices on the active beacon node.
+func (c *nodeClient) ListImplementedServices(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.ImplementedServices, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.ListImplementedServices(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ImplementedServices), nil
+}
+
+// ListPeers calls ListPeers on the active beacon node.
+func (c *nodeClient) ListPeers(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (*ethpb.Peers, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.nodeClient.ListPeers(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.Peers), nil
+}
@@ -0,0 +1,348 @@
+// Package failover lets a validator client use several beacon nodes at once.
+// Requests are routed to the healthiest synced node, and the client fails over to
+// another node as soon as the current one becomes unavailable or falls behind.
+//
+// Failing over never causes anything to be signed twice: a request which failed
+// on one node is retried on another with exactly the same contents, so an already
+// signed block, attestation or exit is only ever resubmitted, never re-signed.
+package failover
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxHeadSlotLag is how many slots the active node may fall behind the best
+// node before the pool switches away from it. Tolerating a small lag avoids
+// flapping between nodes which are a block apart.
+const maxHeadSlotLag = 2
+
+// healthCheckTimeout bounds each health check request to a beacon node.
+var healthCheckTimeout = 2 * time.Second
+
+// Endpoints splits a comma separated list of beacon node endpoints.
+func Endpoints(s string) []string {
+	var endpoints []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints
+}
+
+// Node is a beacon node the pool can route requests to.
+type Node struct {
+	endpoint        string
+	conn            *grpc.ClientConn
+	validatorClient ethpb.BeaconNodeValidatorClient
+	beaconClient    ethpb.BeaconChainClient
+	nodeClient      ethpb.NodeClient
+	healthy         bool
+	syncing         bool
+	headSlot        uint64
+}
+
+// NewNode creates a node from an established connection to a beacon node.
+func NewNode(endpoint string, conn *grpc.ClientConn) *Node {
+	return &Node{
+		endpoint:        endpoint,
+		conn:            conn,
+		validatorClient: ethpb.NewBeaconNodeValidatorClient(conn),
+		beaconClient:    ethpb.NewBeaconChainClient(conn),
+		nodeClient:      ethpb.NewNodeClient(conn),
+		healthy:         true,
+	}
+}
+
+// Dial connects to each of the beacon node endpoints. Dialing does not block, so
+// unreachable nodes are only detected by the health checks of the pool.
+func Dial(ctx context.Context, endpoints []string, opts ...grpc.DialOption) ([]*Node, error) {
+	nodes := make([]*Node, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		conn, err := grpc.DialContext(ctx, endpoint, opts...)
+		if err != nil {
+			for _, n := range nodes {
+				if closeErr := n.conn.Close(); closeErr != nil {
+					log.WithError(closeErr).Error("Could not close connection to beacon node")
+				}
+			}
+			return nil, errors.Wrapf(err, "could not dial endpoint %s", endpoint)
+		}
+		nodes = append(nodes, NewNode(endpoint, conn))
+	}
+	return nodes, nil
+}
+
+// Pool routes validator client requests across a set of beacon nodes.
+type Pool struct {
+	lock                 sync.RWMutex
+	nodes                []*Node
+	active               *Node
+	broadcastSubmissions bool
+}
+
+// NewPool creates a pool over the given nodes, which are preferred in the order
+// given when they are equally healthy. If broadcastSubmissions is set, signed
+// attestations, aggregates and exits are submitted to every healthy node.
+func NewPool(nodes []*Node, broadcastSubmissions bool) *Pool {
+	p := &Pool{
+		nodes:                nodes,
+		broadcastSubmissions: broadcastSubmissions,
+	}
+	if len(nodes) > 0 {
+		p.active = nodes[0]
+	}
+	return p
+}
+
+// Start health checking the beacon nodes once per slot until the context is canceled.
+func (p *Pool) Start(ctx context.Context) {
+	p.checkHealth(ctx)
+	go func() {
+		ticker := time.NewTicker(time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.checkHealth(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Close the connections to all beacon nodes.
+func (p *Pool) Close() error {
+	var firstErr error
+	for _, n := range p.nodes {
+		if n.conn == nil {
+			continue
+		}
+		if err := n.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ValidatorClient returns a validator client routed through the pool.
+func (p *Pool) ValidatorClient() ethpb.BeaconNodeValidatorClient {
+	return &validatorClient{pool: p}
+}
+
+// BeaconChainClient returns a beacon chain client routed through the pool.
+func (p *Pool) BeaconChainClient() ethpb.BeaconChainClient {
+	return &beaconChainClient{pool: p}
+}
+
+// NodeClient returns a node client routed through the pool.
+func (p *Pool) NodeClient() ethpb.NodeClient {
+	return &nodeClient{pool: p}
+}
+
+// Active returns the endpoint of the node requests are currently routed to.
+func (p *Pool) Active() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	if p.active == nil {
+		return ""
+	}
+	return p.active.endpoint
+}
+
+// checkHealth queries the sync status and chain head of every node and then
+// selects the node to route requests to.
+func (p *Pool) checkHealth(ctx context.Context) {
+	type result struct {
+		healthy  bool
+		syncing  bool
+		headSlot uint64
+	}
+	results := make([]result, len(p.nodes))
+	var wg sync.WaitGroup
+	for i, n := range p.nodes {
+		wg.Add(1)
+		go func(i int, n *Node) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			syncStatus, err := n.nodeClient.GetSyncStatus(ctx, &ptypes.Empty{})
+			if err != nil {
+				log.WithError(err).WithField("endpoint", n.endpoint).Debug("Beacon node health check failed")
+				return
+			}
+			head, err := n.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+			if err != nil {
+				log.WithError(err).WithField("endpoint", n.endpoint).Debug("Beacon node health check failed")
+				return
+			}
+			results[i] = result{healthy: true, syncing: syncStatus.Syncing, headSlot: head.HeadSlot}
+		}(i, n)
+	}
+	wg.Wait()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for i, n := range p.nodes {
+		n.healthy = results[i].healthy
+		n.syncing = results[i].syncing
+		n.headSlot = results[i].headSlot
+		if n.healthy && !n.syncing {
+			nodeHealthyGauge.WithLabelValues(n.endpoint).Set(1)
+		} else {
+			nodeHealthyGauge.WithLabelValues(n.endpoint).Set(0)
+		}
+	}
+	p.selectNode()
+}
+
+// selectNode picks the node to route requests to. Synced nodes are preferred over
+// syncing ones, and the active node is kept unless it is unhealthy or lags too far
+// behind the best node. The caller must hold the lock.
+func (p *Pool) selectNode() {
+	var best *Node
+	for _, n := range p.nodes {
+		if !n.healthy {
+			continue
+		}
+		if best == nil || (best.syncing && !n.syncing) || (best.syncing == n.syncing && n.headSlot > best.headSlot) {
+			best = n
+		}
+	}
+	if best == nil || best == p.active {
+		return
+	}
+	if a := p.active; a != nil && a.healthy && a.syncing == best.syncing && a.headSlot+maxHeadSlotLag >= best.headSlot {
+		return
+	}
+	p.switchTo(best)
+}
+
+// switchTo makes the given node the active one. The caller must hold the lock.
+func (p *Pool) switchTo(n *Node) {
+	fields := logrus.Fields{"endpoint": n.endpoint, "headSlot": n.headSlot}
+	if p.active != nil {
+		fields["previousEndpoint"] = p.active.endpoint
+	}
+	log.WithFields(fields).Warn("Failing over to another beacon node")
+	failoverCount.Inc()
+	p.active = n
+}
+
+// current returns the node requests are routed to.
+func (p *Pool) current() *Node {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.active
+}
+
+// markUnavailable records that a request to the node failed because it could not
+// be reached, failing over to the next healthy node if it was the active one.
+func (p *Pool) markUnavailable(n *Node, err error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	log.WithError(err).WithField("endpoint", n.endpoint).Warn("Beacon node is unavailable")
+	n.healthy = false
+	nodeHealthyGauge.WithLabelValues(n.endpoint).Set(0)
+	if p.active != n {
+		return
+	}
+	for _, candidate := range p.nodes {
+		if candidate != n && candidate.healthy {
+			p.switchTo(candidate)
+			return
+		}
+	}
+}
+
+// healthyNodes returns the active node followed by all other healthy nodes.
+func (p *Pool) healthyNodes() []*Node {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	nodes := make([]*Node, 0, len(p.nodes))
+	if p.active != nil {
+		nodes = append(nodes, p.active)
+	}
+	for _, n := range p.nodes {
+		if n != p.active && n.healthy {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// isUnavailable returns true for errors caused by the beacon node not being
+// reachable, which are the only errors worth retrying on another node.
+func isUnavailable(err error) bool {
+	return status.Code(err) == codes.Unavailable
+}
+
+// call runs the request against the active node, retrying it once on the next
+// healthy node if the active node could not be reached.
+func (p *Pool) call(f func(n *Node) (interface{}, error)) (interface{}, error) {
+	n := p.current()
+	if n == nil {
+		return nil, status.Error(codes.Unavailable, "no beacon node configured")
+	}
+	res, err := f(n)
+	if err == nil || !isUnavailable(err) {
+		return res, err
+	}
+	p.markUnavailable(n, err)
+	next := p.current()
+	if next == n {
+		return res, err
+	}
+	return f(next)
+}
+
+// submit sends a signed object to the beacon nodes. Unless submissions are
+// broadcast this behaves like call, otherwise the object is sent to every
+// healthy node and the submission succeeds if any node accepted it.
+func (p *Pool) submit(f func(n *Node) (interface{}, error)) (interface{}, error) {
+	if !p.broadcastSubmissions {
+		return p.call(f)
+	}
+	nodes := p.healthyNodes()
+	if len(nodes) == 0 {
+		return p.call(f)
+	}
+	type result struct {
+		res interface{}
+		err error
+	}
+	results := make([]result, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n *Node) {
+			defer wg.Done()
+			res, err := f(n)
+			if err != nil && isUnavailable(err) {
+				p.markUnavailable(n, err)
+			}
+			results[i] = result{res: res, err: err}
+		}(i, n)
+	}
+	wg.Wait()
+	// Prefer the response of the active node, which comes first.
+	for _, r := range results {
+		if r.err == nil {
+			return r.res, nil
+		}
+	}
+	return nil, results[0].err
+}
@@ -0,0 +1,176 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeNodeClient struct {
+	ethpb.NodeClient
+	syncing bool
+	err     error
+}
+
+func (c *fakeNodeClient) GetSyncStatus(_ context.Context, _ *ptypes.Empty, _ ...grpc.CallOption) (*ethpb.SyncStatus, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ethpb.SyncStatus{Syncing: c.syncing}, nil
+}
+
+type fakeBeaconClient struct {
+	ethpb.BeaconChainClient
+	headSlot uint64
+}
+
+func (c *fakeBeaconClient) GetChainHead(_ context.Context, _ *ptypes.Empty, _ ...grpc.CallOption) (*ethpb.ChainHead, error) {
+	return &ethpb.ChainHead{HeadSlot: c.headSlot}, nil
+}
+
+type fakeValidatorClient struct {
+	ethpb.BeaconNodeValidatorClient
+	lock         sync.Mutex
+	err          error
+	dutiesCalls  int
+	attestations []*ethpb.Attestation
+}
+
+func (c *fakeValidatorClient) GetDuties(_ context.Context, _ *ethpb.DutiesRequest, _ ...grpc.CallOption) (*ethpb.DutiesResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.dutiesCalls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ethpb.DutiesResponse{}, nil
+}
+
+func (c *fakeValidatorClient) ProposeAttestation(_ context.Context, att *ethpb.Attestation, _ ...grpc.CallOption) (*ethpb.AttestResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.attestations = append(c.attestations, att)
+	return &ethpb.AttestResponse{}, nil
+}
+
+func newFakeNode(endpoint string, headSlot uint64, syncing bool) *Node {
+	return &Node{
+		endpoint:        endpoint,
+		validatorClient: &fakeValidatorClient{},
+		beaconClient:    &fakeBeaconClient{headSlot: headSlot},
+		nodeClient:      &fakeNodeClient{syncing: syncing},
+		healthy:         true,
+	}
+}
+
+func TestPool_CheckHealth_SelectsBestSyncedNode(t *testing.T) {
+	a := newFakeNode("a", 100, true)
+	b := newFakeNode("b", 90, false)
+	c := newFakeNode("c", 95, false)
+	p := NewPool([]*Node{a, b, c}, false)
+
+	p.checkHealth(context.Background())
+	if p.Active() != "c" {
+		t.Errorf("Wanted synced node with highest head c, received %s", p.Active())
+	}
+
+	// Staying within the allowed lag keeps the active node.
+	b.beaconClient = &fakeBeaconClient{headSlot: 95 + maxHeadSlotLag}
+	p.checkHealth(context.Background())
+	if p.Active() != "c" {
+		t.Errorf("Wanted active node c to be kept, received %s", p.Active())
+	}
+
+	// Falling behind further fails over.
+	b.beaconClient = &fakeBeaconClient{headSlot: 96 + maxHeadSlotLag}
+	p.checkHealth(context.Background())
+	if p.Active() != "b" {
+		t.Errorf("Wanted failover to b, received %s", p.Active())
+	}
+
+	// An unreachable node is never selected.
+	b.nodeClient = &fakeNodeClient{err: status.Error(codes.Unavailable, "down")}
+	p.checkHealth(context.Background())
+	if p.Active() != "c" {
+		t.Errorf("Wanted failover back to c, received %s", p.Active())
+	}
+}
+
+func TestPool_Call_RetriesUnavailableNode(t *testing.T) {
+	a := newFakeNode("a", 10, false)
+	b := newFakeNode("b", 10, false)
+	a.validatorClient.(*fakeValidatorClient).err = status.Error(codes.Unavailable, "down")
+	p := NewPool([]*Node{a, b}, false)
+
+	if _, err := p.ValidatorClient().GetDuties(context.Background(), &ethpb.DutiesRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if p.Active() != "b" {
+		t.Errorf("Wanted failover to b, received %s", p.Active())
+	}
+	if calls := b.validatorClient.(*fakeValidatorClient).dutiesCalls; calls != 1 {
+		t.Errorf("Wanted 1 retried call on b, received %d", calls)
+	}
+}
+
+func TestPool_Call_DoesNotRetryOtherErrors(t *testing.T) {
+	a := newFakeNode("a", 10, false)
+	b := newFakeNode("b", 10, false)
+	a.validatorClient.(*fakeValidatorClient).err = errors.New("bad request")
+	p := NewPool([]*Node{a, b}, false)
+
+	if _, err := p.ValidatorClient().GetDuties(context.Background(), &ethpb.DutiesRequest{}); err == nil {
+		t.Fatal("Expected error")
+	}
+	if p.Active() != "a" {
+		t.Errorf("Wanted active node a to be kept, received %s", p.Active())
+	}
+	if calls := b.validatorClient.(*fakeValidatorClient).dutiesCalls; calls != 0 {
+		t.Errorf("Wanted no calls on b, received %d", calls)
+	}
+}
+
+func TestPool_Submit_Broadcasts(t *testing.T) {
+	a := newFakeNode("a", 10, false)
+	b := newFakeNode("b", 10, false)
+	c := newFakeNode("c", 10, false)
+	a.validatorClient.(*fakeValidatorClient).err = status.Error(codes.Unavailable, "down")
+	p := NewPool([]*Node{a, b, c}, true)
+
+	att := &ethpb.Attestation{Signature: []byte{'s'}}
+	if _, err := p.ValidatorClient().ProposeAttestation(context.Background(), att); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []*Node{b, c} {
+		got := n.validatorClient.(*fakeValidatorClient).attestations
+		if len(got) != 1 || got[0] != att {
+			t.Errorf("Wanted node %s to receive the same attestation once, received %v", n.endpoint, got)
+		}
+	}
+	if p.Active() == "a" {
+		t.Error("Wanted failover away from unavailable node a")
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	got := Endpoints(" localhost:4000, ,10.0.0.1:4000,")
+	want := []string{"localhost:4000", "10.0.0.1:4000"}
+	if len(got) != len(want) {
+		t.Fatalf("Wanted %v, received %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Wanted %v, received %v", want, got)
+		}
+	}
+}
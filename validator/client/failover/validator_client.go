@@ -0,0 +1,207 @@
+package failover
+
+import (
+	"context"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"google.golang.org/grpc"
+)
+
+// validatorClient implements ethpb.BeaconNodeValidatorClient by routing each request to the
+// active beacon node of the pool.
+type validatorClient struct {
+	pool *Pool
+}
+
+var _ = ethpb.BeaconNodeValidatorClient(&validatorClient{})
+
+// DomainData calls DomainData on the active beacon node.
+func (c *validatorClient) DomainData(ctx context.Context, in *ethpb.DomainRequest, opts ...grpc.CallOption) (*ethpb.DomainResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.DomainData(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.DomainResponse), nil
+}
+
+// GetAttestationData calls GetAttestationData on the active beacon node.
+func (c *validatorClient) GetAttestationData(ctx context.Context, in *ethpb.AttestationDataRequest, opts ...grpc.CallOption) (*ethpb.AttestationData, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.GetAttestationData(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.AttestationData), nil
+}
+
+// GetBlock calls GetBlock on the active beacon node.
+func (c *validatorClient) GetBlock(ctx context.Context, in *ethpb.BlockRequest, opts ...grpc.CallOption) (*ethpb.BeaconBlock, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.GetBlock(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.BeaconBlock), nil
+}
+
+// GetDuties calls GetDuties on the active beacon node.
+func (c *validatorClient) GetDuties(ctx context.Context, in *ethpb.DutiesRequest, opts ...grpc.CallOption) (*ethpb.DutiesResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.GetDuties(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.DutiesResponse), nil
+}
+
+// MultipleValidatorStatus calls MultipleValidatorStatus on the active beacon node.
+func (c *validatorClient) MultipleValidatorStatus(ctx context.Context, in *ethpb.MultipleValidatorStatusRequest, opts ...grpc.CallOption) (*ethpb.MultipleValidatorStatusResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.MultipleValidatorStatus(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.MultipleValidatorStatusResponse), nil
+}
+
+// ProposeAttestation submits to the active beacon node, or to all healthy beacon nodes
+// when submissions are broadcast.
+func (c *validatorClient) ProposeAttestation(ctx context.Context, in *ethpb.Attestation, opts ...grpc.CallOption) (*ethpb.AttestResponse, error) {
+	res, err := c.pool.submit(func(n *Node) (interface{}, error) {
+		return n.validatorClient.ProposeAttestation(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.AttestResponse), nil
+}
+
+// ProposeBlock calls ProposeBlock on the active beacon node.
+func (c *validatorClient) ProposeBlock(ctx context.Context, in *ethpb.SignedBeaconBlock, opts ...grpc.CallOption) (*ethpb.ProposeResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.ProposeBlock(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ProposeResponse), nil
+}
+
+// ProposeExit submits to the active beacon node, or to all healthy beacon nodes
+// when submissions are broadcast.
+func (c *validatorClient) ProposeExit(ctx context.Context, in *ethpb.SignedVoluntaryExit, opts ...grpc.CallOption) (*ptypes.Empty, error) {
+	res, err := c.pool.submit(func(n *Node) (interface{}, error) {
+		return n.validatorClient.ProposeExit(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ptypes.Empty), nil
+}
+
+// StreamDuties calls StreamDuties on the active beacon node.
+func (c *validatorClient) StreamDuties(ctx context.Context, in *ethpb.DutiesRequest, opts ...grpc.CallOption) (ethpb.BeaconNodeValidator_StreamDutiesClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.StreamDuties(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconNodeValidator_StreamDutiesClient), nil
+}
+
+// SubmitAggregateSelectionProof calls SubmitAggregateSelectionProof on the active beacon node.
+func (c *validatorClient) SubmitAggregateSelectionProof(ctx context.Context, in *ethpb.AggregateSelectionRequest, opts ...grpc.CallOption) (*ethpb.AggregateSelectionResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.SubmitAggregateSelectionProof(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.AggregateSelectionResponse), nil
+}
+
+// SubmitSignedAggregateSelectionProof submits to the active beacon node, or to all healthy beacon nodes
+// when submissions are broadcast.
+func (c *validatorClient) SubmitSignedAggregateSelectionProof(ctx context.Context, in *ethpb.SignedAggregateSubmitRequest, opts ...grpc.CallOption) (*ethpb.SignedAggregateSubmitResponse, error) {
+	res, err := c.pool.submit(func(n *Node) (interface{}, error) {
+		return n.validatorClient.SubmitSignedAggregateSelectionProof(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.SignedAggregateSubmitResponse), nil
+}
+
+// SubscribeCommitteeSubnets calls SubscribeCommitteeSubnets on the active beacon node.
+func (c *validatorClient) SubscribeCommitteeSubnets(ctx context.Context, in *ethpb.CommitteeSubnetsSubscribeRequest, opts ...grpc.CallOption) (*ptypes.Empty, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.SubscribeCommitteeSubnets(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ptypes.Empty), nil
+}
+
+// ValidatorIndex calls ValidatorIndex on the active beacon node.
+func (c *validatorClient) ValidatorIndex(ctx context.Context, in *ethpb.ValidatorIndexRequest, opts ...grpc.CallOption) (*ethpb.ValidatorIndexResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.ValidatorIndex(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorIndexResponse), nil
+}
+
+// ValidatorStatus calls ValidatorStatus on the active beacon node.
+func (c *validatorClient) ValidatorStatus(ctx context.Context, in *ethpb.ValidatorStatusRequest, opts ...grpc.CallOption) (*ethpb.ValidatorStatusResponse, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.ValidatorStatus(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ethpb.ValidatorStatusResponse), nil
+}
+
+// WaitForActivation calls WaitForActivation on the active beacon node.
+func (c *validatorClient) WaitForActivation(ctx context.Context, in *ethpb.ValidatorActivationRequest, opts ...grpc.CallOption) (ethpb.BeaconNodeValidator_WaitForActivationClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.WaitForActivation(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconNodeValidator_WaitForActivationClient), nil
+}
+
+// WaitForChainStart calls WaitForChainStart on the active beacon node.
+func (c *validatorClient) WaitForChainStart(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconNodeValidator_WaitForChainStartClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.WaitForChainStart(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconNodeValidator_WaitForChainStartClient), nil
+}
+
+// WaitForSynced calls WaitForSynced on the active beacon node.
+func (c *validatorClient) WaitForSynced(ctx context.Context, in *ptypes.Empty, opts ...grpc.CallOption) (ethpb.BeaconNodeValidator_WaitForSyncedClient, error) {
+	res, err := c.pool.call(func(n *Node) (interface{}, error) {
+		return n.validatorClient.WaitForSynced(ctx, in, opts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(ethpb.BeaconNodeValidator_WaitForSyncedClient), nil
+}
@@ -118,4 +118,46 @@ var (
 			"pubkey",
 		},
 	)
+	// ValidatorDoppelgangerDetectedVec used to track validating keys found in use by another validator client.
+	ValidatorDoppelgangerDetectedVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "doppelganger_detected",
+			Help:      "Set to 1 when a validating key was seen signing from another validator client, which disables its duties",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+		},
+	)
+	// ValidatorShadowMessagesVec used to count the messages a validator in shadow mode would have signed.
+	ValidatorShadowMessagesVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "shadow_messages",
+			Help:      "Count the blocks, attestations and aggregates which would have been signed in shadow mode",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+			// block, attestation or aggregate
+			"type",
+		},
+	)
+	// ValidatorShadowResultsVec used to count how the messages of a validator in shadow mode compare with the chain.
+	ValidatorShadowResultsVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "shadow_results",
+			Help:      "Count the messages of shadow mode which match, mismatch or are missing from what was included on chain",
+		},
+		[]string{
+			// validator pubkey
+			"pubkey",
+			// block, attestation or aggregate
+			"type",
+			// match, mismatch or missing
+			"result",
+		},
+	)
 )
@@ -10,6 +10,7 @@ var _ = Validator(&fakeValidator{})
 type fakeValidator struct {
 	DoneCalled                       bool
 	WaitForActivationCalled          bool
+	CheckDoppelgangersCalled         bool
 	WaitForChainStartCalled          bool
 	WaitForSyncCalled                bool
 	WaitForSyncedCalled              bool
@@ -47,6 +48,15 @@ func (fv *fakeValidator) WaitForActivation(_ context.Context) error {
 	return nil
 }
 
+func (fv *fakeValidator) CheckDoppelgangers(_ context.Context) error {
+	fv.CheckDoppelgangersCalled = true
+	return nil
+}
+
+func (fv *fakeValidator) CheckNewDoppelgangers(_ context.Context) {}
+
+func (fv *fakeValidator) CheckShadowMessages(_ context.Context, _ uint64) {}
+
 func (fv *fakeValidator) WaitForSync(_ context.Context) error {
 	fv.WaitForSyncCalled = true
 	return nil
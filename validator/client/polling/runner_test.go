@@ -54,6 +54,14 @@ func TestCancelledContext_WaitsForActivation(t *testing.T) {
 	}
 }
 
+func TestCancelledContext_ChecksDoppelgangers(t *testing.T) {
+	v := &fakeValidator{}
+	run(cancelledContext(), v)
+	if !v.CheckDoppelgangersCalled {
+		t.Error("Expected CheckDoppelgangers() to be called")
+	}
+}
+
 func TestUpdateDuties_NextSlot(t *testing.T) {
 	v := &fakeValidator{}
 	ctx, cancel := context.WithCancel(context.Background())
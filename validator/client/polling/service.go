@@ -18,8 +18,12 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
+	"github.com/prysmaticlabs/prysm/validator/client/shadow"
 	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/graffiti"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/management"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/plugin/ocgrpc"
@@ -37,7 +41,9 @@ type ValidatorService struct {
 	cancel               context.CancelFunc
 	validator            Validator
 	graffiti             []byte
+	graffitiProvider     *graffiti.Provider
 	conn                 *grpc.ClientConn
+	pool                 *failover.Pool
 	endpoint             string
 	withCert             string
 	dataDir              string
@@ -48,14 +54,23 @@ type ValidatorService struct {
 	grpcRetries          uint
 	grpcHeaders          []string
 	protector            slashingprotection.Protector
+	broadcastSubmissions bool
+	doppelgangerEpochs   uint64
+	shadowMode           bool
+	managementAddress    string
+	managementTokenFile  string
+	management           *management.Server
+	managedKeyManager    *keymanager.Managed
 }
 
-// Config for the validator service.
+// Config for the validator service. Endpoint may be a comma separated list of
+// beacon nodes, in which case requests fail over between them.
 type Config struct {
 	Endpoint                   string
 	DataDir                    string
 	CertFlag                   string
 	GraffitiFlag               string
+	GraffitiFile               string
 	KeyManager                 keymanager.KeyManager
 	LogValidatorBalances       bool
 	EmitAccountMetrics         bool
@@ -63,11 +78,25 @@ type Config struct {
 	GrpcRetriesFlag            uint
 	GrpcHeadersFlag            string
 	Protector                  slashingprotection.Protector
+	BroadcastSubmissions       bool
+	DoppelgangerEpochs         uint64
+	ShadowMode                 bool
+	ManagementAddress          string
+	ManagementTokenFile        string
+	ManagedKeyManager          *keymanager.Managed
 }
 
 // NewValidatorService creates a new validator service for the service
 // registry.
 func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, error) {
+	var graffitiProvider *graffiti.Provider
+	if cfg.GraffitiFile != "" {
+		var err error
+		graffitiProvider, err = graffiti.NewProvider(cfg.GraffitiFile)
+		if err != nil {
+			return nil, err
+		}
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	return &ValidatorService{
 		ctx:                  ctx,
@@ -76,6 +105,7 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 		withCert:             cfg.CertFlag,
 		dataDir:              cfg.DataDir,
 		graffiti:             []byte(cfg.GraffitiFlag),
+		graffitiProvider:     graffitiProvider,
 		keyManager:           cfg.KeyManager,
 		logValidatorBalances: cfg.LogValidatorBalances,
 		emitAccountMetrics:   cfg.EmitAccountMetrics,
@@ -83,6 +113,12 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 		grpcRetries:          cfg.GrpcRetriesFlag,
 		grpcHeaders:          strings.Split(cfg.GrpcHeadersFlag, ","),
 		protector:            cfg.Protector,
+		broadcastSubmissions: cfg.BroadcastSubmissions,
+		doppelgangerEpochs:   cfg.DoppelgangerEpochs,
+		shadowMode:           cfg.ShadowMode,
+		managementAddress:    cfg.ManagementAddress,
+		managementTokenFile:  cfg.ManagementTokenFile,
+		managedKeyManager:    cfg.ManagedKeyManager,
 	}, nil
 }
 
@@ -104,10 +140,31 @@ func (v *ValidatorService) Start() {
 	if dialOpts == nil {
 		return
 	}
-	conn, err := grpc.DialContext(v.ctx, v.endpoint, dialOpts...)
-	if err != nil {
-		log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
-		return
+	var validatorClient ethpb.BeaconNodeValidatorClient
+	var beaconClient ethpb.BeaconChainClient
+	var nodeClient ethpb.NodeClient
+	if endpoints := failover.Endpoints(v.endpoint); len(endpoints) > 1 {
+		nodes, err := failover.Dial(v.ctx, endpoints, dialOpts...)
+		if err != nil {
+			log.Errorf("Could not dial beacon nodes: %v", err)
+			return
+		}
+		v.pool = failover.NewPool(nodes, v.broadcastSubmissions)
+		v.pool.Start(v.ctx)
+		validatorClient = v.pool.ValidatorClient()
+		beaconClient = v.pool.BeaconChainClient()
+		nodeClient = v.pool.NodeClient()
+		log.WithField("endpoints", endpoints).Info("Using beacon node failover")
+	} else {
+		conn, err := grpc.DialContext(v.ctx, v.endpoint, dialOpts...)
+		if err != nil {
+			log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
+			return
+		}
+		v.conn = conn
+		validatorClient = ethpb.NewBeaconNodeValidatorClient(conn)
+		beaconClient = ethpb.NewBeaconChainClient(conn)
+		nodeClient = ethpb.NewNodeClient(conn)
 	}
 	if v.withCert != "" {
 		log.Info("Established secure gRPC connection")
@@ -125,7 +182,22 @@ func (v *ValidatorService) Start() {
 		return
 	}
 
-	v.conn = conn
+	// The management API shares the database, which cannot be opened twice.
+	if v.managedKeyManager != nil && v.managementAddress != "" {
+		v.management = management.NewServer(&management.Config{
+			Address:         v.managementAddress,
+			TokenFile:       v.managementTokenFile,
+			KeyManager:      v.managedKeyManager,
+			DB:              valDB,
+			ValidatorClient: validatorClient,
+			NodeClient:      nodeClient,
+		})
+		if err := v.management.Start(); err != nil {
+			log.Errorf("Could not start management API: %v", err)
+			return
+		}
+	}
+
 	cache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1920, // number of keys to track.
 		MaxCost:     192,  // maximum cost of cache, 1 item = 1 cost.
@@ -141,13 +213,22 @@ func (v *ValidatorService) Start() {
 		return
 	}
 
+	var shadowTracker *shadow.Tracker
+	if v.shadowMode {
+		shadowTracker = shadow.NewTracker(beaconClient, v.emitAccountMetrics)
+		log.Warn("Running in shadow mode, blocks, attestations and aggregates are neither signed nor broadcast. " +
+			"Slashing protection history is checked but not updated, so import the history of the shadowed " +
+			"validator client before cutting over")
+	}
+
 	v.validator = &validator{
 		db:                             valDB,
-		validatorClient:                ethpb.NewBeaconNodeValidatorClient(v.conn),
-		beaconClient:                   ethpb.NewBeaconChainClient(v.conn),
-		node:                           ethpb.NewNodeClient(v.conn),
+		validatorClient:                validatorClient,
+		beaconClient:                   beaconClient,
+		node:                           nodeClient,
 		keyManager:                     v.keyManager,
 		graffiti:                       v.graffiti,
+		graffitiProvider:               v.graffitiProvider,
 		logValidatorBalances:           v.logValidatorBalances,
 		emitAccountMetrics:             v.emitAccountMetrics,
 		prevBalance:                    make(map[[48]byte]uint64),
@@ -155,6 +236,11 @@ func (v *ValidatorService) Start() {
 		domainDataCache:                cache,
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
 		protector:                      v.protector,
+		doppelgangerEpochs:             v.doppelgangerEpochs,
+		shadow:                         shadowTracker,
+	}
+	if v.graffitiProvider != nil {
+		go v.graffitiProvider.ReloadOnSignal(v.ctx)
 	}
 	go run(v.ctx, v.validator)
 }
@@ -163,6 +249,14 @@ func (v *ValidatorService) Start() {
 func (v *ValidatorService) Stop() error {
 	v.cancel()
 	log.Info("Stopping service")
+	if v.management != nil {
+		if err := v.management.Stop(); err != nil {
+			log.WithError(err).Error("Could not stop management API")
+		}
+	}
+	if v.pool != nil {
+		return v.pool.Close()
+	}
 	if v.conn != nil {
 		return v.conn.Close()
 	}
@@ -173,7 +267,7 @@ func (v *ValidatorService) Stop() error {
 //
 // WIP - not done.
 func (v *ValidatorService) Status() error {
-	if v.conn == nil {
+	if v.conn == nil && v.pool == nil {
 		return errors.New("no connection to beacon RPC")
 	}
 	return nil
@@ -26,8 +26,11 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/hashutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/client/doppelganger"
 	"github.com/prysmaticlabs/prysm/validator/client/metrics"
+	"github.com/prysmaticlabs/prysm/validator/client/shadow"
 	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/graffiti"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
@@ -51,6 +54,7 @@ type validator struct {
 	validatorClient                    ethpb.BeaconNodeValidatorClient
 	beaconClient                       ethpb.BeaconChainClient
 	graffiti                           []byte
+	graffitiProvider                   *graffiti.Provider
 	node                               ethpb.NodeClient
 	keyManager                         keymanager.KeyManager
 	prevBalance                        map[[48]byte]uint64
@@ -65,6 +69,11 @@ type validator struct {
 	attesterHistoryByPubKey            map[[48]byte]*slashpb.AttestationHistory
 	attesterHistoryByPubKeyLock        sync.RWMutex
 	protector                          slashingprotection.Protector
+	doppelgangerEpochs                 uint64
+	doppelgangers                      map[[48]byte]bool
+	doppelgangerChecked                map[[48]byte]bool
+	doppelgangerLock                   sync.Mutex
+	shadow                             *shadow.Tracker
 }
 
 // Done cleans up the validator.
@@ -101,6 +110,9 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 		v.genesisTime = chainStartRes.GenesisTime
 		break
 	}
+	if err := v.saveGenesisValidatorsRoot(ctx); err != nil {
+		return err
+	}
 	// Once the ChainStart log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
@@ -166,6 +178,9 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 		v.genesisTime = syncedRes.GenesisTime
 		break
 	}
+	if err := v.saveGenesisValidatorsRoot(ctx); err != nil {
+		return err
+	}
 	// Once the Synced log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
@@ -173,6 +188,19 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 	return nil
 }
 
+// saveGenesisValidatorsRoot records the genesis validators root of the chain in the slashing
+// protection database, which refuses to be used for another chain once it holds a history.
+func (v *validator) saveGenesisValidatorsRoot(ctx context.Context) error {
+	genesis, err := v.node.GetGenesis(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis information")
+	}
+	if err := v.db.SaveGenesisValidatorsRoot(ctx, genesis.GenesisValidatorsRoot); err != nil {
+		return errors.Wrap(err, "could not save genesis validators root")
+	}
+	return nil
+}
+
 // WaitForActivation checks whether the validator pubkey is in the active
 // validator set. If not, this operation will block until an activation message is
 // received.
@@ -223,6 +251,133 @@ func (v *validator) WaitForActivation(ctx context.Context) error {
 	return nil
 }
 
+// CheckDoppelgangers watches the chain for the configured number of epochs before
+// any duties are performed. Keys seen signing from another validator client are
+// excluded from duties, and an error is returned if that leaves no keys at all.
+func (v *validator) CheckDoppelgangers(ctx context.Context) error {
+	if v.doppelgangerEpochs == 0 {
+		return nil
+	}
+	if v.shadow != nil {
+		log.Info("Not checking for doppelgangers in shadow mode, where the keys are in use by the shadowed validator client")
+		return nil
+	}
+	ctx, span := trace.StartSpan(ctx, "validator.CheckDoppelgangers")
+	defer span.End()
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validating keys")
+	}
+	detected, err := doppelganger.Detect(ctx, &doppelganger.Config{
+		BeaconClient: v.beaconClient,
+		GenesisTime:  v.genesisTime,
+		Epochs:       v.doppelgangerEpochs,
+		PubKeys:      validatingKeys,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not check for doppelgangers")
+	}
+	if len(detected) > 0 && len(detected) == len(validatingKeys) {
+		return errors.New("all validating keys are in use by another validator client")
+	}
+	v.doppelgangerLock.Lock()
+	defer v.doppelgangerLock.Unlock()
+	v.doppelgangers = detected
+	v.doppelgangerChecked = make(map[[48]byte]bool, len(validatingKeys))
+	for _, key := range validatingKeys {
+		v.doppelgangerChecked[key] = true
+	}
+	return nil
+}
+
+// CheckNewDoppelgangers checks keys added after startup, such as keys imported through the
+// management API, for doppelgangers. These keys are excluded from duties until their check
+// completes, and keys seen signing from another validator client stay excluded.
+func (v *validator) CheckNewDoppelgangers(ctx context.Context) {
+	v.doppelgangerLock.Lock()
+	if v.doppelgangerChecked == nil {
+		// Doppelganger detection is disabled.
+		v.doppelgangerLock.Unlock()
+		return
+	}
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		v.doppelgangerLock.Unlock()
+		log.WithError(err).Error("Could not fetch validating keys")
+		return
+	}
+	var newKeys [][48]byte
+	for _, key := range validatingKeys {
+		if _, ok := v.doppelgangerChecked[key]; !ok {
+			// Mark the key as being checked, so it is not checked twice.
+			v.doppelgangerChecked[key] = false
+			newKeys = append(newKeys, key)
+		}
+	}
+	v.doppelgangerLock.Unlock()
+	if len(newKeys) == 0 {
+		return
+	}
+
+	ctx, span := trace.StartSpan(ctx, "validator.CheckNewDoppelgangers")
+	defer span.End()
+	log.WithField("numKeys", len(newKeys)).Info("Checking new validating keys for doppelgangers before performing their duties")
+	detected, err := doppelganger.Detect(ctx, &doppelganger.Config{
+		BeaconClient: v.beaconClient,
+		GenesisTime:  v.genesisTime,
+		Epochs:       v.doppelgangerEpochs,
+		PubKeys:      newKeys,
+	})
+
+	v.doppelgangerLock.Lock()
+	defer v.doppelgangerLock.Unlock()
+	if err != nil {
+		log.WithError(err).Error("Could not check new validating keys for doppelgangers")
+		// Check the keys again later.
+		for _, key := range newKeys {
+			delete(v.doppelgangerChecked, key)
+		}
+		return
+	}
+	if v.doppelgangers == nil {
+		v.doppelgangers = make(map[[48]byte]bool)
+	}
+	for _, key := range newKeys {
+		v.doppelgangerChecked[key] = true
+		if detected[key] {
+			v.doppelgangers[key] = true
+		}
+	}
+}
+
+// filterDoppelgangers leaves out keys in use by another validator client, as well as keys
+// whose doppelganger check has not completed yet.
+func (v *validator) filterDoppelgangers(validatingKeys [][48]byte) [][48]byte {
+	v.doppelgangerLock.Lock()
+	defer v.doppelgangerLock.Unlock()
+	if v.doppelgangerChecked != nil {
+		checkedKeys := make([][48]byte, 0, len(validatingKeys))
+		for _, key := range validatingKeys {
+			if v.doppelgangerChecked[key] {
+				checkedKeys = append(checkedKeys, key)
+			}
+		}
+		validatingKeys = checkedKeys
+	}
+	return doppelganger.Filter(validatingKeys, v.doppelgangers)
+}
+
+// CheckShadowMessages compares the blocks, attestations and aggregates which would have been
+// signed in shadow mode with the chain.
+func (v *validator) CheckShadowMessages(ctx context.Context, slot uint64) {
+	if v.shadow == nil {
+		return
+	}
+	if err := v.shadow.Check(ctx, slot); err != nil {
+		log.WithError(err).Warn("Could not compare shadow messages with the chain")
+	}
+}
+
 func (v *validator) checkAndLogValidatorStatus(validatorStatuses []*ethpb.ValidatorActivationResponse_Status) bool {
 	nonexistentIndex := ^uint64(0)
 	var validatorActivated bool
@@ -317,6 +472,8 @@ func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
 	if err != nil {
 		return err
 	}
+	// Keys in use by another validator client must never be given duties.
+	validatingKeys = v.filterDoppelgangers(validatingKeys)
 	req := &ethpb.DutiesRequest{
 		Epoch:      slot / params.BeaconConfig().SlotsPerEpoch,
 		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
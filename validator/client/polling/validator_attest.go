@@ -79,6 +79,16 @@ func (v *validator) SubmitAttestation(ctx context.Context, slot uint64, pubKey [
 		}
 	}
 
+	if v.shadow != nil {
+		if err := v.shadowAttestation(ctx, pubKey, duty, data); err != nil {
+			log.WithError(err).Error("Could not record shadow attestation")
+			if v.emitAccountMetrics {
+				metrics.ValidatorAttestFailVec.WithLabelValues(fmtKey).Inc()
+			}
+		}
+		return
+	}
+
 	sig, err := v.signAtt(ctx, pubKey, data)
 	if err != nil {
 		log.WithError(err).Error("Could not sign attestation")
@@ -184,6 +194,19 @@ func (v *validator) duty(pubKey [48]byte) (*ethpb.DutiesResponse_Duty, error) {
 	return nil, fmt.Errorf("pubkey %#x not in duties", bytesutil.Trunc(pubKey[:]))
 }
 
+// shadowAttestation records the attestation which would have been signed in shadow mode.
+func (v *validator) shadowAttestation(ctx context.Context, pubKey [48]byte, duty *ethpb.DutiesResponse_Duty, data *ethpb.AttestationData) error {
+	domain, err := v.domainData(ctx, data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester[:])
+	if err != nil {
+		return err
+	}
+	root, err := helpers.ComputeSigningRoot(data, domain.SignatureDomain)
+	if err != nil {
+		return err
+	}
+	return v.shadow.Attestation(pubKey, duty, data, root)
+}
+
 // Given validator's public key, this returns the signature of an attestation data.
 func (v *validator) signAtt(ctx context.Context, pubKey [48]byte, data *ethpb.AttestationData) ([]byte, error) {
 	domain, err := v.domainData(ctx, data.Target.Epoch, params.BeaconConfig().DomainBeaconAttester[:])
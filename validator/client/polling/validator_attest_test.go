@@ -18,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/client/shadow"
 	logTest "github.com/sirupsen/logrus/hooks/test"
 	"gopkg.in/d4l3k/messagediff.v1"
 )
@@ -81,6 +82,37 @@ func TestAttestToBlockHead_SubmitAttestation_RequestFailure(t *testing.T) {
 	testutil.AssertLogsContain(t, hook, "Could not submit attestation to beacon node")
 }
 
+func TestAttestToBlockHead_ShadowModeDoesNotSubmit(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, m, finish := setup(t)
+	defer finish()
+	validator.shadow = shadow.NewTracker(nil, false /*emitAccountMetrics*/)
+	validator.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{
+		{
+			PublicKey:      validatorKey.PublicKey.Marshal(),
+			CommitteeIndex: 5,
+			Committee:      []uint64{3, 7},
+			ValidatorIndex: 7,
+		}}}
+	m.validatorClient.EXPECT().GetAttestationData(
+		gomock.Any(), // ctx
+		gomock.AssignableToTypeOf(&ethpb.AttestationDataRequest{}),
+	).Return(&ethpb.AttestationData{
+		Slot:            30,
+		CommitteeIndex:  5,
+		BeaconBlockRoot: make([]byte, 32),
+		Target:          &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+		Source:          &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+	}, nil)
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), // epoch
+	).Return(&ethpb.DomainResponse{}, nil /*err*/)
+
+	validator.SubmitAttestation(context.Background(), 30, validatorPubKey)
+	testutil.AssertLogsContain(t, hook, "Would have signed attestation")
+}
+
 func TestAttestToBlockHead_AttestsCorrectly(t *testing.T) {
 	config := &featureconfig.Flags{
 		ProtectAttester: true,
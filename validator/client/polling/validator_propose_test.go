@@ -3,6 +3,10 @@ package polling
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -13,7 +17,9 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/mock"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/client/shadow"
 	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/graffiti"
 	logTest "github.com/sirupsen/logrus/hooks/test"
 )
 
@@ -305,6 +311,27 @@ func TestProposeBlock_BroadcastsBlock(t *testing.T) {
 	validator.ProposeBlock(context.Background(), 1, validatorPubKey)
 }
 
+func TestProposeBlock_ShadowModeDoesNotBroadcast(t *testing.T) {
+	hook := logTest.NewGlobal()
+	validator, m, finish := setup(t)
+	defer finish()
+	validator.shadow = shadow.NewTracker(nil, false /*emitAccountMetrics*/)
+
+	m.validatorClient.EXPECT().DomainData(
+		gomock.Any(), // ctx
+		gomock.Any(), //epoch
+	).Return(&ethpb.DomainResponse{}, nil /*err*/).Times(2)
+
+	m.validatorClient.EXPECT().GetBlock(
+		gomock.Any(), // ctx
+		gomock.Any(),
+	).Return(&ethpb.BeaconBlock{Slot: 1, Body: &ethpb.BeaconBlockBody{}}, nil /*err*/)
+
+	validator.ProposeBlock(context.Background(), 1, validatorPubKey)
+	testutil.AssertLogsContain(t, hook, "Would have signed block")
+	testutil.AssertLogsDoNotContain(t, hook, "Submitted new block")
+}
+
 func TestProposeBlock_BroadcastsBlock_WithGraffiti(t *testing.T) {
 	validator, m, finish := setup(t)
 	defer finish()
@@ -342,3 +369,37 @@ func TestProposeBlock_BroadcastsBlock_WithGraffiti(t *testing.T) {
 		t.Errorf("Block was broadcast with the wrong graffiti field, wanted \"%v\", got \"%v\"", string(validator.graffiti), string(sentBlock.Block.Body.Graffiti))
 	}
 }
+
+func TestGraffitiFor_UsesGraffitiFile(t *testing.T) {
+	validator, _, finish := setup(t)
+	defer finish()
+	validator.graffiti = []byte("default")
+	if g := validator.graffitiFor(validatorPubKey, 1); string(g) != "default" {
+		t.Errorf("Wanted default graffiti without a graffiti file, received %q", g)
+	}
+
+	path := filepath.Join(testutil.TempDir(), "graffiti.yaml")
+	content := fmt.Sprintf("specific:\n  %#x: \"slot {{.Slot}} index {{.Index}}\"\n", validatorPubKey)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	provider, err := graffiti.NewProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator.graffitiProvider = provider
+	if g := validator.graffitiFor(validatorPubKey, 9); string(g) != "slot 9 index " {
+		t.Errorf("Wanted an empty index without duties, received %q", g)
+	}
+	validator.duties = &ethpb.DutiesResponse{Duties: []*ethpb.DutiesResponse_Duty{
+		{PublicKey: validatorPubKey[:], ValidatorIndex: 5},
+	}}
+	if g := validator.graffitiFor(validatorPubKey, 9); string(g) != "slot 9 index 5" {
+		t.Errorf("Wanted graffiti from the graffiti file, received %q", g)
+	}
+}
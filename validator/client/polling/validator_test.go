@@ -1,6 +1,7 @@
 package polling
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io/ioutil"
@@ -62,10 +63,13 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	n := mock.NewMockNodeClient(ctrl)
 
 	v := validator{
 		keyManager:      testKeyManager,
 		validatorClient: client,
+		node:            n,
+		db:              db2.SetupDB(t, [][48]byte{}),
 	}
 	genesis := uint64(time.Unix(1, 0).Unix())
 	clientStream := mock.NewMockBeaconNodeValidator_WaitForChainStartClient(ctrl)
@@ -80,6 +84,11 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 		},
 		nil,
 	)
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("root"), 32)
+	n.EXPECT().GetGenesis(
+		gomock.Any(),
+		&ptypes.Empty{},
+	).Return(&ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot}, nil)
 	if err := v.WaitForChainStart(context.Background()); err != nil {
 		t.Fatal(err)
 	}
@@ -89,6 +98,13 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 	if v.ticker == nil {
 		t.Error("Expected ticker to be set, received nil")
 	}
+	root, err := v.db.GenesisValidatorsRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
 }
 
 func TestWaitForChainStart_ContextCanceled(t *testing.T) {
@@ -172,10 +188,13 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	n := mock.NewMockNodeClient(ctrl)
 
 	v := validator{
 		keyManager:      testKeyManager,
 		validatorClient: client,
+		node:            n,
+		db:              db2.SetupDB(t, [][48]byte{}),
 	}
 	genesis := uint64(time.Unix(1, 0).Unix())
 	clientStream := mock.NewMockBeaconNodeValidator_WaitForSyncedClient(ctrl)
@@ -190,6 +209,11 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 		},
 		nil,
 	)
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("root"), 32)
+	n.EXPECT().GetGenesis(
+		gomock.Any(),
+		&ptypes.Empty{},
+	).Return(&ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot}, nil)
 	if err := v.WaitForSynced(context.Background()); err != nil {
 		t.Fatal(err)
 	}
@@ -199,6 +223,13 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 	if v.ticker == nil {
 		t.Error("Expected ticker to be set, received nil")
 	}
+	root, err := v.db.GenesisValidatorsRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
 }
 
 func TestWaitForSynced_ContextCanceled(t *testing.T) {
@@ -689,6 +720,69 @@ func TestUpdateDuties_OK(t *testing.T) {
 	}
 }
 
+func TestUpdateDuties_ExcludesDoppelgangers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	keys, err := testKeyManagerThreeValidators.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := validator{
+		keyManager:      testKeyManagerThreeValidators,
+		validatorClient: client,
+		doppelgangers:   map[[48]byte]bool{keys[0]: true},
+	}
+	client.EXPECT().GetDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Do(func(_ context.Context, req *ethpb.DutiesRequest) {
+		if len(req.PublicKeys) != len(keys)-1 {
+			t.Errorf("Wanted %d keys requested, received %d", len(keys)-1, len(req.PublicKeys))
+		}
+		for _, pubKey := range req.PublicKeys {
+			if bytes.Equal(pubKey, keys[0][:]) {
+				t.Error("Expected doppelganger key to be excluded from duties")
+			}
+		}
+	}).Return(&ethpb.DutiesResponse{}, nil)
+
+	if err := v.UpdateDuties(context.Background(), params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatalf("Could not update assignments: %v", err)
+	}
+}
+
+func TestUpdateDuties_ExcludesKeysNotCheckedForDoppelgangers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	keys, err := testKeyManagerThreeValidators.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first key was checked at startup, the second one is being checked and the
+	// third one was imported since the last check.
+	v := validator{
+		keyManager:          testKeyManagerThreeValidators,
+		validatorClient:     client,
+		doppelgangerChecked: map[[48]byte]bool{keys[0]: true, keys[1]: false},
+	}
+	client.EXPECT().GetDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Do(func(_ context.Context, req *ethpb.DutiesRequest) {
+		if len(req.PublicKeys) != 1 || !bytes.Equal(req.PublicKeys[0], keys[0][:]) {
+			t.Error("Expected keys not checked for doppelgangers to be excluded from duties")
+		}
+	}).Return(&ethpb.DutiesResponse{}, nil)
+
+	if err := v.UpdateDuties(context.Background(), params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatalf("Could not update assignments: %v", err)
+	}
+}
+
 func TestUpdateProtections_OK(t *testing.T) {
 	pubKey1 := [48]byte{1}
 	pubKey2 := [48]byte{2}
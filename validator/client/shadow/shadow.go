@@ -0,0 +1,269 @@
+// Package shadow supports running the validator client in shadow mode, to try out a new setup
+// while another validator client is still validating with the same keys. In shadow mode, duties
+// are performed up to the point of signing: blocks, attestations and aggregates are requested from
+// the beacon node, their signing roots computed and checked against slashing protection, but they
+// are neither signed nor broadcast. The tracker records what would have been signed and, once the
+// chain had time to include it, compares it with what the other validator client got on chain.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "shadow")
+
+// Results of comparing a message of shadow mode with the chain.
+const (
+	// ResultMatch means the message on chain is the one which would have been signed.
+	ResultMatch = "match"
+	// ResultMismatch means a different message was included on chain.
+	ResultMismatch = "mismatch"
+	// ResultMissing means no message of the validator was found on chain.
+	ResultMissing = "missing"
+)
+
+// Types of messages recorded in shadow mode.
+const (
+	TypeBlock       = "block"
+	TypeAttestation = "attestation"
+	TypeAggregate   = "aggregate"
+)
+
+// message is a block, attestation or aggregate which would have been signed.
+type message struct {
+	typ              string
+	pubKey           [48]byte
+	slot             uint64
+	signingRoot      [32]byte
+	proposerIndex    uint64
+	parentRoot       []byte
+	data             *ethpb.AttestationData
+	indexInCommittee uint64
+}
+
+// Tracker records the messages which would have been signed in shadow mode, and compares them with
+// the chain.
+type Tracker struct {
+	beaconClient       ethpb.BeaconChainClient
+	emitAccountMetrics bool
+	lock               sync.Mutex
+	pending            []*message
+}
+
+// NewTracker creates a tracker which looks up the chain with the beacon client.
+func NewTracker(beaconClient ethpb.BeaconChainClient, emitAccountMetrics bool) *Tracker {
+	return &Tracker{
+		beaconClient:       beaconClient,
+		emitAccountMetrics: emitAccountMetrics,
+	}
+}
+
+// Block records a block which would have been proposed.
+func (t *Tracker) Block(pubKey [48]byte, blk *ethpb.BeaconBlock, signingRoot [32]byte) {
+	t.record(&message{
+		typ:           TypeBlock,
+		pubKey:        pubKey,
+		slot:          blk.Slot,
+		signingRoot:   signingRoot,
+		proposerIndex: blk.ProposerIndex,
+		parentRoot:    blk.ParentRoot,
+	})
+}
+
+// Attestation records an attestation which would have been made for the duty.
+func (t *Tracker) Attestation(pubKey [48]byte, duty *ethpb.DutiesResponse_Duty, data *ethpb.AttestationData, signingRoot [32]byte) error {
+	for i, index := range duty.Committee {
+		if index == duty.ValidatorIndex {
+			t.record(&message{
+				typ:              TypeAttestation,
+				pubKey:           pubKey,
+				slot:             data.Slot,
+				signingRoot:      signingRoot,
+				data:             data,
+				indexInCommittee: uint64(i),
+			})
+			return nil
+		}
+	}
+	return fmt.Errorf("validator index %d not found in committee of %v", duty.ValidatorIndex, duty.Committee)
+}
+
+// Aggregate records an aggregate which would have been broadcast.
+func (t *Tracker) Aggregate(pubKey [48]byte, aggregate *ethpb.AggregateAttestationAndProof, signingRoot [32]byte) {
+	t.record(&message{
+		typ:         TypeAggregate,
+		pubKey:      pubKey,
+		slot:        aggregate.Aggregate.Data.Slot,
+		signingRoot: signingRoot,
+		data:        aggregate.Aggregate.Data,
+	})
+}
+
+func (t *Tracker) record(m *message) {
+	fields := logrus.Fields{
+		"pubKey":      fmt.Sprintf("%#x", bytesutil.Trunc(m.pubKey[:])),
+		"slot":        m.slot,
+		"signingRoot": fmt.Sprintf("%#x", m.signingRoot),
+	}
+	if m.data != nil {
+		fields["sourceEpoch"] = m.data.Source.Epoch
+		fields["targetEpoch"] = m.data.Target.Epoch
+		fields["blockRoot"] = fmt.Sprintf("%#x", bytesutil.Trunc(m.data.BeaconBlockRoot))
+	}
+	log.WithFields(fields).Infof("Would have signed %s", m.typ)
+	if t.emitAccountMetrics {
+		metrics.ValidatorShadowMessagesVec.WithLabelValues(fmt.Sprintf("%#x", m.pubKey), m.typ).Inc()
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pending = append(t.pending, m)
+}
+
+// Check compares the recorded messages which the chain had time to include by the given slot
+// with the chain. Attestations may be included up to an epoch after their slot, so messages are
+// checked once they are more than an epoch old. Messages which could not be checked because of an
+// error are checked again by the next call.
+func (t *Tracker) Check(ctx context.Context, slot uint64) error {
+	t.lock.Lock()
+	var due, pending []*message
+	for _, m := range t.pending {
+		if m.slot+params.BeaconConfig().SlotsPerEpoch < slot {
+			due = append(due, m)
+		} else {
+			pending = append(pending, m)
+		}
+	}
+	t.pending = pending
+	t.lock.Unlock()
+
+	c := &chain{
+		beaconClient: t.beaconClient,
+		attestations: make(map[uint64][]*ethpb.Attestation),
+	}
+	for i, m := range due {
+		result, err := c.compare(ctx, m)
+		if err != nil {
+			t.lock.Lock()
+			t.pending = append(t.pending, due[i:]...)
+			t.lock.Unlock()
+			return errors.Wrapf(err, "could not compare %s at slot %d with the chain", m.typ, m.slot)
+		}
+		t.report(m, result)
+	}
+	return nil
+}
+
+func (t *Tracker) report(m *message, result string) {
+	log := log.WithFields(logrus.Fields{
+		"pubKey": fmt.Sprintf("%#x", bytesutil.Trunc(m.pubKey[:])),
+		"slot":   m.slot,
+		"result": result,
+	})
+	if result == ResultMatch {
+		log.Infof("Shadow %s matches the chain", m.typ)
+	} else {
+		log.Warnf("Shadow %s does not match the chain", m.typ)
+	}
+	if t.emitAccountMetrics {
+		metrics.ValidatorShadowResultsVec.WithLabelValues(fmt.Sprintf("%#x", m.pubKey), m.typ, result).Inc()
+	}
+}
+
+// chain looks up the blocks and attestations on chain, fetching the attestations included in each
+// epoch only once.
+type chain struct {
+	beaconClient ethpb.BeaconChainClient
+	attestations map[uint64][]*ethpb.Attestation // Inclusion epoch to attestations.
+}
+
+// compare returns how the message compares with the chain. Block bodies depend on the operations
+// pooled by the beacon node at the time, so a block matches when the block on chain was proposed
+// by the same validator on the same parent. An attestation matches when the attestation of the
+// validator on chain has the same data, and an aggregate when an attestation with its data was
+// included.
+func (c *chain) compare(ctx context.Context, m *message) (string, error) {
+	if m.typ == TypeBlock {
+		return c.compareBlock(ctx, m)
+	}
+	var atts []*ethpb.Attestation
+	epoch := helpers.SlotToEpoch(m.slot)
+	for _, inclusionEpoch := range []uint64{epoch, epoch + 1} {
+		included, err := c.includedAttestations(ctx, inclusionEpoch)
+		if err != nil {
+			return "", err
+		}
+		atts = append(atts, included...)
+	}
+	result := ResultMissing
+	for _, att := range atts {
+		if att.Data == nil || att.Data.Slot != m.data.Slot || att.Data.CommitteeIndex != m.data.CommitteeIndex {
+			continue
+		}
+		if m.typ == TypeAttestation && !att.AggregationBits.BitAt(m.indexInCommittee) {
+			continue
+		}
+		if proto.Equal(att.Data, m.data) {
+			return ResultMatch, nil
+		}
+		result = ResultMismatch
+	}
+	return result, nil
+}
+
+func (c *chain) compareBlock(ctx context.Context, m *message) (string, error) {
+	req := &ethpb.ListBlocksRequest{QueryFilter: &ethpb.ListBlocksRequest_Slot{Slot: m.slot}}
+	for {
+		res, err := c.beaconClient.ListBlocks(ctx, req)
+		if err != nil {
+			return "", errors.Wrapf(err, "could not list blocks of slot %d", m.slot)
+		}
+		for _, container := range res.BlockContainers {
+			blk := container.Block.Block
+			if blk.ProposerIndex != m.proposerIndex {
+				continue
+			}
+			if bytes.Equal(blk.ParentRoot, m.parentRoot) {
+				return ResultMatch, nil
+			}
+			return ResultMismatch, nil
+		}
+		if res.NextPageToken == "" || len(res.BlockContainers) == 0 {
+			return ResultMissing, nil
+		}
+		req.PageToken = res.NextPageToken
+	}
+}
+
+func (c *chain) includedAttestations(ctx context.Context, epoch uint64) ([]*ethpb.Attestation, error) {
+	if atts, ok := c.attestations[epoch]; ok {
+		return atts, nil
+	}
+	var atts []*ethpb.Attestation
+	req := &ethpb.ListAttestationsRequest{QueryFilter: &ethpb.ListAttestationsRequest_Epoch{Epoch: epoch}}
+	for {
+		res, err := c.beaconClient.ListAttestations(ctx, req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not list attestations included in epoch %d", epoch)
+		}
+		atts = append(atts, res.Attestations...)
+		if res.NextPageToken == "" || len(res.Attestations) == 0 {
+			break
+		}
+		req.PageToken = res.NextPageToken
+	}
+	c.attestations[epoch] = atts
+	return atts, nil
+}
@@ -0,0 +1,205 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"google.golang.org/grpc"
+)
+
+type fakeBeaconClient struct {
+	ethpb.BeaconChainClient
+	blocks       map[uint64][]*ethpb.BeaconBlockContainer
+	attestations map[uint64][]*ethpb.Attestation
+	err          error
+	requests     int
+}
+
+func (c *fakeBeaconClient) ListBlocks(_ context.Context, req *ethpb.ListBlocksRequest, _ ...grpc.CallOption) (*ethpb.ListBlocksResponse, error) {
+	c.requests++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ethpb.ListBlocksResponse{BlockContainers: c.blocks[req.GetSlot()]}, nil
+}
+
+func (c *fakeBeaconClient) ListAttestations(_ context.Context, req *ethpb.ListAttestationsRequest, _ ...grpc.CallOption) (*ethpb.ListAttestationsResponse, error) {
+	c.requests++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &ethpb.ListAttestationsResponse{Attestations: c.attestations[req.GetEpoch()]}, nil
+}
+
+func attestationData(slot uint64, committeeIndex uint64, blockRoot byte) *ethpb.AttestationData {
+	return &ethpb.AttestationData{
+		Slot:            slot,
+		CommitteeIndex:  committeeIndex,
+		BeaconBlockRoot: bytesutil.PadTo([]byte{blockRoot}, 32),
+		Source:          &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+	}
+}
+
+func attestation(data *ethpb.AttestationData, committeeSize uint64, indices ...uint64) *ethpb.Attestation {
+	bits := bitfield.NewBitlist(committeeSize)
+	for _, i := range indices {
+		bits.SetBitAt(i, true)
+	}
+	return &ethpb.Attestation{Data: data, AggregationBits: bits}
+}
+
+func TestTracker_Results(t *testing.T) {
+	parentRoot := bytesutil.PadTo([]byte{'p'}, 32)
+	client := &fakeBeaconClient{
+		blocks: map[uint64][]*ethpb.BeaconBlockContainer{
+			33: {{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 33, ProposerIndex: 7, ParentRoot: parentRoot}}}},
+			34: {{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 34, ProposerIndex: 8, ParentRoot: parentRoot}}}},
+		},
+		attestations: map[uint64][]*ethpb.Attestation{
+			1: {
+				attestation(attestationData(40, 0, 'a'), 4, 1),
+				attestation(attestationData(40, 1, 'b'), 4, 2),
+			},
+			2: {
+				attestation(attestationData(63, 0, 'a'), 4, 0, 3),
+			},
+		},
+	}
+	tracker := NewTracker(client, false /*emitAccountMetrics*/)
+	duty := func(position uint64) *ethpb.DutiesResponse_Duty {
+		return &ethpb.DutiesResponse_Duty{Committee: []uint64{10, 11, 12, 13}, ValidatorIndex: 10 + position}
+	}
+
+	tests := []struct {
+		name   string
+		record func() error
+		want   string
+	}{
+		{
+			name: "Block on the same parent",
+			record: func() error {
+				tracker.Block([48]byte{1}, &ethpb.BeaconBlock{Slot: 33, ProposerIndex: 7, ParentRoot: parentRoot}, [32]byte{})
+				return nil
+			},
+			want: ResultMatch,
+		},
+		{
+			name: "Block on another parent",
+			record: func() error {
+				tracker.Block([48]byte{1}, &ethpb.BeaconBlock{Slot: 34, ProposerIndex: 8, ParentRoot: make([]byte, 32)}, [32]byte{})
+				return nil
+			},
+			want: ResultMismatch,
+		},
+		{
+			name: "Block not proposed",
+			record: func() error {
+				tracker.Block([48]byte{1}, &ethpb.BeaconBlock{Slot: 35, ProposerIndex: 9, ParentRoot: parentRoot}, [32]byte{})
+				return nil
+			},
+			want: ResultMissing,
+		},
+		{
+			name: "Same attestation",
+			record: func() error {
+				return tracker.Attestation([48]byte{2}, duty(1), attestationData(40, 0, 'a'), [32]byte{})
+			},
+			want: ResultMatch,
+		},
+		{
+			name: "Attestation for another block",
+			record: func() error {
+				return tracker.Attestation([48]byte{2}, duty(2), attestationData(40, 1, 'a'), [32]byte{})
+			},
+			want: ResultMismatch,
+		},
+		{
+			name: "Attestation not included",
+			record: func() error {
+				return tracker.Attestation([48]byte{2}, duty(0), attestationData(40, 0, 'a'), [32]byte{})
+			},
+			want: ResultMissing,
+		},
+		{
+			name: "Attestation included in the next epoch",
+			record: func() error {
+				return tracker.Attestation([48]byte{2}, duty(3), attestationData(63, 0, 'a'), [32]byte{})
+			},
+			want: ResultMatch,
+		},
+		{
+			name: "Aggregate with other data",
+			record: func() error {
+				tracker.Aggregate([48]byte{3}, &ethpb.AggregateAttestationAndProof{
+					Aggregate: attestation(attestationData(40, 1, 'a'), 4, 0),
+				}, [32]byte{})
+				return nil
+			},
+			want: ResultMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.record(); err != nil {
+				t.Fatal(err)
+			}
+			if len(tracker.pending) != 1 {
+				t.Fatalf("Wanted 1 pending message, received %d", len(tracker.pending))
+			}
+			m := tracker.pending[0]
+			tracker.pending = nil
+			c := &chain{beaconClient: client, attestations: make(map[uint64][]*ethpb.Attestation)}
+			result, err := c.compare(context.Background(), m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tt.want {
+				t.Errorf("Wanted result %s, received %s", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestTracker_CheckWaitsForInclusion(t *testing.T) {
+	client := &fakeBeaconClient{}
+	tracker := NewTracker(client, false /*emitAccountMetrics*/)
+	duty := &ethpb.DutiesResponse_Duty{Committee: []uint64{10}, ValidatorIndex: 10}
+	if err := tracker.Attestation([48]byte{1}, duty, attestationData(40, 0, 'a'), [32]byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Check(context.Background(), 72); err != nil {
+		t.Fatal(err)
+	}
+	if client.requests != 0 || len(tracker.pending) != 1 {
+		t.Fatalf("Wanted the attestation to wait for inclusion, made %d requests", client.requests)
+	}
+
+	client.err = errors.New("unavailable")
+	if err := tracker.Check(context.Background(), 73); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(tracker.pending) != 1 {
+		t.Fatalf("Wanted the attestation to be checked again, %d messages pending", len(tracker.pending))
+	}
+
+	client.err = nil
+	if err := tracker.Check(context.Background(), 73); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracker.pending) != 0 {
+		t.Errorf("Wanted no pending messages, received %d", len(tracker.pending))
+	}
+}
+
+func TestTracker_AttestationNotInCommittee(t *testing.T) {
+	tracker := NewTracker(&fakeBeaconClient{}, false /*emitAccountMetrics*/)
+	duty := &ethpb.DutiesResponse_Duty{Committee: []uint64{10, 11}, ValidatorIndex: 12}
+	if err := tracker.Attestation([48]byte{1}, duty, attestationData(40, 0, 'a'), [32]byte{}); err == nil {
+		t.Error("Expected an error for a validator outside of the committee")
+	}
+}
@@ -22,6 +22,9 @@ type Validator interface {
 	WaitForSync(ctx context.Context) error
 	WaitForSynced(ctx context.Context) error
 	WaitForActivation(ctx context.Context) error
+	CheckDoppelgangers(ctx context.Context) error
+	CheckNewDoppelgangers(ctx context.Context)
+	CheckShadowMessages(ctx context.Context, slot uint64)
 	NextSlot() <-chan uint64
 	CurrentSlot() uint64
 	SlotDeadline(slot uint64) time.Time
@@ -64,6 +67,9 @@ func run(ctx context.Context, v Validator) {
 	if err := v.WaitForActivation(ctx); err != nil {
 		log.Fatalf("Could not wait for validator activation: %v", err)
 	}
+	if err := v.CheckDoppelgangers(ctx); err != nil {
+		log.Fatalf("Could not start validator duties: %v", err)
+	}
 	// We listen to a server-side stream of validator duties in the
 	// background of the validator client.
 	go func() {
@@ -100,6 +106,13 @@ func run(ctx context.Context, v Validator) {
 				go v.UpdateDomainDataCaches(ctx, slot+1)
 			}
 
+			// Compare what shadow mode would have signed with the chain, and check keys added
+			// since the last epoch for doppelgangers, once an epoch.
+			if helpers.IsEpochStart(slot) {
+				go v.CheckShadowMessages(ctx, slot)
+				go v.CheckNewDoppelgangers(ctx)
+			}
+
 			var wg sync.WaitGroup
 
 			allRoles, err := v.RolesAt(ctx, slot)
@@ -74,6 +74,16 @@ func (v *validator) SubmitAggregateAndProof(ctx context.Context, slot uint64, pu
 		return
 	}
 
+	if v.shadow != nil {
+		if err := v.shadowAggregate(ctx, pubKey, res.AggregateAndProof); err != nil {
+			log.Errorf("Could not record shadow aggregate and proof: %v", err)
+			if v.emitAccountMetrics {
+				metrics.ValidatorAggFailVec.WithLabelValues(fmtKey).Inc()
+			}
+		}
+		return
+	}
+
 	sig, err := v.aggregateAndProofSig(ctx, pubKey, res.AggregateAndProof)
 	if err != nil {
 		log.Errorf("Could not sign aggregate and proof: %v", err)
@@ -152,6 +162,20 @@ func (v *validator) aggregateAndProofSig(ctx context.Context, pubKey [48]byte, a
 	return sig.Marshal(), nil
 }
 
+// shadowAggregate records the aggregate and proof which would have been signed in shadow mode.
+func (v *validator) shadowAggregate(ctx context.Context, pubKey [48]byte, agg *ethpb.AggregateAttestationAndProof) error {
+	d, err := v.domainData(ctx, helpers.SlotToEpoch(agg.Aggregate.Data.Slot), params.BeaconConfig().DomainAggregateAndProof[:])
+	if err != nil {
+		return err
+	}
+	root, err := helpers.ComputeSigningRoot(agg, d.SignatureDomain)
+	if err != nil {
+		return err
+	}
+	v.shadow.Aggregate(pubKey, agg, root)
+	return nil
+}
+
 func (v *validator) addIndicesToLog(duty *ethpb.DutiesResponse_Duty) error {
 	v.attLogsLock.Lock()
 	defer v.attLogsLock.Unlock()
@@ -16,22 +16,36 @@ import (
 // StreamDuties consumes a server-side stream of validator duties from a beacon node
 // for a set of validating keys passed in as a request type. New duties will be
 // sent over the stream upon a new epoch being reached or from a a chain reorg happening
-// across epochs in the beacon node.
+// across epochs in the beacon node. If the validating keys changed by the time new duties
+// are received, the stream is restarted for the new set of keys.
 func (v *validator) StreamDuties(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "validator.StreamDuties")
 	defer span.End()
 
-	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	for {
+		restart, err := v.streamDuties(ctx)
+		if err != nil || !restart {
+			return err
+		}
+	}
+}
+
+// streamDuties streams the duties of the current validating keys. It returns true if the
+// stream should be restarted because the validating keys changed.
+func (v *validator) streamDuties(ctx context.Context) (bool, error) {
+	validatingKeys, err := v.fetchValidatingKeys()
 	if err != nil {
-		return err
+		return false, err
 	}
 	numValidatingKeys := len(validatingKeys)
 	req := &ethpb.DutiesRequest{
 		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
 	}
-	stream, err := v.validatorClient.StreamDuties(ctx, req)
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := v.validatorClient.StreamDuties(streamCtx, req)
 	if err != nil {
-		return errors.Wrap(err, "Could not setup validator duties streaming client")
+		return false, errors.Wrap(err, "Could not setup validator duties streaming client")
 	}
 	for {
 		res, err := stream.Recv()
@@ -41,10 +55,10 @@ func (v *validator) StreamDuties(ctx context.Context) error {
 		}
 		// If context is canceled we stop the loop.
 		if ctx.Err() == context.Canceled {
-			return errors.Wrap(ctx.Err(), "context has been canceled so shutting down the loop")
+			return false, errors.Wrap(ctx.Err(), "context has been canceled so shutting down the loop")
 		}
 		if err != nil {
-			return errors.Wrap(err, "Could not receive duties from stream")
+			return false, errors.Wrap(err, "Could not receive duties from stream")
 		}
 		// Updates validator duties and requests the beacon node to subscribe
 		// to attestation subnets in advance.
@@ -52,8 +66,44 @@ func (v *validator) StreamDuties(ctx context.Context) error {
 		if err := v.requestSubnetSubscriptions(ctx, res, numValidatingKeys); err != nil {
 			log.WithError(err).Error("Could not request beacon node to subscribe to subnets")
 		}
+		currentKeys, err := v.fetchValidatingKeys()
+		if err != nil {
+			log.WithError(err).Error("Could not fetch validating keys")
+			continue
+		}
+		if !sameKeys(validatingKeys, currentKeys) {
+			log.WithField("numKeys", len(currentKeys)).Info("Validating keys changed, restarting duties stream")
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fetchValidatingKeys fetches the validating keys, leaving out keys in use by another
+// validator client, or not yet checked for it, as those must never be given duties.
+func (v *validator) fetchValidatingKeys() ([][48]byte, error) {
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	return v.filterDoppelgangers(validatingKeys), nil
+}
+
+// sameKeys returns true if both lists hold the same set of keys.
+func sameKeys(a [][48]byte, b [][48]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[[48]byte]bool, len(a))
+	for _, key := range a {
+		set[key] = true
+	}
+	for _, key := range b {
+		if !set[key] {
+			return false
+		}
 	}
-	return nil
+	return true
 }
 
 // RolesAt slot returns the validator roles at the given slot. Returns nil if the
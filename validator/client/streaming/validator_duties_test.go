@@ -135,3 +135,13 @@ func TestStreamDuties_OK(t *testing.T) {
 		)
 	}
 }
+
+func TestSameKeys(t *testing.T) {
+	a := [][48]byte{{1}, {2}}
+	if !sameKeys(a, [][48]byte{{2}, {1}}) {
+		t.Error("Expected keys in a different order to be the same")
+	}
+	if sameKeys(a, [][48]byte{{1}}) || sameKeys(a, [][48]byte{{1}, {3}}) {
+		t.Error("Expected different keys not to be the same")
+	}
+}
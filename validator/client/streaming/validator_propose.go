@@ -16,6 +16,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/validator/client/metrics"
+	"github.com/prysmaticlabs/prysm/validator/graffiti"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
@@ -53,7 +54,7 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 	b, err := v.validatorClient.GetBlock(ctx, &ethpb.BlockRequest{
 		Slot:         slot,
 		RandaoReveal: randaoReveal,
-		Graffiti:     v.graffiti,
+		Graffiti:     v.graffitiFor(pubKey, slot),
 	})
 	if err != nil {
 		log.WithField("blockSlot", slot).WithError(err).Error("Failed to request block from beacon node")
@@ -84,6 +85,16 @@ func (v *validator) ProposeBlock(ctx context.Context, slot uint64, pubKey [48]by
 		}
 	}
 
+	if v.shadow != nil {
+		if err := v.shadowBlock(ctx, pubKey, epoch, b); err != nil {
+			log.WithError(err).Error("Failed to record shadow block")
+			if v.emitAccountMetrics {
+				metrics.ValidatorProposeFailVec.WithLabelValues(fmtKey).Inc()
+			}
+		}
+		return
+	}
+
 	// Sign returned block from beacon node
 	sig, err := v.signBlock(ctx, pubKey, epoch, b)
 	if err != nil {
@@ -171,6 +182,20 @@ func (v *validator) signRandaoReveal(ctx context.Context, pubKey [48]byte, epoch
 	return randaoReveal.Marshal(), nil
 }
 
+// shadowBlock records the block which would have been signed in shadow mode.
+func (v *validator) shadowBlock(ctx context.Context, pubKey [48]byte, epoch uint64, b *ethpb.BeaconBlock) error {
+	domain, err := v.domainData(ctx, epoch, params.BeaconConfig().DomainBeaconProposer[:])
+	if err != nil {
+		return errors.Wrap(err, "could not get domain data")
+	}
+	root, err := helpers.ComputeSigningRoot(b, domain.SignatureDomain)
+	if err != nil {
+		return errors.Wrap(err, "could not get signing root")
+	}
+	v.shadow.Block(pubKey, b, root)
+	return nil
+}
+
 // Sign block with proposer domain and private key.
 func (v *validator) signBlock(ctx context.Context, pubKey [48]byte, epoch uint64, b *ethpb.BeaconBlock) ([]byte, error) {
 	domain, err := v.domainData(ctx, epoch, params.BeaconConfig().DomainBeaconProposer[:])
@@ -206,3 +231,26 @@ func (v *validator) signBlock(ctx context.Context, pubKey [48]byte, epoch uint64
 	}
 	return sig.Marshal(), nil
 }
+
+// graffitiFor returns the graffiti of a block proposed by the key. Graffiti set for the key
+// in the key manager take precedence over the graffiti file, which takes precedence over
+// the default graffiti.
+func (v *validator) graffitiFor(pubKey [48]byte, slot uint64) []byte {
+	if provider, ok := v.keyManager.(keymanager.GraffitiProvider); ok {
+		if g, ok := provider.Graffiti(pubKey); ok {
+			return g
+		}
+	}
+	if v.graffitiProvider != nil {
+		epoch := slot / params.BeaconConfig().SlotsPerEpoch
+		data := &graffiti.Data{Slot: slot, Epoch: epoch}
+		if duty, err := v.duty(pubKey, epoch); err == nil {
+			index := duty.ValidatorIndex
+			data.Index = &index
+		}
+		if g, ok := v.graffitiProvider.Graffiti(pubKey, data); ok {
+			return g
+		}
+	}
+	return v.graffiti
+}
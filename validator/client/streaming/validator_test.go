@@ -1,6 +1,7 @@
 package streaming
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io/ioutil"
@@ -63,10 +64,13 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	n := mock.NewMockNodeClient(ctrl)
 
 	v := validator{
 		keyManager:      testKeyManager,
 		validatorClient: client,
+		node:            n,
+		db:              db2.SetupDB(t, [][48]byte{}),
 	}
 	genesis := uint64(time.Unix(1, 0).Unix())
 	clientStream := mock.NewMockBeaconNodeValidator_WaitForChainStartClient(ctrl)
@@ -81,6 +85,11 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 		},
 		nil,
 	)
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("root"), 32)
+	n.EXPECT().GetGenesis(
+		gomock.Any(),
+		&ptypes.Empty{},
+	).Return(&ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot}, nil)
 	if err := v.WaitForChainStart(context.Background()); err != nil {
 		t.Fatal(err)
 	}
@@ -90,6 +99,13 @@ func TestWaitForChainStart_SetsChainStartGenesisTime(t *testing.T) {
 	if v.ticker == nil {
 		t.Error("Expected ticker to be set, received nil")
 	}
+	root, err := v.db.GenesisValidatorsRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
 }
 
 func TestWaitForChainStart_ContextCanceled(t *testing.T) {
@@ -173,10 +189,13 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	n := mock.NewMockNodeClient(ctrl)
 
 	v := validator{
 		keyManager:      testKeyManager,
 		validatorClient: client,
+		node:            n,
+		db:              db2.SetupDB(t, [][48]byte{}),
 	}
 	genesis := uint64(time.Unix(1, 0).Unix())
 	clientStream := mock.NewMockBeaconNodeValidator_WaitForSyncedClient(ctrl)
@@ -191,6 +210,11 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 		},
 		nil,
 	)
+	genesisValidatorsRoot := bytesutil.PadTo([]byte("root"), 32)
+	n.EXPECT().GetGenesis(
+		gomock.Any(),
+		&ptypes.Empty{},
+	).Return(&ethpb.Genesis{GenesisValidatorsRoot: genesisValidatorsRoot}, nil)
 	if err := v.WaitForSynced(context.Background()); err != nil {
 		t.Fatal(err)
 	}
@@ -200,6 +224,13 @@ func TestWaitForSynced_SetsGenesisTime(t *testing.T) {
 	if v.ticker == nil {
 		t.Error("Expected ticker to be set, received nil")
 	}
+	root, err := v.db.GenesisValidatorsRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
 }
 
 func TestWaitForSynced_ContextCanceled(t *testing.T) {
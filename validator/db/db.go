@@ -82,6 +82,7 @@ func NewKVStore(dirPath string, pubKeys [][48]byte) (*Store, error) {
 			tx,
 			historicProposalsBucket,
 			historicAttestationsBucket,
+			genesisInfoBucket,
 		)
 	}); err != nil {
 		return nil, err
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// ErrGenesisValidatorsRootMismatch is returned when a genesis validators root does not match the one
+// saved in the database.
+var ErrGenesisValidatorsRootMismatch = errors.New("genesis validators root does not match the saved root")
+
+// GenesisValidatorsRoot returns the genesis validators root of the chain the validator signs for.
+// Returns nil if no genesis validators root has been saved yet.
+func (db *Store) GenesisValidatorsRoot(ctx context.Context) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.GenesisValidatorsRoot")
+	defer span.End()
+
+	var root []byte
+	err := db.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(genesisInfoBucket)
+		if bucket == nil {
+			return nil
+		}
+		enc := bucket.Get(genesisValidatorsRootKey)
+		if enc == nil {
+			return nil
+		}
+		root = make([]byte, len(enc))
+		copy(root, enc)
+		return nil
+	})
+	return root, err
+}
+
+// SaveGenesisValidatorsRoot saves the genesis validators root of the chain the validator signs for.
+// It returns ErrGenesisValidatorsRootMismatch if a different root has already been saved, as the
+// slashing protection history of the database would then belong to another chain.
+func (db *Store) SaveGenesisValidatorsRoot(ctx context.Context, genesisValidatorsRoot []byte) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.SaveGenesisValidatorsRoot")
+	defer span.End()
+
+	return db.update(func(tx *bolt.Tx) error {
+		return saveGenesisValidatorsRoot(tx, genesisValidatorsRoot)
+	})
+}
+
+func saveGenesisValidatorsRoot(tx *bolt.Tx, genesisValidatorsRoot []byte) error {
+	bucket, err := tx.CreateBucketIfNotExists(genesisInfoBucket)
+	if err != nil {
+		return err
+	}
+	enc := bucket.Get(genesisValidatorsRootKey)
+	if enc != nil {
+		if !bytes.Equal(enc, genesisValidatorsRoot) {
+			return errors.Wrapf(ErrGenesisValidatorsRootMismatch, "saved %#x, got %#x", enc, genesisValidatorsRoot)
+		}
+		return nil
+	}
+	return bucket.Put(genesisValidatorsRootKey, genesisValidatorsRoot)
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestStore_GenesisValidatorsRoot(t *testing.T) {
+	db := SetupDB(t, [][48]byte{})
+	ctx := context.Background()
+
+	root, err := db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != nil {
+		t.Fatalf("Expected no genesis validators root, received %#x", root)
+	}
+
+	want := bytes.Repeat([]byte{1}, 32)
+	if err := db.SaveGenesisValidatorsRoot(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	// Saving the same root again is a no-op.
+	if err := db.SaveGenesisValidatorsRoot(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	root, err = db.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, want) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", want, root)
+	}
+
+	err = db.SaveGenesisValidatorsRoot(ctx, bytes.Repeat([]byte{2}, 32))
+	if errors.Cause(err) != ErrGenesisValidatorsRootMismatch {
+		t.Errorf("Expected %v, received %v", ErrGenesisValidatorsRootMismatch, err)
+	}
+}
@@ -22,4 +22,7 @@ type ValidatorDB interface {
 	AttestationHistoryForPubKeys(ctx context.Context, publicKeys [][48]byte) (map[[48]byte]*slashpb.AttestationHistory, error)
 	SaveAttestationHistoryForPubKeys(ctx context.Context, historyByPubKey map[[48]byte]*slashpb.AttestationHistory) error
 	DeleteAttestationHistory(ctx context.Context, publicKey []byte) error
+	// Genesis information related methods.
+	GenesisValidatorsRoot(ctx context.Context) ([]byte, error)
+	SaveGenesisValidatorsRoot(ctx context.Context, genesisValidatorsRoot []byte) error
 }
@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/wealdtech/go-bytesutil"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// InterchangeFormatVersion is the version of the slashing protection interchange format
+// produced and understood by the validator database.
+const InterchangeFormatVersion = "5"
+
+// Interchange is a portable JSON document of the slashing protection history of validators,
+// which can be moved between machines or to another client.
+type Interchange struct {
+	Metadata *InterchangeMetadata `json:"metadata"`
+	Data     []*InterchangeData   `json:"data"`
+}
+
+// InterchangeMetadata identifies the format version and the chain of an interchange document.
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// InterchangeData holds the signing history of a single validator public key.
+type InterchangeData struct {
+	PubKey             string                    `json:"pubkey"`
+	SignedBlocks       []*InterchangeBlock       `json:"signed_blocks"`
+	SignedAttestations []*InterchangeAttestation `json:"signed_attestations"`
+}
+
+// InterchangeBlock is a block signed by a validator. The signing root is optional, as it
+// is not recorded in the validator database.
+type InterchangeBlock struct {
+	Slot        uint64 `json:"slot,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// InterchangeAttestation is an attestation signed by a validator. The signing root is optional,
+// as it is not recorded in the validator database.
+type InterchangeAttestation struct {
+	SourceEpoch uint64 `json:"source_epoch,string"`
+	TargetEpoch uint64 `json:"target_epoch,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// ExportInterchange exports the proposal and attestation history of every public key in the
+// database, for the chain identified by genesisValidatorsRoot.
+func (db *Store) ExportInterchange(ctx context.Context, genesisValidatorsRoot []byte) (*Interchange, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.ExportInterchange")
+	defer span.End()
+
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, fmt.Errorf("invalid genesis validators root length %d", len(genesisValidatorsRoot))
+	}
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: []*InterchangeData{},
+	}
+
+	dataByPubKey := make(map[string]*InterchangeData)
+	dataForPubKey := func(pubKey []byte) *InterchangeData {
+		key := fmt.Sprintf("%#x", pubKey)
+		data, ok := dataByPubKey[key]
+		if !ok {
+			data = &InterchangeData{
+				PubKey:             key,
+				SignedBlocks:       []*InterchangeBlock{},
+				SignedAttestations: []*InterchangeAttestation{},
+			}
+			dataByPubKey[key] = data
+			interchange.Data = append(interchange.Data, data)
+		}
+		return data
+	}
+
+	err := db.view(func(tx *bolt.Tx) error {
+		proposalsBucket := tx.Bucket(historicProposalsBucket)
+		if err := proposalsBucket.ForEach(func(pubKey, _ []byte) error {
+			valBucket := proposalsBucket.Bucket(pubKey)
+			if valBucket == nil {
+				return nil
+			}
+			data := dataForPubKey(pubKey)
+			return valBucket.ForEach(func(epoch, slotBits []byte) error {
+				data.SignedBlocks = append(data.SignedBlocks, signedBlocks(binary.LittleEndian.Uint64(epoch), slotBits)...)
+				return nil
+			})
+		}); err != nil {
+			return errors.Wrap(err, "could not export proposal history")
+		}
+
+		attestationsBucket := tx.Bucket(historicAttestationsBucket)
+		return attestationsBucket.ForEach(func(pubKey, enc []byte) error {
+			history, err := unmarshalAttestationHistory(enc)
+			if err != nil {
+				return errors.Wrapf(err, "could not export attestation history for public key %#x", pubKey)
+			}
+			data := dataForPubKey(pubKey)
+			data.SignedAttestations = append(data.SignedAttestations, signedAttestations(history)...)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(interchange.Data, func(i, j int) bool {
+		return interchange.Data[i].PubKey < interchange.Data[j].PubKey
+	})
+	return interchange, nil
+}
+
+// ImportInterchange merges the slashing protection history of an interchange document into the
+// database. The merge is conservative: for every public key, the resulting history is the union of
+// the existing and the imported history, so that nothing the validator may have signed is forgotten.
+// The import is refused if the genesis validators root of the document does not match the one saved
+// in the database, or if an imported attestation has the target of a recorded attestation with
+// another source, as the history holds a single source per target.
+func (db *Store) ImportInterchange(ctx context.Context, interchange *Interchange) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.ImportInterchange")
+	defer span.End()
+
+	if interchange.Metadata == nil {
+		return errors.New("interchange metadata is missing")
+	}
+	if interchange.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf(
+			"unsupported interchange format version %q, expected %q",
+			interchange.Metadata.InterchangeFormatVersion,
+			InterchangeFormatVersion,
+		)
+	}
+	genesisValidatorsRoot, err := decodeInterchangeHex(interchange.Metadata.GenesisValidatorsRoot, 32)
+	if err != nil {
+		return errors.Wrap(err, "invalid genesis validators root")
+	}
+	pubKeys := make([][]byte, len(interchange.Data))
+	for i, data := range interchange.Data {
+		pubKeys[i], err = decodeInterchangeHex(data.PubKey, 48)
+		if err != nil {
+			return errors.Wrapf(err, "invalid public key %s", data.PubKey)
+		}
+	}
+
+	return db.update(func(tx *bolt.Tx) error {
+		if err := saveGenesisValidatorsRoot(tx, genesisValidatorsRoot); err != nil {
+			return err
+		}
+		proposalsBucket := tx.Bucket(historicProposalsBucket)
+		attestationsBucket := tx.Bucket(historicAttestationsBucket)
+		for i, data := range interchange.Data {
+			if err := importSignedBlocks(proposalsBucket, pubKeys[i], data.SignedBlocks); err != nil {
+				return errors.Wrapf(err, "could not import blocks for public key %s", data.PubKey)
+			}
+			if err := importSignedAttestations(attestationsBucket, pubKeys[i], data.SignedAttestations); err != nil {
+				return errors.Wrapf(err, "could not import attestations for public key %s", data.PubKey)
+			}
+		}
+		return nil
+	})
+}
+
+// signedBlocks lists the slots marked in the proposal bitlist of an epoch.
+func signedBlocks(epoch uint64, slotBits bitfield.Bitlist) []*InterchangeBlock {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	var blocks []*InterchangeBlock
+	if len(slotBits) == 0 {
+		return blocks
+	}
+	for i := uint64(0); i < slotsPerEpoch && i < slotBits.Len(); i++ {
+		if slotBits.BitAt(i) {
+			blocks = append(blocks, &InterchangeBlock{Slot: epoch*slotsPerEpoch + i})
+		}
+	}
+	return blocks
+}
+
+// signedAttestations lists the source and target epochs recorded in an attestation history, which
+// holds the targets of the last weak subjectivity period in a ring indexed by target epoch.
+func signedAttestations(history *slashpb.AttestationHistory) []*InterchangeAttestation {
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	latest := history.LatestEpochWritten
+	start := uint64(0)
+	if latest >= wsPeriod {
+		start = latest - wsPeriod + 1
+	}
+	var attestations []*InterchangeAttestation
+	for target := start; target <= latest; target++ {
+		source, ok := history.TargetToSource[target%wsPeriod]
+		if !ok || source == farFuture {
+			continue
+		}
+		attestations = append(attestations, &InterchangeAttestation{
+			SourceEpoch: source,
+			TargetEpoch: target,
+		})
+	}
+	return attestations
+}
+
+func importSignedBlocks(proposalsBucket *bolt.Bucket, pubKey []byte, blocks []*InterchangeBlock) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+	valBucket, err := proposalsBucket.CreateBucketIfNotExists(pubKey)
+	if err != nil {
+		return errors.Wrapf(err, "could not create proposals bucket for public key %#x", pubKey)
+	}
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	for _, block := range blocks {
+		epoch := block.Slot / slotsPerEpoch
+		slotBits := bitfield.NewBitlist(slotsPerEpoch)
+		if enc := valBucket.Get(bytesutil.Bytes8(epoch)); len(enc) != 0 {
+			slotBits = make(bitfield.Bitlist, len(enc))
+			copy(slotBits, enc)
+		}
+		slotBits.SetBitAt(block.Slot%slotsPerEpoch, true)
+		if err := valBucket.Put(bytesutil.Bytes8(epoch), slotBits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importSignedAttestations(attestationsBucket *bolt.Bucket, pubKey []byte, attestations []*InterchangeAttestation) error {
+	if len(attestations) == 0 {
+		return nil
+	}
+	history := &slashpb.AttestationHistory{
+		TargetToSource: map[uint64]uint64{0: params.BeaconConfig().FarFutureEpoch},
+	}
+	if enc := attestationsBucket.Get(pubKey); enc != nil {
+		var err error
+		history, err = unmarshalAttestationHistory(enc)
+		if err != nil {
+			return err
+		}
+	}
+	// Mark the most recent targets last, so that the ring of the history is filled in order.
+	sorted := make([]*InterchangeAttestation, len(attestations))
+	copy(sorted, attestations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].TargetEpoch < sorted[j].TargetEpoch
+	})
+	for _, att := range sorted {
+		if att.SourceEpoch > att.TargetEpoch {
+			return fmt.Errorf("source epoch %d is greater than target epoch %d", att.SourceEpoch, att.TargetEpoch)
+		}
+		if err := markAttestation(history, att.SourceEpoch, att.TargetEpoch); err != nil {
+			return err
+		}
+	}
+	enc, err := proto.Marshal(history)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode attestation history")
+	}
+	return attestationsBucket.Put(pubKey, enc)
+}
+
+// markAttestation records a source and target epoch in the attestation history, the same way the
+// validator client does when it signs an attestation. Targets older than the history are dropped,
+// as the validator client refuses to sign them anyway. If the target is already recorded with
+// another source, an error is returned: keeping either source would forget the other pair, and
+// with it the surround votes it guards against.
+func markAttestation(history *slashpb.AttestationHistory, sourceEpoch uint64, targetEpoch uint64) error {
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	if history.TargetToSource == nil {
+		history.TargetToSource = make(map[uint64]uint64)
+	}
+	if targetEpoch+wsPeriod <= history.LatestEpochWritten {
+		return nil
+	}
+	if targetEpoch > history.LatestEpochWritten {
+		// Clear the epochs skipped since the latest written target.
+		maxToWrite := history.LatestEpochWritten + wsPeriod
+		for i := history.LatestEpochWritten + 1; i < targetEpoch && i <= maxToWrite; i++ {
+			history.TargetToSource[i%wsPeriod] = farFuture
+		}
+		history.LatestEpochWritten = targetEpoch
+		history.TargetToSource[targetEpoch%wsPeriod] = sourceEpoch
+		return nil
+	}
+	existing, ok := history.TargetToSource[targetEpoch%wsPeriod]
+	if ok && existing != farFuture && existing != sourceEpoch {
+		return fmt.Errorf(
+			"target epoch %d is recorded with source epoch %d, cannot import source epoch %d",
+			targetEpoch,
+			existing,
+			sourceEpoch,
+		)
+	}
+	history.TargetToSource[targetEpoch%wsPeriod] = sourceEpoch
+	return nil
+}
+
+func decodeInterchangeHex(s string, length int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != length {
+		return nil, fmt.Errorf("expected %d bytes, got %d", length, len(b))
+	}
+	return b, nil
+}
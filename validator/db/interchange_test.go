@@ -0,0 +1,271 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-bitfield"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestExportImportInterchange(t *testing.T) {
+	ctx := context.Background()
+	pubKeys := [][48]byte{{1}, {2}}
+	source := SetupDB(t, pubKeys)
+	genesisValidatorsRoot := bytes.Repeat([]byte{1}, 32)
+
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(3, true)
+	if err := source.SaveProposalHistoryForEpoch(ctx, pubKeys[0][:], 2, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	history := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{0: farFuture, 1: 0, 2: farFuture, 3: 1},
+		LatestEpochWritten: 3,
+	}
+	if err := source.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKeys[1]: history}); err != nil {
+		t.Fatal(err)
+	}
+
+	interchange, err := source.ExportInterchange(ctx, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 2 {
+		t.Fatalf("Expected data for 2 public keys, received %d", len(interchange.Data))
+	}
+	wantBlocks := []*InterchangeBlock{{Slot: 2*params.BeaconConfig().SlotsPerEpoch + 3}}
+	if !reflect.DeepEqual(interchange.Data[0].SignedBlocks, wantBlocks) {
+		t.Errorf("Unexpected signed blocks %v", interchange.Data[0].SignedBlocks)
+	}
+	wantAttestations := []*InterchangeAttestation{{SourceEpoch: 0, TargetEpoch: 1}, {SourceEpoch: 1, TargetEpoch: 3}}
+	if !reflect.DeepEqual(interchange.Data[1].SignedAttestations, wantAttestations) {
+		t.Errorf("Unexpected signed attestations %v", interchange.Data[1].SignedAttestations)
+	}
+
+	enc, err := json.Marshal(interchange)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &Interchange{}
+	if err := json.Unmarshal(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	target := SetupDB(t, [][48]byte{})
+	if err := target.ImportInterchange(ctx, decoded); err != nil {
+		t.Fatal(err)
+	}
+	root, err := target.GenesisValidatorsRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		t.Errorf("Expected genesis validators root %#x, received %#x", genesisValidatorsRoot, root)
+	}
+	importedBits, err := target.ProposalHistoryForEpoch(ctx, pubKeys[0][:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(importedBits, slotBits) {
+		t.Errorf("Expected proposal history %v, received %v", slotBits, importedBits)
+	}
+	histories, err := target.AttestationHistoryForPubKeys(ctx, [][48]byte{pubKeys[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(histories[pubKeys[1]], history) {
+		t.Errorf("Expected attestation history %v, received %v", history, histories[pubKeys[1]])
+	}
+}
+
+func TestImportInterchange_MergesConservatively(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := SetupDB(t, [][48]byte{pubKey})
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	farFuture := params.BeaconConfig().FarFutureEpoch
+
+	slotBits := bitfield.NewBitlist(slotsPerEpoch)
+	slotBits.SetBitAt(1, true)
+	if err := db.SaveProposalHistoryForEpoch(ctx, pubKey[:], 1, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	existing := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{0: farFuture, 1: farFuture, 2: 1, 3: farFuture, 4: 3},
+		LatestEpochWritten: 4,
+	}
+	if err := db.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKey: existing}); err != nil {
+		t.Fatal(err)
+	}
+
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{1}, 32)),
+		},
+		Data: []*InterchangeData{
+			{
+				PubKey:       fmt.Sprintf("%#x", pubKey),
+				SignedBlocks: []*InterchangeBlock{{Slot: slotsPerEpoch + 2}},
+				SignedAttestations: []*InterchangeAttestation{
+					{SourceEpoch: 1, TargetEpoch: 2},
+					{SourceEpoch: 2, TargetEpoch: 3},
+					{SourceEpoch: 4, TargetEpoch: 6},
+				},
+			},
+		},
+	}
+	if err := db.ImportInterchange(ctx, interchange); err != nil {
+		t.Fatal(err)
+	}
+
+	importedBits, err := db.ProposalHistoryForEpoch(ctx, pubKey[:], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !importedBits.BitAt(1) || !importedBits.BitAt(2) {
+		t.Errorf("Expected existing and imported proposals to be kept, received %v", importedBits)
+	}
+	histories, err := db.AttestationHistoryForPubKeys(ctx, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{0: farFuture, 1: farFuture, 2: 1, 3: 2, 4: 3, 5: farFuture, 6: 4},
+		LatestEpochWritten: 6,
+	}
+	if !reflect.DeepEqual(histories[pubKey], want) {
+		t.Errorf("Expected attestation history %v, received %v", want, histories[pubKey])
+	}
+}
+
+func TestImportInterchange_SurroundingAttestation(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := SetupDB(t, [][48]byte{pubKey})
+	farFuture := params.BeaconConfig().FarFutureEpoch
+
+	existing := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{0: farFuture, 1: farFuture, 2: farFuture, 3: 2},
+		LatestEpochWritten: 3,
+	}
+	if err := db.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKey: existing}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The imported attestation surrounds the existing one, both must be kept.
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{1}, 32)),
+		},
+		Data: []*InterchangeData{
+			{
+				PubKey:             fmt.Sprintf("%#x", pubKey),
+				SignedAttestations: []*InterchangeAttestation{{SourceEpoch: 1, TargetEpoch: 4}},
+			},
+		},
+	}
+	if err := db.ImportInterchange(ctx, interchange); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := db.ExportInterchange(ctx, bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported.Data) != 1 {
+		t.Fatalf("Expected data for 1 public key, received %d", len(exported.Data))
+	}
+	want := []*InterchangeAttestation{{SourceEpoch: 2, TargetEpoch: 3}, {SourceEpoch: 1, TargetEpoch: 4}}
+	if !reflect.DeepEqual(exported.Data[0].SignedAttestations, want) {
+		t.Errorf("Expected signed attestations %v, received %v", want, exported.Data[0].SignedAttestations)
+	}
+}
+
+func TestImportInterchange_ConflictingSourceRefused(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	db := SetupDB(t, [][48]byte{pubKey})
+	farFuture := params.BeaconConfig().FarFutureEpoch
+
+	existing := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{0: farFuture, 1: farFuture, 2: farFuture, 3: 2},
+		LatestEpochWritten: 3,
+	}
+	if err := db.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKey: existing}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one source can be recorded for target 3, importing another would forget a pair.
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{1}, 32)),
+		},
+		Data: []*InterchangeData{
+			{
+				PubKey:             fmt.Sprintf("%#x", pubKey),
+				SignedAttestations: []*InterchangeAttestation{{SourceEpoch: 0, TargetEpoch: 3}},
+			},
+		},
+	}
+	if err := db.ImportInterchange(ctx, interchange); err == nil {
+		t.Fatal("Expected error importing an attestation with a conflicting source")
+	}
+	histories, err := db.AttestationHistoryForPubKeys(ctx, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(histories[pubKey], existing) {
+		t.Errorf("Expected attestation history %v, received %v", existing, histories[pubKey])
+	}
+}
+
+func TestImportInterchange_GenesisValidatorsRootMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := SetupDB(t, [][48]byte{})
+	if err := db.SaveGenesisValidatorsRoot(ctx, bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatal(err)
+	}
+	pubKey := [48]byte{1}
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{2}, 32)),
+		},
+		Data: []*InterchangeData{
+			{
+				PubKey:       fmt.Sprintf("%#x", pubKey),
+				SignedBlocks: []*InterchangeBlock{{Slot: 1}},
+			},
+		},
+	}
+	err := db.ImportInterchange(ctx, interchange)
+	if errors.Cause(err) != ErrGenesisValidatorsRootMismatch {
+		t.Fatalf("Expected %v, received %v", ErrGenesisValidatorsRootMismatch, err)
+	}
+	if _, err := db.ProposalHistoryForEpoch(ctx, pubKey[:], 0); err == nil {
+		t.Error("Expected no proposal history to be imported")
+	}
+}
+
+func TestImportInterchange_UnsupportedVersion(t *testing.T) {
+	db := SetupDB(t, [][48]byte{})
+	interchange := &Interchange{
+		Metadata: &InterchangeMetadata{
+			InterchangeFormatVersion: "1",
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{1}, 32)),
+		},
+	}
+	if err := db.ImportInterchange(context.Background(), interchange); err == nil {
+		t.Error("Expected error importing an unsupported interchange version")
+	}
+}
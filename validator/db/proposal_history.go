@@ -92,6 +92,12 @@ func pruneProposalHistory(valBucket *bolt.Bucket, newestEpoch uint64) error {
 	return nil
 }
 
+// InitializeProposalHistory prepares the proposal history of validator public keys added
+// after the database was opened.
+func (db *Store) InitializeProposalHistory(pubKeys [][48]byte) error {
+	return db.initializeSubBuckets(pubKeys)
+}
+
 func (db *Store) initializeSubBuckets(pubKeys [][48]byte) error {
 	return db.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(historicProposalsBucket)
@@ -5,4 +5,10 @@ var (
 	historicProposalsBucket = []byte("proposal-history-bucket")
 	// Validator slashing protection from slashable attestations.
 	historicAttestationsBucket = []byte("attestation-history-bucket")
+	// Genesis information of the chain the validator is signing for.
+	genesisInfoBucket = []byte("genesis-info-bucket")
+)
+
+var (
+	genesisValidatorsRootKey = []byte("genesis-validators-root")
 )
@@ -14,12 +14,31 @@ var (
 			"of validating keys may wish to disable granular prometheus metrics as it increases " +
 			"the data cardinality.",
 	}
+	// AuditLogDirFlag defines the directory of the signing audit log.
+	AuditLogDirFlag = &cli.StringFlag{
+		Name: "audit-log-dir",
+		Usage: "Directory in which every signature made by the validator is recorded in a hash chained " +
+			"audit log. No audit log is kept if empty",
+	}
+	// AuditLogMaxSizeFlag defines the size at which audit log files are rotated.
+	AuditLogMaxSizeFlag = &cli.Int64Flag{
+		Name:  "audit-log-max-size",
+		Usage: "Size in megabytes at which a new audit log file is started",
+		Value: 100,
+	}
 	// BeaconRPCProviderFlag defines a beacon node RPC endpoint.
 	BeaconRPCProviderFlag = &cli.StringFlag{
-		Name:  "beacon-rpc-provider",
-		Usage: "Beacon node RPC provider endpoint",
+		Name: "beacon-rpc-provider",
+		Usage: "Beacon node RPC provider endpoint. A comma separated list of endpoints makes the " +
+			"validator fail over to the healthiest synced beacon node",
 		Value: "127.0.0.1:4000",
 	}
+	// BroadcastSubmissionsFlag broadcasts signed attestations, aggregates and exits to all beacon nodes.
+	BroadcastSubmissionsFlag = &cli.BoolFlag{
+		Name: "broadcast-submissions",
+		Usage: "Submit signed attestations, aggregates and exits to every healthy beacon node " +
+			"given in --beacon-rpc-provider instead of only the active one",
+	}
 	// CertFlag defines a flag for the node's TLS certificate.
 	CertFlag = &cli.StringFlag{
 		Name:  "tls-cert",
@@ -41,17 +60,65 @@ var (
 		Name:  "disable-rewards-penalties-logging",
 		Usage: "Disable reward/penalty logging during cluster deployment",
 	}
+	// DepositAmountFlag defines the amount of each deposit of the generated deposit data.
+	DepositAmountFlag = &cli.Uint64Flag{
+		Name:  "deposit-amount",
+		Usage: "Amount in Gwei of each deposit of the generated deposit data",
+		Value: 32000000000,
+	}
+	// DepositDataDirFlag defines the directory the deposit data file is written to.
+	DepositDataDirFlag = &cli.StringFlag{
+		Name:  "deposit-data-dir",
+		Usage: "Directory to write the deposit_data-*.json file to",
+		Value: ".",
+	}
+	// ExitPubKeysFlag defines the validating keys to exit.
+	ExitPubKeysFlag = &cli.StringFlag{
+		Name:  "exit-pubkeys",
+		Usage: "Comma separated list of hex encoded public keys to exit. Defaults to all keys of the keymanager",
+	}
+	// ForkVersionFlag defines the fork version of the network deposits are made for.
+	ForkVersionFlag = &cli.StringFlag{
+		Name:  "fork-version",
+		Usage: "Hex encoded genesis fork version of the network to deposit on. Defaults to the fork version of the chain config",
+	}
+	// GenesisValidatorsRootFlag defines the genesis validators root of the chain, used when exporting
+	// the slashing protection history of a validator database which did not record it.
+	GenesisValidatorsRootFlag = &cli.StringFlag{
+		Name:  "genesis-validators-root",
+		Usage: "Hex encoded genesis validators root of the chain the validator signs for",
+	}
 	// GraffitiFlag defines the graffiti value included in proposed blocks
 	GraffitiFlag = &cli.StringFlag{
 		Name:  "graffiti",
 		Usage: "String to include in proposed blocks",
 	}
+	// GraffitiFileFlag defines the path of a YAML file holding the graffiti of each validating key.
+	GraffitiFileFlag = &cli.StringFlag{
+		Name: "graffiti-file",
+		Usage: "Path to a YAML file with a default graffiti and graffiti for specific public keys, which take " +
+			"precedence over --graffiti. Graffiti may use the templates {{.Slot}}, {{.Epoch}}, {{.Index}} and " +
+			"{{.Version}}. The file is reloaded on SIGHUP",
+	}
 	// GrpcRetriesFlag defines the number of times to retry a failed gRPC request.
 	GrpcRetriesFlag = &cli.UintFlag{
 		Name:  "grpc-retries",
 		Usage: "Number of attempts to retry gRPC requests",
 		Value: 5,
 	}
+	// DoppelgangerEpochsFlag defines how many epochs to watch for the validating keys being used elsewhere.
+	DoppelgangerEpochsFlag = &cli.Uint64Flag{
+		Name: "doppelganger-detection-epochs",
+		Usage: "Number of epochs to watch the chain for the validating keys being used by another validator " +
+			"client before performing any duties. Keys found in use are never given duties. 0 disables the check",
+	}
+	// ShadowModeFlag runs the validator client without signing or broadcasting blocks, attestations and aggregates.
+	ShadowModeFlag = &cli.BoolFlag{
+		Name: "shadow-mode",
+		Usage: "Perform duties up to the point of signing, without signing or broadcasting blocks, attestations " +
+			"and aggregates, and compare what would have been signed with the chain. Use it to try out a new " +
+			"setup while another validator client is still validating with the same keys",
+	}
 	// GrpcHeadersFlag defines a list of headers to send with all gRPC requests.
 	GrpcHeadersFlag = &cli.StringFlag{
 		Name: "grpc-headers",
@@ -61,7 +128,7 @@ var (
 	// KeyManager specifies the key manager to use.
 	KeyManager = &cli.StringFlag{
 		Name:  "keymanager",
-		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet)",
+		Usage: "The keymanger to use (unencrypted, interop, keystore, derived, wallet, remote, remote-http, threshold)",
 		Value: "",
 	}
 	// KeyManagerOpts specifies the key manager options.
@@ -70,22 +137,79 @@ var (
 		Usage: "The options for the keymanger, either a JSON string or path to same",
 		Value: "",
 	}
+	// KeysDirFlag defines the location of EIP-2335 keystores to import or export.
+	KeysDirFlag = &cli.StringFlag{
+		Name:  "keys-dir",
+		Usage: "Path to an EIP-2335 keystore file, or a directory of EIP-2335 keystores, to import from or export to",
+	}
+	// KeysPasswordFlag defines the password of EIP-2335 keystores to import or export.
+	KeysPasswordFlag = &cli.StringFlag{
+		Name:  "keys-password",
+		Usage: "String value of the password of the EIP-2335 keystores to import or export",
+	}
 	// KeystorePathFlag defines the location of the keystore directory for a validator's account.
 	KeystorePathFlag = &cli.StringFlag{
 		Name:  "keystore-path",
 		Usage: "Path to the desired keystore directory",
 	}
+	// ManagementAPIFlag enables the API to change the validating keys while the validator is running.
+	ManagementAPIFlag = &cli.BoolFlag{
+		Name: "enable-management-api",
+		Usage: "Enables a local HTTP JSON API to list, import and delete validating keys and set their graffiti " +
+			"while the validator is running. Requests must carry the token of the management API token file",
+	}
+	// ManagementAPIHostFlag defines the host the management API listens on.
+	ManagementAPIHostFlag = &cli.StringFlag{
+		Name:  "management-api-host",
+		Usage: "Host on which the management API listens",
+		Value: "127.0.0.1",
+	}
+	// ManagementAPIPortFlag defines the port the management API listens on.
+	ManagementAPIPortFlag = &cli.IntFlag{
+		Name:  "management-api-port",
+		Usage: "Port on which the management API listens",
+		Value: 7500,
+	}
+	// ManagementAPITokenFileFlag defines the path of the file holding the management API token.
+	ManagementAPITokenFileFlag = &cli.StringFlag{
+		Name:  "management-api-token-file",
+		Usage: "Path of the file holding the management API token, generated if missing. Defaults to a file in the data directory",
+	}
+	// ManagementKeysPasswordFileFlag defines the path of the file holding the password of the keystores
+	// imported through the management API.
+	ManagementKeysPasswordFileFlag = &cli.StringFlag{
+		Name: "management-keys-password-file",
+		Usage: "Path of the file holding the password under which the keystores imported through the management API " +
+			"are encrypted on disk. The password is asked for at startup if unset",
+	}
+	// MnemonicFileFlag defines the path to a file containing the mnemonic of a derived wallet.
+	MnemonicFileFlag = &cli.StringFlag{
+		Name:  "mnemonic-file",
+		Usage: "Path to a file containing the mnemonic to recover a derived wallet from",
+	}
 	// MonitoringPortFlag defines the http port used to serve prometheus metrics.
 	MonitoringPortFlag = &cli.Int64Flag{
 		Name:  "monitoring-port",
 		Usage: "Port used to listening and respond metrics for prometheus.",
 		Value: 8081,
 	}
+	// NumAccountsFlag defines the number of derived accounts to create.
+	NumAccountsFlag = &cli.Uint64Flag{
+		Name:  "num-accounts",
+		Usage: "Number of accounts to derive",
+		Value: 1,
+	}
 	// PasswordFlag defines the password value for storing and retrieving validator private keys from the keystore.
 	PasswordFlag = &cli.StringFlag{
 		Name:  "password",
 		Usage: "String value of the password for your validator private keys",
 	}
+	// SlashingProtectionFileFlag defines the path of a slashing protection interchange JSON file.
+	SlashingProtectionFileFlag = &cli.StringFlag{
+		Name:  "slashing-protection-file",
+		Usage: "Path of the slashing protection interchange JSON file to import from or export to",
+		Value: "slashing_protection.json",
+	}
 	// SourceDirectories defines the locations of the source validator databases while managing validators.
 	SourceDirectories = &cli.StringFlag{
 		Name:  "source-dirs",
@@ -101,6 +225,54 @@ var (
 		Name:  "target-dir",
 		Usage: "The directory of the target validator database",
 	}
+	// ThresholdFlag defines the number of threshold signers needed to sign with a split key.
+	ThresholdFlag = &cli.Uint64Flag{
+		Name:  "threshold",
+		Usage: "Number of threshold signers needed to sign with a split key",
+		Value: 2,
+	}
+	// ThresholdOutputDirFlag defines the directory to write share files and threshold keymanager options to.
+	ThresholdOutputDirFlag = &cli.StringFlag{
+		Name:  "threshold-output-dir",
+		Usage: "Directory to write the share files of the threshold signers and the threshold keymanager options to",
+		Value: ".",
+	}
+	// ThresholdShareFileFlag defines the path of the share file of a threshold signer.
+	ThresholdShareFileFlag = &cli.StringFlag{
+		Name:  "threshold-share-file",
+		Usage: "Path of the share file written by accounts threshold-split holding the key shares of the signer",
+	}
+	// ThresholdSharePasswordFileFlag defines the file holding the password of the key shares of share files.
+	ThresholdSharePasswordFileFlag = &cli.StringFlag{
+		Name:  "threshold-share-password-file",
+		Usage: "File holding the password the key shares of the share files are encrypted under. Asked for on the terminal if not set",
+	}
+	// ThresholdSignerAddressFlag defines the address a threshold signer listens on.
+	ThresholdSignerAddressFlag = &cli.StringFlag{
+		Name:  "threshold-signer-address",
+		Usage: "Address the threshold signer listens on for signing requests",
+		Value: "127.0.0.1:4500",
+	}
+	// ThresholdSignerCertFlag defines the certificate of a threshold signer.
+	ThresholdSignerCertFlag = &cli.StringFlag{
+		Name:  "threshold-signer-tls-cert",
+		Usage: "Certificate for the secure gRPC server of the threshold signer. Pass this and the threshold-signer-tls-key flag in order to use gRPC securely",
+	}
+	// ThresholdSignerKeyFlag defines the key of the certificate of a threshold signer.
+	ThresholdSignerKeyFlag = &cli.StringFlag{
+		Name:  "threshold-signer-tls-key",
+		Usage: "Key of the certificate of the threshold signer",
+	}
+	// ThresholdSignerClientCAFlag defines the CA which must sign the client certificates of a threshold signer.
+	ThresholdSignerClientCAFlag = &cli.StringFlag{
+		Name:  "threshold-signer-tls-client-ca",
+		Usage: "CA certificate which client certificates must be signed by. If set, the threshold signer only serves clients presenting such a certificate",
+	}
+	// ThresholdSignersFlag defines the addresses of the threshold signers to split keys for.
+	ThresholdSignersFlag = &cli.StringFlag{
+		Name:  "threshold-signers",
+		Usage: "Comma separated list of the addresses of the threshold signers, one share of each key is written for each signer",
+	}
 	// UnencryptedKeysFlag specifies a file path of a JSON file of unencrypted validator keys as an
 	// alternative from launching the validator client from decrypting a keystore directory.
 	UnencryptedKeysFlag = &cli.StringFlag{
@@ -108,4 +280,14 @@ var (
 		Usage: "Filepath to a JSON file of unencrypted validator keys for easier launching of the validator client",
 		Value: "",
 	}
+	// WithdrawalCredentialsFlag defines the withdrawal credentials of generated deposits.
+	WithdrawalCredentialsFlag = &cli.StringFlag{
+		Name:  "withdrawal-credentials",
+		Usage: "Hex encoded withdrawal credentials of the generated deposits",
+	}
+	// WithdrawalPubKeyFlag defines the BLS withdrawal public key of generated deposits.
+	WithdrawalPubKeyFlag = &cli.StringFlag{
+		Name:  "withdrawal-pubkey",
+		Usage: "Hex encoded BLS withdrawal public key from which the withdrawal credentials of the generated deposits are derived",
+	}
 )
@@ -0,0 +1,191 @@
+// Package graffiti loads the graffiti of proposed blocks from a YAML file, so that each
+// validating key can propose with its own graffiti. The file holds a default and
+// per-public key overrides:
+//
+//	default: "Prysm {{.Version}}"
+//	specific:
+//	  0xa99a...: "block {{.Slot}} by validator {{.Index}}"
+//
+// Graffiti are Go templates which may use the slot, epoch and validator index of the
+// proposal and the version of the client. The index renders empty when it is unknown.
+// Templates rendering to more than 32 bytes are truncated on a UTF-8 character boundary.
+// The file is reloaded on SIGHUP.
+package graffiti
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/version"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+)
+
+var log = logrus.WithField("prefix", "graffiti")
+
+// maxLength is the length of the graffiti field of a block.
+const maxLength = 32
+
+// Data is the information about a proposal available to graffiti templates. Index is nil
+// if the validator index of the proposer is unknown.
+type Data struct {
+	Slot    uint64
+	Epoch   uint64
+	Index   *uint64
+	Version string
+}
+
+// templateData is the data graffiti templates are rendered with, in which an unknown
+// index is empty rather than the index of another validator.
+type templateData struct {
+	Slot    uint64
+	Epoch   uint64
+	Index   string
+	Version string
+}
+
+type file struct {
+	Default  string            `yaml:"default"`
+	Specific map[string]string `yaml:"specific"`
+}
+
+// Provider provides the graffiti of the validating keys from a graffiti file.
+type Provider struct {
+	path            string
+	lock            sync.RWMutex
+	defaultGraffiti *template.Template
+	specific        map[[48]byte]*template.Template
+}
+
+// NewProvider loads the graffiti file at path.
+func NewProvider(path string) (*Provider, error) {
+	p := &Provider{path: path}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload reads the graffiti file again. The previously loaded graffiti are kept if the
+// file is invalid.
+func (p *Provider) Reload() error {
+	enc, err := ioutil.ReadFile(p.path)
+	if err != nil {
+		return errors.Wrap(err, "could not read graffiti file")
+	}
+	f := &file{}
+	if err := yaml.UnmarshalStrict(enc, f); err != nil {
+		return errors.Wrap(err, "could not decode graffiti file")
+	}
+	var defaultGraffiti *template.Template
+	if f.Default != "" {
+		defaultGraffiti, err = parse("default", f.Default)
+		if err != nil {
+			return err
+		}
+	}
+	specific := make(map[[48]byte]*template.Template, len(f.Specific))
+	for key, graffiti := range f.Specific {
+		pubKey, err := bytesutil.PubKeyFromHex(key)
+		if err != nil {
+			return errors.Wrap(err, "invalid graffiti file")
+		}
+		tmpl, err := parse(key, graffiti)
+		if err != nil {
+			return err
+		}
+		specific[pubKey] = tmpl
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.defaultGraffiti = defaultGraffiti
+	p.specific = specific
+	return nil
+}
+
+// Graffiti returns the graffiti of a block proposed by the key, if the file holds one for
+// the key or a default.
+func (p *Provider) Graffiti(pubKey [48]byte, data *Data) ([]byte, bool) {
+	p.lock.RLock()
+	tmpl, ok := p.specific[pubKey]
+	if !ok {
+		tmpl = p.defaultGraffiti
+	}
+	p.lock.RUnlock()
+	if tmpl == nil {
+		return nil, false
+	}
+	graffiti, err := execute(tmpl, data)
+	if err != nil {
+		log.WithError(err).Error("Could not render graffiti")
+		return nil, false
+	}
+	return graffiti, true
+}
+
+// ReloadOnSignal reloads the graffiti file whenever the process receives SIGHUP, until
+// the context is canceled.
+func (p *Provider) ReloadOnSignal(ctx context.Context) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	defer signal.Stop(sigc)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigc:
+			if err := p.Reload(); err != nil {
+				log.WithError(err).Error("Could not reload graffiti file, keeping previous graffiti")
+				continue
+			}
+			log.WithField("path", p.path).Info("Reloaded graffiti file")
+		}
+	}
+}
+
+// parse parses a graffiti template, checking that it renders.
+func parse(name string, graffiti string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(graffiti)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse graffiti of %s", name)
+	}
+	if _, err := execute(tmpl, &Data{}); err != nil {
+		return nil, errors.Wrapf(err, "could not render graffiti of %s", name)
+	}
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, data *Data) ([]byte, error) {
+	td := &templateData{Slot: data.Slot, Epoch: data.Epoch, Version: data.Version}
+	if td.Version == "" {
+		td.Version = version.GetTag()
+	}
+	if data.Index != nil {
+		td.Index = strconv.FormatUint(*data.Index, 10)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, td); err != nil {
+		return nil, err
+	}
+	graffiti := buf.Bytes()
+	if len(graffiti) > maxLength {
+		log.WithField("graffiti", string(graffiti)).Debugf("Truncating graffiti to %d bytes", maxLength)
+		n := maxLength
+		for n > 0 && !utf8.RuneStart(graffiti[n]) {
+			n--
+		}
+		graffiti = graffiti[:n]
+	}
+	return graffiti, nil
+}
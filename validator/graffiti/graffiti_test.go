@@ -0,0 +1,102 @@
+package graffiti
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProvider_Graffiti(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graffiti")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	specificKey := [48]byte{'a'}
+	path := filepath.Join(dir, "graffiti.yaml")
+	writeFile(t, path, fmt.Sprintf(`
+default: "default {{.Epoch}}"
+specific:
+  %#x: "slot {{.Slot}} index {{.Index}} {{.Version}}"
+`, specificKey))
+
+	p, err := NewProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := uint64(7)
+	data := &Data{Slot: 65, Epoch: 2, Index: &index, Version: "v1"}
+	if graffiti, ok := p.Graffiti(specificKey, data); !ok || string(graffiti) != "slot 65 index 7 v1" {
+		t.Errorf("Unexpected specific graffiti %q", graffiti)
+	}
+	if graffiti, ok := p.Graffiti([48]byte{'b'}, data); !ok || string(graffiti) != "default 2" {
+		t.Errorf("Unexpected default graffiti %q", graffiti)
+	}
+	unknownIndex := &Data{Slot: 65, Epoch: 2, Version: "v1"}
+	if graffiti, ok := p.Graffiti(specificKey, unknownIndex); !ok || string(graffiti) != "slot 65 index  v1" {
+		t.Errorf("Expected an unknown index to render empty, received %q", graffiti)
+	}
+
+	// An invalid file keeps the previous graffiti.
+	writeFile(t, path, `default: "{{.Unknown}}"`)
+	if err := p.Reload(); err == nil {
+		t.Error("Expected reloading a template with an unknown field to fail")
+	}
+	writeFile(t, path, `specific: {"0x01": "short key"}`)
+	if err := p.Reload(); err == nil {
+		t.Error("Expected reloading an invalid public key to fail")
+	}
+	if graffiti, ok := p.Graffiti([48]byte{'b'}, data); !ok || string(graffiti) != "default 2" {
+		t.Errorf("Expected previous graffiti to be kept, received %q", graffiti)
+	}
+
+	writeFile(t, path, `default: "{{.Slot}} is a graffiti that is longer than thirty-two bytes"`)
+	if err := p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if graffiti, ok := p.Graffiti(specificKey, data); !ok || len(graffiti) != maxLength {
+		t.Errorf("Expected long graffiti to be truncated, received %q", graffiti)
+	}
+
+	// Each character is 3 bytes, so 32 bytes would end within the eleventh.
+	writeFile(t, path, `default: "日本語のグラフィティは三十二バイトより長い"`)
+	if err := p.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if graffiti, ok := p.Graffiti(specificKey, data); !ok || string(graffiti) != "日本語のグラフィティ" {
+		t.Errorf("Expected long graffiti to be truncated on a character boundary, received %q", graffiti)
+	}
+}
+
+func TestProvider_NoDefault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graffiti")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	path := filepath.Join(dir, "graffiti.yaml")
+	writeFile(t, path, "specific: {}\n")
+	p, err := NewProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.Graffiti([48]byte{}, &Data{}); ok {
+		t.Error("Expected no graffiti without a default")
+	}
+}
@@ -0,0 +1,177 @@
+package keymanager
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
+)
+
+// Audited is a key manager which records every signature made by the key manager it wraps in an
+// audit log. It always provides the protecting methods, so that the validator passes along the
+// domain and signed object, and signs with the wrapped key manager's own protecting methods when
+// it has them. A signature is only returned once it was recorded.
+type Audited struct {
+	base KeyManager
+	log  *auditlog.Writer
+}
+
+// NewAudited creates a key manager recording the signatures of the base key manager in the log.
+func NewAudited(base KeyManager, w *auditlog.Writer) *Audited {
+	return &Audited{
+		base: base,
+		log:  w,
+	}
+}
+
+// FetchValidatingKeys fetches the keys of the base key manager.
+func (km *Audited) FetchValidatingKeys() ([][48]byte, error) {
+	return km.base.FetchValidatingKeys()
+}
+
+// Sign signs a message with the base key manager. As the domain is not known, the signature is
+// recorded as a generic one.
+func (km *Audited) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	sig, err := km.base.Sign(pubKey, root)
+	if err != nil {
+		return nil, err
+	}
+	if err := km.record(&auditlog.Entry{
+		Type:        auditlog.TypeGeneric,
+		PublicKey:   fmt.Sprintf("%#x", pubKey),
+		SigningRoot: fmt.Sprintf("%#x", root),
+	}); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignGeneric signs a generic root, with the type of the signed object recorded from its domain.
+func (km *Audited) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
+	signingRoot, err := ssz.HashTreeRoot(&p2ppb.SigningData{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	var sig *bls.Signature
+	if protectingKM, ok := km.base.(ProtectingKeyManager); ok {
+		sig, err = protectingKM.SignGeneric(pubKey, root, domain)
+	} else {
+		sig, err = km.base.Sign(pubKey, signingRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := km.record(&auditlog.Entry{
+		Type:        domainType(domain),
+		PublicKey:   fmt.Sprintf("%#x", pubKey),
+		Domain:      fmt.Sprintf("%#x", domain),
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+	}); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignProposal signs a block proposal and records its slot.
+func (km *Audited) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	var sig *bls.Signature
+	if protectingKM, ok := km.base.(ProtectingKeyManager); ok {
+		sig, err = protectingKM.SignProposal(pubKey, domain, data)
+	} else {
+		sig, err = km.base.Sign(pubKey, signingRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := km.record(&auditlog.Entry{
+		Type:        auditlog.TypeBlock,
+		PublicKey:   fmt.Sprintf("%#x", pubKey),
+		Domain:      fmt.Sprintf("%#x", domain),
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+		Slot:        data.Slot,
+		Epoch:       helpers.SlotToEpoch(data.Slot),
+	}); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignAttestation signs an attestation and records its slot, source and target.
+func (km *Audited) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "could not compute signing root")
+	}
+	var sig *bls.Signature
+	if protectingKM, ok := km.base.(ProtectingKeyManager); ok {
+		sig, err = protectingKM.SignAttestation(pubKey, domain, data)
+	} else {
+		sig, err = km.base.Sign(pubKey, signingRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := km.record(&auditlog.Entry{
+		Type:        auditlog.TypeAttestation,
+		PublicKey:   fmt.Sprintf("%#x", pubKey),
+		Domain:      fmt.Sprintf("%#x", domain),
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+		Slot:        data.Slot,
+		Epoch:       data.Target.Epoch,
+		SourceEpoch: data.Source.Epoch,
+		TargetEpoch: data.Target.Epoch,
+	}); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Graffiti returns the graffiti set for the key by the base key manager, if any.
+func (km *Audited) Graffiti(pubKey [48]byte) ([]byte, bool) {
+	if provider, ok := km.base.(GraffitiProvider); ok {
+		return provider.Graffiti(pubKey)
+	}
+	return nil, false
+}
+
+// record appends the entry to the log. A signature which could not be recorded is withheld, so
+// that the log never misses a signature which was used.
+func (km *Audited) record(e *auditlog.Entry) error {
+	if err := km.log.Append(e); err != nil {
+		log.WithError(err).WithField("pubKey", e.PublicKey).Error("Could not record signature in audit log")
+		return errors.Wrap(ErrCannotSign, err.Error())
+	}
+	return nil
+}
+
+// domainType returns the audit log type of objects signed with the domain. Blocks and attestations
+// are only recorded as such by SignProposal and SignAttestation, which know their slot and epochs.
+func domainType(domain [32]byte) string {
+	var typ [4]byte
+	copy(typ[:], domain[:4])
+	cfg := params.BeaconConfig()
+	switch typ {
+	case cfg.DomainRandao:
+		return auditlog.TypeRandao
+	case cfg.DomainSelectionProof:
+		return auditlog.TypeSelectionProof
+	case cfg.DomainAggregateAndProof:
+		return auditlog.TypeAggregateAndProof
+	case cfg.DomainVoluntaryExit:
+		return auditlog.TypeVoluntaryExit
+	case cfg.DomainDeposit:
+		return auditlog.TypeDeposit
+	default:
+		return auditlog.TypeGeneric
+	}
+}
@@ -0,0 +1,127 @@
+package keymanager_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func TestAudited_RecordsSignatures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	w, err := auditlog.NewWriter(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretKey := bls.RandKey()
+	km := keymanager.NewAudited(keymanager.NewDirect([]*bls.SecretKey{secretKey}), w)
+	pubKey := bytesutil.ToBytes48(secretKey.PublicKey().Marshal())
+
+	var domain [32]byte
+	copy(domain[:], params.BeaconConfig().DomainBeaconProposer[:])
+	header := &ethpb.BeaconBlockHeader{Slot: 70, ParentRoot: make([]byte, 32), StateRoot: make([]byte, 32), BodyRoot: make([]byte, 32)}
+	sig, err := km.SignProposal(pubKey, domain, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err := helpers.ComputeSigningRoot(header, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Proposal signature does not verify")
+	}
+
+	copy(domain[:], params.BeaconConfig().DomainBeaconAttester[:])
+	data := &ethpb.AttestationData{
+		Slot:            70,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 2, Root: make([]byte, 32)},
+	}
+	if _, err := km.SignAttestation(pubKey, domain, data); err != nil {
+		t.Fatal(err)
+	}
+
+	copy(domain[:], params.BeaconConfig().DomainRandao[:])
+	root := [32]byte{'r', 'o', 'o', 't'}
+	sig, err = km.SignGeneric(pubKey, root, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err = ssz.HashTreeRoot(&pb.SigningData{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Generic signature does not verify")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := auditlog.Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 3 {
+		t.Errorf("Wanted 3 recorded signatures, received %d", res.Entries)
+	}
+	interchange, err := auditlog.Interchange(dir, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 {
+		t.Fatalf("Wanted history for 1 key, received %d", len(interchange.Data))
+	}
+	history := interchange.Data[0]
+	if len(history.SignedBlocks) != 1 || history.SignedBlocks[0].Slot != 70 {
+		t.Errorf("Wanted a block at slot 70, received %v", history.SignedBlocks)
+	}
+	if len(history.SignedAttestations) != 1 || history.SignedAttestations[0].TargetEpoch != 2 {
+		t.Errorf("Wanted an attestation targeting epoch 2, received %v", history.SignedAttestations)
+	}
+}
+
+func TestAudited_WithholdsUnrecordedSignatures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	w, err := auditlog.NewWriter(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretKey := bls.RandKey()
+	km := keymanager.NewAudited(keymanager.NewDirect([]*bls.SecretKey{secretKey}), w)
+	// Removing the log directory makes appending fail.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := km.Sign(bytesutil.ToBytes48(secretKey.PublicKey().Marshal()), [32]byte{})
+	if err == nil || sig != nil {
+		t.Error("Expected the signature to be withheld when it cannot be recorded")
+	}
+}
@@ -0,0 +1,61 @@
+package keymanager
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Derived is a key manager that loads the validating keys of a wallet derived from a mnemonic,
+// as per EIP-2333 and EIP-2334, and stored as EIP-2335 keystores.
+type Derived struct {
+	*Direct
+}
+
+type derivedOpts struct {
+	Path       string `json:"path"`
+	Passphrase string `json:"passphrase"`
+}
+
+var derivedOptsHelp = `The derived key manager loads keys derived from a mnemonic, created with 'validator accounts derived'.  The options are:
+  - path This is the filesystem path to the wallet directory.  Defaults to the user's home directory if not supplied
+  - passphrase This is the passphrase used to encrypt keys.  Will be asked for if not supplied
+A sample set of options are:
+  {
+    "path":   "/home/me/wallet", // Load the keys from '/home/me/wallet'
+    "passphrase": "secret"       // Use the passphrase 'secret' to decrypt keys
+  }`
+
+// NewDerived creates a key manager populated with the validating keys of the derived wallet at the given path.
+func NewDerived(input string) (*Derived, string, error) {
+	opts := &derivedOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, derivedOptsHelp, err
+	}
+
+	if strings.Contains(opts.Path, "$") || strings.Contains(opts.Path, "~") || strings.Contains(opts.Path, "%") {
+		log.WithField("path", opts.Path).Warn("Wallet path contains unexpanded shell expansion characters")
+	}
+	if opts.Path == "" {
+		opts.Path = accounts.DefaultValidatorDir()
+	}
+	log.WithField("walletPath", opts.Path).Info("Checking derived validator keys")
+
+	if opts.Passphrase == "" {
+		log.Info("Enter your validator wallet password:")
+		bytePassword, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return nil, derivedOptsHelp, err
+		}
+		opts.Passphrase = strings.Replace(string(bytePassword), "\n", "", -1)
+	}
+
+	sks, err := accounts.DecryptDerivedValidatingKeys(opts.Path, opts.Passphrase)
+	if err != nil {
+		return nil, derivedOptsHelp, err
+	}
+	return &Derived{Direct: NewDirect(sks)}, "", nil
+}
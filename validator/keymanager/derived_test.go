@@ -0,0 +1,77 @@
+package keymanager_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func TestDerived_NoWallet(t *testing.T) {
+	walletDir := filepath.Join(testutil.TempDir(), "missing-derived-wallet")
+	opts := fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, walletDir)
+	if _, _, err := keymanager.NewDerived(opts); err != accounts.ErrNoDerivedWallet {
+		t.Errorf("Expected %v, got %v", accounts.ErrNoDerivedWallet, err)
+	}
+}
+
+func TestDerived_FetchAndSign(t *testing.T) {
+	walletDir := filepath.Join(testutil.TempDir(), "derived-keymanager")
+	defer func() {
+		if err := os.RemoveAll(walletDir); err != nil {
+			t.Log(err)
+		}
+	}()
+	passphrase := "secret"
+	_, created, err := accounts.CreateDerivedWallet(walletDir, passphrase, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	km, _, err := keymanager.NewDerived(fmt.Sprintf(`{"path":%q,"passphrase":%q}`, walletDir, passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(created) {
+		t.Fatalf("Incorrect number of keys returned; expected %d, received %d", len(created), len(keys))
+	}
+	for _, account := range created {
+		pubKey := bytesutil.ToBytes48(account.ValidatingPublicKey)
+		found := false
+		for _, key := range keys {
+			if bytes.Equal(key[:], pubKey[:]) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Validating key of account %d not loaded", account.Index)
+		}
+		root := [32]byte{0x01}
+		sig, err := km.Sign(pubKey, root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk, err := bls.PublicKeyFromBytes(account.ValidatingPublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sig.Verify(pk, root[:]) {
+			t.Errorf("Invalid signature for account %d", account.Index)
+		}
+	}
+
+	if _, _, err := keymanager.NewDerived(fmt.Sprintf(`{"path":%q,"passphrase":"wrong"}`, walletDir)); err == nil {
+		t.Error("Expected error loading keys with wrong passphrase")
+	}
+}
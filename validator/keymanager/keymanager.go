@@ -37,3 +37,9 @@ type ProtectingKeyManager interface {
 	// SignAttestation signs an attestation for the validator to broadcast.
 	SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error)
 }
+
+// GraffitiProvider provides the graffiti of blocks proposed by individual keys.
+type GraffitiProvider interface {
+	// Graffiti returns the graffiti set for the key, if any.
+	Graffiti(pubKey [48]byte) ([]byte, bool)
+}
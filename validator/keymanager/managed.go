@@ -0,0 +1,293 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+)
+
+// ErrKeyExists is returned when importing a key which is already validating.
+var ErrKeyExists = errors.New("key already exists")
+
+const (
+	managedKeystorePrefix = "keystore-"
+	managedKeystoreSuffix = ".json"
+	managedRemovedFile    = "removed.json"
+	managedGraffitiFile   = "graffiti.json"
+)
+
+// Managed is a key manager whose keys can be changed while the validator is running. It wraps the
+// configured key manager, to which it adds imported EIP-2335 keystores and from which keys can be
+// removed. Changes are persisted to a directory so that they survive restarts, imported keystores
+// being re-encrypted under the password of the managed key manager.
+type Managed struct {
+	base     KeyManager
+	dir      string
+	password string
+	lock     sync.RWMutex
+	imported map[[48]byte]*bls.SecretKey
+	removed  map[[48]byte]bool
+	graffiti map[[48]byte][]byte
+}
+
+// NewManaged creates a managed key manager on top of the base key manager, loading the changes
+// previously persisted to the directory. The password encrypts the imported keystores saved to the
+// directory. Key managers which protect against slashing themselves cannot be wrapped, as their
+// protection would be bypassed by the imported keys.
+func NewManaged(base KeyManager, dir string, password string) (*Managed, error) {
+	if _, ok := base.(ProtectingKeyManager); ok {
+		return nil, errors.New("keys of a protecting key manager cannot be managed")
+	}
+	if password == "" {
+		return nil, errors.New("a password is required to encrypt the managed keystores")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create managed keys directory")
+	}
+	km := &Managed{
+		base:     base,
+		dir:      dir,
+		password: password,
+		imported: make(map[[48]byte]*bls.SecretKey),
+		removed:  make(map[[48]byte]bool),
+		graffiti: make(map[[48]byte][]byte),
+	}
+	if err := km.load(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// FetchValidatingKeys fetches the keys of the base key manager which were not removed, followed by the
+// imported keys.
+func (km *Managed) FetchValidatingKeys() ([][48]byte, error) {
+	baseKeys, err := km.base.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	km.lock.RLock()
+	defer km.lock.RUnlock()
+	keys := make([][48]byte, 0, len(baseKeys)+len(km.imported))
+	for _, pubKey := range baseKeys {
+		if !km.removed[pubKey] {
+			keys = append(keys, pubKey)
+		}
+	}
+	for pubKey := range km.imported {
+		keys = append(keys, pubKey)
+	}
+	return keys, nil
+}
+
+// Sign signs a message for the validator to broadcast. Removed keys can no longer sign.
+func (km *Managed) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	km.lock.RLock()
+	secretKey, imported := km.imported[pubKey]
+	removed := km.removed[pubKey]
+	km.lock.RUnlock()
+	if imported {
+		return secretKey.Sign(root[:]), nil
+	}
+	if removed {
+		return nil, ErrNoSuchKey
+	}
+	return km.base.Sign(pubKey, root)
+}
+
+// ImportKeystore decrypts an EIP-2335 keystore and starts validating with its key. The key is saved
+// encrypted under the password of the managed key manager, the password of the keystore is not
+// kept. Importing a key which was removed from the base key manager restores it.
+func (km *Managed) ImportKeystore(keystoreJSON []byte, password string) ([48]byte, error) {
+	key, err := keystore.DecryptKeyEIP2335(keystoreJSON, password)
+	if err != nil {
+		return [48]byte{}, errors.Wrap(err, "could not decrypt keystore")
+	}
+	pubKey := bytesutil.ToBytes48(key.PublicKey.Marshal())
+	baseKeys, err := km.base.FetchValidatingKeys()
+	if err != nil {
+		return [48]byte{}, err
+	}
+
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	if _, ok := km.imported[pubKey]; ok {
+		return pubKey, ErrKeyExists
+	}
+	for _, baseKey := range baseKeys {
+		if baseKey != pubKey {
+			continue
+		}
+		if !km.removed[pubKey] {
+			return pubKey, ErrKeyExists
+		}
+		delete(km.removed, pubKey)
+		return pubKey, km.saveRemoved()
+	}
+	if err := km.saveKeystore(key); err != nil {
+		return pubKey, err
+	}
+	km.imported[pubKey] = key.SecretKey
+	return pubKey, nil
+}
+
+// RemoveKey stops validating with a key. Imported keystores are deleted, keys of the base key manager
+// are remembered as removed.
+func (km *Managed) RemoveKey(pubKey [48]byte) error {
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	if _, ok := km.imported[pubKey]; ok {
+		delete(km.imported, pubKey)
+		if err := os.Remove(km.keystorePath(pubKey) + managedKeystoreSuffix); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "could not delete keystore")
+		}
+		return nil
+	}
+	if km.removed[pubKey] {
+		return ErrNoSuchKey
+	}
+	baseKeys, err := km.base.FetchValidatingKeys()
+	if err != nil {
+		return err
+	}
+	for _, baseKey := range baseKeys {
+		if baseKey == pubKey {
+			km.removed[pubKey] = true
+			return km.saveRemoved()
+		}
+	}
+	return ErrNoSuchKey
+}
+
+// IsImported returns true if the key was imported rather than provided by the base key manager.
+func (km *Managed) IsImported(pubKey [48]byte) bool {
+	km.lock.RLock()
+	defer km.lock.RUnlock()
+	_, ok := km.imported[pubKey]
+	return ok
+}
+
+// Graffiti returns the graffiti set for the key, if any.
+func (km *Managed) Graffiti(pubKey [48]byte) ([]byte, bool) {
+	km.lock.RLock()
+	defer km.lock.RUnlock()
+	graffiti, ok := km.graffiti[pubKey]
+	return graffiti, ok
+}
+
+// SetGraffiti sets the graffiti of blocks proposed by the key. Empty graffiti reverts to the default.
+func (km *Managed) SetGraffiti(pubKey [48]byte, graffiti []byte) error {
+	if len(graffiti) > 32 {
+		return fmt.Errorf("graffiti of %d bytes exceeds 32 bytes", len(graffiti))
+	}
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	if len(graffiti) == 0 {
+		delete(km.graffiti, pubKey)
+	} else {
+		km.graffiti[pubKey] = graffiti
+	}
+	byKey := make(map[string]string, len(km.graffiti))
+	for key, g := range km.graffiti {
+		byKey[fmt.Sprintf("%#x", key)] = string(g)
+	}
+	return km.writeJSON(managedGraffitiFile, byKey)
+}
+
+func (km *Managed) keystorePath(pubKey [48]byte) string {
+	return filepath.Join(km.dir, managedKeystorePrefix+hex.EncodeToString(pubKey[:]))
+}
+
+// saveKeystore encrypts the key under the password of the managed key manager and saves it.
+func (km *Managed) saveKeystore(key *keystore.Key) error {
+	enc, err := keystore.EncryptKeyEIP2335(key, km.password, "", keystore.KDFScrypt)
+	if err != nil {
+		return errors.Wrap(err, "could not encrypt keystore")
+	}
+	path := km.keystorePath(bytesutil.ToBytes48(key.PublicKey.Marshal()))
+	return errors.Wrap(ioutil.WriteFile(path+managedKeystoreSuffix, enc, 0600), "could not save keystore")
+}
+
+// load reads the imported keystores, removed keys and graffiti from the directory.
+func (km *Managed) load() error {
+	files, err := ioutil.ReadDir(km.dir)
+	if err != nil {
+		return errors.Wrap(err, "could not read managed keys directory")
+	}
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, managedKeystorePrefix) || !strings.HasSuffix(name, managedKeystoreSuffix) {
+			continue
+		}
+		keystoreJSON, err := ioutil.ReadFile(filepath.Join(km.dir, name))
+		if err != nil {
+			return errors.Wrapf(err, "could not read keystore %s", name)
+		}
+		key, err := keystore.DecryptKeyEIP2335(keystoreJSON, km.password)
+		if err != nil {
+			return errors.Wrapf(err, "could not decrypt keystore %s", name)
+		}
+		km.imported[bytesutil.ToBytes48(key.PublicKey.Marshal())] = key.SecretKey
+	}
+
+	var removed []string
+	if err := km.readJSON(managedRemovedFile, &removed); err != nil {
+		return err
+	}
+	for _, key := range removed {
+		pubKey, err := bytesutil.PubKeyFromHex(key)
+		if err != nil {
+			return err
+		}
+		km.removed[pubKey] = true
+	}
+
+	graffiti := make(map[string]string)
+	if err := km.readJSON(managedGraffitiFile, &graffiti); err != nil {
+		return err
+	}
+	for key, g := range graffiti {
+		pubKey, err := bytesutil.PubKeyFromHex(key)
+		if err != nil {
+			return err
+		}
+		km.graffiti[pubKey] = []byte(g)
+	}
+	return nil
+}
+
+func (km *Managed) saveRemoved() error {
+	removed := make([]string, 0, len(km.removed))
+	for pubKey := range km.removed {
+		removed = append(removed, fmt.Sprintf("%#x", pubKey))
+	}
+	return km.writeJSON(managedRemovedFile, removed)
+}
+
+func (km *Managed) readJSON(name string, v interface{}) error {
+	enc, err := ioutil.ReadFile(filepath.Join(km.dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not read %s", name)
+	}
+	return errors.Wrapf(json.Unmarshal(enc, v), "could not decode %s", name)
+}
+
+func (km *Managed) writeJSON(name string, v interface{}) error {
+	enc, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(km.dir, name), enc, 0600), "could not write %s", name)
+}
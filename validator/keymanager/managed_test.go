@@ -0,0 +1,165 @@
+package keymanager_test
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func managedTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func encryptTestKey(t *testing.T, password string) ([48]byte, []byte) {
+	key, err := keystore.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := keystore.EncryptKeyEIP2335(key, password, "", keystore.KDFPBKDF2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bytesutil.ToBytes48(key.PublicKey.Marshal()), enc
+}
+
+func hasKey(t *testing.T, km keymanager.KeyManager, pubKey [48]byte) bool {
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		if key == pubKey {
+			return true
+		}
+	}
+	return false
+}
+
+func TestManaged_ImportAndRemove(t *testing.T) {
+	dir := managedTestDir(t)
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	sk := bls.RandKey()
+	basePubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	base := keymanager.NewDirect([]*bls.SecretKey{sk})
+	km, err := keymanager.NewManaged(base, dir, "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey, enc := encryptTestKey(t, "password")
+	if _, err := km.ImportKeystore(enc, "wrong"); err == nil {
+		t.Error("Expected import with wrong password to fail")
+	}
+	imported, err := km.ImportKeystore(enc, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != pubKey || !km.IsImported(pubKey) || !hasKey(t, km, pubKey) {
+		t.Fatal("Expected imported key to be validating")
+	}
+	if _, err := km.ImportKeystore(enc, "password"); err != keymanager.ErrKeyExists {
+		t.Errorf("Wanted %v importing twice, received %v", keymanager.ErrKeyExists, err)
+	}
+	if _, err := km.Sign(pubKey, [32]byte{'r'}); err != nil {
+		t.Errorf("Could not sign with imported key: %v", err)
+	}
+
+	if err := km.RemoveKey(basePubKey); err != nil {
+		t.Fatal(err)
+	}
+	if hasKey(t, km, basePubKey) {
+		t.Error("Expected removed base key to no longer be validating")
+	}
+	if _, err := km.Sign(basePubKey, [32]byte{'r'}); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Wanted %v signing with removed key, received %v", keymanager.ErrNoSuchKey, err)
+	}
+	if err := km.RemoveKey(basePubKey); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Wanted %v removing twice, received %v", keymanager.ErrNoSuchKey, err)
+	}
+	if err := km.SetGraffiti(pubKey, []byte("managed")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes survive a restart.
+	reloaded, err := keymanager.NewManaged(keymanager.NewDirect([]*bls.SecretKey{sk}), dir, "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasKey(t, reloaded, pubKey) || hasKey(t, reloaded, basePubKey) {
+		t.Error("Expected imported and removed keys to be persisted")
+	}
+	if graffiti, ok := reloaded.Graffiti(pubKey); !ok || string(graffiti) != "managed" {
+		t.Errorf("Expected graffiti to be persisted, received %q", graffiti)
+	}
+
+	if err := reloaded.RemoveKey(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if hasKey(t, reloaded, pubKey) || reloaded.IsImported(pubKey) {
+		t.Error("Expected imported key to be removed")
+	}
+}
+
+func TestManaged_KeystoreEncryptedUnderManagedPassword(t *testing.T) {
+	dir := managedTestDir(t)
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	km, err := keymanager.NewManaged(keymanager.NewDirect(nil), dir, "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, enc := encryptTestKey(t, "password")
+	if _, err := km.ImportKeystore(enc, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "keystore-"+hex.EncodeToString(pubKey[:])+".json")
+	saved, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keystore.DecryptKeyEIP2335(saved, "password"); err == nil {
+		t.Error("Expected saved keystore to no longer decrypt with the imported password")
+	}
+	if _, err := keystore.DecryptKeyEIP2335(saved, "managed"); err != nil {
+		t.Errorf("Could not decrypt saved keystore with the managed password: %v", err)
+	}
+
+	if _, err := keymanager.NewManaged(keymanager.NewDirect(nil), dir, "wrong"); err == nil {
+		t.Error("Expected loading keystores with the wrong password to fail")
+	}
+}
+
+func TestManaged_SetGraffiti_TooLong(t *testing.T) {
+	dir := managedTestDir(t)
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	km, err := keymanager.NewManaged(keymanager.NewDirect(nil), dir, "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := km.SetGraffiti([48]byte{}, make([]byte, 33)); err == nil {
+		t.Error("Expected graffiti over 32 bytes to be rejected")
+	}
+}
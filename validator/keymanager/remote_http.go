@@ -0,0 +1,384 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+const (
+	// defaultRemoteHTTPTimeout is the default timeout of a single request to the remote signer.
+	defaultRemoteHTTPTimeout = 10 * time.Second
+
+	remoteHTTPPublicKeysPath = "/api/v1/eth2/publicKeys"
+	remoteHTTPSignPath       = "/api/v1/eth2/sign/"
+
+	remoteHTTPTypeBlock           = "BLOCK"
+	remoteHTTPTypeAttestation     = "ATTESTATION"
+	remoteHTTPTypeAggregationSlot = "AGGREGATION_SLOT"
+	remoteHTTPTypeRandaoReveal    = "RANDAO_REVEAL"
+	remoteHTTPTypeGeneric         = "GENERIC"
+)
+
+// RemoteHTTP is a key manager that accesses a remote signer over HTTP.
+type RemoteHTTP struct {
+	client   *http.Client
+	url      string
+	timeout  time.Duration
+	keys     map[[48]byte]bool
+	keysLock sync.RWMutex
+}
+
+type remoteHTTPOpts struct {
+	URL          string                 `json:"url"`
+	Timeout      string                 `json:"timeout"`
+	Certificates *remoteCertificateOpts `json:"certificates"`
+}
+
+// remoteHTTPSignRequest is the JSON body of a signing request. Alongside the signing root, it holds the
+// typed object being signed, so that the remote signer can apply its own slashing protection.
+type remoteHTTPSignRequest struct {
+	Type            string                     `json:"type"`
+	SigningRoot     string                     `json:"signingRoot"`
+	Domain          string                     `json:"domain"`
+	Block           *remoteHTTPBlockHeader     `json:"block,omitempty"`
+	Attestation     *remoteHTTPAttestationData `json:"attestation,omitempty"`
+	AggregationSlot *remoteHTTPAggregationSlot `json:"aggregation_slot,omitempty"`
+	RandaoReveal    *remoteHTTPRandaoReveal    `json:"randao_reveal,omitempty"`
+}
+
+type remoteHTTPBlockHeader struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+type remoteHTTPAttestationData struct {
+	Slot            uint64                `json:"slot,string"`
+	Index           uint64                `json:"index,string"`
+	BeaconBlockRoot string                `json:"beacon_block_root"`
+	Source          *remoteHTTPCheckpoint `json:"source"`
+	Target          *remoteHTTPCheckpoint `json:"target"`
+}
+
+type remoteHTTPCheckpoint struct {
+	Epoch uint64 `json:"epoch,string"`
+	Root  string `json:"root"`
+}
+
+type remoteHTTPAggregationSlot struct {
+	Slot uint64 `json:"slot,string"`
+}
+
+type remoteHTTPRandaoReveal struct {
+	Epoch uint64 `json:"epoch,string"`
+}
+
+var remoteHTTPOptsHelp = `The remote-http key manager connects to a remote signer over HTTP.  The options are:
+  - url This is the base URL of the remote signer.  Public keys are discovered through
+    GET <url>/api/v1/eth2/publicKeys, and signing requests are sent to
+    POST <url>/api/v1/eth2/sign/<public key>
+  - timeout This is the timeout of a single request, for example "5s".  Defaults to 10s
+  - certificates This provides paths to certificates, and is optional:
+    - ca_cert This is the path to the server's certificate authority certificate file
+    - client_cert This is the path to the client's certificate file
+    - client_key This is the path to the client's key file
+
+A sample keymanager options file (with annotations; these should be removed if
+using this as a template) is:
+
+  {
+    "url":     "https://signer.example.com:9000", // Connect to the remote signer at signer.example.com on port 9000
+    "timeout": "5s",                              // Fail requests taking longer than 5 seconds
+    "certificates": {
+      "ca_cert": "/home/eth2/certs/ca.crt"         // Certificate file for the CA that signed the server's certificate
+      "client_cert": "/home/eth2/certs/client.crt" // Certificate file for this client
+      "client_key": "/home/eth2/certs/client.key"  // Key file for this client
+    }
+  }`
+
+// NewRemoteHTTP creates a key manager populated with the keys of a remote signer accessed over HTTP.
+func NewRemoteHTTP(input string) (KeyManager, string, error) {
+	opts := &remoteHTTPOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, remoteHTTPOptsHelp, err
+	}
+	if opts.URL == "" {
+		return nil, remoteHTTPOptsHelp, errors.New("url is required")
+	}
+
+	timeout := defaultRemoteHTTPTimeout
+	if opts.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(opts.Timeout)
+		if err != nil {
+			return nil, remoteHTTPOptsHelp, errors.Wrap(err, "invalid timeout")
+		}
+	}
+
+	transport := &http.Transport{}
+	if opts.Certificates != nil {
+		tlsCfg, err := remoteHTTPTLSConfig(opts.Certificates)
+		if err != nil {
+			return nil, remoteHTTPOptsHelp, err
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	km := &RemoteHTTP{
+		client:  &http.Client{Transport: transport},
+		url:     strings.TrimSuffix(opts.URL, "/"),
+		timeout: timeout,
+	}
+	if err := km.RefreshValidatingKeys(); err != nil {
+		return nil, remoteHTTPOptsHelp, errors.Wrap(err, "failed to fetch public keys from remote signer")
+	}
+	return km, remoteHTTPOptsHelp, nil
+}
+
+// remoteHTTPTLSConfig loads the client certificate and, if present, the CA of the server certificate.
+func remoteHTTPTLSConfig(certs *remoteCertificateOpts) (*tls.Config, error) {
+	if certs.ClientCert == "" {
+		return nil, errors.New("client certificate is required")
+	}
+	if certs.ClientKey == "" {
+		return nil, errors.New("client key is required")
+	}
+	clientPair, err := tls.LoadX509KeyPair(certs.ClientCert, certs.ClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain client's certificate and/or key")
+	}
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{clientPair},
+	}
+	if certs.CACert != "" {
+		serverCA, err := ioutil.ReadFile(certs.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain server's CA certificate")
+		}
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM(serverCA) {
+			return nil, errors.New("failed to add server's CA certificate to pool")
+		}
+		tlsCfg.RootCAs = cp
+	}
+	return tlsCfg, nil
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *RemoteHTTP) FetchValidatingKeys() ([][48]byte, error) {
+	km.keysLock.RLock()
+	defer km.keysLock.RUnlock()
+	res := make([][48]byte, 0, len(km.keys))
+	for pubKey := range km.keys {
+		res = append(res, pubKey)
+	}
+	return res, nil
+}
+
+// Sign without protection is not supported by remote keymanagers.
+func (km *RemoteHTTP) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	return nil, errors.New("remote keymanager does not support unprotected signing")
+}
+
+// SignGeneric signs a generic root. Randao reveals and aggregation slot selection proofs are
+// recognised from their domain, and sent to the remote signer along with their epoch or slot.
+func (km *RemoteHTTP) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
+	signingRoot, err := ssz.HashTreeRoot(&pb.SigningData{
+		ObjectRoot: root[:],
+		Domain:     domain[:],
+	})
+	if err != nil {
+		return nil, err
+	}
+	req := &remoteHTTPSignRequest{
+		Type:        remoteHTTPTypeGeneric,
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+		Domain:      fmt.Sprintf("%#x", domain),
+	}
+	if value, ok := uint64FromRoot(root); ok {
+		domainType := bytesutil.ToBytes4(domain[:4])
+		switch domainType {
+		case params.BeaconConfig().DomainRandao:
+			req.Type = remoteHTTPTypeRandaoReveal
+			req.RandaoReveal = &remoteHTTPRandaoReveal{Epoch: value}
+		case params.BeaconConfig().DomainSelectionProof:
+			req.Type = remoteHTTPTypeAggregationSlot
+			req.AggregationSlot = &remoteHTTPAggregationSlot{Slot: value}
+		}
+	}
+	return km.sign(pubKey, req)
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *RemoteHTTP) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, &remoteHTTPSignRequest{
+		Type:        remoteHTTPTypeBlock,
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+		Domain:      fmt.Sprintf("%#x", domain),
+		Block: &remoteHTTPBlockHeader{
+			Slot:          data.Slot,
+			ProposerIndex: data.ProposerIndex,
+			ParentRoot:    fmt.Sprintf("%#x", data.ParentRoot),
+			StateRoot:     fmt.Sprintf("%#x", data.StateRoot),
+			BodyRoot:      fmt.Sprintf("%#x", data.BodyRoot),
+		},
+	})
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *RemoteHTTP) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, &remoteHTTPSignRequest{
+		Type:        remoteHTTPTypeAttestation,
+		SigningRoot: fmt.Sprintf("%#x", signingRoot),
+		Domain:      fmt.Sprintf("%#x", domain),
+		Attestation: &remoteHTTPAttestationData{
+			Slot:            data.Slot,
+			Index:           data.CommitteeIndex,
+			BeaconBlockRoot: fmt.Sprintf("%#x", data.BeaconBlockRoot),
+			Source: &remoteHTTPCheckpoint{
+				Epoch: data.Source.Epoch,
+				Root:  fmt.Sprintf("%#x", data.Source.Root),
+			},
+			Target: &remoteHTTPCheckpoint{
+				Epoch: data.Target.Epoch,
+				Root:  fmt.Sprintf("%#x", data.Target.Root),
+			},
+		},
+	})
+}
+
+// RefreshValidatingKeys refreshes the list of validating keys from the remote signer.
+func (km *RemoteHTTP) RefreshValidatingKeys() error {
+	ctx, cancel := context.WithTimeout(context.Background(), km.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, km.url+remoteHTTPPublicKeysPath, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := km.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("remote signer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var pubKeys []string
+	if err := json.Unmarshal(body, &pubKeys); err != nil {
+		return errors.Wrap(err, "could not decode public keys")
+	}
+	keys := make(map[[48]byte]bool, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		key, err := hex.DecodeString(strings.TrimPrefix(pubKey, "0x"))
+		if err != nil || len(key) != 48 {
+			log.WithField("pubKey", pubKey).Warn("Received invalid public key from server; ignoring")
+			continue
+		}
+		keys[bytesutil.ToBytes48(key)] = true
+	}
+
+	km.keysLock.Lock()
+	km.keys = keys
+	km.keysLock.Unlock()
+	return nil
+}
+
+// sign sends a signing request to the remote signer, and returns the signature of its response.
+func (km *RemoteHTTP) sign(pubKey [48]byte, signReq *remoteHTTPSignRequest) (*bls.Signature, error) {
+	km.keysLock.RLock()
+	known := km.keys[pubKey]
+	km.keysLock.RUnlock()
+	if !known {
+		return nil, ErrNoSuchKey
+	}
+
+	enc, err := json.Marshal(signReq)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), km.timeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s%#x", km.url, remoteHTTPSignPath, pubKey), bytes.NewReader(enc))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := km.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(ErrCannotSign, err.Error())
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Debug("Could not close response body")
+		}
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(ErrCannotSign, err.Error())
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ErrNoSuchKey
+	case http.StatusForbidden, http.StatusPreconditionFailed:
+		return nil, ErrDenied
+	default:
+		return nil, errors.Wrapf(ErrCannotSign, "remote signer returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(body)), "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode signature")
+	}
+	return bls.SignatureFromBytes(sig)
+}
+
+// uint64FromRoot returns the value of the hash tree root of an uint64, which is its little-endian
+// encoding padded to 32 bytes.
+func uint64FromRoot(root [32]byte) (uint64, bool) {
+	for _, b := range root[8:] {
+		if b != 0 {
+			return 0, false
+		}
+	}
+	return binary.LittleEndian.Uint64(root[:8]), true
+}
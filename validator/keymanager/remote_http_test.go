@@ -0,0 +1,295 @@
+package keymanager_test
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+// remoteSigner is an in-process remote signer, which signs the signing root of every request.
+type remoteSigner struct {
+	secretKey   *bls.SecretKey
+	delay       time.Duration
+	deny        bool
+	lastRequest map[string]interface{}
+	lock        sync.Mutex
+}
+
+func (s *remoteSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pubKey := fmt.Sprintf("%#x", s.secretKey.PublicKey().Marshal())
+	s.lock.Lock()
+	delay, deny := s.delay, s.deny
+	s.lock.Unlock()
+	time.Sleep(delay)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth2/publicKeys":
+		if err := json.NewEncoder(w).Encode([]string{pubKey, "0xinvalid"}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/eth2/sign/"+pubKey:
+		req := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.lock.Lock()
+		s.lastRequest = req
+		s.lock.Unlock()
+		if deny {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		signingRoot, err := hex.DecodeString(strings.TrimPrefix(req["signingRoot"].(string), "0x"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, err := fmt.Fprintf(w, "%#x", s.secretKey.Sign(signingRoot).Marshal()); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *remoteSigner) set(delay time.Duration, deny bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.delay = delay
+	s.deny = deny
+}
+
+func (s *remoteSigner) request() map[string]interface{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lastRequest
+}
+
+func newRemoteHTTP(t *testing.T, signer *remoteSigner, opts string) keymanager.ProtectingKeyManager {
+	km, _, err := keymanager.NewRemoteHTTP(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != bytesutil.ToBytes48(signer.secretKey.PublicKey().Marshal()) {
+		t.Fatalf("Unexpected validating keys %#x", keys)
+	}
+	protectingKM, ok := km.(keymanager.ProtectingKeyManager)
+	if !ok {
+		t.Fatal("Remote HTTP keymanager is not a protecting keymanager")
+	}
+	return protectingKM
+}
+
+func TestRemoteHTTP_Sign(t *testing.T) {
+	signer := &remoteSigner{secretKey: bls.RandKey()}
+	srv := httptest.NewServer(signer)
+	defer srv.Close()
+	km := newRemoteHTTP(t, signer, fmt.Sprintf(`{"url":%q}`, srv.URL))
+	pubKey := bytesutil.ToBytes48(signer.secretKey.PublicKey().Marshal())
+	domain := bytesutil.ToBytes32([]byte("domain"))
+
+	header := &ethpb.BeaconBlockHeader{
+		Slot:          5,
+		ProposerIndex: 2,
+		ParentRoot:    make([]byte, 32),
+		StateRoot:     make([]byte, 32),
+		BodyRoot:      make([]byte, 32),
+	}
+	sig, err := km.SignProposal(pubKey, domain, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err := helpers.ComputeSigningRoot(header, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(signer.secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Block signature does not verify")
+	}
+	req := signer.request()
+	if req["type"] != "BLOCK" || req["block"].(map[string]interface{})["slot"] != "5" {
+		t.Errorf("Unexpected block signing request %v", req)
+	}
+
+	data := &ethpb.AttestationData{
+		Slot:            6,
+		CommitteeIndex:  1,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 2, Root: make([]byte, 32)},
+	}
+	sig, err = km.SignAttestation(pubKey, domain, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err = helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(signer.secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Attestation signature does not verify")
+	}
+	req = signer.request()
+	if req["type"] != "ATTESTATION" || req["attestation"].(map[string]interface{})["target"].(map[string]interface{})["epoch"] != "2" {
+		t.Errorf("Unexpected attestation signing request %v", req)
+	}
+}
+
+func TestRemoteHTTP_SignGeneric(t *testing.T) {
+	signer := &remoteSigner{secretKey: bls.RandKey()}
+	srv := httptest.NewServer(signer)
+	defer srv.Close()
+	km := newRemoteHTTP(t, signer, fmt.Sprintf(`{"url":%q}`, srv.URL))
+	pubKey := bytesutil.ToBytes48(signer.secretKey.PublicKey().Marshal())
+
+	randaoDomain := params.BeaconConfig().DomainRandao
+	selectionDomain := params.BeaconConfig().DomainSelectionProof
+	aggregateDomain := params.BeaconConfig().DomainAggregateAndProof
+	tests := []struct {
+		name       string
+		root       [32]byte
+		domain     [32]byte
+		wantType   string
+		wantObject string
+	}{
+		{
+			name:       "randao reveal",
+			root:       bytesutil.ToBytes32(bytesutil.Bytes8(7)),
+			domain:     bytesutil.ToBytes32(randaoDomain[:]),
+			wantType:   "RANDAO_REVEAL",
+			wantObject: "randao_reveal",
+		},
+		{
+			name:       "aggregation slot",
+			root:       bytesutil.ToBytes32(bytesutil.Bytes8(7)),
+			domain:     bytesutil.ToBytes32(selectionDomain[:]),
+			wantType:   "AGGREGATION_SLOT",
+			wantObject: "aggregation_slot",
+		},
+		{
+			name:     "generic",
+			root:     bytesutil.ToBytes32([]byte("aggregate and proof root")),
+			domain:   bytesutil.ToBytes32(aggregateDomain[:]),
+			wantType: "GENERIC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := km.SignGeneric(pubKey, tt.root, tt.domain); err != nil {
+				t.Fatal(err)
+			}
+			req := signer.request()
+			if req["type"] != tt.wantType {
+				t.Errorf("Expected type %s, received %v", tt.wantType, req["type"])
+			}
+			if tt.wantObject != "" {
+				object, ok := req[tt.wantObject].(map[string]interface{})
+				if !ok {
+					t.Fatalf("Expected %s in request %v", tt.wantObject, req)
+				}
+				for _, v := range object {
+					if v != "7" {
+						t.Errorf("Expected value 7, received %v", v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRemoteHTTP_Errors(t *testing.T) {
+	signer := &remoteSigner{secretKey: bls.RandKey()}
+	srv := httptest.NewServer(signer)
+	defer srv.Close()
+	km := newRemoteHTTP(t, signer, fmt.Sprintf(`{"url":%q,"timeout":"100ms"}`, srv.URL))
+	pubKey := bytesutil.ToBytes48(signer.secretKey.PublicKey().Marshal())
+	root := bytesutil.ToBytes32([]byte("root"))
+	domain := bytesutil.ToBytes32([]byte("domain"))
+
+	if _, err := km.SignGeneric(bytesutil.ToBytes48([]byte("unknown")), root, domain); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Expected %v, received %v", keymanager.ErrNoSuchKey, err)
+	}
+
+	signer.set(0, true)
+	if _, err := km.SignGeneric(pubKey, root, domain); err != keymanager.ErrDenied {
+		t.Errorf("Expected %v, received %v", keymanager.ErrDenied, err)
+	}
+
+	signer.set(time.Second, false)
+	if _, err := km.SignGeneric(pubKey, root, domain); err == nil {
+		t.Error("Expected request to time out")
+	}
+}
+
+func TestRemoteHTTP_ClientCertificates(t *testing.T) {
+	signer := &remoteSigner{secretKey: bls.RandKey()}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		signer.ServeHTTP(w, r)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	dir := filepath.Join(testutil.TempDir(), "remotehttp")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Logf("Could not remove directory: %v", err)
+		}
+	}()
+	caCertPath := filepath.Join(dir, "ca.crt")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := ioutil.WriteFile(caCertPath, caCert, 0600); err != nil {
+		t.Fatal(err)
+	}
+	clientCertPath := filepath.Join(dir, "client.crt")
+	if err := ioutil.WriteFile(clientCertPath, []byte(validClientCert), 0600); err != nil {
+		t.Fatal(err)
+	}
+	clientKeyPath := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(clientKeyPath, []byte(validClientKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := fmt.Sprintf(
+		`{"url":%q,"certificates":{"ca_cert":%q,"client_cert":%q,"client_key":%q}}`,
+		srv.URL,
+		caCertPath,
+		clientCertPath,
+		clientKeyPath,
+	)
+	km := newRemoteHTTP(t, signer, opts)
+	pubKey := bytesutil.ToBytes48(signer.secretKey.PublicKey().Marshal())
+	if _, err := km.SignGeneric(pubKey, bytesutil.ToBytes32([]byte("root")), bytesutil.ToBytes32([]byte("domain"))); err != nil {
+		t.Fatal(err)
+	}
+}
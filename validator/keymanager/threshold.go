@@ -0,0 +1,366 @@
+package keymanager
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// defaultThresholdTimeout is the default time to wait for the partial signatures of the signers.
+const defaultThresholdTimeout = 2 * time.Second
+
+// Threshold is a key manager whose keys are split into t-of-n Shamir shares, each held by a
+// separate signer. It signs by gathering partial signatures from the signers and combining any
+// threshold of them into the signature of the key.
+type Threshold struct {
+	signers  []*thresholdSigner
+	accounts map[[48]byte]*thresholdAccount
+	timeout  time.Duration
+}
+
+type thresholdSigner struct {
+	id      uint64
+	address string
+	client  pb.SignerClient
+}
+
+type thresholdAccount struct {
+	name      string
+	threshold int
+	publicKey *bls.PublicKey
+	// shareKeys are the public keys of the shares, by share id.
+	shareKeys map[uint64]*bls.PublicKey
+}
+
+type thresholdOpts struct {
+	Signers      []*thresholdSignerOpts  `json:"signers"`
+	Accounts     []*thresholdAccountOpts `json:"accounts"`
+	Timeout      string                  `json:"timeout"`
+	Certificates *remoteCertificateOpts  `json:"certificates"`
+}
+
+type thresholdSignerOpts struct {
+	ID      uint64 `json:"id"`
+	Address string `json:"address"`
+}
+
+type thresholdAccountOpts struct {
+	VerificationVector []string `json:"verification_vector"`
+}
+
+// thresholdPartial is the partial signature of a signer.
+type thresholdPartial struct {
+	id    uint64
+	sig   *bls.Signature
+	state pb.ResponseState
+	err   error
+}
+
+var thresholdOptsHelp = `The threshold key manager signs with keys split into t-of-n shares by
+"validator accounts threshold-split", each held by a "validator threshold-signer".  The options are:
+  - signers This is the list of signers, with the share id and the address of each signer
+  - accounts This is the list of split keys.  The verification vector of a key is written by
+    "validator accounts threshold-split".  Its first entry is the public key of the key, and its
+    length is the number of signers needed to sign
+  - timeout This is the time to wait for the signers, for example "1s".  Defaults to 2s
+  - certificates This provides paths to certificates, and is optional:
+    - ca_cert This is the path to the signers' certificate authority certificate file
+    - client_cert This is the path to the client's certificate file
+    - client_key This is the path to the client's key file
+
+A sample keymanager options file (with annotations; these should be removed if
+using this as a template) is:
+
+  {
+    "signers": [
+      {"id": 1, "address": "signer1.example.com:4500"}, // Signer holding share 1
+      {"id": 2, "address": "signer2.example.com:4500"}, // Signer holding share 2
+      {"id": 3, "address": "signer3.example.com:4500"}  // Signer holding share 3
+    ],
+    "accounts": [
+      {"verification_vector": ["0xa99a...", "0xb0f1..."]} // Key 0xa99a..., signed by any 2 of the signers
+    ],
+    "timeout": "1s"
+  }`
+
+// NewThreshold creates a key manager signing with the shares held by threshold signers.
+func NewThreshold(input string) (KeyManager, string, error) {
+	opts := &thresholdOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, thresholdOptsHelp, err
+	}
+	if len(opts.Signers) == 0 {
+		return nil, thresholdOptsHelp, errors.New("at least one signer is required")
+	}
+	if len(opts.Accounts) == 0 {
+		return nil, thresholdOptsHelp, errors.New("at least one account is required")
+	}
+	timeout := defaultThresholdTimeout
+	if opts.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(opts.Timeout)
+		if err != nil {
+			return nil, thresholdOptsHelp, errors.Wrap(err, "invalid timeout")
+		}
+	}
+
+	grpcOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if opts.Certificates != nil {
+		tlsCfg, err := remoteHTTPTLSConfig(opts.Certificates)
+		if err != nil {
+			return nil, thresholdOptsHelp, err
+		}
+		grpcOpts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg))}
+	} else {
+		log.Warn("No certificates provided for the threshold signers, connecting without TLS")
+	}
+	signers := make([]*thresholdSigner, len(opts.Signers))
+	for i, signer := range opts.Signers {
+		// Dialing does not block, so that the key manager starts while some signers are offline.
+		conn, err := grpc.Dial(signer.Address, grpcOpts...)
+		if err != nil {
+			return nil, thresholdOptsHelp, errors.Wrapf(err, "failed to connect to signer at %s", signer.Address)
+		}
+		signers[i] = &thresholdSigner{
+			id:      signer.ID,
+			address: signer.Address,
+			client:  pb.NewSignerClient(conn),
+		}
+	}
+	km, err := newThreshold(signers, opts.Accounts, timeout)
+	if err != nil {
+		return nil, thresholdOptsHelp, err
+	}
+	return km, thresholdOptsHelp, nil
+}
+
+func newThreshold(signers []*thresholdSigner, accounts []*thresholdAccountOpts, timeout time.Duration) (*Threshold, error) {
+	ids := make(map[uint64]bool, len(signers))
+	for _, signer := range signers {
+		if signer.id == 0 || ids[signer.id] {
+			return nil, fmt.Errorf("invalid or duplicate share id %d of signer %s", signer.id, signer.address)
+		}
+		ids[signer.id] = true
+	}
+	km := &Threshold{
+		signers:  signers,
+		accounts: make(map[[48]byte]*thresholdAccount, len(accounts)),
+		timeout:  timeout,
+	}
+	for _, account := range accounts {
+		if len(account.VerificationVector) == 0 || len(account.VerificationVector) > len(signers) {
+			return nil, fmt.Errorf("verification vector must have between 1 and %d entries", len(signers))
+		}
+		verificationVector := make([]*bls.PublicKey, len(account.VerificationVector))
+		for i, key := range account.VerificationVector {
+			enc, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid verification vector entry %s", key)
+			}
+			verificationVector[i], err = bls.PublicKeyFromBytes(enc)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid verification vector entry %s", key)
+			}
+		}
+		pubKey := bytesutil.ToBytes48(verificationVector[0].Marshal())
+		shareKeys := make(map[uint64]*bls.PublicKey, len(signers))
+		for _, signer := range signers {
+			shareKey, err := bls.PublicKeyShare(verificationVector, signer.id)
+			if err != nil {
+				return nil, err
+			}
+			shareKeys[signer.id] = shareKey
+		}
+		km.accounts[pubKey] = &thresholdAccount{
+			// Signers know the shares by the public key of the split key.
+			name:      fmt.Sprintf("%#x", pubKey),
+			threshold: len(verificationVector),
+			publicKey: verificationVector[0],
+			shareKeys: shareKeys,
+		}
+	}
+	return km, nil
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *Threshold) FetchValidatingKeys() ([][48]byte, error) {
+	res := make([][48]byte, 0, len(km.accounts))
+	for pubKey := range km.accounts {
+		res = append(res, pubKey)
+	}
+	return res, nil
+}
+
+// Sign without protection is not supported by threshold keymanagers.
+func (km *Threshold) Sign(pubKey [48]byte, root [32]byte) (*bls.Signature, error) {
+	return nil, errors.New("threshold keymanager does not support unprotected signing")
+}
+
+// SignGeneric signs a generic message for the validator to broadcast.
+func (km *Threshold) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (*bls.Signature, error) {
+	signingRoot, err := ssz.HashTreeRoot(&p2ppb.SigningData{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, signingRoot, func(ctx context.Context, client pb.SignerClient, account string) (*pb.SignResponse, error) {
+		return client.Sign(ctx, &pb.SignRequest{
+			Id:     &pb.SignRequest_Account{Account: account},
+			Data:   root[:],
+			Domain: domain[:],
+		})
+	})
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *Threshold) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, signingRoot, func(ctx context.Context, client pb.SignerClient, account string) (*pb.SignResponse, error) {
+		return client.SignBeaconProposal(ctx, &pb.SignBeaconProposalRequest{
+			Id:     &pb.SignBeaconProposalRequest_Account{Account: account},
+			Domain: domain[:],
+			Data: &pb.BeaconBlockHeader{
+				Slot:          data.Slot,
+				ProposerIndex: data.ProposerIndex,
+				ParentRoot:    data.ParentRoot,
+				StateRoot:     data.StateRoot,
+				BodyRoot:      data.BodyRoot,
+			},
+		})
+	})
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *Threshold) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (*bls.Signature, error) {
+	signingRoot, err := helpers.ComputeSigningRoot(data, domain[:])
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, signingRoot, func(ctx context.Context, client pb.SignerClient, account string) (*pb.SignResponse, error) {
+		return client.SignBeaconAttestation(ctx, &pb.SignBeaconAttestationRequest{
+			Id:     &pb.SignBeaconAttestationRequest_Account{Account: account},
+			Domain: domain[:],
+			Data: &pb.AttestationData{
+				Slot:            data.Slot,
+				CommitteeIndex:  data.CommitteeIndex,
+				BeaconBlockRoot: data.BeaconBlockRoot,
+				Source: &pb.Checkpoint{
+					Epoch: data.Source.Epoch,
+					Root:  data.Source.Root,
+				},
+				Target: &pb.Checkpoint{
+					Epoch: data.Target.Epoch,
+					Root:  data.Target.Root,
+				},
+			},
+		})
+	})
+}
+
+// sign requests partial signatures of the signing root from all signers, and combines the first
+// threshold valid ones into the signature of the key.
+func (km *Threshold) sign(
+	pubKey [48]byte,
+	signingRoot [32]byte,
+	request func(ctx context.Context, client pb.SignerClient, account string) (*pb.SignResponse, error),
+) (*bls.Signature, error) {
+	account, exists := km.accounts[pubKey]
+	if !exists {
+		return nil, ErrNoSuchKey
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), km.timeout)
+	defer cancel()
+	partials := make(chan *thresholdPartial, len(km.signers))
+	for _, signer := range km.signers {
+		go func(signer *thresholdSigner) {
+			partials <- km.partialSignature(ctx, signer, account, signingRoot, request)
+		}(signer)
+	}
+
+	sigs := make([]*bls.Signature, 0, account.threshold)
+	ids := make([]uint64, 0, account.threshold)
+	denied := 0
+	for range km.signers {
+		partial := <-partials
+		if partial.err != nil || partial.state != pb.ResponseState_SUCCEEDED {
+			if partial.state == pb.ResponseState_DENIED {
+				denied++
+			}
+			log.WithFields(logrus.Fields{
+				"pubKey":  fmt.Sprintf("%#x", pubKey),
+				"shareId": partial.id,
+				"state":   partial.state,
+			}).WithError(partial.err).Debug("Did not receive partial signature")
+			continue
+		}
+		sigs = append(sigs, partial.sig)
+		ids = append(ids, partial.id)
+		if len(sigs) == account.threshold {
+			break
+		}
+	}
+	if len(sigs) < account.threshold {
+		if len(km.signers)-denied < account.threshold {
+			return nil, ErrDenied
+		}
+		return nil, errors.Wrapf(ErrCannotSign, "received %d of %d required partial signatures", len(sigs), account.threshold)
+	}
+	sig, err := bls.RecoverSignature(sigs, ids)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(account.publicKey, signingRoot[:]) {
+		return nil, errors.Wrap(ErrCannotSign, "combined signature is invalid")
+	}
+	return sig, nil
+}
+
+// partialSignature requests the partial signature of a signer and verifies it against the
+// public key of the signer's share.
+func (km *Threshold) partialSignature(
+	ctx context.Context,
+	signer *thresholdSigner,
+	account *thresholdAccount,
+	signingRoot [32]byte,
+	request func(ctx context.Context, client pb.SignerClient, account string) (*pb.SignResponse, error),
+) *thresholdPartial {
+	partial := &thresholdPartial{id: signer.id}
+	resp, err := request(ctx, signer.client, account.name)
+	if err != nil {
+		partial.err = err
+		return partial
+	}
+	partial.state = resp.State
+	if resp.State != pb.ResponseState_SUCCEEDED {
+		return partial
+	}
+	sig, err := bls.SignatureFromBytes(resp.Signature)
+	if err != nil {
+		partial.err = err
+		return partial
+	}
+	if !sig.Verify(account.shareKeys[signer.id], signingRoot[:]) {
+		partial.err = errors.New("invalid partial signature")
+		return partial
+	}
+	partial.sig = sig
+	return partial
+}
@@ -0,0 +1,190 @@
+package keymanager_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/thresholdsigner"
+)
+
+// startThresholdSigners splits a new key 3-of-5 and starts an in-process signer for each share,
+// except those whose share id is offline. It returns the keymanager options and the key.
+func startThresholdSigners(t *testing.T, offline ...uint64) (string, *bls.SecretKey) {
+	secretKey := bls.RandKey()
+	shareFiles, splitKeys, err := thresholdsigner.SplitKeys([]*bls.SecretKey{secretKey}, 3, 5, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	isOffline := make(map[uint64]bool)
+	for _, id := range offline {
+		isOffline[id] = true
+	}
+	type signerOpts struct {
+		ID      uint64 `json:"id"`
+		Address string `json:"address"`
+	}
+	var signers []*signerOpts
+	for _, shareFile := range shareFiles {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		signers = append(signers, &signerOpts{ID: shareFile.ID, Address: lis.Addr().String()})
+		if isOffline[shareFile.ID] {
+			if err := lis.Close(); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		dataDir, err := ioutil.TempDir("", "threshold-signer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := os.RemoveAll(dataDir); err != nil {
+				t.Error(err)
+			}
+		})
+		server, err := thresholdsigner.NewServer(shareFile, "password", dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		server.Start(lis)
+		t.Cleanup(server.Stop)
+	}
+	verificationVector := make([]string, len(splitKeys[0].VerificationVector))
+	for i, pub := range splitKeys[0].VerificationVector {
+		verificationVector[i] = fmt.Sprintf("%#x", pub.Marshal())
+	}
+	opts, err := json.Marshal(map[string]interface{}{
+		"signers": signers,
+		"accounts": []map[string]interface{}{
+			{"verification_vector": verificationVector},
+		},
+		"timeout": "5s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(opts), secretKey
+}
+
+func TestThreshold_SignWithOfflineSigners(t *testing.T) {
+	opts, secretKey := startThresholdSigners(t, 2, 4)
+	km, _, err := keymanager.NewThreshold(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubKey [48]byte
+	copy(pubKey[:], secretKey.PublicKey().Marshal())
+	if len(keys) != 1 || keys[0] != pubKey {
+		t.Fatalf("Wanted validating key %#x, received %#x", pubKey, keys)
+	}
+
+	protectingKM, ok := km.(keymanager.ProtectingKeyManager)
+	if !ok {
+		t.Fatal("Threshold keymanager is not a protecting keymanager")
+	}
+	var domain [32]byte
+	copy(domain[:], params.BeaconConfig().DomainBeaconProposer[:])
+	header := &ethpb.BeaconBlockHeader{Slot: 10, ProposerIndex: 3, ParentRoot: make([]byte, 32), StateRoot: make([]byte, 32), BodyRoot: make([]byte, 32)}
+	sig, err := protectingKM.SignProposal(pubKey, domain, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err := helpers.ComputeSigningRoot(header, domain[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Combined proposal signature does not verify")
+	}
+
+	root := [32]byte{'r', 'o', 'o', 't'}
+	sig, err = protectingKM.SignGeneric(pubKey, root, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingRoot, err = ssz.HashTreeRoot(&pb.SigningData{ObjectRoot: root[:], Domain: domain[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(secretKey.PublicKey(), signingRoot[:]) {
+		t.Error("Combined generic signature does not verify")
+	}
+}
+
+func TestThreshold_TooManySignersOffline(t *testing.T) {
+	opts, secretKey := startThresholdSigners(t, 1, 3, 5)
+	km, _, err := keymanager.NewThreshold(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubKey [48]byte
+	copy(pubKey[:], secretKey.PublicKey().Marshal())
+	_, err = km.(keymanager.ProtectingKeyManager).SignGeneric(pubKey, [32]byte{}, [32]byte{})
+	if !errors.Is(err, keymanager.ErrCannotSign) {
+		t.Errorf("Wanted %v with 2 of 5 signers online, received %v", keymanager.ErrCannotSign, err)
+	}
+}
+
+func TestThreshold_SignersDenyConflictingProposal(t *testing.T) {
+	opts, secretKey := startThresholdSigners(t, 5)
+	km, _, err := keymanager.NewThreshold(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	protectingKM := km.(keymanager.ProtectingKeyManager)
+	var pubKey [48]byte
+	copy(pubKey[:], secretKey.PublicKey().Marshal())
+	header := &ethpb.BeaconBlockHeader{Slot: 10, ParentRoot: make([]byte, 32), StateRoot: make([]byte, 32), BodyRoot: make([]byte, 32)}
+	if _, err := protectingKM.SignProposal(pubKey, [32]byte{}, header); err != nil {
+		t.Fatal(err)
+	}
+	// Signing the same proposal again is not slashable.
+	if _, err := protectingKM.SignProposal(pubKey, [32]byte{}, header); err != nil {
+		t.Fatal(err)
+	}
+	bodyRoot := [32]byte{'b', 'o', 'd', 'y'}
+	header.BodyRoot = bodyRoot[:]
+	if _, err := protectingKM.SignProposal(pubKey, [32]byte{}, header); err != keymanager.ErrDenied {
+		t.Errorf("Wanted %v for a conflicting proposal, received %v", keymanager.ErrDenied, err)
+	}
+}
+
+func TestNewThreshold_InvalidOpts(t *testing.T) {
+	tests := []struct {
+		name string
+		opts string
+	}{
+		{name: "Empty", opts: ``},
+		{name: "NoSigners", opts: `{"accounts":[{"verification_vector":["0x00"]}]}`},
+		{name: "NoAccounts", opts: `{"signers":[{"id":1,"address":"127.0.0.1:1"}]}`},
+		{name: "ShareIDZero", opts: fmt.Sprintf(`{"signers":[{"id":0,"address":"127.0.0.1:1"}],"accounts":[{"verification_vector":["%#x"]}]}`, bls.RandKey().PublicKey().Marshal())},
+		{name: "ThresholdAboveSigners", opts: fmt.Sprintf(`{"signers":[{"id":1,"address":"127.0.0.1:1"}],"accounts":[{"verification_vector":["%#x","%#x"]}]}`, bls.RandKey().PublicKey().Marshal(), bls.RandKey().PublicKey().Marshal())},
+		{name: "InvalidTimeout", opts: `{"signers":[{"id":1,"address":"127.0.0.1:1"}],"accounts":[{"verification_vector":["0x00"]}],"timeout":"soon"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := keymanager.NewThreshold(tt.opts); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
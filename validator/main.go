@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"runtime"
@@ -18,10 +19,12 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
 	"github.com/prysmaticlabs/prysm/shared/logutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
 	"github.com/prysmaticlabs/prysm/validator/client/streaming"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/node"
@@ -46,8 +49,10 @@ func startNode(ctx *cli.Context) error {
 
 var appFlags = []cli.Flag{
 	flags.BeaconRPCProviderFlag,
+	flags.BroadcastSubmissionsFlag,
 	flags.CertFlag,
 	flags.GraffitiFlag,
+	flags.GraffitiFileFlag,
 	flags.KeystorePathFlag,
 	flags.SourceDirectories,
 	flags.SourceDirectory,
@@ -59,6 +64,15 @@ var appFlags = []cli.Flag{
 	flags.InteropNumValidators,
 	flags.GrpcRetriesFlag,
 	flags.GrpcHeadersFlag,
+	flags.DoppelgangerEpochsFlag,
+	flags.ShadowModeFlag,
+	flags.ManagementAPIFlag,
+	flags.ManagementAPIHostFlag,
+	flags.ManagementAPIPortFlag,
+	flags.ManagementAPITokenFileFlag,
+	flags.ManagementKeysPasswordFileFlag,
+	flags.AuditLogDirFlag,
+	flags.AuditLogMaxSizeFlag,
 	flags.KeyManager,
 	flags.KeyManagerOpts,
 	flags.DisableAccountMetricsFlag,
@@ -133,6 +147,173 @@ contract in order to activate the validator client`,
 						return nil
 					},
 				},
+				{
+					Name:        "import",
+					Description: "imports EIP-2335 keystores into the validator keystore",
+					Flags: []cli.Flag{
+						flags.KeysDirFlag,
+						flags.KeysPasswordFlag,
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						keysDir := cliCtx.String(flags.KeysDirFlag.Name)
+						if keysDir == "" {
+							log.Error("Please specify the EIP-2335 keystores to import with --keys-dir")
+							return nil
+						}
+						keysPassword, err := accounts.HandleEmptyPasswordFlag(cliCtx, flags.KeysPasswordFlag,
+							"Please enter the password of the keystores to import", false /*confirmPassword*/)
+						if err != nil {
+							log.WithError(err).Error("Could not read the keystores password")
+							return nil
+						}
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, true /*confirmPassword*/)
+						if err != nil {
+							log.WithError(err).Error("Could not read keystore path and/or password")
+							return nil
+						}
+						imported, err := accounts.ImportEIP2335Keystores(keysDir, keysPassword, keystorePath, passphrase)
+						if err != nil {
+							log.WithError(err).Errorf("Could not import keystores from %s", keysDir)
+							return nil
+						}
+						log.WithField("count", len(imported)).Info("Import completed successfully")
+						return nil
+					},
+				},
+				{
+					Name:        "export",
+					Description: "exports the validator keystore keys as EIP-2335 keystores",
+					Flags: []cli.Flag{
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.KeysDirFlag,
+						flags.KeysPasswordFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						keysDir := cliCtx.String(flags.KeysDirFlag.Name)
+						if keysDir == "" {
+							log.Error("Please specify the directory to export keystores to with --keys-dir")
+							return nil
+						}
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						if err != nil {
+							log.WithError(err).Error("Could not read keystore path and/or password")
+							return nil
+						}
+						keysPassword, err := accounts.HandleEmptyPasswordFlag(cliCtx, flags.KeysPasswordFlag,
+							"Please enter the password to encrypt the exported keystores with", true /*confirmPassword*/)
+						if err != nil {
+							log.WithError(err).Error("Could not read the keystores password")
+							return nil
+						}
+						exported, err := accounts.ExportEIP2335Keystores(keystorePath, passphrase, keysDir, keysPassword, keystore.KDFScrypt)
+						if err != nil {
+							log.WithError(err).Errorf("Could not export keystores to %s", keysDir)
+							return nil
+						}
+						log.WithField("count", len(exported)).Info("Export completed successfully")
+						return nil
+					},
+				},
+				{
+					Name:        "derived",
+					Description: "manages validator accounts derived from a mnemonic, following EIP-2333 and EIP-2334",
+					Subcommands: []*cli.Command{
+						{
+							Name: "create",
+							Description: `creates a new derived wallet and prints its mnemonic, or derives more accounts
+if a wallet already exists at the keystore path`,
+							Flags: []cli.Flag{
+								flags.KeystorePathFlag,
+								flags.PasswordFlag,
+								flags.NumAccountsFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								walletDir, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, true /*confirmPassword*/)
+								if err != nil {
+									log.WithError(err).Error("Could not read keystore path and/or password")
+									return nil
+								}
+								numAccounts := cliCtx.Uint64(flags.NumAccountsFlag.Name)
+								if _, err := accounts.ListDerivedAccounts(walletDir); err == nil {
+									derived, err := accounts.CreateDerivedAccounts(walletDir, passphrase, numAccounts)
+									if err != nil {
+										log.WithError(err).Errorf("Could not derive accounts in wallet at path %s", walletDir)
+										return nil
+									}
+									accounts.PrintDerivedAccounts(derived)
+									return nil
+								}
+								mnemonic, derived, err := accounts.CreateDerivedWallet(walletDir, passphrase, numAccounts)
+								if err != nil {
+									log.WithError(err).Errorf("Could not create wallet at path %s", walletDir)
+									return nil
+								}
+								fmt.Printf(`
+==========================Mnemonic=========================
+
+%s
+
+===================================================================
+`, mnemonic)
+								fmt.Println("***Write down the mnemonic above and keep it safe, it is the only way to recover your accounts***")
+								accounts.PrintDerivedAccounts(derived)
+								return nil
+							},
+						},
+						{
+							Name:        "recover",
+							Description: "recovers a derived wallet and its accounts from a mnemonic",
+							Flags: []cli.Flag{
+								flags.KeystorePathFlag,
+								flags.PasswordFlag,
+								flags.MnemonicFileFlag,
+								flags.NumAccountsFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								mnemonic, err := accounts.ReadMnemonic(cliCtx.String(flags.MnemonicFileFlag.Name))
+								if err != nil {
+									log.WithError(err).Error("Could not read mnemonic")
+									return nil
+								}
+								walletDir, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, true /*confirmPassword*/)
+								if err != nil {
+									log.WithError(err).Error("Could not read keystore path and/or password")
+									return nil
+								}
+								derived, err := accounts.RecoverDerivedWallet(walletDir, mnemonic, passphrase, cliCtx.Uint64(flags.NumAccountsFlag.Name))
+								if err != nil {
+									log.WithError(err).Errorf("Could not recover wallet at path %s", walletDir)
+									return nil
+								}
+								accounts.PrintDerivedAccounts(derived)
+								return nil
+							},
+						},
+						{
+							Name:        "list",
+							Description: "lists the accounts of a derived wallet",
+							Flags: []cli.Flag{
+								flags.KeystorePathFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								walletDir := cliCtx.String(flags.KeystorePathFlag.Name)
+								if walletDir == "" {
+									walletDir = accounts.DefaultValidatorDir()
+								}
+								derived, err := accounts.ListDerivedAccounts(walletDir)
+								if err != nil {
+									log.WithError(err).Errorf("Could not list accounts of wallet at path %s", walletDir)
+									return nil
+								}
+								accounts.PrintDerivedAccounts(derived)
+								return nil
+							},
+						},
+					},
+				},
 				{
 					Name:        "keys",
 					Description: `lists the private keys for 'keystore' keymanager keys`,
@@ -188,7 +369,12 @@ contract in order to activate the validator client`,
 							strings.Split(cliCtx.String(flags.GrpcHeadersFlag.Name), ","),
 							cliCtx.Uint(flags.GrpcRetriesFlag.Name),
 							grpc.WithBlock())
-						endpoint := cliCtx.String(flags.BeaconRPCProviderFlag.Name)
+						endpoints := failover.Endpoints(cliCtx.String(flags.BeaconRPCProviderFlag.Name))
+						if len(endpoints) == 0 {
+							return fmt.Errorf("no beacon node endpoint provided")
+						}
+						// Only the first beacon node is needed to report the status.
+						endpoint := endpoints[0]
 						conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
 						if err != nil {
 							log.WithError(err).Errorf("Failed to dial beacon node endpoint at %s", endpoint)
@@ -201,6 +387,67 @@ contract in order to activate the validator client`,
 						return err
 					},
 				},
+				{
+					Name: "deposit-data",
+					Description: `generates a deposit_data-*.json file for the validating keys of the selected keymanager,
+as accepted by the staking launchpad. The deposit signatures are verified before the file is written`,
+					Flags: []cli.Flag{
+						flags.KeyManager,
+						flags.KeyManagerOpts,
+						flags.WithdrawalPubKeyFlag,
+						flags.WithdrawalCredentialsFlag,
+						flags.DepositAmountFlag,
+						flags.ForkVersionFlag,
+						flags.DepositDataDirFlag,
+						flags.AuditLogDirFlag,
+						flags.AuditLogMaxSizeFlag,
+						cmd.ChainConfigFileFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+							params.LoadChainConfigFile(cliCtx.String(cmd.ChainConfigFileFlag.Name))
+						}
+						return node.GenerateDepositData(cliCtx)
+					},
+				},
+				{
+					Name: "exit",
+					Description: `submits voluntary exits for validating keys of the selected keymanager, after checking
+that they have been active long enough to exit and asking for confirmation. Exits cannot be undone`,
+					Flags: []cli.Flag{
+						flags.KeyManager,
+						flags.KeyManagerOpts,
+						flags.ExitPubKeysFlag,
+						flags.BeaconRPCProviderFlag,
+						flags.CertFlag,
+						flags.GrpcHeadersFlag,
+						flags.GrpcRetriesFlag,
+						cmd.GrpcMaxCallRecvMsgSizeFlag,
+						flags.AuditLogDirFlag,
+						flags.AuditLogMaxSizeFlag,
+						cmd.ChainConfigFileFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						if cliCtx.IsSet(cmd.ChainConfigFileFlag.Name) {
+							params.LoadChainConfigFile(cliCtx.String(cmd.ChainConfigFileFlag.Name))
+						}
+						return node.ExitValidators(cliCtx)
+					},
+				},
+				{
+					Name: "threshold-split",
+					Description: `splits the keys of the keystore into shares for threshold signers, any threshold of which can
+sign with a key. Writes a share file for each signer and the options of the threshold keymanager`,
+					Flags: []cli.Flag{
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+						flags.ThresholdFlag,
+						flags.ThresholdSignersFlag,
+						flags.ThresholdOutputDirFlag,
+						flags.ThresholdSharePasswordFileFlag,
+					},
+					Action: node.SplitThresholdKeys,
+				},
 				{
 					Name:        "change-password",
 					Description: "changes password for all keys located in a keystore",
@@ -271,7 +518,121 @@ contract in order to activate the validator client`,
 						return nil
 					},
 				},
+				{
+					Name:        "slashing-protection",
+					Description: "imports and exports the slashing protection history of the validator database in the interchange format",
+					Subcommands: []*cli.Command{
+						{
+							Name:        "export",
+							Description: "exports the slashing protection history of the validator database to an interchange JSON file",
+							Flags: []cli.Flag{
+								cmd.DataDirFlag,
+								flags.SlashingProtectionFileFlag,
+								flags.GenesisValidatorsRootFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								var genesisValidatorsRoot []byte
+								if cliCtx.IsSet(flags.GenesisValidatorsRootFlag.Name) {
+									root, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(flags.GenesisValidatorsRootFlag.Name), "0x"))
+									if err != nil {
+										log.WithError(err).Error("Could not decode genesis validators root")
+										return nil
+									}
+									genesisValidatorsRoot = root
+								}
+								dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+								outputFile := cliCtx.String(flags.SlashingProtectionFileFlag.Name)
+
+								if err := accounts.ExportSlashingProtection(context.Background(), dataDir, outputFile, genesisValidatorsRoot); err != nil {
+									log.WithError(err).Error("Exporting slashing protection history failed")
+								} else {
+									log.WithField("path", outputFile).Info("Export completed successfully")
+								}
+
+								return nil
+							},
+						},
+						{
+							Name:        "import",
+							Description: "merges the slashing protection history of an interchange JSON file into the validator database",
+							Flags: []cli.Flag{
+								cmd.DataDirFlag,
+								flags.SlashingProtectionFileFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+								inputFile := cliCtx.String(flags.SlashingProtectionFileFlag.Name)
+
+								if err := accounts.ImportSlashingProtection(context.Background(), dataDir, inputFile); err != nil {
+									log.WithError(err).Error("Importing slashing protection history failed")
+								} else {
+									log.Info("Import completed successfully")
+								}
+
+								return nil
+							},
+						},
+					},
+				},
+				{
+					Name:        "audit-log",
+					Description: "verifies the signing audit log and rebuilds slashing protection history from it",
+					Subcommands: []*cli.Command{
+						{
+							Name:        "verify",
+							Description: "checks that the hash chain of the signing audit log is intact and has no gaps",
+							Flags: []cli.Flag{
+								flags.AuditLogDirFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								// The error is returned so that scripts checking the log see a non-zero exit code.
+								return accounts.VerifyAuditLog(cliCtx.String(flags.AuditLogDirFlag.Name))
+							},
+						},
+						{
+							Name:        "rebuild-slashing-protection",
+							Description: "merges the blocks and attestations recorded in the signing audit log into the validator database",
+							Flags: []cli.Flag{
+								cmd.DataDirFlag,
+								flags.AuditLogDirFlag,
+								flags.GenesisValidatorsRootFlag,
+							},
+							Action: func(cliCtx *cli.Context) error {
+								genesisValidatorsRoot, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(flags.GenesisValidatorsRootFlag.Name), "0x"))
+								if err != nil {
+									log.WithError(err).Error("Could not decode genesis validators root")
+									return nil
+								}
+								dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+								auditDir := cliCtx.String(flags.AuditLogDirFlag.Name)
+
+								if err := accounts.RebuildSlashingProtection(context.Background(), dataDir, auditDir, genesisValidatorsRoot); err != nil {
+									log.WithError(err).Error("Rebuilding slashing protection history failed")
+								} else {
+									log.Info("Rebuild completed successfully")
+								}
+
+								return nil
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "threshold-signer",
+			Category: "accounts",
+			Usage:    "serves partial signatures with the key shares of a threshold signer to the threshold keymanager",
+			Flags: []cli.Flag{
+				cmd.DataDirFlag,
+				flags.ThresholdShareFileFlag,
+				flags.ThresholdSharePasswordFileFlag,
+				flags.ThresholdSignerAddressFlag,
+				flags.ThresholdSignerCertFlag,
+				flags.ThresholdSignerKeyFlag,
+				flags.ThresholdSignerClientCAFlag,
 			},
+			Action: node.RunThresholdSigner,
 		},
 	}
 
@@ -0,0 +1,272 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/sirupsen/logrus"
+)
+
+// Statuses of the individual keys of import and delete requests.
+const (
+	statusImported  = "imported"
+	statusDeleted   = "deleted"
+	statusDuplicate = "duplicate"
+	statusNotFound  = "not_found"
+	statusError     = "error"
+)
+
+// nonexistentIndex is the index reported for keys without a validator on the beacon chain.
+const nonexistentIndex = ^uint64(0)
+
+// keyResponse describes a validating key. Index is nil while the validator index of the
+// key is unknown, as 0 is a valid index.
+type keyResponse struct {
+	PubKey   string  `json:"pubkey"`
+	Index    *uint64 `json:"index,string,omitempty"`
+	Status   string  `json:"status"`
+	Imported bool    `json:"imported"`
+	Graffiti string  `json:"graffiti,omitempty"`
+}
+
+type importRequest struct {
+	Keystores []json.RawMessage `json:"keystores"`
+	Passwords []string          `json:"passwords"`
+}
+
+type deleteRequest struct {
+	PubKeys []string `json:"pubkeys"`
+}
+
+type graffitiRequest struct {
+	PubKey   string `json:"pubkey"`
+	Graffiti string `json:"graffiti"`
+}
+
+type keyResult struct {
+	PubKey  string `json:"pubkey,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+func (s *Server) handleKeys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.listKeys(w, r)
+	case http.MethodPost:
+		s.importKeys(w, r)
+	case http.MethodDelete:
+		s.deleteKeys(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// listKeys responds with the validating keys along with their status on the beacon chain.
+func (s *Server) listKeys(w http.ResponseWriter, r *http.Request) {
+	pubKeys, err := s.keyManager.FetchValidatingKeys()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	keys := make([]*keyResponse, len(pubKeys))
+	byPubKey := make(map[[48]byte]*keyResponse, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		keys[i] = &keyResponse{
+			PubKey:   fmt.Sprintf("%#x", pubKey),
+			Status:   "UNKNOWN",
+			Imported: s.keyManager.IsImported(pubKey),
+		}
+		if graffiti, ok := s.keyManager.Graffiti(pubKey); ok {
+			keys[i].Graffiti = string(graffiti)
+		}
+		byPubKey[pubKey] = keys[i]
+	}
+	if s.validatorClient != nil && len(pubKeys) > 0 {
+		statuses, err := accounts.FetchAccountStatuses(r.Context(), s.validatorClient, bytesutil.FromBytes48Array(pubKeys))
+		if err != nil {
+			log.WithError(err).Warn("Could not fetch validator statuses")
+		}
+		for _, st := range statuses {
+			key, ok := byPubKey[bytesutil.ToBytes48(st.PublicKey)]
+			if !ok || st.Metadata == nil {
+				continue
+			}
+			if st.Index != nonexistentIndex {
+				index := st.Index
+				key.Index = &index
+			}
+			key.Status = st.Metadata.Status.String()
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": keys})
+}
+
+// importKeys imports EIP-2335 keystores, each decrypted with the password at the same
+// position. Imported keys start performing duties from the next epoch.
+func (s *Server) importKeys(w http.ResponseWriter, r *http.Request) {
+	req := &importRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "could not decode request: "+err.Error())
+		return
+	}
+	if len(req.Keystores) != len(req.Passwords) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("got %d keystores but %d passwords", len(req.Keystores), len(req.Passwords)))
+		return
+	}
+	results := make([]*keyResult, len(req.Keystores))
+	for i, ks := range req.Keystores {
+		// Keystores may be sent as JSON objects or as strings holding the JSON object.
+		var enc string
+		if err := json.Unmarshal(ks, &enc); err == nil {
+			ks = []byte(enc)
+		}
+		pubKey, err := s.keyManager.ImportKeystore(ks, req.Passwords[i])
+		switch {
+		case err == keymanager.ErrKeyExists:
+			results[i] = &keyResult{PubKey: fmt.Sprintf("%#x", pubKey), Status: statusDuplicate}
+			continue
+		case err != nil:
+			results[i] = &keyResult{Status: statusError, Message: err.Error()}
+			continue
+		}
+		if err := s.db.InitializeProposalHistory([][48]byte{pubKey}); err != nil {
+			results[i] = &keyResult{PubKey: fmt.Sprintf("%#x", pubKey), Status: statusError, Message: err.Error()}
+			continue
+		}
+		results[i] = &keyResult{PubKey: fmt.Sprintf("%#x", pubKey), Status: statusImported}
+		log.WithField("publicKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:]))).Info("Imported validating key")
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": results})
+}
+
+// deleteKeys stops validating with the keys and responds with their slashing protection
+// history in the EIP-3076 interchange format. The history is exported only after the duties
+// of the current slot completed, so that it includes everything the keys may have signed.
+func (s *Server) deleteKeys(w http.ResponseWriter, r *http.Request) {
+	req := &deleteRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "could not decode request: "+err.Error())
+		return
+	}
+	pubKeys := make([][48]byte, len(req.PubKeys))
+	for i, key := range req.PubKeys {
+		pubKey, err := bytesutil.PubKeyFromHex(key)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		pubKeys[i] = pubKey
+	}
+	genesisValidatorsRoot, err := s.db.GenesisValidatorsRoot(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if genesisValidatorsRoot == nil {
+		writeError(w, http.StatusServiceUnavailable, "validator has not connected to the chain yet")
+		return
+	}
+
+	results := make([]*keyResult, len(pubKeys))
+	deleted := make(map[string]bool)
+	for i, pubKey := range pubKeys {
+		key := fmt.Sprintf("%#x", pubKey)
+		results[i] = &keyResult{PubKey: key, Status: statusDeleted}
+		switch err := s.keyManager.RemoveKey(pubKey); {
+		case err == keymanager.ErrNoSuchKey:
+			results[i].Status = statusNotFound
+		case err != nil:
+			results[i].Status = statusError
+			results[i].Message = err.Error()
+		default:
+			deleted[key] = true
+			log.WithField("publicKey", fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:]))).Info("Deleted validating key")
+		}
+	}
+
+	if err := s.waitForSlotEnd(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	interchange, err := s.db.ExportInterchange(r.Context(), genesisValidatorsRoot)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	data := make([]*db.InterchangeData, 0, len(deleted))
+	for _, d := range interchange.Data {
+		if deleted[d.PubKey] {
+			data = append(data, d)
+		}
+	}
+	interchange.Data = data
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data":                results,
+		"slashing_protection": interchange,
+	})
+}
+
+func (s *Server) handleGraffiti(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	req := &graffitiRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "could not decode request: "+err.Error())
+		return
+	}
+	pubKey, err := bytesutil.PubKeyFromHex(req.PubKey)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := s.keyManager.SetGraffiti(pubKey, []byte(req.Graffiti)); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	log.WithFields(logrus.Fields{
+		"publicKey": fmt.Sprintf("%#x", bytesutil.Trunc(pubKey[:])),
+		"graffiti":  req.Graffiti,
+	}).Info("Set graffiti")
+	w.WriteHeader(http.StatusOK)
+}
+
+// waitForSlotEnd waits until the current slot ended, by which time the duties of the slot
+// completed and their slashing protection was saved.
+func (s *Server) waitForSlotEnd(ctx context.Context) error {
+	if s.nodeClient == nil {
+		return nil
+	}
+	genesis, err := s.nodeClient.GetGenesis(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get genesis time")
+	}
+	if genesis.GenesisTime == nil {
+		return nil
+	}
+	genesisTime := uint64(genesis.GenesisTime.Seconds)
+	slot := slotutil.SlotsSinceGenesis(time.Unix(int64(genesisTime), 0))
+	wait := roughtime.Until(slotutil.SlotStartTime(genesisTime, slot+1))
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+// Package management implements a local HTTP JSON API to change the validating keys of a
+// running validator client. Keys can be listed, imported from EIP-2335 keystores and
+// deleted, in which case their slashing protection history is returned so that they can
+// safely be moved to another validator client. Every request must carry the bearer token
+// stored in the token file.
+package management
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "management")
+
+// TokenFileName is the name of the file holding the API token, created in the data
+// directory unless another path is configured.
+const TokenFileName = "management-api-token"
+
+const shutdownTimeout = 5 * time.Second
+
+// Config for the management API server.
+type Config struct {
+	Address         string
+	TokenFile       string
+	KeyManager      *keymanager.Managed
+	DB              *db.Store
+	ValidatorClient ethpb.BeaconNodeValidatorClient
+	NodeClient      ethpb.NodeClient
+}
+
+// Server serves the management API.
+type Server struct {
+	keyManager      *keymanager.Managed
+	db              *db.Store
+	validatorClient ethpb.BeaconNodeValidatorClient
+	nodeClient      ethpb.NodeClient
+	address         string
+	tokenFile       string
+	token           string
+	listener        net.Listener
+	httpServer      *http.Server
+}
+
+// NewServer creates a management API server.
+func NewServer(cfg *Config) *Server {
+	return &Server{
+		keyManager:      cfg.KeyManager,
+		db:              cfg.DB,
+		validatorClient: cfg.ValidatorClient,
+		nodeClient:      cfg.NodeClient,
+		address:         cfg.Address,
+		tokenFile:       cfg.TokenFile,
+	}
+}
+
+// Start loads or creates the API token and starts serving requests.
+func (s *Server) Start() error {
+	token, err := loadOrCreateToken(s.tokenFile)
+	if err != nil {
+		return err
+	}
+	s.token = token
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return errors.Wrapf(err, "could not listen on %s", s.address)
+	}
+	s.listener = lis
+	s.httpServer = &http.Server{Handler: s.Handler()}
+	go func() {
+		if err := s.httpServer.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Management API server failed")
+		}
+	}()
+	log.WithFields(logrus.Fields{
+		"address":   lis.Addr().String(),
+		"tokenFile": s.tokenFile,
+	}).Info("Management API listening")
+	return nil
+}
+
+// Stop shuts the server down, waiting for in-flight requests to complete.
+func (s *Server) Stop() error {
+	if s.httpServer == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.httpServer.Shutdown(ctx)
+}
+
+// Handler returns the HTTP handler serving all management API endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/keys", s.handleKeys)
+	mux.HandleFunc("/v1/keys/graffiti", s.handleGraffiti)
+	return s.authenticate(mux)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loadOrCreateToken reads the API token from the file, generating a random token readable
+// only by the current user if the file does not exist yet.
+func loadOrCreateToken(path string) (string, error) {
+	enc, err := ioutil.ReadFile(path)
+	if err == nil {
+		token := strings.TrimSpace(string(enc))
+		if token == "" {
+			return "", fmt.Errorf("management API token file %s is empty", path)
+		}
+		return token, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "could not read management API token")
+	}
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "could not generate management API token")
+	}
+	token := hex.EncodeToString(b)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", errors.Wrap(err, "could not create management API token directory")
+	}
+	if err := ioutil.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", errors.Wrap(err, "could not write management API token")
+	}
+	return token, nil
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]interface{}{
+		"code":    code,
+		"message": message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
@@ -0,0 +1,255 @@
+package management
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+const testToken = "secret"
+
+func setupServer(t *testing.T, keys []*bls.SecretKey) (*Server, *db.Store) {
+	dir, err := ioutil.TempDir("", "management")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	km, err := keymanager.NewManaged(keymanager.NewDirect(keys), dir, "managed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeys := make([][48]byte, len(keys))
+	for i, key := range keys {
+		pubKeys[i] = bytesutil.ToBytes48(key.PublicKey().Marshal())
+	}
+	valDB := db.SetupDB(t, pubKeys)
+	s := NewServer(&Config{KeyManager: km, DB: valDB})
+	s.token = testToken
+	return s, valDB
+}
+
+func request(t *testing.T, h http.Handler, method string, path string, body interface{}) (int, map[string]json.RawMessage) {
+	var enc []byte
+	if body != nil {
+		var err error
+		enc, err = json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(enc))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	res := make(map[string]json.RawMessage)
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return rec.Code, res
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	s, _ := setupServer(t, nil)
+	for _, auth := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/keys", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Wanted %d with authorization %q, received %d", http.StatusUnauthorized, auth, rec.Code)
+		}
+	}
+}
+
+func TestServer_ListKeys_Index(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+	keys := []*bls.SecretKey{bls.RandKey(), bls.RandKey()}
+	s, _ := setupServer(t, keys)
+	s.validatorClient = client
+
+	pubKeys := [][]byte{keys[0].PublicKey().Marshal(), keys[1].PublicKey().Marshal()}
+	client.EXPECT().MultipleValidatorStatus(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&ethpb.MultipleValidatorStatusResponse{
+		PublicKeys: pubKeys,
+		Statuses: []*ethpb.ValidatorStatusResponse{
+			{Status: ethpb.ValidatorStatus_ACTIVE},
+			{Status: ethpb.ValidatorStatus_UNKNOWN_STATUS},
+		},
+		Indices: []uint64{0, nonexistentIndex},
+	}, nil)
+
+	_, res := request(t, s.Handler(), http.MethodGet, "/v1/keys", nil)
+	var listed []map[string]interface{}
+	if err := json.Unmarshal(res["data"], &listed); err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("Unexpected keys %s", res["data"])
+	}
+	for _, key := range listed {
+		index, ok := key["index"]
+		switch key["pubkey"] {
+		case fmt.Sprintf("%#x", pubKeys[0]):
+			if !ok || index != "0" {
+				t.Errorf("Wanted index 0 for the active key, received %v", index)
+			}
+		case fmt.Sprintf("%#x", pubKeys[1]):
+			if ok {
+				t.Errorf("Wanted no index for the unknown key, received %v", index)
+			}
+		default:
+			t.Errorf("Unexpected key %v", key["pubkey"])
+		}
+	}
+}
+
+func TestServer_ImportListDelete(t *testing.T) {
+	ctx := context.Background()
+	sk := bls.RandKey()
+	s, valDB := setupServer(t, []*bls.SecretKey{sk})
+	h := s.Handler()
+	if err := valDB.SaveGenesisValidatorsRoot(ctx, bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := keystore.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := keystore.EncryptKeyEIP2335(key, "password", "", keystore.KDFPBKDF2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, res := request(t, h, http.MethodPost, "/v1/keys", map[string]interface{}{
+		"keystores": []json.RawMessage{enc, enc},
+		"passwords": []string{"password", "password"},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	var imported []*keyResult
+	if err := json.Unmarshal(res["data"], &imported); err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 2 || imported[0].Status != statusImported || imported[1].Status != statusDuplicate {
+		t.Fatalf("Unexpected import results %v", res["data"])
+	}
+
+	_, res = request(t, h, http.MethodGet, "/v1/keys", nil)
+	var keys []*keyResponse
+	if err := json.Unmarshal(res["data"], &keys); err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].Imported || !keys[1].Imported || keys[1].PubKey != imported[0].PubKey {
+		t.Fatalf("Unexpected keys %v", res["data"])
+	}
+
+	// The imported key has its proposal history initialized, so it can be recorded.
+	pubKey := bytesutil.ToBytes48(key.PublicKey.Marshal())
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(1, true)
+	if err := valDB.SaveProposalHistoryForEpoch(ctx, pubKey[:], 3, slotBits); err != nil {
+		t.Fatal(err)
+	}
+
+	code, res = request(t, h, http.MethodDelete, "/v1/keys", map[string]interface{}{
+		"pubkeys": []string{imported[0].PubKey, "0x" + string(bytes.Repeat([]byte("ab"), 48))},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	var deleted []*keyResult
+	if err := json.Unmarshal(res["data"], &deleted); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || deleted[0].Status != statusDeleted || deleted[1].Status != statusNotFound {
+		t.Fatalf("Unexpected delete results %v", res["data"])
+	}
+	interchange := &db.Interchange{}
+	if err := json.Unmarshal(res["slashing_protection"], interchange); err != nil {
+		t.Fatal(err)
+	}
+	if len(interchange.Data) != 1 || interchange.Data[0].PubKey != imported[0].PubKey || len(interchange.Data[0].SignedBlocks) != 1 {
+		t.Errorf("Expected slashing protection of the deleted key only, received %v", res["slashing_protection"])
+	}
+	if s.keyManager.IsImported(pubKey) {
+		t.Error("Expected deleted key to be removed")
+	}
+}
+
+func TestServer_SetGraffiti(t *testing.T) {
+	sk := bls.RandKey()
+	s, _ := setupServer(t, []*bls.SecretKey{sk})
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	code, _ := request(t, s.Handler(), http.MethodPost, "/v1/keys/graffiti", &graffitiRequest{
+		PubKey:   fmt.Sprintf("%#x", pubKey),
+		Graffiti: "hello",
+	})
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	if graffiti, ok := s.keyManager.Graffiti(pubKey); !ok || string(graffiti) != "hello" {
+		t.Errorf("Wanted graffiti hello, received %q", graffiti)
+	}
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	path := filepath.Join(dir, TokenFileName)
+	token, err := loadOrCreateToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Wanted token file mode 0600, received %v", info.Mode().Perm())
+	}
+	reloaded, err := loadOrCreateToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token == "" || reloaded != token {
+		t.Errorf("Expected the token to be reloaded, received %q and %q", token, reloaded)
+	}
+}
@@ -0,0 +1,110 @@
+package node
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-ssz"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/urfave/cli/v2"
+)
+
+// GenerateDepositData writes a deposit data file for the keys of the key manager selected by
+// the command line flags, in the format of the staking launchpad.
+func GenerateDepositData(ctx *cli.Context) error {
+	withdrawalCredentials, err := withdrawalCredentialsFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	var forkVersion []byte
+	if ctx.IsSet(flags.ForkVersionFlag.Name) {
+		forkVersion, err = decodeHexFlag(ctx, flags.ForkVersionFlag.Name, 4)
+		if err != nil {
+			return err
+		}
+	}
+	km, err := selectKeyManager(ctx)
+	if err != nil {
+		return err
+	}
+	km, auditLog, err := auditKeyManager(ctx, km)
+	if err != nil {
+		return err
+	}
+	defer closeAuditLog(auditLog)
+	pubKeys, err := km.FetchValidatingKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validating keys")
+	}
+	if len(pubKeys) == 0 {
+		return errors.New("no validating keys found")
+	}
+	deposits, err := accounts.GenerateDepositData(
+		depositSigner(km),
+		pubKeys,
+		withdrawalCredentials,
+		ctx.Uint64(flags.DepositAmountFlag.Name),
+		forkVersion,
+	)
+	if err != nil {
+		return err
+	}
+	path, err := accounts.WriteDepositData(ctx.String(flags.DepositDataDirFlag.Name), deposits)
+	if err != nil {
+		return err
+	}
+	log.WithField("path", path).WithField("deposits", len(deposits)).Info("Generated deposit data")
+	return nil
+}
+
+// depositSigner signs deposits with the key manager, letting key managers which protect
+// against slashing compute the signing root themselves.
+func depositSigner(km keymanager.KeyManager) accounts.DepositSigner {
+	return func(pubKey [48]byte, messageRoot [32]byte, domain []byte) (*bls.Signature, error) {
+		if protectingKeymanager, supported := km.(keymanager.ProtectingKeyManager); supported {
+			return protectingKeymanager.SignGeneric(pubKey, messageRoot, bytesutil.ToBytes32(domain))
+		}
+		root, err := ssz.HashTreeRoot(&pb.SigningData{ObjectRoot: messageRoot[:], Domain: domain})
+		if err != nil {
+			return nil, err
+		}
+		return km.Sign(pubKey, root)
+	}
+}
+
+// withdrawalCredentialsFromFlags returns the withdrawal credentials given directly or derived
+// from the withdrawal public key.
+func withdrawalCredentialsFromFlags(ctx *cli.Context) ([]byte, error) {
+	pubKeySet := ctx.IsSet(flags.WithdrawalPubKeyFlag.Name)
+	credentialsSet := ctx.IsSet(flags.WithdrawalCredentialsFlag.Name)
+	if pubKeySet == credentialsSet {
+		return nil, fmt.Errorf("exactly one of --%s and --%s must be provided",
+			flags.WithdrawalPubKeyFlag.Name, flags.WithdrawalCredentialsFlag.Name)
+	}
+	if credentialsSet {
+		return decodeHexFlag(ctx, flags.WithdrawalCredentialsFlag.Name, 32)
+	}
+	pubKey, err := decodeHexFlag(ctx, flags.WithdrawalPubKeyFlag.Name, 48)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bls.PublicKeyFromBytes(pubKey); err != nil {
+		return nil, errors.Wrap(err, "invalid withdrawal public key")
+	}
+	return accounts.WithdrawalCredentials(pubKey), nil
+}
+
+func decodeHexFlag(ctx *cli.Context, name string, length int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(ctx.String(name), "0x"))
+	if err != nil || len(b) != length {
+		return nil, fmt.Errorf("--%s must be %d hex encoded bytes", name, length)
+	}
+	return b, nil
+}
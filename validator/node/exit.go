@@ -0,0 +1,176 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
+	"github.com/prysmaticlabs/prysm/validator/client/streaming"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+)
+
+// ExitValidators signs and submits voluntary exits for the keys of the key manager selected by
+// the command line flags, after checking that they may exit and asking for confirmation.
+func ExitValidators(cliCtx *cli.Context) error {
+	km, err := selectKeyManager(cliCtx)
+	if err != nil {
+		return err
+	}
+	km, auditLog, err := auditKeyManager(cliCtx, km)
+	if err != nil {
+		return err
+	}
+	defer closeAuditLog(auditLog)
+	pubKeys, err := exitPubKeys(cliCtx, km)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	conn, err := dialBeaconNode(ctx, cliCtx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Error("Could not close connection to beacon node")
+		}
+	}()
+	validatorClient := ethpb.NewBeaconNodeValidatorClient(conn)
+	head, err := ethpb.NewBeaconChainClient(conn).GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "could not get chain head")
+	}
+	statuses, err := accounts.FetchAccountStatuses(ctx, validatorClient, bytesutil.FromBytes48Array(pubKeys))
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validator statuses")
+	}
+	eligible, ineligible := accounts.CheckExitEligibility(statuses, head.HeadEpoch)
+	accounts.PrintExitResults(ineligible)
+	if len(eligible) == 0 {
+		return errors.New("no validators are eligible to exit")
+	}
+
+	exiting := make([]string, len(eligible))
+	for i, v := range eligible {
+		exiting[i] = fmt.Sprintf("%#x", v.PublicKey)
+	}
+	actionText := fmt.Sprintf("This will exit %d validators at epoch %d:\n%s\n"+
+		"Exits cannot be undone, exited validators can neither validate again nor withdraw until withdrawals are enabled. "+
+		"Do you want to proceed? (Y/N)", len(eligible), head.HeadEpoch, strings.Join(exiting, "\n"))
+	deniedText := "No validators were exited."
+	confirmed, err := cmd.ConfirmAction(actionText, deniedText)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	// Confirmation waits on the operator, so the exits get their own timeout.
+	submitCtx, cancelSubmit := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelSubmit()
+	results := accounts.SubmitExits(submitCtx, validatorClient, exitSigner(km), eligible, head.HeadEpoch)
+	accounts.PrintExitResults(results)
+	for _, r := range results {
+		if r.Err != nil {
+			return errors.New("not all validators were exited")
+		}
+	}
+	return nil
+}
+
+// exitPubKeys returns the keys to exit, which are the keys given on the command line or all
+// keys of the key manager.
+func exitPubKeys(cliCtx *cli.Context, km keymanager.KeyManager) ([][48]byte, error) {
+	validatingKeys, err := km.FetchValidatingKeys()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch validating keys")
+	}
+	if !cliCtx.IsSet(flags.ExitPubKeysFlag.Name) {
+		if len(validatingKeys) == 0 {
+			return nil, errors.New("no validating keys found")
+		}
+		return validatingKeys, nil
+	}
+	known := make(map[string]bool, len(validatingKeys))
+	for _, pubKey := range validatingKeys {
+		known[fmt.Sprintf("%x", pubKey)] = true
+	}
+	var pubKeys [][48]byte
+	for _, key := range strings.Split(cliCtx.String(flags.ExitPubKeysFlag.Name), ",") {
+		key = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(key)), "0x")
+		if key == "" {
+			continue
+		}
+		if !known[key] {
+			return nil, fmt.Errorf("public key 0x%s is not a key of the keymanager", key)
+		}
+		pubKey, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid public key 0x%s", key)
+		}
+		pubKeys = append(pubKeys, bytesutil.ToBytes48(pubKey))
+	}
+	if len(pubKeys) == 0 {
+		return nil, fmt.Errorf("no public keys given with --%s", flags.ExitPubKeysFlag.Name)
+	}
+	return pubKeys, nil
+}
+
+// exitSigner signs exits with the key manager, letting key managers which protect against
+// slashing compute the signing root themselves.
+func exitSigner(km keymanager.KeyManager) accounts.ExitSigner {
+	return func(pubKey [48]byte, exit *ethpb.VoluntaryExit, domain []byte) (*bls.Signature, error) {
+		if protectingKeymanager, supported := km.(keymanager.ProtectingKeyManager); supported {
+			root, err := ssz.HashTreeRoot(exit)
+			if err != nil {
+				return nil, err
+			}
+			return protectingKeymanager.SignGeneric(pubKey, root, bytesutil.ToBytes32(domain))
+		}
+		root, err := helpers.ComputeSigningRoot(exit, domain)
+		if err != nil {
+			return nil, err
+		}
+		return km.Sign(pubKey, root)
+	}
+}
+
+// dialBeaconNode connects to the first beacon node given on the command line.
+func dialBeaconNode(ctx context.Context, cliCtx *cli.Context) (*grpc.ClientConn, error) {
+	endpoints := failover.Endpoints(cliCtx.String(flags.BeaconRPCProviderFlag.Name))
+	if len(endpoints) == 0 {
+		return nil, errors.New("no beacon node endpoint provided")
+	}
+	dialOpts := streaming.ConstructDialOptions(
+		cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+		cliCtx.String(flags.CertFlag.Name),
+		strings.Split(cliCtx.String(flags.GrpcHeadersFlag.Name), ","),
+		cliCtx.Uint(flags.GrpcRetriesFlag.Name),
+		grpc.WithBlock())
+	if dialOpts == nil {
+		return nil, errors.New("could not construct dial options")
+	}
+	conn, err := grpc.DialContext(ctx, endpoints[0], dialOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not dial beacon node at %s", endpoints[0])
+	}
+	return conn, nil
+}
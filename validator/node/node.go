@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,11 +23,13 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/prometheus"
 	"github.com/prysmaticlabs/prysm/shared/tracing"
 	"github.com/prysmaticlabs/prysm/shared/version"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
 	"github.com/prysmaticlabs/prysm/validator/client/polling"
 	"github.com/prysmaticlabs/prysm/validator/client/streaming"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/management"
 	slashing_protection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -41,7 +44,8 @@ type ValidatorClient struct {
 	cliCtx   *cli.Context
 	services *shared.ServiceRegistry // Lifecycle and service store.
 	lock     sync.RWMutex
-	stop     chan struct{} // Channel to wait for termination notifications.
+	stop     chan struct{}    // Channel to wait for termination notifications.
+	auditLog *auditlog.Writer // Audit log of the signatures of the key manager, if any.
 }
 
 // NewValidatorClient creates a new, Ethereum Serenity validator client.
@@ -81,6 +85,25 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 	if err != nil {
 		return nil, err
 	}
+	var managed *keymanager.Managed
+	if cliCtx.Bool(flags.ManagementAPIFlag.Name) {
+		managedDir := filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), "managed-keys")
+		password, err := managedKeysPassword(cliCtx)
+		if err != nil {
+			return nil, err
+		}
+		managed, err = keymanager.NewManaged(keyManager, managedDir, password)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not manage validating keys")
+		}
+		keyManager = managed
+	}
+	// Signatures are recorded in the audit log below the management API, which manages the keys of
+	// the wrapped key manager.
+	keyManager, ValidatorClient.auditLog, err = auditKeyManager(cliCtx, keyManager)
+	if err != nil {
+		return nil, err
+	}
 
 	pubKeys, err := keyManager.FetchValidatingKeys()
 	if err != nil {
@@ -121,7 +144,7 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 			return nil, err
 		}
 	}
-	if err := ValidatorClient.registerClientService(keyManager); err != nil {
+	if err := ValidatorClient.registerClientService(keyManager, managed); err != nil {
 		return nil, err
 	}
 
@@ -168,6 +191,7 @@ func (s *ValidatorClient) Close() {
 	defer s.lock.Unlock()
 
 	s.services.StopAll()
+	closeAuditLog(s.auditLog)
 	log.Info("Stopping sharding validator")
 
 	close(s.stop)
@@ -182,15 +206,28 @@ func (s *ValidatorClient) registerPrometheusService() error {
 	return s.services.RegisterService(service)
 }
 
-func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager) error {
+func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager, managed *keymanager.Managed) error {
 	endpoint := s.cliCtx.String(flags.BeaconRPCProviderFlag.Name)
 	dataDir := s.cliCtx.String(cmd.DataDirFlag.Name)
 	logValidatorBalances := !s.cliCtx.Bool(flags.DisablePenaltyRewardLogFlag.Name)
 	emitAccountMetrics := !s.cliCtx.Bool(flags.DisableAccountMetricsFlag.Name)
 	cert := s.cliCtx.String(flags.CertFlag.Name)
 	graffiti := s.cliCtx.String(flags.GraffitiFlag.Name)
+	graffitiFile := s.cliCtx.String(flags.GraffitiFileFlag.Name)
 	maxCallRecvMsgSize := s.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name)
 	grpcRetries := s.cliCtx.Uint(flags.GrpcRetriesFlag.Name)
+	broadcastSubmissions := s.cliCtx.Bool(flags.BroadcastSubmissionsFlag.Name)
+	doppelgangerEpochs := s.cliCtx.Uint64(flags.DoppelgangerEpochsFlag.Name)
+	shadowMode := s.cliCtx.Bool(flags.ShadowModeFlag.Name)
+	var managementAddress, managementTokenFile string
+	if s.cliCtx.Bool(flags.ManagementAPIFlag.Name) {
+		managementAddress = fmt.Sprintf("%s:%d",
+			s.cliCtx.String(flags.ManagementAPIHostFlag.Name), s.cliCtx.Int(flags.ManagementAPIPortFlag.Name))
+		managementTokenFile = s.cliCtx.String(flags.ManagementAPITokenFileFlag.Name)
+		if managementTokenFile == "" {
+			managementTokenFile = filepath.Join(dataDir, management.TokenFileName)
+		}
+	}
 	var sp *slashing_protection.Service
 	var protector slashing_protection.Protector
 	if err := s.services.FetchService(&sp); err == nil {
@@ -205,10 +242,17 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 			EmitAccountMetrics:         emitAccountMetrics,
 			CertFlag:                   cert,
 			GraffitiFlag:               graffiti,
+			GraffitiFile:               graffitiFile,
 			GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
 			GrpcRetriesFlag:            grpcRetries,
 			GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
 			Protector:                  protector,
+			BroadcastSubmissions:       broadcastSubmissions,
+			DoppelgangerEpochs:         doppelgangerEpochs,
+			ShadowMode:                 shadowMode,
+			ManagementAddress:          managementAddress,
+			ManagementTokenFile:        managementTokenFile,
+			ManagedKeyManager:          managed,
 		})
 
 		if err != nil {
@@ -224,10 +268,17 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 		EmitAccountMetrics:         emitAccountMetrics,
 		CertFlag:                   cert,
 		GraffitiFlag:               graffiti,
+		GraffitiFile:               graffitiFile,
 		GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
 		GrpcRetriesFlag:            grpcRetries,
 		GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
 		Protector:                  protector,
+		BroadcastSubmissions:       broadcastSubmissions,
+		DoppelgangerEpochs:         doppelgangerEpochs,
+		ShadowMode:                 shadowMode,
+		ManagementAddress:          managementAddress,
+		ManagementTokenFile:        managementTokenFile,
+		ManagedKeyManager:          managed,
 	})
 
 	if err != nil {
@@ -257,6 +308,20 @@ func (s *ValidatorClient) registerSlasherClientService() error {
 	return s.services.RegisterService(sp)
 }
 
+// managedKeysPassword reads the password of the keystores imported through the management API from
+// its file, or asks for it on the terminal.
+func managedKeysPassword(cliCtx *cli.Context) (string, error) {
+	if path := cliCtx.String(flags.ManagementKeysPasswordFileFlag.Name); path != "" {
+		enc, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read managed keys password file")
+		}
+		return strings.TrimRight(string(enc), "\r\n"), nil
+	}
+	log.Info("The keystores imported through the management API are encrypted under a password")
+	return cmd.EnterPassword(false, cmd.StdInPasswordReader{})
+}
+
 // selectKeyManager selects the key manager depending on the options provided by the user.
 func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 	manager := strings.ToLower(ctx.String(flags.KeyManager.Name))
@@ -308,10 +373,16 @@ func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 		km, help, err = keymanager.NewUnencrypted(opts)
 	case "keystore":
 		km, help, err = keymanager.NewKeystore(opts)
+	case "derived":
+		km, help, err = keymanager.NewDerived(opts)
 	case "wallet":
 		km, help, err = keymanager.NewWallet(opts)
 	case "remote":
 		km, help, err = keymanager.NewRemoteWallet(opts)
+	case "remote-http":
+		km, help, err = keymanager.NewRemoteHTTP(opts)
+	case "threshold":
+		km, help, err = keymanager.NewThreshold(opts)
 	default:
 		return nil, fmt.Errorf("unknown keymanager %q", manager)
 	}
@@ -325,6 +396,32 @@ func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 	return km, nil
 }
 
+// auditKeyManager wraps the key manager to record its signatures in the audit log, when an audit
+// log directory is given. The returned writer is nil without an audit log, and must otherwise be
+// closed once the key manager is no longer used.
+func auditKeyManager(cliCtx *cli.Context, km keymanager.KeyManager) (keymanager.KeyManager, *auditlog.Writer, error) {
+	dir := cliCtx.String(flags.AuditLogDirFlag.Name)
+	if dir == "" {
+		return km, nil, nil
+	}
+	auditLog, err := auditlog.NewWriter(dir, cliCtx.Int64(flags.AuditLogMaxSizeFlag.Name)*1024*1024)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not open audit log")
+	}
+	log.WithField("dir", dir).Info("Recording signatures in audit log")
+	return keymanager.NewAudited(km, auditLog), auditLog, nil
+}
+
+// closeAuditLog closes the audit log, if there is one.
+func closeAuditLog(auditLog *auditlog.Writer) {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Close(); err != nil {
+		log.WithError(err).Error("Could not close audit log")
+	}
+}
+
 func clearDB(dataDir string, pubkeys [][48]byte, force bool) error {
 	var err error
 	clearDBConfirmed := force
@@ -3,10 +3,17 @@ package node
 import (
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
 
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/auditlog"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/urfave/cli/v2"
 )
 
@@ -39,3 +46,38 @@ func TestNode_Builds(t *testing.T) {
 		t.Fatalf("Failed to create ValidatorClient: %v", err)
 	}
 }
+
+func TestExitSigner_RecordedInAuditLog(t *testing.T) {
+	dir := filepath.Join(testutil.TempDir(), "auditlog")
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	}()
+	app := cli.App{}
+	set := flag.NewFlagSet("test", 0)
+	set.String("audit-log-dir", dir, "audit log directory")
+	set.Int64("audit-log-max-size", 1, "audit log file size")
+	cliCtx := cli.NewContext(&app, set, nil)
+
+	sk := bls.RandKey()
+	km, auditLog, err := auditKeyManager(cliCtx, keymanager.NewDirect([]*bls.SecretKey{sk}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain := make([]byte, 32)
+	copy(domain, params.BeaconConfig().DomainVoluntaryExit[:])
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	if _, err := exitSigner(km)(pubKey, &ethpb.VoluntaryExit{Epoch: 1}, domain); err != nil {
+		t.Fatal(err)
+	}
+	closeAuditLog(auditLog)
+
+	res, err := auditlog.Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 1 {
+		t.Errorf("Wanted the exit signature recorded in the audit log, found %d entries", res.Entries)
+	}
+}
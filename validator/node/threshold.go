@@ -0,0 +1,194 @@
+package node
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/accounts"
+	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/thresholdsigner"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// thresholdKeymanagerOptsFileName is the name of the threshold keymanager options file written
+// when splitting keys.
+const thresholdKeymanagerOptsFileName = "threshold-keymanager.json"
+
+// SplitThresholdKeys splits the keys of the keystore into shares for threshold signers, writing
+// a share file for each signer and the options of the threshold keymanager.
+func SplitThresholdKeys(cliCtx *cli.Context) error {
+	var addresses []string
+	for _, address := range strings.Split(cliCtx.String(flags.ThresholdSignersFlag.Name), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("no signer addresses given with --%s", flags.ThresholdSignersFlag.Name)
+	}
+	threshold := cliCtx.Uint64(flags.ThresholdFlag.Name)
+	keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+	if err != nil {
+		return err
+	}
+	keys, err := accounts.DecryptKeysFromKeystore(keystorePath, params.BeaconConfig().ValidatorPrivkeyFileName, passphrase)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return errors.New("no validating keys found")
+	}
+	secretKeys := make([]*bls.SecretKey, 0, len(keys))
+	for _, key := range keys {
+		secretKeys = append(secretKeys, key.SecretKey)
+	}
+	sharePassword, err := thresholdSharePassword(cliCtx, true /*confirmPassword*/)
+	if err != nil {
+		return err
+	}
+	shareFiles, splitKeys, err := thresholdsigner.SplitKeys(secretKeys, threshold, uint64(len(addresses)), sharePassword)
+	if err != nil {
+		return err
+	}
+
+	outputDir := cliCtx.String(flags.ThresholdOutputDirFlag.Name)
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return errors.Wrapf(err, "could not create output directory %s", outputDir)
+	}
+	type signerOpts struct {
+		ID      uint64 `json:"id"`
+		Address string `json:"address"`
+	}
+	type accountOpts struct {
+		VerificationVector []string `json:"verification_vector"`
+	}
+	opts := struct {
+		Signers  []*signerOpts  `json:"signers"`
+		Accounts []*accountOpts `json:"accounts"`
+	}{}
+	for i, shareFile := range shareFiles {
+		path := filepath.Join(outputDir, fmt.Sprintf("threshold-share-%d.json", shareFile.ID))
+		if err := thresholdsigner.WriteShareFile(path, shareFile); err != nil {
+			return err
+		}
+		log.WithField("path", path).WithField("signer", addresses[i]).Info("Wrote share file")
+		opts.Signers = append(opts.Signers, &signerOpts{ID: shareFile.ID, Address: addresses[i]})
+	}
+	for _, splitKey := range splitKeys {
+		verificationVector := make([]string, len(splitKey.VerificationVector))
+		for i, pub := range splitKey.VerificationVector {
+			verificationVector[i] = fmt.Sprintf("%#x", pub.Marshal())
+		}
+		opts.Accounts = append(opts.Accounts, &accountOpts{VerificationVector: verificationVector})
+	}
+	enc, err := json.MarshalIndent(opts, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode keymanager options")
+	}
+	path := filepath.Join(outputDir, thresholdKeymanagerOptsFileName)
+	if err := ioutil.WriteFile(path, enc, 0644); err != nil {
+		return errors.Wrapf(err, "could not write keymanager options to %s", path)
+	}
+	log.WithField("path", path).Infof(
+		"Split %d keys into %d-of-%d shares. Give each signer its share file, delete the share files "+
+			"from this machine and start the validator with --keymanager=threshold --keymanageropts=%s",
+		len(splitKeys), threshold, len(addresses), path)
+	return nil
+}
+
+// RunThresholdSigner serves signing requests with the shares of the share file until the process
+// is interrupted.
+func RunThresholdSigner(cliCtx *cli.Context) error {
+	shareFile, err := thresholdsigner.LoadShareFile(cliCtx.String(flags.ThresholdShareFileFlag.Name))
+	if err != nil {
+		return err
+	}
+	sharePassword, err := thresholdSharePassword(cliCtx, false /*confirmPassword*/)
+	if err != nil {
+		return err
+	}
+	server, err := thresholdsigner.NewServer(shareFile, sharePassword, cliCtx.String(cmd.DataDirFlag.Name))
+	if err != nil {
+		return err
+	}
+	var opts []grpc.ServerOption
+	if cliCtx.IsSet(flags.ThresholdSignerCertFlag.Name) {
+		tlsCfg, err := thresholdSignerTLSConfig(cliCtx)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	} else {
+		log.Warn("You are using an insecure gRPC server for the threshold signer. Provide a certificate and key with " +
+			"--threshold-signer-tls-cert and --threshold-signer-tls-key to connect securely")
+	}
+	address := cliCtx.String(flags.ThresholdSignerAddressFlag.Name)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "could not listen on %s", address)
+	}
+	server.Start(lis, opts...)
+	defer server.Stop()
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigc)
+	<-sigc
+	log.Info("Got interrupt, shutting down threshold signer...")
+	return nil
+}
+
+// thresholdSharePassword reads the password of the key shares from its file, or asks for it on the
+// terminal.
+func thresholdSharePassword(cliCtx *cli.Context, confirm bool) (string, error) {
+	if path := cliCtx.String(flags.ThresholdSharePasswordFileFlag.Name); path != "" {
+		enc, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read share password file")
+		}
+		return strings.TrimRight(string(enc), "\r\n"), nil
+	}
+	log.Info("The key shares of the share files are encrypted under a password")
+	return cmd.EnterPassword(confirm, cmd.StdInPasswordReader{})
+}
+
+// thresholdSignerTLSConfig loads the certificate of the signer and, if present, the CA which
+// client certificates must be signed by.
+func thresholdSignerTLSConfig(cliCtx *cli.Context) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(
+		cliCtx.String(flags.ThresholdSignerCertFlag.Name),
+		cliCtx.String(flags.ThresholdSignerKeyFlag.Name),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load the threshold signer certificate and key")
+	}
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if cliCtx.IsSet(flags.ThresholdSignerClientCAFlag.Name) {
+		clientCA, err := ioutil.ReadFile(cliCtx.String(flags.ThresholdSignerClientCAFlag.Name))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read the client CA certificate")
+		}
+		cp := x509.NewCertPool()
+		if !cp.AppendCertsFromPEM(clientCA) {
+			return nil, errors.New("could not add the client CA certificate to pool")
+		}
+		tlsCfg.ClientCAs = cp
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,117 @@
+package thresholdsigner
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// protectionFileName is the name of the database of the signer's slashing protection.
+const protectionFileName = "threshold-signer.db"
+
+var (
+	// The last proposal signed by each account.
+	proposalsBucket = []byte("proposals")
+	// The last attestation signed by each account.
+	attestationsBucket = []byte("attestations")
+)
+
+// protectionDB persists the last proposal and attestation signed by each account, so that the
+// signer's slashing protection survives restarts.
+type protectionDB struct {
+	db *bolt.DB
+}
+
+// openProtectionDB opens the slashing protection database of the signer in dir, creating it if
+// it does not exist yet.
+func openProtectionDB(dir string) (*protectionDB, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	boltDB, err := bolt.Open(filepath.Join(dir, protectionFileName), 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	if err := boltDB.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{proposalsBucket, attestationsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return &protectionDB{db: boltDB}, nil
+}
+
+func (p *protectionDB) close() error {
+	return p.db.Close()
+}
+
+// lastProposal returns the last proposal signed by the account, or nil if it signed none.
+func (p *protectionDB) lastProposal(account string) (*signedProposal, error) {
+	var proposal *signedProposal
+	err := p.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(proposalsBucket).Get([]byte(account))
+		if enc == nil {
+			return nil
+		}
+		if len(enc) != 40 {
+			return errors.New("invalid proposal record")
+		}
+		proposal = &signedProposal{slot: binary.BigEndian.Uint64(enc[:8])}
+		copy(proposal.signingRoot[:], enc[8:])
+		return nil
+	})
+	return proposal, err
+}
+
+// saveProposal records the proposal as the last one signed by the account.
+func (p *protectionDB) saveProposal(account string, proposal *signedProposal) error {
+	enc := make([]byte, 40)
+	binary.BigEndian.PutUint64(enc[:8], proposal.slot)
+	copy(enc[8:], proposal.signingRoot[:])
+	return p.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(proposalsBucket).Put([]byte(account), enc)
+	})
+}
+
+// lastAttestation returns the last attestation signed by the account, or nil if it signed none.
+func (p *protectionDB) lastAttestation(account string) (*signedAttestation, error) {
+	var attestation *signedAttestation
+	err := p.db.View(func(tx *bolt.Tx) error {
+		enc := tx.Bucket(attestationsBucket).Get([]byte(account))
+		if enc == nil {
+			return nil
+		}
+		if len(enc) != 48 {
+			return errors.New("invalid attestation record")
+		}
+		attestation = &signedAttestation{
+			sourceEpoch: binary.BigEndian.Uint64(enc[:8]),
+			targetEpoch: binary.BigEndian.Uint64(enc[8:16]),
+		}
+		copy(attestation.signingRoot[:], enc[16:])
+		return nil
+	})
+	return attestation, err
+}
+
+// saveAttestation records the attestation as the last one signed by the account.
+func (p *protectionDB) saveAttestation(account string, attestation *signedAttestation) error {
+	enc := make([]byte, 48)
+	binary.BigEndian.PutUint64(enc[:8], attestation.sourceEpoch)
+	binary.BigEndian.PutUint64(enc[8:16], attestation.targetEpoch)
+	copy(enc[16:], attestation.signingRoot[:])
+	return p.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(attestationsBucket).Put([]byte(account), enc)
+	})
+}
@@ -0,0 +1,226 @@
+package thresholdsigner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	p2ppb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/sirupsen/logrus"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+	"google.golang.org/grpc"
+)
+
+var log = logrus.WithField("prefix", "threshold-signer")
+
+// AccountName is the account name the threshold keymanager signs with for a validating key.
+func AccountName(pubKey [48]byte) string {
+	return fmt.Sprintf("%#x", pubKey)
+}
+
+// Server signs with its key shares over the signer API of walletd. It refuses to sign a proposal
+// or attestation which conflicts with one it signed before, so that signers protect against
+// slashing independently of the validator client. This protection is persisted in the data
+// directory of the signer, so that it survives restarts.
+type Server struct {
+	id         uint64
+	shares     map[string]*bls.SecretKey
+	grpcServer *grpc.Server
+	lock       sync.Mutex
+	protection *protectionDB
+}
+
+type signedProposal struct {
+	slot        uint64
+	signingRoot [32]byte
+}
+
+type signedAttestation struct {
+	sourceEpoch uint64
+	targetEpoch uint64
+	signingRoot [32]byte
+}
+
+// NewServer creates a signer for the shares of the share file, decrypting them with the password.
+// Its slashing protection is kept in a database in dataDir.
+func NewServer(shareFile *ShareFile, password string, dataDir string) (*Server, error) {
+	shares := make(map[string]*bls.SecretKey, len(shareFile.Shares))
+	for _, share := range shareFile.Shares {
+		pubKey, err := decodeHex(share.PublicKey)
+		if err != nil || len(pubKey) != 48 {
+			return nil, fmt.Errorf("invalid public key %s", share.PublicKey)
+		}
+		if share.Crypto == nil {
+			return nil, fmt.Errorf("share of public key %s is not encrypted", share.PublicKey)
+		}
+		secretKey, err := keystore.DecryptSecretEIP2335(share.Crypto, password)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not decrypt share of public key %s", share.PublicKey)
+		}
+		shareKey, err := bls.SecretKeyFromBytes(secretKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid share of public key %s", share.PublicKey)
+		}
+		shares[AccountName(bytesutil.ToBytes48(pubKey))] = shareKey
+	}
+	protection, err := openProtectionDB(dataDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open slashing protection database")
+	}
+	return &Server{
+		id:         shareFile.ID,
+		shares:     shares,
+		protection: protection,
+	}, nil
+}
+
+// Start serves signing requests on the listener until Stop is called.
+func (s *Server) Start(lis net.Listener, opts ...grpc.ServerOption) {
+	s.grpcServer = grpc.NewServer(opts...)
+	pb.RegisterSignerServer(s.grpcServer, s)
+	log.WithFields(logrus.Fields{
+		"address": lis.Addr().String(),
+		"shareId": s.id,
+		"keys":    len(s.shares),
+	}).Info("Threshold signer started")
+	go func() {
+		if err := s.grpcServer.Serve(lis); err != nil {
+			log.WithError(err).Error("Threshold signer stopped serving")
+		}
+	}()
+}
+
+// Stop stops serving signing requests and closes the slashing protection database.
+func (s *Server) Stop() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.protection.close(); err != nil {
+		log.WithError(err).Error("Could not close slashing protection database")
+	}
+}
+
+// Sign signs the root of an object in a signature domain with the share of the account.
+func (s *Server) Sign(ctx context.Context, req *pb.SignRequest) (*pb.SignResponse, error) {
+	if len(req.Data) != 32 || len(req.Domain) != 32 {
+		return failed("data and domain must be 32 bytes")
+	}
+	signingRoot, err := ssz.HashTreeRoot(&p2ppb.SigningData{ObjectRoot: req.Data, Domain: req.Domain})
+	if err != nil {
+		return nil, err
+	}
+	return s.sign(req.GetAccount(), signingRoot)
+}
+
+// SignBeaconProposal signs a block header with the share of the account, unless the account
+// signed another block at the same or a later slot.
+func (s *Server) SignBeaconProposal(ctx context.Context, req *pb.SignBeaconProposalRequest) (*pb.SignResponse, error) {
+	if req.Data == nil || len(req.Domain) != 32 {
+		return failed("invalid proposal")
+	}
+	header := &ethpb.BeaconBlockHeader{
+		Slot:          req.Data.Slot,
+		ProposerIndex: req.Data.ProposerIndex,
+		ParentRoot:    req.Data.ParentRoot,
+		StateRoot:     req.Data.StateRoot,
+		BodyRoot:      req.Data.BodyRoot,
+	}
+	signingRoot, err := helpers.ComputeSigningRoot(header, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	account := req.GetAccount()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	last, err := s.protection.lastProposal(account)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read last signed proposal")
+	}
+	if last != nil && header.Slot <= last.slot && signingRoot != last.signingRoot {
+		log.WithFields(logrus.Fields{"account": account, "slot": header.Slot}).Warn("Refusing to sign conflicting proposal")
+		return &pb.SignResponse{State: pb.ResponseState_DENIED}, nil
+	}
+	resp, err := s.sign(account, signingRoot)
+	if err != nil || resp.State != pb.ResponseState_SUCCEEDED {
+		return resp, err
+	}
+	// The signature is only returned once the proposal is recorded.
+	if err := s.protection.saveProposal(account, &signedProposal{slot: header.Slot, signingRoot: signingRoot}); err != nil {
+		return nil, errors.Wrap(err, "could not record signed proposal")
+	}
+	return resp, nil
+}
+
+// SignBeaconAttestation signs attestation data with the share of the account, unless the account
+// attested to the same or a later target, or to a later source, with different data.
+func (s *Server) SignBeaconAttestation(ctx context.Context, req *pb.SignBeaconAttestationRequest) (*pb.SignResponse, error) {
+	if req.Data == nil || req.Data.Source == nil || req.Data.Target == nil || len(req.Domain) != 32 {
+		return failed("invalid attestation")
+	}
+	data := &ethpb.AttestationData{
+		Slot:            req.Data.Slot,
+		CommitteeIndex:  req.Data.CommitteeIndex,
+		BeaconBlockRoot: req.Data.BeaconBlockRoot,
+		Source:          &ethpb.Checkpoint{Epoch: req.Data.Source.Epoch, Root: req.Data.Source.Root},
+		Target:          &ethpb.Checkpoint{Epoch: req.Data.Target.Epoch, Root: req.Data.Target.Root},
+	}
+	signingRoot, err := helpers.ComputeSigningRoot(data, req.Domain)
+	if err != nil {
+		return nil, err
+	}
+	account := req.GetAccount()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	last, err := s.protection.lastAttestation(account)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read last signed attestation")
+	}
+	if last != nil && signingRoot != last.signingRoot &&
+		(data.Target.Epoch <= last.targetEpoch || data.Source.Epoch < last.sourceEpoch) {
+		log.WithFields(logrus.Fields{
+			"account":     account,
+			"sourceEpoch": data.Source.Epoch,
+			"targetEpoch": data.Target.Epoch,
+		}).Warn("Refusing to sign conflicting attestation")
+		return &pb.SignResponse{State: pb.ResponseState_DENIED}, nil
+	}
+	resp, err := s.sign(account, signingRoot)
+	if err != nil || resp.State != pb.ResponseState_SUCCEEDED {
+		return resp, err
+	}
+	// The signature is only returned once the attestation is recorded.
+	if err := s.protection.saveAttestation(account, &signedAttestation{
+		sourceEpoch: data.Source.Epoch,
+		targetEpoch: data.Target.Epoch,
+		signingRoot: signingRoot,
+	}); err != nil {
+		return nil, errors.Wrap(err, "could not record signed attestation")
+	}
+	return resp, nil
+}
+
+func (s *Server) sign(account string, signingRoot [32]byte) (*pb.SignResponse, error) {
+	share, ok := s.shares[account]
+	if !ok {
+		return failed("unknown account")
+	}
+	return &pb.SignResponse{
+		State:     pb.ResponseState_SUCCEEDED,
+		Signature: share.Sign(signingRoot[:]).Marshal(),
+	}, nil
+}
+
+func failed(reason string) (*pb.SignResponse, error) {
+	log.Debugf("Failed to sign: %s", reason)
+	return &pb.SignResponse{State: pb.ResponseState_FAILED}, nil
+}
@@ -0,0 +1,196 @@
+package thresholdsigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	pb "github.com/wealdtech/eth2-signer-api/pb/v1"
+)
+
+func signerDataDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "threshold-signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Error(err)
+		}
+	})
+	return dir
+}
+
+func TestShareFile_WriteLoad(t *testing.T) {
+	secretKeys := []*bls.SecretKey{bls.RandKey(), bls.RandKey()}
+	shareFiles, splitKeys, err := SplitKeys(secretKeys, 2, 3, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shareFiles) != 3 || len(splitKeys) != 2 {
+		t.Fatalf("Wanted 3 share files and 2 split keys, received %d and %d", len(shareFiles), len(splitKeys))
+	}
+	path := filepath.Join(testutil.TempDir(), "threshold-share-file.json")
+	if err := WriteShareFile(path, shareFiles[1]); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadShareFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ID != 2 || len(loaded.Shares) != 2 {
+		t.Fatalf("Loaded share file with id %d and %d shares, wanted id 2 and 2 shares", loaded.ID, len(loaded.Shares))
+	}
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secretKey := range secretKeys {
+		if bytes.Contains(enc, []byte(hex.EncodeToString(secretKey.Marshal()))) {
+			t.Error("Share file holds a secret key in plaintext")
+		}
+	}
+	if _, err := NewServer(loaded, "wrong", signerDataDir(t)); err == nil {
+		t.Error("Expected decrypting shares with the wrong password to fail")
+	}
+	s, err := NewServer(loaded, "password", signerDataDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Stop()
+}
+
+func TestServer_SignBeaconAttestation(t *testing.T) {
+	shareFiles, splitKeys, err := SplitKeys([]*bls.SecretKey{bls.RandKey()}, 1, 1, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewServer(shareFiles[0], "password", signerDataDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	account := AccountName(splitKeys[0].PublicKey)
+	attest := func(source uint64, target uint64, blockRoot byte) pb.ResponseState {
+		resp, err := s.SignBeaconAttestation(context.Background(), &pb.SignBeaconAttestationRequest{
+			Id:     &pb.SignBeaconAttestationRequest_Account{Account: account},
+			Domain: make([]byte, 32),
+			Data: &pb.AttestationData{
+				Slot:            target * 32,
+				BeaconBlockRoot: bytesutil.PadTo([]byte{blockRoot}, 32),
+				Source:          &pb.Checkpoint{Epoch: source, Root: make([]byte, 32)},
+				Target:          &pb.Checkpoint{Epoch: target, Root: make([]byte, 32)},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.State
+	}
+
+	tests := []struct {
+		name      string
+		source    uint64
+		target    uint64
+		blockRoot byte
+		want      pb.ResponseState
+	}{
+		{name: "First attestation", source: 1, target: 2, want: pb.ResponseState_SUCCEEDED},
+		{name: "Same attestation", source: 1, target: 2, want: pb.ResponseState_SUCCEEDED},
+		{name: "Double vote", source: 1, target: 2, blockRoot: 1, want: pb.ResponseState_DENIED},
+		{name: "Lower source", source: 0, target: 3, want: pb.ResponseState_DENIED},
+		{name: "Next attestation", source: 2, target: 3, want: pb.ResponseState_SUCCEEDED},
+	}
+	for _, tt := range tests {
+		if state := attest(tt.source, tt.target, tt.blockRoot); state != tt.want {
+			t.Errorf("%s: wanted state %v, received %v", tt.name, tt.want, state)
+		}
+	}
+
+	resp, err := s.Sign(context.Background(), &pb.SignRequest{
+		Id:     &pb.SignRequest_Account{Account: "0x00"},
+		Data:   make([]byte, 32),
+		Domain: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != pb.ResponseState_FAILED {
+		t.Errorf("Wanted state %v for an unknown account, received %v", pb.ResponseState_FAILED, resp.State)
+	}
+}
+
+func TestServer_ProtectionSurvivesRestart(t *testing.T) {
+	shareFiles, splitKeys, err := SplitKeys([]*bls.SecretKey{bls.RandKey()}, 1, 1, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataDir := signerDataDir(t)
+	account := AccountName(splitKeys[0].PublicKey)
+	propose := func(s *Server, stateRoot byte) pb.ResponseState {
+		resp, err := s.SignBeaconProposal(context.Background(), &pb.SignBeaconProposalRequest{
+			Id:     &pb.SignBeaconProposalRequest_Account{Account: account},
+			Domain: make([]byte, 32),
+			Data: &pb.BeaconBlockHeader{
+				Slot:       5,
+				ParentRoot: make([]byte, 32),
+				StateRoot:  bytesutil.PadTo([]byte{stateRoot}, 32),
+				BodyRoot:   make([]byte, 32),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.State
+	}
+	attest := func(s *Server, blockRoot byte) pb.ResponseState {
+		resp, err := s.SignBeaconAttestation(context.Background(), &pb.SignBeaconAttestationRequest{
+			Id:     &pb.SignBeaconAttestationRequest_Account{Account: account},
+			Domain: make([]byte, 32),
+			Data: &pb.AttestationData{
+				Slot:            64,
+				BeaconBlockRoot: bytesutil.PadTo([]byte{blockRoot}, 32),
+				Source:          &pb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+				Target:          &pb.Checkpoint{Epoch: 2, Root: make([]byte, 32)},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.State
+	}
+
+	s, err := NewServer(shareFiles[0], "password", dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := propose(s, 0); state != pb.ResponseState_SUCCEEDED {
+		t.Fatalf("Wanted state %v for the first proposal, received %v", pb.ResponseState_SUCCEEDED, state)
+	}
+	if state := attest(s, 0); state != pb.ResponseState_SUCCEEDED {
+		t.Fatalf("Wanted state %v for the first attestation, received %v", pb.ResponseState_SUCCEEDED, state)
+	}
+	s.Stop()
+
+	restarted, err := NewServer(shareFiles[0], "password", dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Stop()
+	if state := propose(restarted, 1); state != pb.ResponseState_DENIED {
+		t.Errorf("Wanted state %v for a conflicting proposal after a restart, received %v", pb.ResponseState_DENIED, state)
+	}
+	if state := attest(restarted, 1); state != pb.ResponseState_DENIED {
+		t.Errorf("Wanted state %v for a double vote after a restart, received %v", pb.ResponseState_DENIED, state)
+	}
+	if state := attest(restarted, 0); state != pb.ResponseState_SUCCEEDED {
+		t.Errorf("Wanted state %v for the same attestation after a restart, received %v", pb.ResponseState_SUCCEEDED, state)
+	}
+}
@@ -0,0 +1,103 @@
+// Package thresholdsigner holds the t-of-n Shamir shares of validating keys and signs with them on
+// behalf of the threshold keymanager. Each share of a key is held by a separate signer, so that
+// no single signer can produce a signature of the key on its own.
+package thresholdsigner
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+)
+
+// ShareFile is the file holding the key shares of a signer. All shares of a signer have the same
+// share id.
+type ShareFile struct {
+	ID     uint64   `json:"id"`
+	Shares []*Share `json:"shares"`
+}
+
+// Share is a share of a validating key. The public key of the validating key is hex encoded with
+// a 0x prefix, and the secret key of the share is encrypted as in an EIP-2335 keystore.
+type Share struct {
+	PublicKey string                  `json:"public_key"`
+	Crypto    *keystore.EIP2335Crypto `json:"crypto"`
+}
+
+// SplitKey is a validating key split into shares.
+type SplitKey struct {
+	PublicKey [48]byte
+	// VerificationVector holds the public keys of the coefficients of the sharing polynomial, from
+	// which the public key of each share is derived.
+	VerificationVector []*bls.PublicKey
+}
+
+// SplitKeys splits the secret keys into total shares each, any threshold of which can sign. It
+// returns the share files of the signers, with share ids 1 to total and the shares encrypted under
+// the password, and the split keys.
+func SplitKeys(secretKeys []*bls.SecretKey, threshold uint64, total uint64, password string) ([]*ShareFile, []*SplitKey, error) {
+	shareFiles := make([]*ShareFile, total)
+	for i := range shareFiles {
+		shareFiles[i] = &ShareFile{ID: uint64(i) + 1}
+	}
+	splitKeys := make([]*SplitKey, len(secretKeys))
+	for i, secretKey := range secretKeys {
+		shares, verificationVector, err := bls.SplitSecretKey(secretKey, threshold, total)
+		if err != nil {
+			return nil, nil, err
+		}
+		pubKey := secretKey.PublicKey().Marshal()
+		for j, share := range shares {
+			crypto, err := keystore.EncryptSecretEIP2335(share.Marshal(), password, keystore.KDFPBKDF2)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "could not encrypt key share")
+			}
+			shareFiles[j].Shares = append(shareFiles[j].Shares, &Share{
+				PublicKey: fmt.Sprintf("%#x", pubKey),
+				Crypto:    crypto,
+			})
+		}
+		var key [48]byte
+		copy(key[:], pubKey)
+		splitKeys[i] = &SplitKey{PublicKey: key, VerificationVector: verificationVector}
+	}
+	return shareFiles, splitKeys, nil
+}
+
+// WriteShareFile writes the share file to path, readable only by the current user. The shares
+// in it stay encrypted.
+func WriteShareFile(path string, shareFile *ShareFile) error {
+	enc, err := json.MarshalIndent(shareFile, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not encode share file")
+	}
+	if err := ioutil.WriteFile(path, enc, 0600); err != nil {
+		return errors.Wrapf(err, "could not write share file %s", path)
+	}
+	return nil
+}
+
+// LoadShareFile reads the share file at path.
+func LoadShareFile(path string) (*ShareFile, error) {
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read share file %s", path)
+	}
+	shareFile := &ShareFile{}
+	if err := json.Unmarshal(enc, shareFile); err != nil {
+		return nil, errors.Wrapf(err, "could not decode share file %s", path)
+	}
+	if shareFile.ID == 0 {
+		return nil, fmt.Errorf("share file %s has no share id", path)
+	}
+	return shareFile, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
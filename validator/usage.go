@@ -76,6 +76,7 @@ var appHelpFlagGroups = []flagGroup{
 		Name: "validator",
 		Flags: []cli.Flag{
 			flags.BeaconRPCProviderFlag,
+			flags.BroadcastSubmissionsFlag,
 			flags.CertFlag,
 			flags.KeyManager,
 			flags.KeyManagerOpts,
@@ -84,8 +85,18 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisablePenaltyRewardLogFlag,
 			flags.UnencryptedKeysFlag,
 			flags.GraffitiFlag,
+			flags.GraffitiFileFlag,
 			flags.GrpcRetriesFlag,
 			flags.GrpcHeadersFlag,
+			flags.DoppelgangerEpochsFlag,
+			flags.ShadowModeFlag,
+			flags.ManagementAPIFlag,
+			flags.ManagementAPIHostFlag,
+			flags.ManagementAPIPortFlag,
+			flags.ManagementAPITokenFileFlag,
+			flags.ManagementKeysPasswordFileFlag,
+			flags.AuditLogDirFlag,
+			flags.AuditLogMaxSizeFlag,
 			flags.SlasherRPCProviderFlag,
 			flags.SlasherCertFlag,
 			flags.SourceDirectories,